@@ -0,0 +1,99 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+)
+
+// cacheStats reports the number of objects of each type held in the cache
+// of the manager
+type cacheStats struct {
+	cache  cache.Cache
+	scheme *runtime.Scheme
+}
+
+// NOTE: only objects that are always watched by the controllers are listed,
+// since listing any other type starts a new informer
+var cachedLists = []client.ObjectList{
+	&v1alpha1.KeplerList{},
+	&v1alpha1.KeplerInternalList{},
+	// NOTE: only the metadata of configmaps and secrets is cached
+	&metav1.PartialObjectMetadataList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMapList"}},
+	&metav1.PartialObjectMetadataList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "SecretList"}},
+	&corev1.ServiceList{},
+	&corev1.ServiceAccountList{},
+	&corev1.EventList{},
+	&appsv1.DaemonSetList{},
+	&appsv1.DeploymentList{},
+	&rbacv1.ClusterRoleList{},
+	&rbacv1.ClusterRoleBindingList{},
+}
+
+func (s *cacheStats) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if s.cache == nil {
+		http.Error(w, "cache is not ready", http.StatusServiceUnavailable)
+		return
+	}
+
+	stats := map[string]int{}
+	for _, l := range cachedLists {
+		list := l.DeepCopyObject().(client.ObjectList)
+		if err := s.cache.List(req.Context(), list); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		gvk, err := apiutil.GVKForObject(list, s.scheme)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		stats[strings.TrimSuffix(gvk.Kind, "List")] = meta.LenList(list)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(stats)
+}
+
+// diagnosticsHandlers returns the pprof handlers along with a handler that
+// reports the objects held in the cache. The handlers are served by the
+// metrics server so that they are protected by the same auth proxy.
+func diagnosticsHandlers(stats *cacheStats) map[string]http.Handler {
+	return map[string]http.Handler{
+		"/debug/pprof/":        http.HandlerFunc(pprof.Index),
+		"/debug/pprof/cmdline": http.HandlerFunc(pprof.Cmdline),
+		"/debug/pprof/profile": http.HandlerFunc(pprof.Profile),
+		"/debug/pprof/symbol":  http.HandlerFunc(pprof.Symbol),
+		"/debug/pprof/trace":   http.HandlerFunc(pprof.Trace),
+		"/debug/cache":         stats,
+	}
+}
@@ -19,44 +19,83 @@ package main
 import (
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
+	"time"
+	// NOTE: embed the IANA time zones the tariffs of electricity prices are
+	// in since the operator image may not ship them
+	_ "time/tzdata"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
+	"k8s.io/client-go/discovery"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 
 	monv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	configv1 "github.com/openshift/api/config/v1"
+	consolev1 "github.com/openshift/api/console/v1"
 	securityv1 "github.com/openshift/api/security/v1"
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/time/rate"
 
 	keplersystemv1alpha1 "github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components/carbon"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components/consoleplugin"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/components/estimator"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/components/exporter"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/components/modelserver"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components/otel"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components/telegraf"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/controllers"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/features"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/reconciler"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/registry"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/sigstore"
 	//+kubebuilder:scaffold:imports
 )
 
 var (
 	scheme   = runtime.NewScheme()
 	setupLog = ctrl.Log.WithName("setup")
+
+	// uncachedObjects are read directly from the api server since only their
+	// metadata is watched; caching them would hold their data in memory
+	uncachedObjects = []client.Object{&corev1.Secret{}, &corev1.ConfigMap{}}
 )
 
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(keplersystemv1alpha1.AddToScheme(scheme))
 	utilruntime.Must(securityv1.AddToScheme(scheme))
+	utilruntime.Must(configv1.AddToScheme(scheme))
+	utilruntime.Must(consolev1.AddToScheme(scheme))
 	utilruntime.Must(monv1.AddToScheme(scheme))
 
 	//+kubebuilder:scaffold:scheme
@@ -75,52 +114,362 @@ func (s *stringList) Set(value string) error {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "render" {
+		if err := render(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "report" {
+		if err := runReport(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
+	var leaderElectionNamespace string
+	var leaseDuration, renewDeadline, retryPeriod time.Duration
 	var openshift bool
+	var platform string
 	var probeAddr string
 	var additionalNamespaces stringList
+	var keplerConcurrency, internalConcurrency int
+	var retryBaseDelay, retryMaxDelay time.Duration
+	var retryQPS float64
+	var retryBurst int
+	var syncPeriod, resyncInterval time.Duration
+	var apiDiscoveryInterval time.Duration
+	var modelVerificationInterval time.Duration
+	var dryRun bool
+	var enableProfiling bool
+	var digestOnly bool
+	var resolveDigests bool
+	var pullSecrets stringList
+	var fips bool
+	var secureMetrics bool
+	var tlsMinVersion string
+	var tlsCipherSuites stringList
+	prometheus := &controllers.PrometheusClient{}
+	var nodePower bool
+	nodePowerAnnotator := &controllers.NodePowerAnnotator{Prometheus: prometheus}
+	var idlePower bool
+	idlePowerCalibrator := &controllers.IdlePowerCalibrator{Prometheus: prometheus}
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
-
-	flag.StringVar(&controllers.KeplerDeploymentNS, "deployment-namespace", controllers.KeplerDeploymentNS,
+	flag.StringVar(&leaderElectionNamespace, "leader-elect-namespace", "",
+		"Namespace in which the leader election lease is created; defaults to the namespace the operator runs in.")
+	flag.DurationVar(&leaseDuration, "leader-elect-lease-duration", 15*time.Second,
+		"Duration that non-leader candidates will wait to force acquire leadership.")
+	flag.DurationVar(&renewDeadline, "leader-elect-renew-deadline", 10*time.Second,
+		"Duration that the acting leader will retry refreshing leadership before giving up.")
+	flag.DurationVar(&retryPeriod, "leader-elect-retry-period", 2*time.Second,
+		"Duration the leader election clients should wait between tries of actions.")
+
+	// NOTE: DEPLOYMENT_NAMESPACE can be set as env or flag, flag takes precedence over env
+	deploymentNS := controllers.KeplerDeploymentNS
+	if ns := os.Getenv("DEPLOYMENT_NAMESPACE"); ns != "" {
+		deploymentNS = ns
+	}
+	flag.StringVar(&controllers.KeplerDeploymentNS, "deployment-namespace", deploymentNS,
 		"Namespace where kepler and its components are deployed.")
 
 	flag.CommandLine.Var(flag.Value(&additionalNamespaces), "watch-namespaces",
-		"Namespaces other than deployment-namespace where kepler-internal may be deployed.")
-
+		"Namespaces other than deployment-namespace where kepler and kepler-internal may be deployed.")
+
+	flag.IntVar(&keplerConcurrency, "kepler.max-concurrent-reconciles", 1,
+		"Maximum number of Kepler objects reconciled concurrently.")
+	flag.IntVar(&internalConcurrency, "kepler-internal.max-concurrent-reconciles", 1,
+		"Maximum number of KeplerInternal objects reconciled concurrently.")
+
+	// NOTE: defaults are the same as workqueue.DefaultControllerRateLimiter
+	flag.DurationVar(&retryBaseDelay, "reconcile.retry-base-delay", 5*time.Millisecond,
+		"Delay before a failed reconcile is retried; doubles on each consecutive failure.")
+	flag.DurationVar(&retryMaxDelay, "reconcile.retry-max-delay", 1000*time.Second,
+		"Maximum delay before a failed reconcile is retried.")
+	flag.Float64Var(&retryQPS, "reconcile.retry-qps", 10,
+		"Overall number of retries per second across all objects of a controller.")
+	flag.IntVar(&retryBurst, "reconcile.retry-burst", 100,
+		"Overall number of retries allowed in a burst across all objects of a controller.")
+
+	// NOTE: defaults to the same period as controller-runtime
+	flag.DurationVar(&syncPeriod, "sync-period", 10*time.Hour,
+		"Period at which all watched objects are reconciled even if they have not changed; "+
+			"lower values revert out-of-band changes sooner at the cost of more API requests.")
+	flag.DurationVar(&resyncInterval, "reconcile.resync-interval", 100*time.Millisecond,
+		"Interval at which the periodic reconciles, held back behind the changes made by users, "+
+			"are queued one at a time while no change is queued.")
+
+	flag.DurationVar(&apiDiscoveryInterval, "api-discovery-interval", 5*time.Minute,
+		"Interval at which the api groups served by the cluster are discovered to detect add-ons such as the Prometheus Operator.")
+	flag.DurationVar(&modelVerificationInterval, "model-verification-interval", time.Hour,
+		"Interval at which the signatures of the models deployed with model verification are verified again.")
+
+	flag.Func("feature-gates", "A set of key=value pairs that enable or disable features. Options are:\n"+
+		strings.Join(features.Gate.KnownFeatures(), "\n"), features.Gate.Set)
+
+	flag.BoolVar(&enableProfiling, "enable-profiling", false,
+		"Serve pprof and cache statistics under /debug on the metrics endpoint.")
+	flag.BoolVar(&dryRun, "dry-run", false,
+		"Log the changes the operator would make without making any changes to the cluster.")
+
+	flag.BoolVar(&controllers.Config.EnableUserWorkloadMonitoring, "openshift.enable-user-workload-monitoring", false,
+		"Enable User Workload Monitoring on OpenShift if it is disabled so that kepler metrics are collected.")
+
+	flag.Int64Var(&controllers.Config.SeriesWarningThreshold, "cardinality.warning-threshold", 1000000,
+		"Number of metric series kepler is estimated to produce above which the HighCardinality condition is set; 0 disables the condition.")
+
+	flag.IntVar(&controllers.Config.Shard.Count, "shard.count", 1,
+		"Number of shards Kepler and KeplerInternal objects are partitioned into; run a replica of the operator per shard.")
+	flag.IntVar(&controllers.Config.Shard.Index, "shard.index", 0,
+		"Index of the shard reconciled by this replica; objects are assigned by the "+controllers.ShardLabel+" label or the hash of their name.")
+
+	flag.StringVar(&prometheus.URL, "prometheus.url", "",
+		"URL of the Prometheus compatible API the power of the nodes is read from by the node power annotator and the idle power calibrator.")
+	flag.StringVar(&prometheus.TokenFile, "prometheus.token-file", "",
+		"File with the bearer token sent to Prometheus, e.g. /var/run/secrets/kubernetes.io/serviceaccount/token.")
+	flag.StringVar(&prometheus.CAFile, "prometheus.ca-file", "",
+		"CA bundle trusted, in addition to the system CAs, to verify Prometheus, e.g. /var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt.")
+
+	flag.BoolVar(&nodePower, "node-power.enable", false,
+		"Annotate the nodes with their smoothed and peak power read from Prometheus for power aware schedulers; "+
+			"only the first shard annotates the nodes.")
+	flag.DurationVar(&nodePowerAnnotator.Interval, "node-power.interval", time.Minute,
+		"Interval at which the node annotations are updated.")
+	flag.Float64Var(&nodePowerAnnotator.MinChange, "node-power.min-change", 5,
+		"Change in watts of the power of a node below which its annotations are not updated.")
+	flag.Float64Var(&nodePowerAnnotator.Smoothing, "node-power.smoothing", 0.3,
+		"Weight of the latest power in the moving average of the power of a node; 1 disables smoothing.")
+	flag.DurationVar(&nodePowerAnnotator.PeakWindow, "node-power.peak-window", 24*time.Hour,
+		"Window over which the peak power of a node is taken.")
+
+	flag.BoolVar(&idlePower, "idle-power.enable", false,
+		"Record the idle power measured on each node in the "+controllers.IdlePowerConfigMap+" ConfigMap and the kepler_operator_node_idle_power_watts metric; "+
+			"only the first shard calibrates the idle power.")
+	flag.DurationVar(&idlePowerCalibrator.Interval, "idle-power.interval", 6*time.Hour,
+		"Interval at which the idle power is calibrated.")
+	flag.DurationVar(&idlePowerCalibrator.Window, "idle-power.window", 7*24*time.Hour,
+		"Window over which the lowest power of a node is taken as its idle power.")
+
+	flag.StringVar(&platform, "platform", "auto",
+		"Platform the operator runs on: auto, kubernetes or openshift; auto detects OpenShift using the api groups of the cluster.")
 	flag.BoolVar(&openshift, "openshift", false,
-		"Indicate if the operator is running on an OpenShift cluster.")
-
-	// NOTE: RELATED_IMAGE_KEPLER can be set as env or flag, flag takes precedence over env
-	keplerImage := os.Getenv("RELATED_IMAGE_KEPLER")
-	flag.StringVar(&controllers.Config.Image, "kepler.image", keplerImage, "kepler image")
-
-	flag.StringVar(&controllers.InternalConfig.ModelServerImage, "estimator.image", estimator.StableImage, "kepler estimator image")
-	flag.StringVar(&controllers.InternalConfig.EstimatorImage, "model-server.image", modelserver.StableImage, "kepler model server image")
-
+		"Indicate if the operator is running on an OpenShift cluster; same as --platform=openshift.")
+
+	// NOTE: images can be set as RELATED_IMAGE_* env populated from the
+	// relatedImages of the CSV or as flags; flags take precedence over env
+	flag.StringVar(&controllers.Config.Image, "kepler.image", relatedImage("KEPLER", ""), "kepler image")
+
+	flag.StringVar(&controllers.InternalConfig.EstimatorImage, "estimator.image",
+		relatedImage("ESTIMATOR", estimator.StableImage), "kepler estimator image")
+	flag.StringVar(&controllers.InternalConfig.ModelServerImage, "model-server.image",
+		relatedImage("MODEL_SERVER", modelserver.StableImage), "kepler model server image")
+	flag.StringVar(&controllers.InternalConfig.ConsolePluginImage, "console-plugin.image",
+		relatedImage("CONSOLE_PLUGIN", consoleplugin.StableImage), "kepler console plugin image")
+	flag.StringVar(&controllers.InternalConfig.OTelCollectorImage, "otel-collector.image",
+		relatedImage("OTEL_COLLECTOR", otel.StableImage), "opentelemetry collector image")
+	flag.StringVar(&controllers.InternalConfig.RBACProxyImage, "kube-rbac-proxy.image",
+		relatedImage("KUBE_RBAC_PROXY", exporter.RBACProxyImage), "kube-rbac-proxy image")
+	flag.StringVar(&controllers.InternalConfig.CarbonIntensityImage, "carbon-intensity-exporter.image",
+		relatedImage("CARBON_INTENSITY_EXPORTER", carbon.StableImage), "carbon intensity exporter image")
+	flag.StringVar(&controllers.InternalConfig.EnergyReportImage, "energy-report.image",
+		relatedImage("ENERGY_REPORT", ""), "energy report image; the image of the operator")
+	flag.StringVar(&controllers.InternalConfig.TelegrafImage, "telegraf.image",
+		relatedImage("TELEGRAF", telegraf.StableImage), "telegraf image")
+	flag.BoolVar(&fips, "fips", false,
+		"Require FIPS validated crypto and refuse TLS settings not approved by FIPS; "+
+			"the operator must be built with GOEXPERIMENT=boringcrypto.")
+	flag.BoolVar(&secureMetrics, "metrics-secure", false,
+		"Serve the metrics over https with the TLS settings of --tls-min-version and --tls-cipher-suites; "+
+			"metrics are served over http otherwise, e.g. to kube-rbac-proxy on localhost.")
+	flag.StringVar(&tlsMinVersion, "tls-min-version", "VersionTLS12",
+		"Minimum TLS version of the webhook and metrics servers and of kube-rbac-proxy of the exporter: VersionTLS12 or VersionTLS13.")
+	flag.Var(&tlsCipherSuites, "tls-cipher-suites",
+		"Comma-separated list of TLS 1.2 cipher suites of the webhook and metrics servers and of kube-rbac-proxy of the exporter; "+
+			"defaults to the Go defaults.")
+	flag.BoolVar(&resolveDigests, "images.resolve-digests", false,
+		"Resolve the tags of operand images to digests, deploy them by digest and refuse to deploy images "+
+			"that cannot be resolved; images are not resolved again unless the configured image changes.")
+	flag.Var(&pullSecrets, "images.pull-secrets",
+		"Comma-separated list of image pull secrets in the deployment namespace with the credentials used to resolve "+
+			"the tags of images in private registries.")
+	flag.BoolVar(&digestOnly, "images.digest-only", false,
+		"Require all operand images, including those set in the spec, to be referenced by digest as needed to use mirrors in disconnected clusters.")
+
+	// NOTE: log level and encoding can be changed using --zap-log-level and
+	// --zap-encoder=json; each log entry of a reconcile includes its reconcileID
 	opts := zap.Options{
 		Development: true,
+		TimeEncoder: zapcore.ISO8601TimeEncoder,
 	}
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
-	if openshift {
-		controllers.Config.Cluster = k8s.OpenShift
+	if errs := validation.IsDNS1123Label(controllers.KeplerDeploymentNS); len(errs) != 0 {
+		setupLog.Error(fmt.Errorf("%s", strings.Join(errs, "; ")), "invalid deployment namespace",
+			"namespace", controllers.KeplerDeploymentNS)
+		os.Exit(1)
+	}
+	for _, ns := range additionalNamespaces {
+		if errs := validation.IsDNS1123Label(ns); len(errs) != 0 {
+			setupLog.Error(fmt.Errorf("%s", strings.Join(errs, "; ")), "invalid watch namespace", "namespace", ns)
+			os.Exit(1)
+		}
 	}
+	controllers.Config.WatchNamespaces = additionalNamespaces
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme: scheme,
-		Metrics: metricsserver.Options{
-			BindAddress: metricsAddr,
+	if keplerConcurrency < 1 || internalConcurrency < 1 {
+		setupLog.Error(fmt.Errorf("max concurrent reconciles must be at least 1"), "invalid concurrency flags")
+		os.Exit(1)
+	}
+
+	if retryBaseDelay <= 0 || retryMaxDelay < retryBaseDelay || retryQPS <= 0 || retryBurst < 1 {
+		setupLog.Error(fmt.Errorf("base delay %s, max delay %s, qps %v and burst %d must be positive and max delay must not be less than base delay",
+			retryBaseDelay, retryMaxDelay, retryQPS, retryBurst), "invalid reconcile retry flags")
+		os.Exit(1)
+	}
+
+	if digestOnly {
+		if err := controllers.ValidateDigestImages(); err != nil {
+			setupLog.Error(err, "invalid operand images")
+			os.Exit(1)
+		}
+	}
+
+	if fips && !fipsEnabled() {
+		setupLog.Error(fmt.Errorf("FIPS mode requires the operator to be built with GOEXPERIMENT=boringcrypto"),
+			"FIPS validated crypto is not available")
+		os.Exit(1)
+	}
+	tlsOpts, err := tlsOptions(tlsMinVersion, tlsCipherSuites, fips)
+	if err != nil {
+		setupLog.Error(err, "invalid tls flags", "fips", fips)
+		os.Exit(1)
+	}
+	controllers.InternalConfig.RBACProxyTLSMinVersion = tlsMinVersion
+	controllers.InternalConfig.RBACProxyTLSCipherSuites = cipherSuiteNames(tlsCipherSuites, fips)
+
+	if syncPeriod <= 0 {
+		setupLog.Error(fmt.Errorf("sync period %s must be positive", syncPeriod), "invalid sync period")
+		os.Exit(1)
+	}
+
+	if resyncInterval <= 0 {
+		setupLog.Error(fmt.Errorf("resync interval %s must be positive", resyncInterval), "invalid resync interval")
+		os.Exit(1)
+	}
+
+	if apiDiscoveryInterval <= 0 {
+		setupLog.Error(fmt.Errorf("api discovery interval %s must be positive", apiDiscoveryInterval), "invalid api discovery interval")
+		os.Exit(1)
+	}
+
+	if modelVerificationInterval <= 0 {
+		setupLog.Error(fmt.Errorf("model verification interval %s must be positive", modelVerificationInterval), "invalid model verification interval")
+		os.Exit(1)
+	}
+
+	if nodePower {
+		if err := nodePowerAnnotator.Validate(); err != nil {
+			setupLog.Error(err, "invalid node power flags")
+			os.Exit(1)
+		}
+	}
+
+	if idlePower {
+		if err := idlePowerCalibrator.Validate(); err != nil {
+			setupLog.Error(err, "invalid idle power flags")
+			os.Exit(1)
+		}
+	}
+
+	if err := controllers.Config.Shard.Validate(); err != nil {
+		setupLog.Error(err, "invalid shard flags")
+		os.Exit(1)
+	}
+
+	// NOTE: each shard elects its own leader so that the shards reconcile
+	// their objects concurrently
+	leaderElectionID := "0d9cbc82.sustainable.computing.io"
+	if controllers.Config.Shard.IsSharded() {
+		leaderElectionID = fmt.Sprintf("shard-%d.%s", controllers.Config.Shard.Index, leaderElectionID)
+	}
+
+	if renewDeadline >= leaseDuration {
+		setupLog.Error(fmt.Errorf("renew deadline %s must be less than lease duration %s", renewDeadline, leaseDuration),
+			"invalid leader election flags")
+		os.Exit(1)
+	}
+
+	restConfig := ctrl.GetConfigOrDie()
+	cluster, err := platformCluster(restConfig, platform, openshift)
+	if err != nil {
+		setupLog.Error(err, "unable to determine the platform", "platform", platform)
+		os.Exit(1)
+	}
+	setupLog.Info("running on platform", "platform", cluster, "requested", platform)
+	controllers.Config.Cluster = cluster
+	openshift = cluster == k8s.OpenShift
+
+	// NOTE: the api groups served by the cluster are discovered once and
+	// refreshed periodically rather than on every reconcile
+	dc, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		setupLog.Error(err, "unable to create discovery client")
+		os.Exit(1)
+	}
+	apiGroups := k8s.NewAPIGroups(dc, apiDiscoveryInterval)
+	if err := apiGroups.Refresh(); err != nil {
+		setupLog.Error(err, "unable to discover api groups")
+	}
+	if apiGroups.Has(controllers.KubeVirtAPIGroup) {
+		setupLog.Info("detected kubevirt; set spec.exporter.virtualMachines.enabled to attribute power to virtual machines")
+	}
+	controllers.Config.APIGroups = apiGroups
+
+	// NOTE: OPERATOR_CONDITION_NAME is set by OLM when the operator is
+	// installed using OLM
+	controllers.Config.OperatorCondition = types.NamespacedName{
+		Name:      os.Getenv("OPERATOR_CONDITION_NAME"),
+		Namespace: os.Getenv("OPERATOR_NAMESPACE"),
+	}
+
+	stats := &cacheStats{scheme: scheme}
+	// NOTE: the TLS options only apply if the metrics are served over https
+	metricsOpts := metricsserver.Options{
+		BindAddress:   metricsAddr,
+		SecureServing: secureMetrics,
+		TLSOpts:       tlsOpts,
+	}
+	if enableProfiling {
+		setupLog.Info("profiling is enabled", "address", metricsAddr)
+		metricsOpts.ExtraHandlers = diagnosticsHandlers(stats)
+	}
+
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
+		Scheme:        scheme,
+		Metrics:       metricsOpts,
+		WebhookServer: webhook.NewServer(webhook.Options{TLSOpts: tlsOpts}),
+		Cache: cache.Options{
+			SyncPeriod:       &syncPeriod,
+			DefaultTransform: controllers.TrimCachedObject,
 		},
-		// TODO: add new introduced namespace from KeplerInternal.Spec.Deployment.Namespace
+		Client: client.Options{
+			Cache: &client.CacheOptions{
+				DisableFor: uncachedObjects,
+			},
+		},
+		// NOTE: the namespace of kepler must be one of the namespaces cached,
+		// see controllers.Config.WatchNamespaces, so that its resources are watched
 		NewCache: func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
 			cacheNs := map[string]cache.Config{
 				controllers.KeplerDeploymentNS: {},
@@ -132,12 +481,52 @@ func main() {
 				cacheNs[ns] = cache.Config{}
 			}
 			opts.DefaultNamespaces = cacheNs
+
+			// NOTE: cache only the objects managed by the operator along with
+			// all nodes, trimmed by the transform, and the metadata of secrets
+			// and configmaps so that the memory used by the operator does not
+			// grow with the size of the cluster
+			managed := cache.ByObject{Label: labels.SelectorFromSet(components.CommonLabels.ToMap())}
+			opts.ByObject = map[client.Object]cache.ByObject{
+				&corev1.ConfigMap{}:             managed,
+				&corev1.ServiceAccount{}:        managed,
+				&corev1.Service{}:               managed,
+				&corev1.Pod{}:                   managed,
+				&corev1.PersistentVolumeClaim{}: managed,
+				&corev1.Namespace{}:             managed,
+				&appsv1.DaemonSet{}:             managed,
+				&appsv1.Deployment{}:            managed,
+				&networkingv1.NetworkPolicy{}:   managed,
+				&policyv1.PodDisruptionBudget{}: managed,
+				&rbacv1.ClusterRole{}:           managed,
+				&rbacv1.ClusterRoleBinding{}:    managed,
+				// NOTE: RoleBindings of the power-metrics-viewer are created in
+				// the namespaces of the tenants
+				&rbacv1.RoleBinding{}: {
+					Label:      managed.Label,
+					Namespaces: map[string]cache.Config{cache.AllNamespaces: {}},
+				},
+				&corev1.Event{}: {
+					Field: fields.OneTermEqualSelector("involvedObject.kind", "DaemonSet"),
+				},
+			}
+			if openshift {
+				opts.ByObject[&securityv1.SecurityContextConstraints{}] = managed
+				opts.ByObject[&consolev1.ConsolePlugin{}] = managed
+				opts.ByObject[&configv1.Proxy{}] = cache.ByObject{
+					Field: fields.OneTermEqualSelector("metadata.name", modelserver.ClusterProxyName),
+				}
+			}
 			return cache.New(config, opts)
 		},
 
-		HealthProbeBindAddress: probeAddr,
-		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "0d9cbc82.sustainable.computing.io",
+		HealthProbeBindAddress:  probeAddr,
+		LeaderElection:          enableLeaderElection,
+		LeaderElectionID:        leaderElectionID,
+		LeaderElectionNamespace: leaderElectionNamespace,
+		LeaseDuration:           &leaseDuration,
+		RenewDeadline:           &renewDeadline,
+		RetryPeriod:             &retryPeriod,
 
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
@@ -156,20 +545,80 @@ func main() {
 		os.Exit(1)
 	}
 
+	stats.cache = mgr.GetCache()
+
+	if err := mgr.Add(apiGroups); err != nil {
+		setupLog.Error(err, "unable to refresh api groups")
+		os.Exit(1)
+	}
+
+	// NOTE: in dry-run mode, all changes are sent with dry-run set so that
+	// they are validated by the api server but not persisted, and events are
+	// dropped
+	c := mgr.GetClient()
+	var recorder record.EventRecorder = mgr.GetEventRecorderFor(controllers.EventSource)
+	if dryRun {
+		setupLog.Info("running in dry-run mode; changes will only be logged")
+		c = reconciler.NewDryRunClient(c)
+		recorder = &record.FakeRecorder{}
+	}
+
 	if err = (&controllers.KeplerReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   c,
+		Scheme:   mgr.GetScheme(),
+		Recorder: recorder,
+
+		MaxConcurrentReconciles: keplerConcurrency,
+		RateLimiter:             newRateLimiter(retryBaseDelay, retryMaxDelay, retryQPS, retryBurst),
+		ResyncInterval:          resyncInterval,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "kepler")
 		os.Exit(1)
 	}
 	if err = (&controllers.KeplerInternalReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:    c,
+		Scheme:    mgr.GetScheme(),
+		Recorder:  recorder,
+		APIReader: mgr.GetAPIReader(),
+
+		MaxConcurrentReconciles: internalConcurrency,
+		RateLimiter:             newRateLimiter(retryBaseDelay, retryMaxDelay, retryQPS, retryBurst),
+		ResyncInterval:          resyncInterval,
+		ImageResolver:           imageResolver(resolveDigests, mgr.GetAPIReader(), pullSecrets),
+		DigestOnlyImages:        digestOnly,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "kepler-internal")
 		os.Exit(1)
 	}
+	if err = (&controllers.ModelVerificationReconciler{
+		Client:    c,
+		Recorder:  recorder,
+		APIReader: mgr.GetAPIReader(),
+		Verifier:  sigstore.Verifier{Client: &http.Client{Timeout: 5 * time.Minute}},
+		Interval:  modelVerificationInterval,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "model-verification")
+		os.Exit(1)
+	}
+
+	// NOTE: each shard elects its own leader, so the nodes are annotated and
+	// the idle power ConfigMap is written by the leader of the first shard only
+	if nodePower && controllers.Config.Shard.Index == 0 {
+		nodePowerAnnotator.Client = c
+		if err := mgr.Add(nodePowerAnnotator); err != nil {
+			setupLog.Error(err, "unable to add node power annotator")
+			os.Exit(1)
+		}
+	}
+
+	if idlePower && controllers.Config.Shard.Index == 0 {
+		idlePowerCalibrator.Client = c
+		idlePowerCalibrator.Namespace = controllers.KeplerDeploymentNS
+		if err := mgr.Add(idlePowerCalibrator); err != nil {
+			setupLog.Error(err, "unable to add idle power calibrator")
+			os.Exit(1)
+		}
+	}
 
 	// Setup webhooks
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
@@ -203,3 +652,56 @@ func setupWebhooks(mgr ctrl.Manager) error {
 	}
 	return nil
 }
+
+// relatedImage returns the image set in the RELATED_IMAGE_<name> env var or
+// fallback if it is not set
+func relatedImage(name, fallback string) string {
+	if image := os.Getenv("RELATED_IMAGE_" + name); image != "" {
+		return image
+	}
+	return fallback
+}
+
+// platformCluster returns the cluster selected by the platform flag; the
+// --openshift flag takes precedence for backwards compatibility
+func platformCluster(cfg *rest.Config, platform string, openshift bool) (k8s.Cluster, error) {
+	if openshift {
+		return k8s.OpenShift, nil
+	}
+
+	switch platform {
+	case "openshift":
+		return k8s.OpenShift, nil
+	case "kubernetes":
+		return k8s.Kubernetes, nil
+	case "auto":
+		dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+		if err != nil {
+			return k8s.Kubernetes, err
+		}
+		return k8s.DetectCluster(dc)
+	}
+	return k8s.Kubernetes, fmt.Errorf("unsupported platform %q; must be auto, kubernetes or openshift", platform)
+}
+
+// imageResolver returns the resolver of operand image tags if enabled; the
+// pull secrets are read on every resolve so that rotated credentials are used
+func imageResolver(enabled bool, c client.Reader, pullSecrets []string) registry.Resolver {
+	if !enabled {
+		return nil
+	}
+	resolver := registry.HTTPResolver{Client: &http.Client{Timeout: 30 * time.Second}}
+	if len(pullSecrets) != 0 {
+		resolver.Keychain = controllers.PullSecretsKeychain(c, controllers.KeplerDeploymentNS, pullSecrets)
+	}
+	return resolver
+}
+
+// newRateLimiter returns a rate limiter that retries each failed item with an
+// exponential backoff while limiting the overall rate of retries
+func newRateLimiter(baseDelay, maxDelay time.Duration, qps float64, burst int) ratelimiter.RateLimiter {
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(baseDelay, maxDelay),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(qps), burst)},
+	)
+}
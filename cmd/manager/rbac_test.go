@@ -0,0 +1,64 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+)
+
+// grants returns true if one of the rules grants verb on all objects of the
+// resource
+func grants(rules []rbacv1.PolicyRule, group, resource, verb string) bool {
+	for _, r := range rules {
+		if len(r.ResourceNames) != 0 {
+			continue
+		}
+		if (slices.Contains(r.APIGroups, group) || slices.Contains(r.APIGroups, rbacv1.APIGroupAll)) &&
+			(slices.Contains(r.Resources, resource) || slices.Contains(r.Resources, rbacv1.ResourceAll)) &&
+			(slices.Contains(r.Verbs, verb) || slices.Contains(r.Verbs, rbacv1.VerbAll)) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestUncachedObjectsRBAC(t *testing.T) {
+	data, err := os.ReadFile("../../config/rbac/role.yaml")
+	assert.NoError(t, err)
+	role := rbacv1.ClusterRole{}
+	assert.NoError(t, yaml.Unmarshal(data, &role))
+
+	// NOTE: uncached objects are read from the api server, which requires get
+	// in addition to the list and watch needed to cache their metadata
+	for _, obj := range uncachedObjects {
+		gvk, err := apiutil.GVKForObject(obj, scheme)
+		assert.NoError(t, err)
+		resource, _ := meta.UnsafeGuessKindToResource(gvk)
+		for _, verb := range []string{"get", "list", "watch"} {
+			assert.True(t, grants(role.Rules, gvk.Group, resource.Resource, verb),
+				"%s must be granted on %s", verb, resource.Resource)
+		}
+	}
+}
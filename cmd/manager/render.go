@@ -0,0 +1,157 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components/carbon"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components/consoleplugin"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components/estimator"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components/exporter"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components/modelserver"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components/otel"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components/telegraf"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/controllers"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/features"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
+)
+
+// render writes the resources the operator creates for the Kepler or
+// KeplerInternal read from a file to stdout, e.g.
+//
+//	manager render -f kepler.yaml
+func render(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("render", flag.ContinueOnError)
+
+	file := fs.String("f", "-", "File containing a Kepler or KeplerInternal; - reads from stdin.")
+	openshift := fs.Bool("openshift", false, "Render resources for an OpenShift cluster.")
+	fs.StringVar(&controllers.KeplerDeploymentNS, "deployment-namespace", controllers.KeplerDeploymentNS,
+		"Namespace where kepler and its components are deployed.")
+	fs.StringVar(&controllers.Config.Image, "kepler.image", relatedImage("KEPLER", ""), "kepler image")
+	fs.StringVar(&controllers.InternalConfig.EstimatorImage, "estimator.image",
+		relatedImage("ESTIMATOR", estimator.StableImage), "kepler estimator image")
+	fs.StringVar(&controllers.InternalConfig.ModelServerImage, "model-server.image",
+		relatedImage("MODEL_SERVER", modelserver.StableImage), "kepler model server image")
+	fs.StringVar(&controllers.InternalConfig.ConsolePluginImage, "console-plugin.image",
+		relatedImage("CONSOLE_PLUGIN", consoleplugin.StableImage), "kepler console plugin image")
+	fs.StringVar(&controllers.InternalConfig.OTelCollectorImage, "otel-collector.image",
+		relatedImage("OTEL_COLLECTOR", otel.StableImage), "opentelemetry collector image")
+	fs.StringVar(&controllers.InternalConfig.RBACProxyImage, "kube-rbac-proxy.image",
+		relatedImage("KUBE_RBAC_PROXY", exporter.RBACProxyImage), "kube-rbac-proxy image")
+	fs.StringVar(&controllers.InternalConfig.CarbonIntensityImage, "carbon-intensity-exporter.image",
+		relatedImage("CARBON_INTENSITY_EXPORTER", carbon.StableImage), "carbon intensity exporter image")
+	fs.StringVar(&controllers.InternalConfig.EnergyReportImage, "energy-report.image",
+		relatedImage("ENERGY_REPORT", ""), "energy report image; the image of the operator")
+	fs.StringVar(&controllers.InternalConfig.TelegrafImage, "telegraf.image",
+		relatedImage("TELEGRAF", telegraf.StableImage), "telegraf image")
+	fs.Func("feature-gates", "A set of key=value pairs that enable or disable features.", features.Gate.Set)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *openshift {
+		controllers.Config.Cluster = k8s.OpenShift
+	}
+
+	data, err := readFile(*file)
+	if err != nil {
+		return err
+	}
+
+	resources, err := renderResources(data)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range resources {
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(r)
+		if err != nil {
+			return fmt.Errorf("failed to convert %s: %w", k8s.GVKName(r), err)
+		}
+		// NOTE: status and creation timestamp are set by the api server
+		delete(u, "status")
+		unstructured.RemoveNestedField(u, "metadata", "creationTimestamp")
+
+		y, err := yaml.Marshal(u)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", k8s.GVKName(r), err)
+		}
+		fmt.Fprintf(out, "---\n%s", y)
+	}
+	return nil
+}
+
+func readFile(name string) ([]byte, error) {
+	if name == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(name)
+}
+
+// renderResources renders the resources of the Kepler or KeplerInternal in data
+func renderResources(data []byte) ([]client.Object, error) {
+	meta := struct {
+		Kind string `json:"kind"`
+	}{}
+	if err := yaml.Unmarshal(data, &meta); err != nil {
+		return nil, err
+	}
+
+	switch meta.Kind {
+	case "Kepler":
+		k := v1alpha1.Kepler{}
+		if err := yaml.UnmarshalStrict(data, &k); err != nil {
+			return nil, err
+		}
+		defaultExporterDeployment(&k.Spec.Exporter.Deployment)
+		return controllers.Render(&k)
+
+	case "KeplerInternal":
+		ki := v1alpha1.KeplerInternal{}
+		if err := yaml.UnmarshalStrict(data, &ki); err != nil {
+			return nil, err
+		}
+		defaultExporterDeployment(&ki.Spec.Exporter.Deployment.ExporterDeploymentSpec)
+		return controllers.RenderInternal(&ki)
+	}
+	return nil, fmt.Errorf("unsupported kind %q; must be Kepler or KeplerInternal", meta.Kind)
+}
+
+// defaultExporterDeployment sets the defaults that would be set by the api
+// server
+func defaultExporterDeployment(d *v1alpha1.ExporterDeploymentSpec) {
+	if d.Port == 0 {
+		d.Port = 9103
+	}
+	if d.NodeSelector == nil {
+		d.NodeSelector = map[string]string{"kubernetes.io/os": "linux"}
+	}
+	if d.Tolerations == nil {
+		d.Tolerations = []corev1.Toleration{{Operator: corev1.TolerationOpExists}}
+	}
+}
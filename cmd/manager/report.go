@@ -0,0 +1,155 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/report"
+)
+
+// runReport writes the energy consumed by each namespace over the past week
+// or month to a ConfigMap or an S3 bucket; it is run by the energy report
+// CronJob, e.g.
+//
+//	manager report --prometheus-url=http://prometheus:9090 --namespace=kepler --configmap=kepler/kepler-energy-report
+func runReport(args []string) error {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+
+	prometheusURL := fs.String("prometheus-url", "", "URL of the Prometheus compatible API; the bearer token is read from $PROMETHEUS_TOKEN.")
+	caFile := fs.String("ca-file", "", "CA bundle trusted, in addition to the system CAs, to verify the Prometheus compatible API.")
+	namespace := fs.String("namespace", "", "Namespace of the kepler whose metrics are reported.")
+	schedule := fs.String("schedule", string(v1alpha1.WeeklyEnergyReport), "Period of the report: Weekly or Monthly.")
+	format := fs.String("format", string(v1alpha1.JSONEnergyReport), "Format of the report: JSON or CSV.")
+	emissions := fs.Bool("emissions", false, "Report the emissions recorded by the carbon intensity rules.")
+	configMap := fs.String("configmap", "", "namespace/name of the ConfigMap the report is written to.")
+	s3Endpoint := fs.String("s3-endpoint", "", "Endpoint of the S3 compatible API the report is uploaded to; "+
+		"the credentials are read from $AWS_ACCESS_KEY_ID and $AWS_SECRET_ACCESS_KEY.")
+	s3Region := fs.String("s3-region", "us-east-1", "Region of the bucket.")
+	s3Bucket := fs.String("s3-bucket", "", "Bucket the report is uploaded to.")
+	s3Prefix := fs.String("s3-prefix", "", "Prefix of the key of the report.")
+	timeout := fs.Duration("timeout", 5*time.Minute, "Timeout of the queries and of writing the report.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *prometheusURL == "" {
+		return fmt.Errorf("--prometheus-url is required")
+	}
+	if *namespace == "" {
+		return fmt.Errorf("--namespace is required")
+	}
+	if (*configMap == "") == (*s3Endpoint == "") {
+		return fmt.Errorf("exactly one of --configmap and --s3-endpoint is required")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	end := time.Now().UTC().Truncate(time.Hour)
+	start := report.Period(end, *schedule == string(v1alpha1.MonthlyEnergyReport))
+	querier := report.Querier{
+		URL:       strings.TrimSuffix(*prometheusURL, "/"),
+		Token:     os.Getenv("PROMETHEUS_TOKEN"),
+		Namespace: *namespace,
+	}
+	if *caFile != "" {
+		client, err := report.NewClient(*caFile)
+		if err != nil {
+			return fmt.Errorf("failed to load the CA bundle: %w", err)
+		}
+		querier.Client = client
+	}
+	r, err := querier.Report(ctx, start, end, *emissions)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	ext, contentType := "json", "application/json"
+	if *format == string(v1alpha1.CSVEnergyReport) {
+		ext, contentType = "csv", "text/csv"
+		data, err = r.CSV()
+	} else {
+		data, err = r.JSON()
+	}
+	if err != nil {
+		return err
+	}
+
+	if *configMap != "" {
+		return writeReportConfigMap(ctx, *configMap, "report."+ext, r, data)
+	}
+
+	s3 := report.S3{
+		Endpoint:        *s3Endpoint,
+		Region:          *s3Region,
+		Bucket:          *s3Bucket,
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+	}
+	key := fmt.Sprintf("%senergy-report-%s.%s", *s3Prefix, end.Format("2006-01-02"), ext)
+	return s3.Put(ctx, key, contentType, data)
+}
+
+// writeReportConfigMap replaces the report in the ConfigMap; the period of the
+// report is recorded in its annotations
+func writeReportConfigMap(ctx context.Context, ref, key string, r report.Report, data []byte) error {
+	ns, name, ok := strings.Cut(ref, "/")
+	if !ok {
+		return fmt.Errorf("invalid --configmap %q; must be namespace/name", ref)
+	}
+
+	c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+
+	cm := &corev1.ConfigMap{}
+	err = c.Get(ctx, client.ObjectKey{Namespace: ns, Name: name}, cm)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	notFound := apierrors.IsNotFound(err)
+	if notFound {
+		cm.ObjectMeta = metav1.ObjectMeta{Namespace: ns, Name: name}
+	}
+
+	if cm.Annotations == nil {
+		cm.Annotations = map[string]string{}
+	}
+	cm.Annotations["sustainable-computing.io/report-start"] = r.Start.Format(time.RFC3339)
+	cm.Annotations["sustainable-computing.io/report-end"] = r.End.Format(time.RFC3339)
+	cm.Data = map[string]string{key: string(data)}
+
+	if notFound {
+		return c.Create(ctx, cm)
+	}
+	return c.Update(ctx, cm)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var tlsVersions = map[string]uint16{
+	"VersionTLS12": tls.VersionTLS12,
+	"VersionTLS13": tls.VersionTLS13,
+}
+
+// fipsCipherSuites are the TLS 1.2 cipher suites approved by FIPS 140-2;
+// cipher suites of TLS 1.3 are not configurable
+var fipsCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+}
+
+// tlsOptions returns the options applied to the TLS config of the webhook and
+// metrics servers; in FIPS mode, cipher suites that are not approved by FIPS
+// are refused and only approved suites are used by default
+func tlsOptions(minVersion string, cipherSuites []string, fips bool) ([]func(*tls.Config), error) {
+	version, ok := tlsVersions[minVersion]
+	if !ok {
+		return nil, fmt.Errorf("unsupported tls min version %q; must be VersionTLS12 or VersionTLS13", minVersion)
+	}
+
+	suites := []uint16{}
+	invalid := []string{}
+	for _, name := range cipherSuites {
+		id, ok := cipherSuiteID(name)
+		if !ok || (fips && !slices.Contains(fipsCipherSuites, id)) {
+			invalid = append(invalid, name)
+			continue
+		}
+		suites = append(suites, id)
+	}
+	if len(invalid) != 0 {
+		return nil, fmt.Errorf("unsupported tls cipher suites: %s", strings.Join(invalid, ", "))
+	}
+	if fips && len(suites) == 0 {
+		suites = fipsCipherSuites
+	}
+
+	return []func(*tls.Config){func(c *tls.Config) {
+		c.MinVersion = version
+		if len(suites) != 0 {
+			c.CipherSuites = suites
+		}
+	}}, nil
+}
+
+// cipherSuiteID returns the id of a secure cipher suite
+func cipherSuiteID(name string) (uint16, bool) {
+	for _, s := range tls.CipherSuites() {
+		if s.Name == name {
+			return s.ID, true
+		}
+	}
+	return 0, false
+}
+
+// cipherSuiteNames returns the names of the cipher suites applied by
+// tlsOptions so that they can be passed on to kube-rbac-proxy
+func cipherSuiteNames(cipherSuites []string, fips bool) []string {
+	if len(cipherSuites) != 0 || !fips {
+		return cipherSuites
+	}
+	names := make([]string, 0, len(fipsCipherSuites))
+	for _, id := range fipsCipherSuites {
+		names = append(names, tls.CipherSuiteName(id))
+	}
+	return names
+}
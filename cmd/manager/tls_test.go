@@ -0,0 +1,102 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTLSOptions(t *testing.T) {
+	tt := []struct {
+		scenario     string
+		minVersion   string
+		cipherSuites []string
+		fips         bool
+		version      uint16
+		suites       []uint16
+		names        []string
+		err          bool
+	}{
+		{
+			scenario:   "go defaults",
+			minVersion: "VersionTLS12",
+			version:    tls.VersionTLS12,
+		},
+		{
+			scenario:     "cipher suites",
+			minVersion:   "VersionTLS13",
+			cipherSuites: []string{"TLS_CHACHA20_POLY1305_SHA256", "TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256"},
+			version:      tls.VersionTLS13,
+			suites:       []uint16{tls.TLS_CHACHA20_POLY1305_SHA256, tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256},
+			names:        []string{"TLS_CHACHA20_POLY1305_SHA256", "TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256"},
+		},
+		{
+			scenario:   "fips defaults",
+			minVersion: "VersionTLS12",
+			fips:       true,
+			version:    tls.VersionTLS12,
+			suites:     fipsCipherSuites,
+			names: []string{
+				"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+				"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+				"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+				"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+			},
+		},
+		{
+			scenario:     "fips refuses unapproved cipher suites",
+			minVersion:   "VersionTLS12",
+			cipherSuites: []string{"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256"},
+			fips:         true,
+			err:          true,
+		},
+		{
+			scenario:     "insecure cipher suites",
+			minVersion:   "VersionTLS12",
+			cipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"},
+			err:          true,
+		},
+		{
+			scenario:   "tls 1.1",
+			minVersion: "VersionTLS11",
+			err:        true,
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			opts, err := tlsOptions(tc.minVersion, tc.cipherSuites, tc.fips)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			c := &tls.Config{}
+			for _, opt := range opts {
+				opt(c)
+			}
+			assert.Equal(t, tc.version, c.MinVersion)
+			assert.Equal(t, tc.suites, c.CipherSuites)
+			assert.Equal(t, tc.names, cipherSuiteNames(tc.cipherSuites, tc.fips))
+		})
+	}
+}
@@ -32,11 +32,12 @@ source "$PROJECT_ROOT/hack/utils.bash"
 #   com.redhat.openshift.versions: "v4.9-v4.12"
 
 OPERATOR_IMG=${OPERATOR_IMG:-quay.io/sustainable_computing_io/kepler-operator}
+DEPLOYMENT_NAMESPACE=${DEPLOYMENT_NAMESPACE:-kepler-operator}
 VERSION_REPLACED=${VERSION_REPLACED:-}
 VERSION=${VERSION:-"$(cat "$PROJECT_ROOT/VERSION")"}
 BUNDLE_GEN_FLAGS=${BUNDLE_GEN_FLAGS:-}
 
-declare -r OPERATOR_IMG VERSION BUNDLE_GEN_FLAGS
+declare -r OPERATOR_IMG DEPLOYMENT_NAMESPACE VERSION BUNDLE_GEN_FLAGS
 
 main() {
 	cd "$PROJECT_ROOT"
@@ -66,6 +67,7 @@ main() {
 		sed \
 			-e "s|<OPERATOR_IMG>|$OPERATOR_IMG|g" \
 			-e "s|<KEPLER_IMG>|$KEPLER_IMG|g" \
+			-e "s|<DEPLOYMENT_NAMESPACE>|$DEPLOYMENT_NAMESPACE|g" \
 			-e "s|<OLD_BUNDLE_VERSION>|$old_bundle_version|g" |
 		tee tmp/pre-bundle.yaml |
 		operator-sdk generate bundle "${gen_opts[@]}"
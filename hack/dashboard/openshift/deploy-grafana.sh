@@ -97,7 +97,7 @@ validate_cluster() {
 wait_for_kepler_to_be_available() {
 	header "waiting for kepler to be available"
 	wait_until 10 10 "kepler to be available" condition_check "True" oc get kepler kepler \
-		-o jsonpath="{.status.exporter.conditions[?(@.type=='Available')].status}" && {
+		-o jsonpath="{.status.conditions[?(@.type=='Available')].status}" && {
 		ok "kepler is available"
 		return 0
 	}
@@ -106,7 +106,7 @@ wait_for_kepler_to_be_available() {
 	line 50
 	oc get kepler kepler -o jsonpath="$(
 		cat <<-EOF
-			{range .status.exporter.conditions[?(@.status!="True")]}
+			{range .status.conditions[?(@.status!="True")]}
 				{" * "}{.type}{":"} {.status}
 				      {.reason}
 				      {.message}
@@ -0,0 +1,176 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// Condition types reported on the status of Kepler and KeplerInternal. The
+// types follow the conventions used by Kubernetes and OpenShift so that
+// generic tooling (e.g. `kubectl wait`, Argo CD health checks) can interpret
+// them without any Kepler specific knowledge.
+const (
+	// Available indicates that the kepler exporter is deployed and available
+	// on all the nodes it is scheduled to run on.
+	Available = "Available"
+
+	// Progressing indicates that a rollout of the kepler exporter is in progress.
+	Progressing = "Progressing"
+
+	// Degraded indicates that the kepler exporter or one of its components
+	// is not functioning as expected.
+	Degraded = "Degraded"
+
+	// Reconciled indicates that the last reconciliation of the resource
+	// succeeded.
+	Reconciled = "Reconciled"
+
+	// MetricsCollected indicates whether the metrics exported by kepler are
+	// collected by the monitoring stack of the cluster; only set on OpenShift.
+	MetricsCollected = "MetricsCollected"
+
+	// PodSecurityConfigured indicates whether the security policy of the
+	// platform permits the privileged kepler pods i.e. the
+	// SecurityContextConstraints on OpenShift and the Pod Security Admission
+	// labels of the namespace elsewhere.
+	PodSecurityConfigured = "PodSecurityConfigured"
+
+	// HighCardinality indicates that the number of metric series kepler is
+	// estimated to produce exceeds the threshold configured for the operator.
+	HighCardinality = "HighCardinality"
+
+	// FeatureDisabled indicates that some of the features configured in the
+	// spec are ignored since they are disabled by the feature gates of the
+	// operator; the condition is removed when there are none.
+	FeatureDisabled = "FeatureDisabled"
+)
+
+// Reasons set on the conditions of Kepler and KeplerInternal. The reasons are
+// part of the API and can be relied upon by other controllers; the messages
+// are meant for humans and may change.
+const (
+	// ReconcileComplete indicates the CR was successfully reconciled
+	ReconcileComplete = "ReconcileSuccess"
+
+	// ReconcileError indicates an error was encountered while reconciling the CR
+	ReconcileError = "ReconcileError"
+
+	// ReconcilePaused indicates that reconciliation of the CR has been paused
+	// using the PausedAnnotation
+	ReconcilePaused = "ReconcilePaused"
+
+	// InvalidKeplerResource indicates the CR name was invalid
+	InvalidKeplerResource = "InvalidKeplerResource"
+
+	// InvalidSpec indicates the spec of the CR is invalid and cannot be
+	// reconciled
+	InvalidSpec = "InvalidSpec"
+
+	// DaemonSetNotFound indicates the DaemonSet created for a kepler was not found
+	DaemonSetNotFound = "DaemonSetNotFound"
+
+	// DaemonSetError indicates an error occurred while fetching the DaemonSet
+	DaemonSetError = "DaemonSetError"
+
+	// DaemonSetNotAvailable indicates kepler pods are not available on some
+	// of the nodes they are scheduled on
+	DaemonSetNotAvailable = "DaemonSetNotAvailable"
+
+	// DaemonSetPartiallyAvailable indicates only some of the updated kepler
+	// pods are available
+	DaemonSetPartiallyAvailable = "DaemonSetPartiallyAvailable"
+
+	// DaemonSetPodsNotRunning indicates kepler pods are not running on any node
+	DaemonSetPodsNotRunning = "DaemonSetPodsNotRunning"
+
+	// DaemonSetRolloutInProgress indicates a rollout of the DaemonSet is in progress
+	DaemonSetRolloutInProgress = "DaemonSetRolloutInProgress"
+
+	// DaemonSetRolloutComplete indicates the rollout of the DaemonSet is complete
+	DaemonSetRolloutComplete = "DaemonSetRolloutComplete"
+
+	// DaemonSetReady indicates kepler pods are available on all nodes
+	DaemonSetReady = "DaemonSetReady"
+
+	// DaemonSetOutOfSync indicates the DaemonSet controller has not yet
+	// observed the latest generation of the DaemonSet
+	DaemonSetOutOfSync = "DaemonSetOutOfSync"
+
+	// SCCNotPermitted indicates kepler pods cannot be created because they
+	// are not permitted by the SecurityContextConstraints or Pod Security
+	// Admission of the namespace
+	SCCNotPermitted = "SCCNotPermitted"
+
+	// InvalidNodesFound indicates that kepler cannot run or cannot measure
+	// power on some of the nodes
+	InvalidNodesFound = "InvalidNodesFound"
+
+	// UserWorkloadMonitoringEnabled indicates that User Workload Monitoring
+	// is enabled, so the metrics of kepler are collected
+	UserWorkloadMonitoringEnabled = "UserWorkloadMonitoringEnabled"
+
+	// UserWorkloadMonitoringDisabled indicates that User Workload Monitoring
+	// is disabled, so the metrics of kepler are not collected
+	UserWorkloadMonitoringDisabled = "UserWorkloadMonitoringDisabled"
+
+	// UserWorkloadMonitoringUnknown indicates that the state of User Workload
+	// Monitoring could not be determined
+	UserWorkloadMonitoringUnknown = "UserWorkloadMonitoringUnknown"
+
+	// SCCConfigured indicates that the SecurityContextConstraints created for
+	// kepler permit its service account
+	SCCConfigured = "SCCConfigured"
+
+	// SCCNotFound indicates that the SecurityContextConstraints created for
+	// kepler were not found
+	SCCNotFound = "SCCNotFound"
+
+	// PodSecurityLabelsApplied indicates that the namespace of kepler enforces
+	// the privileged Pod Security Standard
+	PodSecurityLabelsApplied = "PodSecurityLabelsApplied"
+
+	// PodSecurityLabelsOverridden indicates that the namespace of kepler
+	// enforces a Pod Security Standard other than privileged e.g. because the
+	// labels were changed by another controller
+	PodSecurityLabelsOverridden = "PodSecurityLabelsOverridden"
+
+	// PodSecurityUnknown indicates that the security policy applied to kepler
+	// could not be determined
+	PodSecurityUnknown = "PodSecurityUnknown"
+
+	// ModelVerificationFailed indicates that the signature of a model archive
+	// could not be verified, so the models are not deployed
+	ModelVerificationFailed = "ModelVerificationFailed"
+
+	// PermissionDenied indicates that the operator is not permitted to manage
+	// some of the resources e.g. because its RBAC or an admission policy
+	// forbids it
+	PermissionDenied = "PermissionDenied"
+
+	// APINotAvailable indicates that the api of some of the resources is not
+	// served by the cluster e.g. because the CRD of an add-on is not installed
+	APINotAvailable = "APINotAvailable"
+
+	// SeriesAboveThreshold indicates that the number of metric series kepler
+	// is estimated to produce exceeds the configured threshold
+	SeriesAboveThreshold = "SeriesAboveThreshold"
+
+	// SeriesWithinThreshold indicates that the number of metric series kepler
+	// is estimated to produce is within the configured threshold
+	SeriesWithinThreshold = "SeriesWithinThreshold"
+
+	// FeatureGateDisabled indicates that the feature gate of a feature
+	// configured in the spec is disabled, so the feature is not deployed
+	FeatureGateDisabled = "FeatureGateDisabled"
+)
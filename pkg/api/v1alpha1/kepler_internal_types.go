@@ -41,6 +41,83 @@ type InternalExporterSpec struct {
 	Deployment InternalExporterDeploymentSpec `json:"deployment"`
 
 	Redfish *RedfishSpec `json:"redfish,omitempty"`
+
+	// +kubebuilder:default=Privileged
+	// +optional
+	CollectionMode CollectionMode `json:"collectionMode,omitempty"`
+
+	// +optional
+	IdlePower IdlePowerAttribution `json:"idlePower,omitempty"`
+
+	// +optional
+	MetricLabels *MetricLabelsSpec `json:"metricLabels,omitempty"`
+
+	// +optional
+	ServiceMonitor *ServiceMonitorSpec `json:"serviceMonitor,omitempty"`
+
+	// +optional
+	PodMonitor *PodMonitorSpec `json:"podMonitor,omitempty"`
+
+	// +optional
+	ScrapeAnnotations *ScrapeAnnotationsSpec `json:"scrapeAnnotations,omitempty"`
+
+	// +optional
+	Alerts *AlertsSpec `json:"alerts,omitempty"`
+
+	// +optional
+	Aggregations *AggregationsSpec `json:"aggregations,omitempty"`
+
+	// +optional
+	ElectricityPrice *ElectricityPriceSpec `json:"electricityPrice,omitempty"`
+
+	// +optional
+	IdleWaste *IdleWasteSpec `json:"idleWaste,omitempty"`
+
+	// +optional
+	PUE *PUESpec `json:"pue,omitempty"`
+
+	// +optional
+	OpenCost *OpenCostSpec `json:"openCost,omitempty"`
+
+	// +optional
+	RBACProxy *InternalRBACProxySpec `json:"rbacProxy,omitempty"`
+
+	// +optional
+	VerticalPodAutoscaler *VerticalPodAutoscalerSpec `json:"verticalPodAutoscaler,omitempty"`
+
+	// +optional
+	NodeFeatureDiscovery *NodeFeatureDiscoverySpec `json:"nodeFeatureDiscovery,omitempty"`
+
+	// +optional
+	KernelPrerequisites *KernelPrerequisitesSpec `json:"kernelPrerequisites,omitempty"`
+
+	// +optional
+	VirtualMachines *VirtualMachinesSpec `json:"virtualMachines,omitempty"`
+}
+
+type InternalRBACProxySpec struct {
+	RBACProxySpec `json:",inline"`
+
+	// Image of kube-rbac-proxy
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// TLSMinVersion is the minimum TLS version served by kube-rbac-proxy;
+	// defaults to the --tls-min-version of the operator
+	// +kubebuilder:validation:Enum=VersionTLS12;VersionTLS13
+	// +optional
+	TLSMinVersion string `json:"tlsMinVersion,omitempty"`
+
+	// TLSCipherSuites are the TLS 1.2 cipher suites served by kube-rbac-proxy;
+	// defaults to the --tls-cipher-suites of the operator
+	// +optional
+	TLSCipherSuites []string `json:"tlsCipherSuites,omitempty"`
+}
+
+// IsEnabled returns true if the metrics of the exporter are served by
+// kube-rbac-proxy
+func (s *InternalRBACProxySpec) IsEnabled() bool {
+	return s != nil && s.Enabled
 }
 
 type DashboardSpec struct {
@@ -48,10 +125,35 @@ type DashboardSpec struct {
 	Enabled bool `json:"enabled,omitempty"`
 }
 
+type InternalConsolePluginSpec struct {
+	// +kubebuilder:default=false
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Image of the console plugin to be deployed
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Replicas of the console plugin
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// PodDisruptionBudget is created when more than one replica is deployed
+	// +optional
+	PodDisruptionBudget *PodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
+
+	// AutomountServiceAccountToken of the console plugin pods
+	// +optional
+	AutomountServiceAccountToken *bool `json:"automountServiceAccountToken,omitempty"`
+}
+
 type OpenShiftSpec struct {
 	// +kubebuilder:default=true
 	Enabled   bool          `json:"enabled"`
 	Dashboard DashboardSpec `json:"dashboard,omitempty"`
+
+	// +optional
+	ConsolePlugin InternalConsolePluginSpec `json:"consolePlugin,omitempty"`
 }
 
 // KeplerInternalSpec defines the desired state of KeplerInternal
@@ -60,6 +162,121 @@ type KeplerInternalSpec struct {
 	Estimator   *InternalEstimatorSpec   `json:"estimator,omitempty"`
 	ModelServer *InternalModelServerSpec `json:"modelServer,omitempty"`
 	OpenShift   OpenShiftSpec            `json:"openshift,omitempty"`
+	Dashboards  InternalDashboardsSpec   `json:"dashboards,omitempty"`
+
+	// +optional
+	OpenTelemetry *InternalOpenTelemetrySpec `json:"openTelemetry,omitempty"`
+
+	// +optional
+	CarbonIntensity *InternalCarbonIntensitySpec `json:"carbonIntensity,omitempty"`
+
+	// +optional
+	EnergyReport *InternalEnergyReportSpec `json:"energyReport,omitempty"`
+
+	// +optional
+	Telegraf *InternalTelegrafSpec `json:"telegraf,omitempty"`
+
+	// +optional
+	NetworkPolicy *NetworkPolicySpec `json:"networkPolicy,omitempty"`
+
+	// +optional
+	MetricsViewer *MetricsViewerSpec `json:"metricsViewer,omitempty"`
+
+	// ModelVerification requires the model archives pulled by the model server
+	// and the estimator to be signed with cosign; models that fail the
+	// verification are not deployed and pods refuse archives whose digest
+	// differs from the verified one
+	// +optional
+	ModelVerification *ModelVerificationSpec `json:"modelVerification,omitempty"`
+}
+
+// ModelVerificationSpec configures the verification of the cosign signatures
+// published next to the model archives as <url>.sig or, for keyless
+// signatures, as the bundle written by cosign sign-blob --bundle at
+// <url>.bundle
+type ModelVerificationSpec struct {
+	// SecretRef is the name of the secret in the namespace of kepler holding
+	// the public key under cosign.pub or, for keyless signatures, the
+	// certificate chain of the Fulcio instance under fulcio.crt and the public
+	// key of the Rekor transparency log under rekor.pub
+	// +kubebuilder:validation:MinLength=1
+	SecretRef string `json:"secretRef"`
+
+	// Keyless trusts the signatures made by this identity instead of a key
+	// +optional
+	Keyless *KeylessIdentity `json:"keyless,omitempty"`
+}
+
+// KeylessIdentity is the identity of a keyless signer
+type KeylessIdentity struct {
+	// Issuer is the OIDC issuer that authenticated the signer e.g.
+	// https://token.actions.githubusercontent.com
+	// +kubebuilder:validation:MinLength=1
+	Issuer string `json:"issuer"`
+
+	// Subject is the email or URI the signing certificate is issued to
+	// +kubebuilder:validation:MinLength=1
+	Subject string `json:"subject"`
+}
+
+type InternalOpenTelemetrySpec struct {
+	OpenTelemetrySpec `json:",inline"`
+
+	// Image of the OpenTelemetry Collector to be deployed
+	// +optional
+	Image string `json:"image,omitempty"`
+}
+
+// IsEnabled returns true if the collector has to be deployed
+func (s *InternalOpenTelemetrySpec) IsEnabled() bool {
+	return s != nil && s.Enabled
+}
+
+type InternalCarbonIntensitySpec struct {
+	CarbonIntensitySpec `json:",inline"`
+
+	// Image of the exporter that queries the provider of the intensity
+	// +optional
+	Image string `json:"image,omitempty"`
+}
+
+// IsEnabled returns true if the carbon intensity recording rules have to be
+// created
+func (s *InternalCarbonIntensitySpec) IsEnabled() bool {
+	return s != nil && s.Enabled
+}
+
+type InternalEnergyReportSpec struct {
+	EnergyReportSpec `json:",inline"`
+
+	// Image of the CronJob writing the reports, i.e. the image of the operator
+	// +optional
+	Image string `json:"image,omitempty"`
+}
+
+// IsEnabled returns true if the energy reports have to be written
+func (s *InternalEnergyReportSpec) IsEnabled() bool {
+	return s != nil && s.Enabled
+}
+
+type InternalTelegrafSpec struct {
+	TelegrafSpec `json:",inline"`
+
+	// Image of Telegraf
+	// +optional
+	Image string `json:"image,omitempty"`
+}
+
+// IsEnabled returns true if Telegraf has to be deployed
+func (s *InternalTelegrafSpec) IsEnabled() bool {
+	return s != nil && s.Enabled
+}
+
+// InternalDashboardsSpec configures the dashboards created for kepler other
+// than the OpenShift console dashboards which are configured in OpenShiftSpec
+type InternalDashboardsSpec struct {
+	// +optional
+	Grafana *GrafanaDashboardsSpec `json:"grafana,omitempty"`
 }
 
 // Kepler Model Server Spec
@@ -94,9 +311,34 @@ type InternalModelServerSpec struct {
 	ErrorKey string `json:"errKey,omitempty"`
 
 	Storage ModelServerStorageSpec `json:"storage,omitempty"`
+
+	// Replicas of the model server; replicas share the persistent volume
+	// claim, so it must support ReadWriteMany when there are more than one
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// PodDisruptionBudget is created when more than one replica is deployed
+	// +optional
+	PodDisruptionBudget *PodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
+
+	// SecurityProfiles of the model server pods
+	// +optional
+	SecurityProfiles *SecurityProfilesSpec `json:"securityProfiles,omitempty"`
+
+	// AutomountServiceAccountToken of the model server pods, which do not use
+	// the Kubernetes API
+	// +optional
+	AutomountServiceAccountToken *bool `json:"automountServiceAccountToken,omitempty"`
+
+	// ReadOnlyRootFilesystem of the model server containers
+	// +optional
+	ReadOnlyRootFilesystem *ReadOnlyRootFilesystemSpec `json:"readOnlyRootFilesystem,omitempty"`
 }
 
 type ModelServerStorageSpec struct {
+	// PersistentVolumeClaim holding the models of the model server; the claim
+	// is kept when the model server is disabled and is deleted along with kepler
 	PersistentVolumeClaim *corev1.PersistentVolumeClaimSpec `json:"persistentVolumeClaim,omitempty"`
 }
 
@@ -142,9 +384,11 @@ type ModelSelectorSpec struct {
 // +kubebuilder:printcolumn:name="Current",type=integer,JSONPath=`.status.exporter.currentNumberScheduled`
 // +kubebuilder:printcolumn:name="Up-to-date",type=integer,JSONPath=`.status.exporter.updatedNumberScheduled`
 // +kubebuilder:printcolumn:name="Ready",type=integer,JSONPath=`.status.exporter.numberReady`
-// +kubebuilder:printcolumn:name="Available",type=integer,JSONPath=`.status.exporter.numberAvailable`
+// +kubebuilder:printcolumn:name="Available",type=string,JSONPath=`.status.conditions[?(@.type=="Available")].status`
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="Image",type=string,JSONPath=`.spec.exporter.deployment.image`
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.status.keplerVersion`,priority=10
+// +kubebuilder:printcolumn:name="Degraded",type=string,JSONPath=`.status.conditions[?(@.type=="Degraded")].status`,priority=10
 // +kubebuilder:printcolumn:name="Node-Selector",type=string,JSONPath=`.spec.exporter.deployment.nodeSelector`,priority=10
 // +kubebuilder:printcolumn:name="Tolerations",type=string,JSONPath=`.spec.exporter.deployment.tolerations`,priority=10
 // +kubebuilder:printcolumn:name="Estimator",type=string,JSONPath=`.status.estimator.status`
@@ -169,17 +413,108 @@ const (
 
 // KeplerInternalStatus represents status of KeplerInternal
 type KeplerInternalStatus struct {
+	// ObservedGeneration is the generation of kepler-internal last reconciled
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	Exporter    ExporterStatus    `json:"exporter,omitempty"`
 	Estimator   EstimatorStatus   `json:"estimator,omitempty"`
 	ModelServer ModelServerStatus `json:"modelServer,omitempty"`
+
+	// KeplerVersion is the version of kepler parsed from the deployed image
+	// +optional
+	KeplerVersion string `json:"keplerVersion,omitempty"`
+
+	// InvalidNodes lists the nodes on which kepler cannot run or measure power
+	// +optional
+	InvalidNodes []InvalidNode `json:"invalidNodes,omitempty"`
+
+	// Endpoints lists where the metrics exported by kepler can be scraped from
+	// +optional
+	Endpoints []Endpoint `json:"endpoints,omitempty"`
+
+	// PowerSources lists the expected source of power readings per group of nodes
+	// +optional
+	PowerSources []PowerSource `json:"powerSources,omitempty"`
+
+	// KernelPrerequisites reports per node whether the kernel prerequisites
+	// of kepler are met when they are managed by the operator
+	// +optional
+	KernelPrerequisites []NodeKernelStatus `json:"kernelPrerequisites,omitempty"`
+
+	// Images lists the digests the operand images are pinned to when the
+	// operator resolves image tags to digests
+	// +optional
+	Images []ImageStatus `json:"images,omitempty"`
+
+	// VerifiedModels lists the digests of the model archives whose signatures
+	// were verified when model verification is enabled
+	// +optional
+	VerifiedModels []VerifiedModel `json:"verifiedModels,omitempty"`
+
+	// conditions represent the latest available observations of kepler-internal
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// VerifiedModel is a model archive whose signature was verified
+type VerifiedModel struct {
+	// URL the model archive is pulled from
+	URL string `json:"url"`
+
+	// Digest of the model archive that was verified
+	Digest string `json:"digest"`
 }
 
 type EstimatorStatus struct {
 	Status DeploymentStatus `json:"status,omitempty"`
+
+	// The number of kepler pods in which the estimator sidecar is ready
+	// +optional
+	NumberReady int32 `json:"numberReady,omitempty"`
+
+	// Models that the estimator is configured to use
+	// +optional
+	Models []ModelStatus `json:"models,omitempty"`
+
+	// LastError is the most recent error reported by an estimator sidecar
+	// +optional
+	LastError string `json:"lastError,omitempty"`
 }
 
 type ModelServerStatus struct {
 	Status DeploymentStatus `json:"status,omitempty"`
+
+	// Image of the model server that is deployed
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// The number of model server pods that are ready
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// URL of the model server that the exporter is configured with
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// Models that the exporter is configured to request from the model server
+	// +optional
+	Models []ModelStatus `json:"models,omitempty"`
+}
+
+type ModelStatus struct {
+	// Type of power the model estimates e.g. NODE_TOTAL
+	Type string `json:"type"`
+
+	// Name of the model selected
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// InitURL is the url the model is initially loaded from
+	// +optional
+	InitURL string `json:"initUrl,omitempty"`
 }
 
 func (ki KeplerInternal) Namespace() string {
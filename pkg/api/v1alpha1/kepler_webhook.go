@@ -62,17 +62,25 @@ func (r *Kepler) ValidateCreate() (admission.Warnings, error) {
 	if r.Name != KeplerInstanceName {
 		return nil, apierrors.NewBadRequest(fmt.Sprintf("invalid name %q; name must be %q", r.Name, KeplerInstanceName))
 	}
-
-	// TODO(user): fill in your validation logic upon object creation.
-	return nil, nil
+	return nil, r.validateSpec()
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *Kepler) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
 	keplerlog.Info("validate update", "name", r.Name)
+	return nil, r.validateSpec()
+}
 
-	// TODO(user): fill in your validation logic upon object update.
-	return nil, nil
+// validateSpec returns a bad request error for settings that cannot be
+// validated by the schema of the CRD
+func (r *Kepler) validateSpec() error {
+	if err := r.Spec.Exporter.MetricLabels.Validate(); err != nil {
+		return apierrors.NewBadRequest(fmt.Sprintf("invalid spec.exporter.metricLabels: %s", err))
+	}
+	if err := r.Spec.Telegraf.Validate(); err != nil {
+		return apierrors.NewBadRequest(fmt.Sprintf("invalid spec.telegraf: %s", err))
+	}
+	return nil
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -22,22 +22,144 @@ limitations under the License.
 package v1alpha1
 
 import (
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Condition) DeepCopyInto(out *Condition) {
+func (in *AggregationsSpec) DeepCopyInto(out *AggregationsSpec) {
 	*out = *in
-	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
-func (in *Condition) DeepCopy() *Condition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AggregationsSpec.
+func (in *AggregationsSpec) DeepCopy() *AggregationsSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Condition)
+	out := new(AggregationsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertsSpec) DeepCopyInto(out *AlertsSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertsSpec.
+func (in *AlertsSpec) DeepCopy() *AlertsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CarbonIntensityProviderSpec) DeepCopyInto(out *CarbonIntensityProviderSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CarbonIntensityProviderSpec.
+func (in *CarbonIntensityProviderSpec) DeepCopy() *CarbonIntensityProviderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CarbonIntensityProviderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CarbonIntensitySpec) DeepCopyInto(out *CarbonIntensitySpec) {
+	*out = *in
+	if in.Intensity != nil {
+		in, out := &in.Intensity, &out.Intensity
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Regions != nil {
+		in, out := &in.Regions, &out.Regions
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Provider != nil {
+		in, out := &in.Provider, &out.Provider
+		*out = new(CarbonIntensityProviderSpec)
+		**out = **in
+	}
+	if in.SCI != nil {
+		in, out := &in.SCI, &out.SCI
+		*out = new(SCISpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Embodied != nil {
+		in, out := &in.Embodied, &out.Embodied
+		*out = new(EmbodiedCarbonSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CarbonIntensitySpec.
+func (in *CarbonIntensitySpec) DeepCopy() *CarbonIntensitySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CarbonIntensitySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerSpec) DeepCopyInto(out *CertManagerSpec) {
+	*out = *in
+	out.IssuerRef = in.IssuerRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerSpec.
+func (in *CertManagerSpec) DeepCopy() *CertManagerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsolePluginSpec) DeepCopyInto(out *ConsolePluginSpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(PodDisruptionBudgetSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AutomountServiceAccountToken != nil {
+		in, out := &in.AutomountServiceAccountToken, &out.AutomountServiceAccountToken
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsolePluginSpec.
+func (in *ConsolePluginSpec) DeepCopy() *ConsolePluginSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsolePluginSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -57,6 +179,180 @@ func (in *DashboardSpec) DeepCopy() *DashboardSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardsSpec) DeepCopyInto(out *DashboardsSpec) {
+	*out = *in
+	if in.Grafana != nil {
+		in, out := &in.Grafana, &out.Grafana
+		*out = new(GrafanaDashboardsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OpenShift != nil {
+		in, out := &in.OpenShift, &out.OpenShift
+		*out = new(OpenShiftDashboardsSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardsSpec.
+func (in *DashboardsSpec) DeepCopy() *DashboardsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElectricityPriceSpec) DeepCopyInto(out *ElectricityPriceSpec) {
+	*out = *in
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make(map[string]PricePerKWh, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tariffs != nil {
+		in, out := &in.Tariffs, &out.Tariffs
+		*out = make([]ElectricityTariffSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElectricityPriceSpec.
+func (in *ElectricityPriceSpec) DeepCopy() *ElectricityPriceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElectricityPriceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElectricityTariffSpec) DeepCopyInto(out *ElectricityTariffSpec) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]TariffDay, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElectricityTariffSpec.
+func (in *ElectricityTariffSpec) DeepCopy() *ElectricityTariffSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElectricityTariffSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmbodiedCarbonSpec) DeepCopyInto(out *EmbodiedCarbonSpec) {
+	*out = *in
+	if in.KgCO2e != nil {
+		in, out := &in.KgCO2e, &out.KgCO2e
+		*out = new(int32)
+		**out = **in
+	}
+	if in.InstanceTypes != nil {
+		in, out := &in.InstanceTypes, &out.InstanceTypes
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmbodiedCarbonSpec.
+func (in *EmbodiedCarbonSpec) DeepCopy() *EmbodiedCarbonSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EmbodiedCarbonSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Endpoint) DeepCopyInto(out *Endpoint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Endpoint.
+func (in *Endpoint) DeepCopy() *Endpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(Endpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnergyReportPrometheusSpec) DeepCopyInto(out *EnergyReportPrometheusSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnergyReportPrometheusSpec.
+func (in *EnergyReportPrometheusSpec) DeepCopy() *EnergyReportPrometheusSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EnergyReportPrometheusSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnergyReportS3Spec) DeepCopyInto(out *EnergyReportS3Spec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnergyReportS3Spec.
+func (in *EnergyReportS3Spec) DeepCopy() *EnergyReportS3Spec {
+	if in == nil {
+		return nil
+	}
+	out := new(EnergyReportS3Spec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnergyReportSpec) DeepCopyInto(out *EnergyReportSpec) {
+	*out = *in
+	out.Prometheus = in.Prometheus
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(EnergyReportS3Spec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnergyReportSpec.
+func (in *EnergyReportSpec) DeepCopy() *EnergyReportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EnergyReportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EstimatorConfig) DeepCopyInto(out *EstimatorConfig) {
 	*out = *in
@@ -67,471 +363,1672 @@ func (in *EstimatorConfig) DeepCopyInto(out *EstimatorConfig) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EstimatorConfig.
-func (in *EstimatorConfig) DeepCopy() *EstimatorConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EstimatorConfig.
+func (in *EstimatorConfig) DeepCopy() *EstimatorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(EstimatorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EstimatorGroup) DeepCopyInto(out *EstimatorGroup) {
+	*out = *in
+	if in.Total != nil {
+		in, out := &in.Total, &out.Total
+		*out = new(EstimatorConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = new(EstimatorConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EstimatorGroup.
+func (in *EstimatorGroup) DeepCopy() *EstimatorGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(EstimatorGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EstimatorStatus) DeepCopyInto(out *EstimatorStatus) {
+	*out = *in
+	if in.Models != nil {
+		in, out := &in.Models, &out.Models
+		*out = make([]ModelStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EstimatorStatus.
+func (in *EstimatorStatus) DeepCopy() *EstimatorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EstimatorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExporterDeploymentSpec) DeepCopyInto(out *ExporterDeploymentSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SecurityProfiles != nil {
+		in, out := &in.SecurityProfiles, &out.SecurityProfiles
+		*out = new(SecurityProfilesSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadOnlyRootFilesystem != nil {
+		in, out := &in.ReadOnlyRootFilesystem, &out.ReadOnlyRootFilesystem
+		*out = new(ReadOnlyRootFilesystemSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExporterDeploymentSpec.
+func (in *ExporterDeploymentSpec) DeepCopy() *ExporterDeploymentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExporterDeploymentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExporterSpec) DeepCopyInto(out *ExporterSpec) {
+	*out = *in
+	in.Deployment.DeepCopyInto(&out.Deployment)
+	if in.Redfish != nil {
+		in, out := &in.Redfish, &out.Redfish
+		*out = new(RedfishSpec)
+		**out = **in
+	}
+	if in.MetricLabels != nil {
+		in, out := &in.MetricLabels, &out.MetricLabels
+		*out = new(MetricLabelsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceMonitor != nil {
+		in, out := &in.ServiceMonitor, &out.ServiceMonitor
+		*out = new(ServiceMonitorSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodMonitor != nil {
+		in, out := &in.PodMonitor, &out.PodMonitor
+		*out = new(PodMonitorSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScrapeAnnotations != nil {
+		in, out := &in.ScrapeAnnotations, &out.ScrapeAnnotations
+		*out = new(ScrapeAnnotationsSpec)
+		**out = **in
+	}
+	if in.Alerts != nil {
+		in, out := &in.Alerts, &out.Alerts
+		*out = new(AlertsSpec)
+		**out = **in
+	}
+	if in.Aggregations != nil {
+		in, out := &in.Aggregations, &out.Aggregations
+		*out = new(AggregationsSpec)
+		**out = **in
+	}
+	if in.ElectricityPrice != nil {
+		in, out := &in.ElectricityPrice, &out.ElectricityPrice
+		*out = new(ElectricityPriceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IdleWaste != nil {
+		in, out := &in.IdleWaste, &out.IdleWaste
+		*out = new(IdleWasteSpec)
+		**out = **in
+	}
+	if in.PUE != nil {
+		in, out := &in.PUE, &out.PUE
+		*out = new(PUESpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OpenCost != nil {
+		in, out := &in.OpenCost, &out.OpenCost
+		*out = new(OpenCostSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RBACProxy != nil {
+		in, out := &in.RBACProxy, &out.RBACProxy
+		*out = new(RBACProxySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VerticalPodAutoscaler != nil {
+		in, out := &in.VerticalPodAutoscaler, &out.VerticalPodAutoscaler
+		*out = new(VerticalPodAutoscalerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeFeatureDiscovery != nil {
+		in, out := &in.NodeFeatureDiscovery, &out.NodeFeatureDiscovery
+		*out = new(NodeFeatureDiscoverySpec)
+		**out = **in
+	}
+	if in.KernelPrerequisites != nil {
+		in, out := &in.KernelPrerequisites, &out.KernelPrerequisites
+		*out = new(KernelPrerequisitesSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VirtualMachines != nil {
+		in, out := &in.VirtualMachines, &out.VirtualMachines
+		*out = new(VirtualMachinesSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExporterSpec.
+func (in *ExporterSpec) DeepCopy() *ExporterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExporterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExporterStatus) DeepCopyInto(out *ExporterStatus) {
+	*out = *in
+	if in.ConfigRolledOutTime != nil {
+		in, out := &in.ConfigRolledOutTime, &out.ConfigRolledOutTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExporterStatus.
+func (in *ExporterStatus) DeepCopy() *ExporterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExporterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDashboardsSpec) DeepCopyInto(out *GrafanaDashboardsSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Datasource != nil {
+		in, out := &in.Datasource, &out.Datasource
+		*out = new(GrafanaDatasourceSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDashboardsSpec.
+func (in *GrafanaDashboardsSpec) DeepCopy() *GrafanaDashboardsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDashboardsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDatasourceSpec) DeepCopyInto(out *GrafanaDatasourceSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDatasourceSpec.
+func (in *GrafanaDatasourceSpec) DeepCopy() *GrafanaDatasourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDatasourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdleWasteSpec) DeepCopyInto(out *IdleWasteSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdleWasteSpec.
+func (in *IdleWasteSpec) DeepCopy() *IdleWasteSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IdleWasteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageStatus) DeepCopyInto(out *ImageStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageStatus.
+func (in *ImageStatus) DeepCopy() *ImageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalCarbonIntensitySpec) DeepCopyInto(out *InternalCarbonIntensitySpec) {
+	*out = *in
+	in.CarbonIntensitySpec.DeepCopyInto(&out.CarbonIntensitySpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalCarbonIntensitySpec.
+func (in *InternalCarbonIntensitySpec) DeepCopy() *InternalCarbonIntensitySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalCarbonIntensitySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalConsolePluginSpec) DeepCopyInto(out *InternalConsolePluginSpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(PodDisruptionBudgetSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AutomountServiceAccountToken != nil {
+		in, out := &in.AutomountServiceAccountToken, &out.AutomountServiceAccountToken
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalConsolePluginSpec.
+func (in *InternalConsolePluginSpec) DeepCopy() *InternalConsolePluginSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalConsolePluginSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalDashboardsSpec) DeepCopyInto(out *InternalDashboardsSpec) {
+	*out = *in
+	if in.Grafana != nil {
+		in, out := &in.Grafana, &out.Grafana
+		*out = new(GrafanaDashboardsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalDashboardsSpec.
+func (in *InternalDashboardsSpec) DeepCopy() *InternalDashboardsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalDashboardsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalEnergyReportSpec) DeepCopyInto(out *InternalEnergyReportSpec) {
+	*out = *in
+	in.EnergyReportSpec.DeepCopyInto(&out.EnergyReportSpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalEnergyReportSpec.
+func (in *InternalEnergyReportSpec) DeepCopy() *InternalEnergyReportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalEnergyReportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalEstimatorSpec) DeepCopyInto(out *InternalEstimatorSpec) {
+	*out = *in
+	in.Node.DeepCopyInto(&out.Node)
+	in.Container.DeepCopyInto(&out.Container)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalEstimatorSpec.
+func (in *InternalEstimatorSpec) DeepCopy() *InternalEstimatorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalEstimatorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalExporterDeploymentSpec) DeepCopyInto(out *InternalExporterDeploymentSpec) {
+	*out = *in
+	in.ExporterDeploymentSpec.DeepCopyInto(&out.ExporterDeploymentSpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalExporterDeploymentSpec.
+func (in *InternalExporterDeploymentSpec) DeepCopy() *InternalExporterDeploymentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalExporterDeploymentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalExporterSpec) DeepCopyInto(out *InternalExporterSpec) {
+	*out = *in
+	in.Deployment.DeepCopyInto(&out.Deployment)
+	if in.Redfish != nil {
+		in, out := &in.Redfish, &out.Redfish
+		*out = new(RedfishSpec)
+		**out = **in
+	}
+	if in.MetricLabels != nil {
+		in, out := &in.MetricLabels, &out.MetricLabels
+		*out = new(MetricLabelsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceMonitor != nil {
+		in, out := &in.ServiceMonitor, &out.ServiceMonitor
+		*out = new(ServiceMonitorSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodMonitor != nil {
+		in, out := &in.PodMonitor, &out.PodMonitor
+		*out = new(PodMonitorSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScrapeAnnotations != nil {
+		in, out := &in.ScrapeAnnotations, &out.ScrapeAnnotations
+		*out = new(ScrapeAnnotationsSpec)
+		**out = **in
+	}
+	if in.Alerts != nil {
+		in, out := &in.Alerts, &out.Alerts
+		*out = new(AlertsSpec)
+		**out = **in
+	}
+	if in.Aggregations != nil {
+		in, out := &in.Aggregations, &out.Aggregations
+		*out = new(AggregationsSpec)
+		**out = **in
+	}
+	if in.ElectricityPrice != nil {
+		in, out := &in.ElectricityPrice, &out.ElectricityPrice
+		*out = new(ElectricityPriceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IdleWaste != nil {
+		in, out := &in.IdleWaste, &out.IdleWaste
+		*out = new(IdleWasteSpec)
+		**out = **in
+	}
+	if in.PUE != nil {
+		in, out := &in.PUE, &out.PUE
+		*out = new(PUESpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OpenCost != nil {
+		in, out := &in.OpenCost, &out.OpenCost
+		*out = new(OpenCostSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RBACProxy != nil {
+		in, out := &in.RBACProxy, &out.RBACProxy
+		*out = new(InternalRBACProxySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VerticalPodAutoscaler != nil {
+		in, out := &in.VerticalPodAutoscaler, &out.VerticalPodAutoscaler
+		*out = new(VerticalPodAutoscalerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeFeatureDiscovery != nil {
+		in, out := &in.NodeFeatureDiscovery, &out.NodeFeatureDiscovery
+		*out = new(NodeFeatureDiscoverySpec)
+		**out = **in
+	}
+	if in.KernelPrerequisites != nil {
+		in, out := &in.KernelPrerequisites, &out.KernelPrerequisites
+		*out = new(KernelPrerequisitesSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VirtualMachines != nil {
+		in, out := &in.VirtualMachines, &out.VirtualMachines
+		*out = new(VirtualMachinesSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalExporterSpec.
+func (in *InternalExporterSpec) DeepCopy() *InternalExporterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalExporterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalModelServerSpec) DeepCopyInto(out *InternalModelServerSpec) {
+	*out = *in
+	in.Storage.DeepCopyInto(&out.Storage)
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(PodDisruptionBudgetSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityProfiles != nil {
+		in, out := &in.SecurityProfiles, &out.SecurityProfiles
+		*out = new(SecurityProfilesSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AutomountServiceAccountToken != nil {
+		in, out := &in.AutomountServiceAccountToken, &out.AutomountServiceAccountToken
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ReadOnlyRootFilesystem != nil {
+		in, out := &in.ReadOnlyRootFilesystem, &out.ReadOnlyRootFilesystem
+		*out = new(ReadOnlyRootFilesystemSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalModelServerSpec.
+func (in *InternalModelServerSpec) DeepCopy() *InternalModelServerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalModelServerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalOpenTelemetrySpec) DeepCopyInto(out *InternalOpenTelemetrySpec) {
+	*out = *in
+	in.OpenTelemetrySpec.DeepCopyInto(&out.OpenTelemetrySpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalOpenTelemetrySpec.
+func (in *InternalOpenTelemetrySpec) DeepCopy() *InternalOpenTelemetrySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalOpenTelemetrySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalRBACProxySpec) DeepCopyInto(out *InternalRBACProxySpec) {
+	*out = *in
+	in.RBACProxySpec.DeepCopyInto(&out.RBACProxySpec)
+	if in.TLSCipherSuites != nil {
+		in, out := &in.TLSCipherSuites, &out.TLSCipherSuites
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalRBACProxySpec.
+func (in *InternalRBACProxySpec) DeepCopy() *InternalRBACProxySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalRBACProxySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalTelegrafSpec) DeepCopyInto(out *InternalTelegrafSpec) {
+	*out = *in
+	out.TelegrafSpec = in.TelegrafSpec
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalTelegrafSpec.
+func (in *InternalTelegrafSpec) DeepCopy() *InternalTelegrafSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalTelegrafSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InvalidNode) DeepCopyInto(out *InvalidNode) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InvalidNode.
+func (in *InvalidNode) DeepCopy() *InvalidNode {
+	if in == nil {
+		return nil
+	}
+	out := new(InvalidNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IssuerReference) DeepCopyInto(out *IssuerReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IssuerReference.
+func (in *IssuerReference) DeepCopy() *IssuerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(IssuerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Kepler) DeepCopyInto(out *Kepler) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Kepler.
+func (in *Kepler) DeepCopy() *Kepler {
+	if in == nil {
+		return nil
+	}
+	out := new(Kepler)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Kepler) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeplerInternal) DeepCopyInto(out *KeplerInternal) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeplerInternal.
+func (in *KeplerInternal) DeepCopy() *KeplerInternal {
+	if in == nil {
+		return nil
+	}
+	out := new(KeplerInternal)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeplerInternal) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeplerInternalList) DeepCopyInto(out *KeplerInternalList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KeplerInternal, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeplerInternalList.
+func (in *KeplerInternalList) DeepCopy() *KeplerInternalList {
+	if in == nil {
+		return nil
+	}
+	out := new(KeplerInternalList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeplerInternalList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeplerInternalSpec) DeepCopyInto(out *KeplerInternalSpec) {
+	*out = *in
+	in.Exporter.DeepCopyInto(&out.Exporter)
+	if in.Estimator != nil {
+		in, out := &in.Estimator, &out.Estimator
+		*out = new(InternalEstimatorSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ModelServer != nil {
+		in, out := &in.ModelServer, &out.ModelServer
+		*out = new(InternalModelServerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	in.OpenShift.DeepCopyInto(&out.OpenShift)
+	in.Dashboards.DeepCopyInto(&out.Dashboards)
+	if in.OpenTelemetry != nil {
+		in, out := &in.OpenTelemetry, &out.OpenTelemetry
+		*out = new(InternalOpenTelemetrySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CarbonIntensity != nil {
+		in, out := &in.CarbonIntensity, &out.CarbonIntensity
+		*out = new(InternalCarbonIntensitySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EnergyReport != nil {
+		in, out := &in.EnergyReport, &out.EnergyReport
+		*out = new(InternalEnergyReportSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Telegraf != nil {
+		in, out := &in.Telegraf, &out.Telegraf
+		*out = new(InternalTelegrafSpec)
+		**out = **in
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(NetworkPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MetricsViewer != nil {
+		in, out := &in.MetricsViewer, &out.MetricsViewer
+		*out = new(MetricsViewerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ModelVerification != nil {
+		in, out := &in.ModelVerification, &out.ModelVerification
+		*out = new(ModelVerificationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeplerInternalSpec.
+func (in *KeplerInternalSpec) DeepCopy() *KeplerInternalSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeplerInternalSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeplerInternalStatus) DeepCopyInto(out *KeplerInternalStatus) {
+	*out = *in
+	in.Exporter.DeepCopyInto(&out.Exporter)
+	in.Estimator.DeepCopyInto(&out.Estimator)
+	in.ModelServer.DeepCopyInto(&out.ModelServer)
+	if in.InvalidNodes != nil {
+		in, out := &in.InvalidNodes, &out.InvalidNodes
+		*out = make([]InvalidNode, len(*in))
+		copy(*out, *in)
+	}
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]Endpoint, len(*in))
+		copy(*out, *in)
+	}
+	if in.PowerSources != nil {
+		in, out := &in.PowerSources, &out.PowerSources
+		*out = make([]PowerSource, len(*in))
+		copy(*out, *in)
+	}
+	if in.KernelPrerequisites != nil {
+		in, out := &in.KernelPrerequisites, &out.KernelPrerequisites
+		*out = make([]NodeKernelStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]ImageStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.VerifiedModels != nil {
+		in, out := &in.VerifiedModels, &out.VerifiedModels
+		*out = make([]VerifiedModel, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeplerInternalStatus.
+func (in *KeplerInternalStatus) DeepCopy() *KeplerInternalStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KeplerInternalStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeplerList) DeepCopyInto(out *KeplerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Kepler, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeplerList.
+func (in *KeplerList) DeepCopy() *KeplerList {
+	if in == nil {
+		return nil
+	}
+	out := new(KeplerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeplerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeplerSpec) DeepCopyInto(out *KeplerSpec) {
+	*out = *in
+	in.Exporter.DeepCopyInto(&out.Exporter)
+	in.Dashboards.DeepCopyInto(&out.Dashboards)
+	if in.ConsolePlugin != nil {
+		in, out := &in.ConsolePlugin, &out.ConsolePlugin
+		*out = new(ConsolePluginSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OpenTelemetry != nil {
+		in, out := &in.OpenTelemetry, &out.OpenTelemetry
+		*out = new(OpenTelemetrySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CarbonIntensity != nil {
+		in, out := &in.CarbonIntensity, &out.CarbonIntensity
+		*out = new(CarbonIntensitySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EnergyReport != nil {
+		in, out := &in.EnergyReport, &out.EnergyReport
+		*out = new(EnergyReportSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Telegraf != nil {
+		in, out := &in.Telegraf, &out.Telegraf
+		*out = new(TelegrafSpec)
+		**out = **in
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(NetworkPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MetricsViewer != nil {
+		in, out := &in.MetricsViewer, &out.MetricsViewer
+		*out = new(MetricsViewerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeplerSpec.
+func (in *KeplerSpec) DeepCopy() *KeplerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeplerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeplerStatus) DeepCopyInto(out *KeplerStatus) {
+	*out = *in
+	in.Exporter.DeepCopyInto(&out.Exporter)
+	if in.Estimator != nil {
+		in, out := &in.Estimator, &out.Estimator
+		*out = new(EstimatorStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ModelServer != nil {
+		in, out := &in.ModelServer, &out.ModelServer
+		*out = new(ModelServerStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InvalidNodes != nil {
+		in, out := &in.InvalidNodes, &out.InvalidNodes
+		*out = make([]InvalidNode, len(*in))
+		copy(*out, *in)
+	}
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]Endpoint, len(*in))
+		copy(*out, *in)
+	}
+	if in.PowerSources != nil {
+		in, out := &in.PowerSources, &out.PowerSources
+		*out = make([]PowerSource, len(*in))
+		copy(*out, *in)
+	}
+	if in.KernelPrerequisites != nil {
+		in, out := &in.KernelPrerequisites, &out.KernelPrerequisites
+		*out = make([]NodeKernelStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]ImageStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeplerStatus.
+func (in *KeplerStatus) DeepCopy() *KeplerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KeplerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KernelPrerequisitesSpec) DeepCopyInto(out *KernelPrerequisitesSpec) {
+	*out = *in
+	if in.Modules != nil {
+		in, out := &in.Modules, &out.Modules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AutomountServiceAccountToken != nil {
+		in, out := &in.AutomountServiceAccountToken, &out.AutomountServiceAccountToken
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelPrerequisitesSpec.
+func (in *KernelPrerequisitesSpec) DeepCopy() *KernelPrerequisitesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KernelPrerequisitesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeylessIdentity) DeepCopyInto(out *KeylessIdentity) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeylessIdentity.
+func (in *KeylessIdentity) DeepCopy() *KeylessIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(KeylessIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricLabelsSpec) DeepCopyInto(out *MetricLabelsSpec) {
+	*out = *in
+	if in.Extra != nil {
+		in, out := &in.Extra, &out.Extra
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricLabelsSpec.
+func (in *MetricLabelsSpec) DeepCopy() *MetricLabelsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricLabelsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsViewerBinding) DeepCopyInto(out *MetricsViewerBinding) {
+	*out = *in
+	if in.Subjects != nil {
+		in, out := &in.Subjects, &out.Subjects
+		*out = make([]rbacv1.Subject, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsViewerBinding.
+func (in *MetricsViewerBinding) DeepCopy() *MetricsViewerBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsViewerBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsViewerSpec) DeepCopyInto(out *MetricsViewerSpec) {
+	*out = *in
+	if in.Bindings != nil {
+		in, out := &in.Bindings, &out.Bindings
+		*out = make([]MetricsViewerBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsViewerSpec.
+func (in *MetricsViewerSpec) DeepCopy() *MetricsViewerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsViewerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelSelectorSpec) DeepCopyInto(out *ModelSelectorSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelSelectorSpec.
+func (in *ModelSelectorSpec) DeepCopy() *ModelSelectorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelSelectorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelServerStatus) DeepCopyInto(out *ModelServerStatus) {
+	*out = *in
+	if in.Models != nil {
+		in, out := &in.Models, &out.Models
+		*out = make([]ModelStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelServerStatus.
+func (in *ModelServerStatus) DeepCopy() *ModelServerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelServerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelServerStorageSpec) DeepCopyInto(out *ModelServerStorageSpec) {
+	*out = *in
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(v1.PersistentVolumeClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelServerStorageSpec.
+func (in *ModelServerStorageSpec) DeepCopy() *ModelServerStorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelServerStorageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelStatus) DeepCopyInto(out *ModelStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelStatus.
+func (in *ModelStatus) DeepCopy() *ModelStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelVerificationSpec) DeepCopyInto(out *ModelVerificationSpec) {
+	*out = *in
+	if in.Keyless != nil {
+		in, out := &in.Keyless, &out.Keyless
+		*out = new(KeylessIdentity)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelVerificationSpec.
+func (in *ModelVerificationSpec) DeepCopy() *ModelVerificationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelVerificationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicySpec) DeepCopyInto(out *NetworkPolicySpec) {
+	*out = *in
+	if in.MonitoringNamespaces != nil {
+		in, out := &in.MonitoringNamespaces, &out.MonitoringNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Egress != nil {
+		in, out := &in.Egress, &out.Egress
+		*out = make([]networkingv1.NetworkPolicyEgressRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicySpec.
+func (in *NetworkPolicySpec) DeepCopy() *NetworkPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeFeatureDiscoverySpec) DeepCopyInto(out *NodeFeatureDiscoverySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeFeatureDiscoverySpec.
+func (in *NodeFeatureDiscoverySpec) DeepCopy() *NodeFeatureDiscoverySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeFeatureDiscoverySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeKernelStatus) DeepCopyInto(out *NodeKernelStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeKernelStatus.
+func (in *NodeKernelStatus) DeepCopy() *NodeKernelStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeKernelStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenCostScrapeConfigSpec) DeepCopyInto(out *OpenCostScrapeConfigSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenCostScrapeConfigSpec.
+func (in *OpenCostScrapeConfigSpec) DeepCopy() *OpenCostScrapeConfigSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(EstimatorConfig)
+	out := new(OpenCostScrapeConfigSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EstimatorGroup) DeepCopyInto(out *EstimatorGroup) {
+func (in *OpenCostSpec) DeepCopyInto(out *OpenCostSpec) {
 	*out = *in
-	if in.Total != nil {
-		in, out := &in.Total, &out.Total
-		*out = new(EstimatorConfig)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Components != nil {
-		in, out := &in.Components, &out.Components
-		*out = new(EstimatorConfig)
-		(*in).DeepCopyInto(*out)
+	if in.ScrapeConfig != nil {
+		in, out := &in.ScrapeConfig, &out.ScrapeConfig
+		*out = new(OpenCostScrapeConfigSpec)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EstimatorGroup.
-func (in *EstimatorGroup) DeepCopy() *EstimatorGroup {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenCostSpec.
+func (in *OpenCostSpec) DeepCopy() *OpenCostSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(EstimatorGroup)
+	out := new(OpenCostSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EstimatorStatus) DeepCopyInto(out *EstimatorStatus) {
+func (in *OpenShiftDashboardsSpec) DeepCopyInto(out *OpenShiftDashboardsSpec) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EstimatorStatus.
-func (in *EstimatorStatus) DeepCopy() *EstimatorStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenShiftDashboardsSpec.
+func (in *OpenShiftDashboardsSpec) DeepCopy() *OpenShiftDashboardsSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(EstimatorStatus)
+	out := new(OpenShiftDashboardsSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExporterDeploymentSpec) DeepCopyInto(out *ExporterDeploymentSpec) {
+func (in *OpenShiftSpec) DeepCopyInto(out *OpenShiftSpec) {
 	*out = *in
-	if in.NodeSelector != nil {
-		in, out := &in.NodeSelector, &out.NodeSelector
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Tolerations != nil {
-		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]v1.Toleration, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	out.Dashboard = in.Dashboard
+	in.ConsolePlugin.DeepCopyInto(&out.ConsolePlugin)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExporterDeploymentSpec.
-func (in *ExporterDeploymentSpec) DeepCopy() *ExporterDeploymentSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenShiftSpec.
+func (in *OpenShiftSpec) DeepCopy() *OpenShiftSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ExporterDeploymentSpec)
+	out := new(OpenShiftSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExporterSpec) DeepCopyInto(out *ExporterSpec) {
+func (in *OpenTelemetrySpec) DeepCopyInto(out *OpenTelemetrySpec) {
 	*out = *in
-	in.Deployment.DeepCopyInto(&out.Deployment)
-	if in.Redfish != nil {
-		in, out := &in.Redfish, &out.Redfish
-		*out = new(RedfishSpec)
+	if in.AutomountServiceAccountToken != nil {
+		in, out := &in.AutomountServiceAccountToken, &out.AutomountServiceAccountToken
+		*out = new(bool)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExporterSpec.
-func (in *ExporterSpec) DeepCopy() *ExporterSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenTelemetrySpec.
+func (in *OpenTelemetrySpec) DeepCopy() *OpenTelemetrySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ExporterSpec)
+	out := new(OpenTelemetrySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExporterStatus) DeepCopyInto(out *ExporterStatus) {
+func (in *PUESpec) DeepCopyInto(out *PUESpec) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make(map[string]PUEFactor, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExporterStatus.
-func (in *ExporterStatus) DeepCopy() *ExporterStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PUESpec.
+func (in *PUESpec) DeepCopy() *PUESpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ExporterStatus)
+	out := new(PUESpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InternalEstimatorSpec) DeepCopyInto(out *InternalEstimatorSpec) {
+func (in *PodDisruptionBudgetSpec) DeepCopyInto(out *PodDisruptionBudgetSpec) {
 	*out = *in
-	in.Node.DeepCopyInto(&out.Node)
-	in.Container.DeepCopyInto(&out.Container)
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalEstimatorSpec.
-func (in *InternalEstimatorSpec) DeepCopy() *InternalEstimatorSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDisruptionBudgetSpec.
+func (in *PodDisruptionBudgetSpec) DeepCopy() *PodDisruptionBudgetSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(InternalEstimatorSpec)
+	out := new(PodDisruptionBudgetSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InternalExporterDeploymentSpec) DeepCopyInto(out *InternalExporterDeploymentSpec) {
+func (in *PodMonitorSpec) DeepCopyInto(out *PodMonitorSpec) {
 	*out = *in
-	in.ExporterDeploymentSpec.DeepCopyInto(&out.ExporterDeploymentSpec)
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.ScrapeSpec.DeepCopyInto(&out.ScrapeSpec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalExporterDeploymentSpec.
-func (in *InternalExporterDeploymentSpec) DeepCopy() *InternalExporterDeploymentSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodMonitorSpec.
+func (in *PodMonitorSpec) DeepCopy() *PodMonitorSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(InternalExporterDeploymentSpec)
+	out := new(PodMonitorSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InternalExporterSpec) DeepCopyInto(out *InternalExporterSpec) {
+func (in *PowerSource) DeepCopyInto(out *PowerSource) {
 	*out = *in
-	in.Deployment.DeepCopyInto(&out.Deployment)
-	if in.Redfish != nil {
-		in, out := &in.Redfish, &out.Redfish
-		*out = new(RedfishSpec)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalExporterSpec.
-func (in *InternalExporterSpec) DeepCopy() *InternalExporterSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerSource.
+func (in *PowerSource) DeepCopy() *PowerSource {
 	if in == nil {
 		return nil
 	}
-	out := new(InternalExporterSpec)
+	out := new(PowerSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InternalModelServerSpec) DeepCopyInto(out *InternalModelServerSpec) {
+func (in *RBACProxySpec) DeepCopyInto(out *RBACProxySpec) {
 	*out = *in
-	in.Storage.DeepCopyInto(&out.Storage)
+	if in.CertManager != nil {
+		in, out := &in.CertManager, &out.CertManager
+		*out = new(CertManagerSpec)
+		**out = **in
+	}
+	if in.ScrapeServiceAccount != nil {
+		in, out := &in.ScrapeServiceAccount, &out.ScrapeServiceAccount
+		*out = new(ScrapeServiceAccountSpec)
+		**out = **in
+	}
+	if in.TokenAudiences != nil {
+		in, out := &in.TokenAudiences, &out.TokenAudiences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalModelServerSpec.
-func (in *InternalModelServerSpec) DeepCopy() *InternalModelServerSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBACProxySpec.
+func (in *RBACProxySpec) DeepCopy() *RBACProxySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(InternalModelServerSpec)
+	out := new(RBACProxySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Kepler) DeepCopyInto(out *Kepler) {
+func (in *ReadOnlyRootFilesystemSpec) DeepCopyInto(out *ReadOnlyRootFilesystemSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.ScratchPaths != nil {
+		in, out := &in.ScratchPaths, &out.ScratchPaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Kepler.
-func (in *Kepler) DeepCopy() *Kepler {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadOnlyRootFilesystemSpec.
+func (in *ReadOnlyRootFilesystemSpec) DeepCopy() *ReadOnlyRootFilesystemSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Kepler)
+	out := new(ReadOnlyRootFilesystemSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Kepler) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeplerInternal) DeepCopyInto(out *KeplerInternal) {
+func (in *RedfishSpec) DeepCopyInto(out *RedfishSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	out.ProbeInterval = in.ProbeInterval
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeplerInternal.
-func (in *KeplerInternal) DeepCopy() *KeplerInternal {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedfishSpec.
+func (in *RedfishSpec) DeepCopy() *RedfishSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KeplerInternal)
+	out := new(RedfishSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KeplerInternal) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeplerInternalList) DeepCopyInto(out *KeplerInternalList) {
+func (in *SCIFunctionalUnitSpec) DeepCopyInto(out *SCIFunctionalUnitSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]KeplerInternal, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeplerInternalList.
-func (in *KeplerInternalList) DeepCopy() *KeplerInternalList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SCIFunctionalUnitSpec.
+func (in *SCIFunctionalUnitSpec) DeepCopy() *SCIFunctionalUnitSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KeplerInternalList)
+	out := new(SCIFunctionalUnitSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KeplerInternalList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeplerInternalSpec) DeepCopyInto(out *KeplerInternalSpec) {
+func (in *SCISpec) DeepCopyInto(out *SCISpec) {
 	*out = *in
-	in.Exporter.DeepCopyInto(&out.Exporter)
-	if in.Estimator != nil {
-		in, out := &in.Estimator, &out.Estimator
-		*out = new(InternalEstimatorSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ModelServer != nil {
-		in, out := &in.ModelServer, &out.ModelServer
-		*out = new(InternalModelServerSpec)
-		(*in).DeepCopyInto(*out)
+	if in.FunctionalUnit != nil {
+		in, out := &in.FunctionalUnit, &out.FunctionalUnit
+		*out = new(SCIFunctionalUnitSpec)
+		**out = **in
 	}
-	out.OpenShift = in.OpenShift
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeplerInternalSpec.
-func (in *KeplerInternalSpec) DeepCopy() *KeplerInternalSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SCISpec.
+func (in *SCISpec) DeepCopy() *SCISpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KeplerInternalSpec)
+	out := new(SCISpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeplerInternalStatus) DeepCopyInto(out *KeplerInternalStatus) {
+func (in *ScrapeAnnotationsSpec) DeepCopyInto(out *ScrapeAnnotationsSpec) {
 	*out = *in
-	in.Exporter.DeepCopyInto(&out.Exporter)
-	out.Estimator = in.Estimator
-	out.ModelServer = in.ModelServer
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeplerInternalStatus.
-func (in *KeplerInternalStatus) DeepCopy() *KeplerInternalStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScrapeAnnotationsSpec.
+func (in *ScrapeAnnotationsSpec) DeepCopy() *ScrapeAnnotationsSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KeplerInternalStatus)
+	out := new(ScrapeAnnotationsSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeplerList) DeepCopyInto(out *KeplerList) {
+func (in *ScrapeServiceAccountSpec) DeepCopyInto(out *ScrapeServiceAccountSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Kepler, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeplerList.
-func (in *KeplerList) DeepCopy() *KeplerList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScrapeServiceAccountSpec.
+func (in *ScrapeServiceAccountSpec) DeepCopy() *ScrapeServiceAccountSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KeplerList)
+	out := new(ScrapeServiceAccountSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KeplerList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeplerSpec) DeepCopyInto(out *KeplerSpec) {
+func (in *ScrapeSpec) DeepCopyInto(out *ScrapeSpec) {
 	*out = *in
-	in.Exporter.DeepCopyInto(&out.Exporter)
+	if in.Relabelings != nil {
+		in, out := &in.Relabelings, &out.Relabelings
+		*out = make([]*monitoringv1.RelabelConfig, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(monitoringv1.RelabelConfig)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.MetricRelabelings != nil {
+		in, out := &in.MetricRelabelings, &out.MetricRelabelings
+		*out = make([]*monitoringv1.RelabelConfig, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(monitoringv1.RelabelConfig)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeplerSpec.
-func (in *KeplerSpec) DeepCopy() *KeplerSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScrapeSpec.
+func (in *ScrapeSpec) DeepCopy() *ScrapeSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KeplerSpec)
+	out := new(ScrapeSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeplerStatus) DeepCopyInto(out *KeplerStatus) {
+func (in *SecurityProfilesSpec) DeepCopyInto(out *SecurityProfilesSpec) {
 	*out = *in
-	in.Exporter.DeepCopyInto(&out.Exporter)
+	if in.SeccompProfile != nil {
+		in, out := &in.SeccompProfile, &out.SeccompProfile
+		*out = new(v1.SeccompProfile)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeplerStatus.
-func (in *KeplerStatus) DeepCopy() *KeplerStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityProfilesSpec.
+func (in *SecurityProfilesSpec) DeepCopy() *SecurityProfilesSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KeplerStatus)
+	out := new(SecurityProfilesSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ModelSelectorSpec) DeepCopyInto(out *ModelSelectorSpec) {
+func (in *ServiceMonitorSpec) DeepCopyInto(out *ServiceMonitorSpec) {
 	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.ScrapeSpec.DeepCopyInto(&out.ScrapeSpec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelSelectorSpec.
-func (in *ModelSelectorSpec) DeepCopy() *ModelSelectorSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceMonitorSpec.
+func (in *ServiceMonitorSpec) DeepCopy() *ServiceMonitorSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ModelSelectorSpec)
+	out := new(ServiceMonitorSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ModelServerStatus) DeepCopyInto(out *ModelServerStatus) {
+func (in *TelegrafSpec) DeepCopyInto(out *TelegrafSpec) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelServerStatus.
-func (in *ModelServerStatus) DeepCopy() *ModelServerStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TelegrafSpec.
+func (in *TelegrafSpec) DeepCopy() *TelegrafSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ModelServerStatus)
+	out := new(TelegrafSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ModelServerStorageSpec) DeepCopyInto(out *ModelServerStorageSpec) {
+func (in *VerifiedModel) DeepCopyInto(out *VerifiedModel) {
 	*out = *in
-	if in.PersistentVolumeClaim != nil {
-		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
-		*out = new(v1.PersistentVolumeClaimSpec)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelServerStorageSpec.
-func (in *ModelServerStorageSpec) DeepCopy() *ModelServerStorageSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerifiedModel.
+func (in *VerifiedModel) DeepCopy() *VerifiedModel {
 	if in == nil {
 		return nil
 	}
-	out := new(ModelServerStorageSpec)
+	out := new(VerifiedModel)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenShiftSpec) DeepCopyInto(out *OpenShiftSpec) {
+func (in *VerticalPodAutoscalerSpec) DeepCopyInto(out *VerticalPodAutoscalerSpec) {
 	*out = *in
-	out.Dashboard = in.Dashboard
+	if in.MinAllowed != nil {
+		in, out := &in.MinAllowed, &out.MinAllowed
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.MaxAllowed != nil {
+		in, out := &in.MaxAllowed, &out.MaxAllowed
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenShiftSpec.
-func (in *OpenShiftSpec) DeepCopy() *OpenShiftSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerticalPodAutoscalerSpec.
+func (in *VerticalPodAutoscalerSpec) DeepCopy() *VerticalPodAutoscalerSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenShiftSpec)
+	out := new(VerticalPodAutoscalerSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RedfishSpec) DeepCopyInto(out *RedfishSpec) {
+func (in *VirtualMachinesSpec) DeepCopyInto(out *VirtualMachinesSpec) {
 	*out = *in
-	out.ProbeInterval = in.ProbeInterval
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedfishSpec.
-func (in *RedfishSpec) DeepCopy() *RedfishSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachinesSpec.
+func (in *VirtualMachinesSpec) DeepCopy() *VirtualMachinesSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RedfishSpec)
+	out := new(VirtualMachinesSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -0,0 +1,608 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package carbon
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	monv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/common/model"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components/exporter"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// StableImage is the json_exporter of the Prometheus community which
+	// converts the response of the API of the provider into a metric
+	StableImage = "quay.io/prometheuscommunity/json-exporter:v0.6.0"
+
+	// IntensityMetric is the metric of the carbon intensity of the grid in
+	// gCO2e/kWh exported by the exporter
+	IntensityMetric = "grid_carbon_intensity_gco2e_per_kwh"
+
+	// DefaultPath is where the intensity is looked up in the response of the
+	// provider; Electricity Maps responds with the intensity in carbonIntensity
+	DefaultPath = "{.carbonIntensity}"
+
+	// DefaultInterval is the interval at which the provider is queried
+	DefaultInterval monv1.Duration = "15m"
+
+	// DefaultWorkloadLabel is the label of the pods naming their workload
+	DefaultWorkloadLabel = "app.kubernetes.io/name"
+
+	// DefaultAmortizationYears is the lifetime of the hardware of the nodes
+	// over which their embodied carbon is amortized
+	DefaultAmortizationYears = 4
+
+	secondsPerYear = 365 * 24 * 3600
+
+	port      = 7979
+	portName  = "http"
+	module    = "default"
+	configKey = "config.yml"
+
+	configPath = "/etc/json-exporter"
+	authPath   = "/var/run/secrets/carbon-intensity"
+	authKey    = "token"
+
+	// authConfigPath is where the configuration with the token in the auth
+	// header is written when the pod starts; the token is kept out of the
+	// configmap
+	authConfigPath = "/tmp/json-exporter"
+	authToken      = "${AUTH_TOKEN}"
+)
+
+var (
+	invalidLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+	// common labels for all resources of the exporter
+	labels = components.CommonLabels.Merge(k8s.StringMap{
+		"app.kubernetes.io/component":  "carbon-intensity-exporter",
+		"sustainable-computing.io/app": "carbon-intensity-exporter",
+	})
+)
+
+// Name returns the name of the exporter and its resources
+func Name(ki *v1alpha1.KeplerInternal) string {
+	return ki.Name + "-carbon-intensity"
+}
+
+func podSelector(ki *v1alpha1.KeplerInternal) k8s.StringMap {
+	return labels.Merge(k8s.StringMap{
+		"app.kubernetes.io/name":                     "carbon-intensity-exporter",
+		"operator.sustainable-computing.io/internal": ki.Name,
+	})
+}
+
+// NeedsExporter returns true if the intensity is queried from a provider by
+// the exporter; static and regional intensities are set in the recording
+// rules directly
+func NeedsExporter(ki *v1alpha1.KeplerInternal) bool {
+	ci := ki.Spec.CarbonIntensity
+	return ci.IsEnabled() && ci.Source == v1alpha1.ProviderCarbonIntensity
+}
+
+// Validate returns an error if the spec lacks the intensity of its source
+func Validate(ki *v1alpha1.KeplerInternal) error {
+	ci := spec(ki)
+	switch ci.Source {
+	case v1alpha1.StaticCarbonIntensity, "":
+		if ci.Intensity == nil {
+			return fmt.Errorf("carbon intensity source %s requires an intensity", v1alpha1.StaticCarbonIntensity)
+		}
+	case v1alpha1.RegionCarbonIntensity:
+		if len(ci.Regions) == 0 {
+			return fmt.Errorf("carbon intensity source %s requires the intensity of one or more regions", ci.Source)
+		}
+	case v1alpha1.ProviderCarbonIntensity:
+		if ci.Provider == nil || ci.Provider.URL == "" {
+			return fmt.Errorf("carbon intensity source %s requires the url of the provider", ci.Source)
+		}
+	default:
+		return fmt.Errorf("unknown carbon intensity source %q", ci.Source)
+	}
+	if e := ci.Embodied; e.IsEnabled() && e.KgCO2e == nil && len(e.InstanceTypes) == 0 && len(e.Nodes) == 0 {
+		return fmt.Errorf("embodied carbon requires the embodied carbon of the nodes, of their instance types or a default")
+	}
+	return nil
+}
+
+// Config returns the configuration of the json_exporter that looks up the
+// intensity in the response of the provider
+func Config(ki *v1alpha1.KeplerInternal) (string, error) {
+	provider := providerSpec(ki)
+
+	path := provider.Path
+	if path == "" {
+		path = DefaultPath
+	}
+
+	mod := map[string]interface{}{
+		"metrics": []interface{}{map[string]interface{}{
+			"name": IntensityMetric,
+			"help": "Carbon intensity of the grid in gCO2e/kWh",
+			"type": "value",
+			"path": path,
+		}},
+	}
+	switch {
+	case provider.AuthSecretRef == "":
+	case provider.AuthHeader != "":
+		// NOTE: the json_exporter reads headers from the config only, so the
+		// token is substituted when the pod starts; see NewDeployment
+		mod["headers"] = map[string]interface{}{provider.AuthHeader: authToken}
+	default:
+		mod["http_client_config"] = map[string]interface{}{
+			"authorization": map[string]interface{}{
+				"type":             "Bearer",
+				"credentials_file": authPath + "/" + authKey,
+			},
+		}
+	}
+
+	data, err := yaml.Marshal(map[string]interface{}{
+		"modules": map[string]interface{}{module: mod},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal json exporter config: %w", err)
+	}
+	return string(data), nil
+}
+
+// NewConfigMap returns the configmap holding the configuration of the exporter
+func NewConfigMap(d components.Detail, ki *v1alpha1.KeplerInternal) (*corev1.ConfigMap, error) {
+	cm := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      Name(ki),
+			Namespace: ki.Namespace(),
+			Labels:    labels,
+		},
+	}
+	if d == components.Metadata {
+		return cm, nil
+	}
+
+	cfg, err := Config(ki)
+	if err != nil {
+		return nil, err
+	}
+	cm.Data = map[string]string{configKey: cfg}
+	return cm, nil
+}
+
+// NewDeployment returns the deployment of the exporter; the pods are
+// redeployed when the configuration in cm changes
+func NewDeployment(ki *v1alpha1.KeplerInternal, cm *corev1.ConfigMap) *appsv1.Deployment {
+	name := Name(ki)
+	ci := spec(ki)
+
+	mounts := []corev1.VolumeMount{{
+		Name:      "config",
+		MountPath: configPath,
+		ReadOnly:  true,
+	}}
+	volumes := []corev1.Volume{
+		k8s.VolumeFromConfigMap("config", name),
+	}
+	args := []string{
+		"--config.file=" + configPath + "/" + configKey,
+		fmt.Sprintf("--web.listen-address=:%d", port),
+	}
+	var command []string
+	if provider := providerSpec(ki); provider.AuthSecretRef != "" {
+		mounts = append(mounts, corev1.VolumeMount{Name: "auth", MountPath: authPath, ReadOnly: true})
+		volumes = append(volumes, k8s.VolumeFromSecret("auth", provider.AuthSecretRef))
+		if provider.AuthHeader != "" {
+			mounts = append(mounts, corev1.VolumeMount{Name: "auth-config", MountPath: authConfigPath})
+			volumes = append(volumes, k8s.VolumeFromEmptyDir("auth-config"))
+			command, args = []string{"/bin/sh", "-c"}, []string{authHeaderScript()}
+		}
+	}
+
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+			Kind:       "Deployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ki.Namespace(),
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To(int32(1)),
+			Selector: &metav1.LabelSelector{MatchLabels: podSelector(ki)},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: podSelector(ki),
+					Annotations: map[string]string{
+						exporter.ConfigHashAnnotation: exporter.ConfigHash(cm.Data),
+					},
+				},
+				Spec: corev1.PodSpec{
+					// NOTE: the exporter queries the provider only and does
+					// not use the Kubernetes API
+					AutomountServiceAccountToken: ptr.To(false),
+					Containers: []corev1.Container{{
+						Name:            "json-exporter",
+						Image:           ci.Image,
+						ImagePullPolicy: corev1.PullIfNotPresent,
+						Command:         command,
+						Args:            args,
+						Ports: []corev1.ContainerPort{{
+							ContainerPort: port,
+							Name:          portName,
+						}},
+						VolumeMounts: mounts,
+					}},
+					Volumes: volumes,
+				},
+			},
+		},
+	}
+}
+
+// authHeaderScript returns the script that writes the configuration with the
+// token of the secret in the auth header and runs the exporter with it
+func authHeaderScript() string {
+	return fmt.Sprintf(`token=$(sed -e "s/'/''/g" -e 's/[\\&|]/\\&/g' %s/%s) && `+
+		`sed "s|\%s|'${token}'|" %s/%s > %s/%s && `+
+		`exec /bin/json_exporter --config.file=%s/%s --web.listen-address=:%d`,
+		authPath, authKey, authToken,
+		configPath, configKey, authConfigPath, configKey,
+		authConfigPath, configKey, port)
+}
+
+// NewService returns the service the exporter is scraped through
+func NewService(ki *v1alpha1.KeplerInternal) *corev1.Service {
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      Name(ki),
+			Namespace: ki.Namespace(),
+			Labels:    podSelector(ki),
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: podSelector(ki),
+			Ports: []corev1.ServicePort{{
+				Name:       portName,
+				Port:       port,
+				TargetPort: intstr.FromString(portName),
+			}},
+		},
+	}
+}
+
+// NewServiceMonitor returns the ServiceMonitor that scrapes the exporter,
+// which queries the provider on each scrape, at the interval in the spec
+func NewServiceMonitor(d components.Detail, ki *v1alpha1.KeplerInternal) *monv1.ServiceMonitor {
+	sm := &monv1.ServiceMonitor{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: monv1.SchemeGroupVersion.String(),
+			Kind:       "ServiceMonitor",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      Name(ki),
+			Namespace: ki.Namespace(),
+			Labels:    labels,
+		},
+	}
+	if d == components.Metadata {
+		return sm
+	}
+
+	provider := providerSpec(ki)
+	interval := provider.Interval
+	if interval == "" {
+		interval = DefaultInterval
+	}
+	sm.Spec = monv1.ServiceMonitorSpec{
+		Endpoints: []monv1.Endpoint{{
+			Port:     portName,
+			Path:     "/probe",
+			Interval: interval,
+			Params: map[string][]string{
+				"module": {module},
+				"target": {provider.URL},
+			},
+		}},
+		Selector: metav1.LabelSelector{MatchLabels: podSelector(ki)},
+	}
+	return sm
+}
+
+// NewPrometheusRule returns the recording rules of the carbon intensity of
+// each node and of the emissions, in gCO2e per second, of the containers
+// measured by kepler aggregated by container, namespace and cluster
+func NewPrometheusRule(d components.Detail, ki *v1alpha1.KeplerInternal) (*monv1.PrometheusRule, error) {
+	rule := &monv1.PrometheusRule{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: monv1.SchemeGroupVersion.String(),
+			Kind:       "PrometheusRule",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      Name(ki),
+			Namespace: ki.Namespace(),
+			Labels:    labels,
+		},
+	}
+	if d == components.Metadata {
+		return rule, nil
+	}
+
+	intensity, err := nodeIntensity(ki)
+	if err != nil {
+		return nil, err
+	}
+
+	ns := ki.Namespace()
+	interval := monv1.Duration("30s")
+	rule.Spec = monv1.PrometheusRuleSpec{
+		Groups: []monv1.RuleGroup{{
+			Name:     "kepler.carbon",
+			Interval: &interval,
+			Rules: []monv1.Rule{{
+				Record: "node:kepler_grid_carbon_intensity:gco2e_per_kwh",
+				Expr:   intstr.FromString(intensity),
+			}, {
+				Record: "container:kepler_container_gco2e:rate5m",
+				Expr: intstr.FromString(fmt.Sprintf(
+					`sum by (container_namespace, pod_name, container_name, instance) (rate(kepler_container_joules_total{namespace=%q}[5m]))`+
+						` / %d * on (instance) group_left () node:kepler_grid_carbon_intensity:gco2e_per_kwh`, ns, exporter.JoulesPerKWh)),
+			}, {
+				Record: "namespace:kepler_container_gco2e:rate5m",
+				Expr:   intstr.FromString(`sum by (container_namespace) (container:kepler_container_gco2e:rate5m)`),
+			}, {
+				Record: "cluster:kepler_container_gco2e:rate5m",
+				Expr:   intstr.FromString(`sum (container:kepler_container_gco2e:rate5m)`),
+			}},
+		}},
+	}
+	embodied := spec(ki).Embodied
+	if embodied.IsEnabled() {
+		rule.Spec.Groups = append(rule.Spec.Groups, monv1.RuleGroup{
+			Name:     "kepler.embodied-carbon",
+			Interval: &interval,
+			Rules:    embodiedRules(ns, embodied),
+		})
+	}
+	if sci := spec(ki).SCI; sci.IsEnabled() {
+		rule.Spec.Groups = append(rule.Spec.Groups, monv1.RuleGroup{
+			Name:     "kepler.sci",
+			Interval: &interval,
+			Rules:    sciRules(sci, embodied.IsEnabled()),
+		})
+	}
+	return rule, nil
+}
+
+// embodiedRules returns the recording rules of the embodied carbon of each
+// node amortized over its lifetime, of the share of the containers in it by
+// the energy they consume on the node, and of the lifecycle emissions of the
+// containers, i.e. their operational and amortized embodied emissions
+func embodiedRules(ns string, e *v1alpha1.EmbodiedCarbonSpec) []monv1.Rule {
+	years := e.AmortizationYears
+	if years <= 0 {
+		years = DefaultAmortizationYears
+	}
+	energy := fmt.Sprintf(`rate(kepler_container_joules_total{namespace=%q}[5m])`, ns)
+
+	return []monv1.Rule{{
+		Record: "node:kepler_embodied_carbon:kgco2e",
+		Expr:   intstr.FromString(nodeEmbodied(ns, e)),
+	}, {
+		Record: "node:kepler_embodied_gco2e:per_second",
+		Expr:   intstr.FromString(fmt.Sprintf(`node:kepler_embodied_carbon:kgco2e * 1000 / %d`, int64(years)*secondsPerYear)),
+	}, {
+		Record: "container:kepler_container_embodied_gco2e:rate5m",
+		Expr: intstr.FromString(fmt.Sprintf(
+			`sum by (container_namespace, pod_name, container_name, instance) (%s)`+
+				` / on (instance) group_left () sum by (instance) (%s)`+
+				` * on (instance) group_left () node:kepler_embodied_gco2e:per_second`, energy, energy)),
+	}, {
+		// NOTE: containers on nodes without embodied carbon report their
+		// operational emissions only
+		Record: "container:kepler_container_lifecycle_gco2e:rate5m",
+		Expr: intstr.FromString(`container:kepler_container_gco2e:rate5m + container:kepler_container_embodied_gco2e:rate5m` +
+			` or container:kepler_container_gco2e:rate5m`),
+	}, {
+		Record: "namespace:kepler_container_lifecycle_gco2e:rate5m",
+		Expr:   intstr.FromString(`sum by (container_namespace) (container:kepler_container_lifecycle_gco2e:rate5m)`),
+	}, {
+		Record: "cluster:kepler_container_lifecycle_gco2e:rate5m",
+		Expr:   intstr.FromString(`sum (container:kepler_container_lifecycle_gco2e:rate5m)`),
+	}, {
+		Record: "cluster:kepler_embodied_gco2e:per_second",
+		Expr:   intstr.FromString(`sum (node:kepler_embodied_gco2e:per_second)`),
+	}}
+}
+
+// nodeEmbodied returns the expression of the embodied carbon in kgCO2e of
+// each node exporting kepler metrics, labelled by instance as the kepler
+// metrics are; nodes take precedence over instance types, which take
+// precedence over the default
+func nodeEmbodied(ns string, e *v1alpha1.EmbodiedCarbonSpec) string {
+	exprs := []string{}
+	for _, n := range sortedKeys(e.Nodes) {
+		exprs = append(exprs, fmt.Sprintf(`0 * max by (instance) (kepler_node_info{namespace=%q, instance=%q}) + %d`,
+			ns, n, e.Nodes[n]))
+	}
+	for _, t := range sortedKeys(e.InstanceTypes) {
+		exprs = append(exprs, fmt.Sprintf(
+			`0 * max by (instance) (label_replace(kube_node_labels{label_node_kubernetes_io_instance_type=%q}, "instance", "$1", "node", "(.*)")) + %d`,
+			t, e.InstanceTypes[t]))
+	}
+	if e.KgCO2e != nil {
+		exprs = append(exprs, fmt.Sprintf(`0 * max by (instance) (kepler_node_info{namespace=%q}) + %d`, ns, *e.KgCO2e))
+	}
+	return strings.Join(exprs, " or ")
+}
+
+func sortedKeys(m map[string]int32) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sciRules returns the recording rules of the emissions of each workload and,
+// if a functional unit is configured, of the emissions per functional unit.
+// The SCI includes the amortized embodied emissions (M) along with the
+// operational emissions (E * I) if the embodied carbon rules are created.
+func sciRules(sci *v1alpha1.SCISpec, embodied bool) []monv1.Rule {
+	workloadLabel := sci.WorkloadLabel
+	if workloadLabel == "" {
+		workloadLabel = DefaultWorkloadLabel
+	}
+
+	// NOTE: kube-state-metrics exports the labels of pods as label_<name>
+	// with the characters that are invalid in label names replaced by _
+	podLabel := "label_" + invalidLabelChars.ReplaceAllString(workloadLabel, "_")
+	workloads := fmt.Sprintf(`max by (container_namespace, pod_name, workload) (`+
+		`label_replace(label_replace(label_replace(kube_pod_labels{%s!=""}, `+
+		`"workload", "$1", %q, "(.*)"), `+
+		`"container_namespace", "$1", "namespace", "(.*)"), `+
+		`"pod_name", "$1", "pod", "(.*)"))`, podLabel, podLabel)
+
+	byWorkload := func(record string) string {
+		return `sum by (container_namespace, workload) (` + record +
+			` * on (container_namespace, pod_name) group_left (workload) ` + workloads + `)`
+	}
+
+	rules := []monv1.Rule{{
+		Record: "workload:kepler_container_gco2e:rate5m",
+		Expr:   intstr.FromString(byWorkload("container:kepler_container_gco2e:rate5m")),
+	}}
+	emissions := "workload:kepler_container_gco2e:rate5m"
+	if embodied {
+		rules = append(rules, monv1.Rule{
+			Record: "workload:kepler_container_lifecycle_gco2e:rate5m",
+			Expr:   intstr.FromString(byWorkload("container:kepler_container_lifecycle_gco2e:rate5m")),
+		})
+		emissions = "workload:kepler_container_lifecycle_gco2e:rate5m"
+	}
+
+	fu := sci.FunctionalUnit
+	if fu == nil {
+		return rules
+	}
+	units := fmt.Sprintf(`sum by (container_namespace, workload) (`+
+		`label_replace(label_replace(rate(%s[5m]), "workload", "$1", %q, "(.*)"), `+
+		`"container_namespace", "$1", "namespace", "(.*)"))`, fu.Metric, fu.Label)
+	return append(rules, monv1.Rule{
+		Record: "workload:kepler_sci:gco2e_per_unit",
+		Expr:   intstr.FromString(emissions + ` / on (container_namespace, workload) ` + units),
+	})
+}
+
+// nodeIntensity returns the expression of the intensity of the grid of each
+// node exporting kepler metrics, labelled by instance as the kepler metrics are
+func nodeIntensity(ki *v1alpha1.KeplerInternal) (string, error) {
+	if err := Validate(ki); err != nil {
+		return "", err
+	}
+
+	ci := spec(ki)
+	nodes := fmt.Sprintf(`0 * max by (instance) (kepler_node_info{namespace=%q})`, ki.Namespace())
+
+	switch ci.Source {
+	case v1alpha1.RegionCarbonIntensity:
+		exprs := []string{}
+		for _, r := range sortedKeys(ci.Regions) {
+			exprs = append(exprs, fmt.Sprintf(
+				`0 * max by (instance) (label_replace(kube_node_labels{label_topology_kubernetes_io_region=%q}, "instance", "$1", "node", "(.*)")) + %d`,
+				r, ci.Regions[r]))
+		}
+		// nodes in regions that are not listed default to the intensity
+		if ci.Intensity != nil {
+			exprs = append(exprs, fmt.Sprintf(`%s + %d`, nodes, *ci.Intensity))
+		}
+		return strings.Join(exprs, " or "), nil
+
+	case v1alpha1.ProviderCarbonIntensity:
+		lookback, err := providerLookback(providerSpec(ki).Interval)
+		if err != nil {
+			return "", err
+		}
+		// NOTE: the provider is scraped less often than samples go stale, so
+		// the last intensity scraped is looked up
+		return fmt.Sprintf(`%s + on () group_left () max(last_over_time(%s{namespace=%q, service=%q}[%s]))`,
+			nodes, IntensityMetric, ki.Namespace(), Name(ki), lookback), nil
+	}
+	return fmt.Sprintf(`%s + %d`, nodes, *ci.Intensity), nil
+}
+
+// providerLookback returns the range over which the last intensity scraped
+// from the provider is looked up; it spans two scrapes so that a single failed
+// scrape does not drop the intensity
+func providerLookback(interval monv1.Duration) (model.Duration, error) {
+	if interval == "" {
+		interval = DefaultInterval
+	}
+	d, err := model.ParseDuration(string(interval))
+	if err != nil {
+		return 0, fmt.Errorf("invalid carbon intensity provider interval %q: %w", interval, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("carbon intensity provider interval %q must be positive", interval)
+	}
+	return 2 * d, nil
+}
+
+// PodSecrets returns the names of the secrets used by the exporter pods
+// which have to be redeployed when the secrets are rotated
+func PodSecrets(ki *v1alpha1.KeplerInternal) []string {
+	if ref := providerSpec(ki).AuthSecretRef; ref != "" {
+		return []string{ref}
+	}
+	return nil
+}
+
+func spec(ki *v1alpha1.KeplerInternal) *v1alpha1.InternalCarbonIntensitySpec {
+	if ki.Spec.CarbonIntensity == nil {
+		return &v1alpha1.InternalCarbonIntensitySpec{}
+	}
+	return ki.Spec.CarbonIntensity
+}
+
+func providerSpec(ki *v1alpha1.KeplerInternal) *v1alpha1.CarbonIntensityProviderSpec {
+	if p := spec(ki).Provider; p != nil {
+		return p
+	}
+	return &v1alpha1.CarbonIntensityProviderSpec{}
+}
@@ -0,0 +1,331 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package carbon
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+)
+
+func newKeplerInternal(ci v1alpha1.CarbonIntensitySpec) *v1alpha1.KeplerInternal {
+	return &v1alpha1.KeplerInternal{
+		ObjectMeta: metav1.ObjectMeta{Name: "kepler"},
+		Spec: v1alpha1.KeplerInternalSpec{
+			Exporter: v1alpha1.InternalExporterSpec{
+				Deployment: v1alpha1.InternalExporterDeploymentSpec{
+					Namespace: "power-monitoring",
+				},
+			},
+			CarbonIntensity: &v1alpha1.InternalCarbonIntensitySpec{
+				CarbonIntensitySpec: ci,
+				Image:               "json-exporter:test",
+			},
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tt := []struct {
+		spec     v1alpha1.CarbonIntensitySpec
+		valid    bool
+		scenario string
+	}{
+		{
+			spec:     v1alpha1.CarbonIntensitySpec{Enabled: true, Intensity: ptr.To(int32(400))},
+			valid:    true,
+			scenario: "static intensity",
+		},
+		{
+			spec:     v1alpha1.CarbonIntensitySpec{Enabled: true, Source: v1alpha1.StaticCarbonIntensity},
+			scenario: "static without intensity",
+		},
+		{
+			spec: v1alpha1.CarbonIntensitySpec{
+				Enabled: true, Source: v1alpha1.RegionCarbonIntensity,
+				Regions: map[string]int32{"eu-west-1": 300},
+			},
+			valid:    true,
+			scenario: "regions",
+		},
+		{
+			spec:     v1alpha1.CarbonIntensitySpec{Enabled: true, Source: v1alpha1.RegionCarbonIntensity},
+			scenario: "region without regions",
+		},
+		{
+			spec: v1alpha1.CarbonIntensitySpec{
+				Enabled: true, Source: v1alpha1.ProviderCarbonIntensity,
+				Provider: &v1alpha1.CarbonIntensityProviderSpec{URL: "https://api.example.com"},
+			},
+			valid:    true,
+			scenario: "provider",
+		},
+		{
+			spec:     v1alpha1.CarbonIntensitySpec{Enabled: true, Source: v1alpha1.ProviderCarbonIntensity},
+			scenario: "provider without url",
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := Validate(newKeplerInternal(tc.spec))
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestNodeIntensity(t *testing.T) {
+	tt := []struct {
+		spec     v1alpha1.CarbonIntensitySpec
+		expr     string
+		scenario string
+	}{
+		{
+			spec:     v1alpha1.CarbonIntensitySpec{Enabled: true, Intensity: ptr.To(int32(400))},
+			expr:     `0 * max by (instance) (kepler_node_info{namespace="power-monitoring"}) + 400`,
+			scenario: "static intensity",
+		},
+		{
+			spec: v1alpha1.CarbonIntensitySpec{
+				Enabled: true, Source: v1alpha1.RegionCarbonIntensity,
+				Regions:   map[string]int32{"us-east-1": 380, "eu-north-1": 30},
+				Intensity: ptr.To(int32(400)),
+			},
+			expr: `0 * max by (instance) (label_replace(kube_node_labels{label_topology_kubernetes_io_region="eu-north-1"}, "instance", "$1", "node", "(.*)")) + 30` +
+				` or 0 * max by (instance) (label_replace(kube_node_labels{label_topology_kubernetes_io_region="us-east-1"}, "instance", "$1", "node", "(.*)")) + 380` +
+				` or 0 * max by (instance) (kepler_node_info{namespace="power-monitoring"}) + 400`,
+			scenario: "regions with a default intensity",
+		},
+		{
+			spec: v1alpha1.CarbonIntensitySpec{
+				Enabled: true, Source: v1alpha1.ProviderCarbonIntensity,
+				Provider: &v1alpha1.CarbonIntensityProviderSpec{URL: "https://api.example.com"},
+			},
+			expr: `0 * max by (instance) (kepler_node_info{namespace="power-monitoring"})` +
+				` + on () group_left () max(last_over_time(grid_carbon_intensity_gco2e_per_kwh{namespace="power-monitoring", service="kepler-carbon-intensity"}[30m]))`,
+			scenario: "provider",
+		},
+		{
+			spec: v1alpha1.CarbonIntensitySpec{
+				Enabled: true, Source: v1alpha1.ProviderCarbonIntensity,
+				Provider: &v1alpha1.CarbonIntensityProviderSpec{URL: "https://api.example.com", Interval: "1h"},
+			},
+			expr: `0 * max by (instance) (kepler_node_info{namespace="power-monitoring"})` +
+				` + on () group_left () max(last_over_time(grid_carbon_intensity_gco2e_per_kwh{namespace="power-monitoring", service="kepler-carbon-intensity"}[2h]))`,
+			scenario: "provider queried hourly",
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			expr, err := nodeIntensity(newKeplerInternal(tc.spec))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expr, expr)
+		})
+	}
+}
+
+func TestPrometheusRule(t *testing.T) {
+	ki := newKeplerInternal(v1alpha1.CarbonIntensitySpec{Enabled: true, Intensity: ptr.To(int32(400))})
+	rule, err := NewPrometheusRule(components.Full, ki)
+	assert.NoError(t, err)
+
+	records := []string{}
+	for _, r := range rule.Spec.Groups[0].Rules {
+		records = append(records, r.Record)
+	}
+	assert.Equal(t, []string{
+		"node:kepler_grid_carbon_intensity:gco2e_per_kwh",
+		"container:kepler_container_gco2e:rate5m",
+		"namespace:kepler_container_gco2e:rate5m",
+		"cluster:kepler_container_gco2e:rate5m",
+	}, records)
+	assert.Contains(t, rule.Spec.Groups[0].Rules[1].Expr.String(), "/ 3600000 * on (instance)")
+
+	_, err = NewPrometheusRule(components.Full, newKeplerInternal(v1alpha1.CarbonIntensitySpec{Enabled: true}))
+	assert.Error(t, err)
+}
+
+func TestProviderExporter(t *testing.T) {
+	ki := newKeplerInternal(v1alpha1.CarbonIntensitySpec{
+		Enabled: true, Source: v1alpha1.ProviderCarbonIntensity,
+		Provider: &v1alpha1.CarbonIntensityProviderSpec{
+			URL:           "https://api.example.com/latest?zone=DE",
+			AuthSecretRef: "provider-token",
+		},
+	})
+	assert.True(t, NeedsExporter(ki))
+	assert.Equal(t, []string{"provider-token"}, PodSecrets(ki))
+
+	cm, err := NewConfigMap(components.Full, ki)
+	assert.NoError(t, err)
+	assert.Contains(t, cm.Data[configKey], "path: '{.carbonIntensity}'")
+	assert.Contains(t, cm.Data[configKey], "credentials_file: /var/run/secrets/carbon-intensity/token")
+
+	spec := NewDeployment(ki, cm).Spec.Template.Spec
+	assert.Equal(t, "json-exporter:test", spec.Containers[0].Image)
+	assert.Len(t, spec.Volumes, 2)
+	assert.Equal(t, "provider-token", spec.Volumes[1].Secret.SecretName)
+
+	endpoint := NewServiceMonitor(components.Full, ki).Spec.Endpoints[0]
+	assert.Equal(t, []string{"https://api.example.com/latest?zone=DE"}, endpoint.Params["target"])
+	assert.EqualValues(t, DefaultInterval, endpoint.Interval)
+
+	static := newKeplerInternal(v1alpha1.CarbonIntensitySpec{Enabled: true, Intensity: ptr.To(int32(400))})
+	assert.False(t, NeedsExporter(static))
+}
+
+func TestProviderAuthHeader(t *testing.T) {
+	ki := newKeplerInternal(v1alpha1.CarbonIntensitySpec{
+		Enabled: true, Source: v1alpha1.ProviderCarbonIntensity,
+		Provider: &v1alpha1.CarbonIntensityProviderSpec{
+			URL:           "https://api.electricitymap.org/v3/carbon-intensity/latest?zone=DE",
+			AuthSecretRef: "electricity-maps",
+			AuthHeader:    "auth-token",
+		},
+	})
+
+	cm, err := NewConfigMap(components.Full, ki)
+	assert.NoError(t, err)
+	assert.NotContains(t, cm.Data[configKey], "authorization")
+
+	spec := NewDeployment(ki, cm).Spec.Template.Spec
+	assert.Len(t, spec.Volumes, 3)
+	assert.Equal(t, "electricity-maps", spec.Volumes[1].Secret.SecretName)
+	assert.NotNil(t, spec.Volumes[2].EmptyDir)
+	container := spec.Containers[0]
+	assert.Equal(t, []string{"/bin/sh", "-c"}, container.Command)
+
+	// run the script against a copy of the config and the token, with the
+	// exporter replaced by a script printing the config it is run with
+	dir := t.TempDir()
+	exporter := filepath.Join(dir, "json_exporter")
+	assert.NoError(t, os.WriteFile(exporter, []byte("#!/bin/sh\ncat \"${1#--config.file=}\"\n"), 0o755))
+	for _, d := range []string{configPath, authPath, authConfigPath} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(dir, d), 0o755))
+	}
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, configPath, configKey), []byte(cm.Data[configKey]), 0o644))
+	token := `s3cr|t&'\`
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, authPath, authKey), []byte(token+"\n"), 0o644))
+
+	script := container.Args[0]
+	for _, d := range []string{configPath, authPath, authConfigPath} {
+		script = strings.ReplaceAll(script, d, filepath.Join(dir, d))
+	}
+	script = strings.ReplaceAll(script, "/bin/json_exporter", exporter)
+	out, err := exec.Command("/bin/sh", "-c", script).Output()
+	assert.NoError(t, err)
+
+	cfg := map[string]map[string]struct {
+		Headers map[string]string `json:"headers"`
+	}{}
+	assert.NoError(t, yaml.Unmarshal(out, &cfg))
+	assert.Equal(t, map[string]string{"auth-token": token}, cfg["modules"][module].Headers)
+}
+
+func TestSCIRules(t *testing.T) {
+	ki := newKeplerInternal(v1alpha1.CarbonIntensitySpec{Enabled: true, Intensity: ptr.To(int32(400))})
+	rule, err := NewPrometheusRule(components.Full, ki)
+	assert.NoError(t, err)
+	assert.Len(t, rule.Spec.Groups, 1)
+
+	ki = newKeplerInternal(v1alpha1.CarbonIntensitySpec{
+		Enabled: true, Intensity: ptr.To(int32(400)),
+		SCI: &v1alpha1.SCISpec{
+			Enabled:        true,
+			FunctionalUnit: &v1alpha1.SCIFunctionalUnitSpec{Metric: "http_requests_total", Label: "service"},
+		},
+	})
+	rule, err = NewPrometheusRule(components.Full, ki)
+	assert.NoError(t, err)
+	assert.Len(t, rule.Spec.Groups, 2)
+
+	rules := rule.Spec.Groups[1].Rules
+	assert.Len(t, rules, 2)
+	assert.Equal(t, "workload:kepler_container_gco2e:rate5m", rules[0].Record)
+	assert.Contains(t, rules[0].Expr.String(), `kube_pod_labels{label_app_kubernetes_io_name!=""}`)
+	assert.Contains(t, rules[0].Expr.String(), `"workload", "$1", "label_app_kubernetes_io_name", "(.*)"`)
+	assert.Equal(t, "workload:kepler_sci:gco2e_per_unit", rules[1].Record)
+	assert.Contains(t, rules[1].Expr.String(), `label_replace(rate(http_requests_total[5m]), "workload", "$1", "service", "(.*)")`)
+	assert.True(t, strings.HasPrefix(rules[1].Expr.String(), "workload:kepler_container_gco2e:rate5m / "))
+
+	t.Run("embodied", func(t *testing.T) {
+		ki := ki.DeepCopy()
+		ki.Spec.CarbonIntensity.Embodied = &v1alpha1.EmbodiedCarbonSpec{Enabled: true, KgCO2e: ptr.To(int32(1200))}
+		rule, err := NewPrometheusRule(components.Full, ki)
+		assert.NoError(t, err)
+		assert.Len(t, rule.Spec.Groups, 3)
+		assert.Equal(t, "kepler.sci", rule.Spec.Groups[2].Name)
+
+		rules := rule.Spec.Groups[2].Rules
+		assert.Len(t, rules, 3)
+		assert.Equal(t, "workload:kepler_container_lifecycle_gco2e:rate5m", rules[1].Record)
+		assert.True(t, strings.HasPrefix(rules[1].Expr.String(),
+			"sum by (container_namespace, workload) (container:kepler_container_lifecycle_gco2e:rate5m * "))
+		assert.True(t, strings.HasPrefix(rules[2].Expr.String(), "workload:kepler_container_lifecycle_gco2e:rate5m / "))
+	})
+}
+
+func TestEmbodiedRules(t *testing.T) {
+	ki := newKeplerInternal(v1alpha1.CarbonIntensitySpec{
+		Enabled: true, Intensity: ptr.To(int32(400)),
+		Embodied: &v1alpha1.EmbodiedCarbonSpec{Enabled: true},
+	})
+	assert.Error(t, Validate(ki))
+
+	ki.Spec.CarbonIntensity.Embodied = &v1alpha1.EmbodiedCarbonSpec{
+		Enabled:       true,
+		KgCO2e:        ptr.To(int32(1200)),
+		InstanceTypes: map[string]int32{"m5.large": 800},
+		Nodes:         map[string]int32{"gpu-0": 3000},
+	}
+	rule, err := NewPrometheusRule(components.Full, ki)
+	assert.NoError(t, err)
+	assert.Len(t, rule.Spec.Groups, 2)
+	assert.Equal(t, "kepler.embodied-carbon", rule.Spec.Groups[1].Name)
+
+	records := map[string]string{}
+	for _, r := range rule.Spec.Groups[1].Rules {
+		records[r.Record] = r.Expr.String()
+	}
+	assert.Equal(t,
+		`0 * max by (instance) (kepler_node_info{namespace="power-monitoring", instance="gpu-0"}) + 3000`+
+			` or 0 * max by (instance) (label_replace(kube_node_labels{label_node_kubernetes_io_instance_type="m5.large"}, "instance", "$1", "node", "(.*)")) + 800`+
+			` or 0 * max by (instance) (kepler_node_info{namespace="power-monitoring"}) + 1200`,
+		records["node:kepler_embodied_carbon:kgco2e"])
+	assert.Equal(t, `node:kepler_embodied_carbon:kgco2e * 1000 / 126144000`, records["node:kepler_embodied_gco2e:per_second"])
+	assert.Contains(t, records, "container:kepler_container_embodied_gco2e:rate5m")
+	assert.Contains(t, records, "namespace:kepler_container_lifecycle_gco2e:rate5m")
+
+	ki.Spec.CarbonIntensity.Embodied.AmortizationYears = 6
+	rule, err = NewPrometheusRule(components.Full, ki)
+	assert.NoError(t, err)
+	assert.Equal(t, `node:kepler_embodied_carbon:kgco2e * 1000 / 189216000`, rule.Spec.Groups[1].Rules[1].Expr.String())
+}
@@ -17,9 +17,18 @@ limitations under the License.
 package components
 
 import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 )
 
 type Detail int
@@ -29,7 +38,23 @@ const (
 	Metadata Detail = iota
 )
 
+const (
+	// AppArmorAnnotationPrefix is the prefix of the annotation that sets the
+	// AppArmor profile of a container of a pod
+	AppArmorAnnotationPrefix = "container.apparmor.security.beta.kubernetes.io/"
+
+	// PodSecurityEnforceLabel is the namespace label that sets the Pod
+	// Security Standard enforced by Pod Security Admission
+	PodSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+
+	// PodSecurityPrivileged is the Pod Security Standard required by kepler
+	PodSecurityPrivileged = "privileged"
+)
+
 var (
+	// nonDNSLabelRe matches characters not allowed in volume names
+	nonDNSLabelRe = regexp.MustCompile(`[^a-z0-9-]+`)
+
 	CommonLabels = k8s.StringMap{
 		"app.kubernetes.io/managed-by": "kepler-operator",
 	}
@@ -43,17 +68,165 @@ func NewNamespace(ns string) *corev1.Namespace {
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name: ns,
-			Labels: CommonLabels.Merge(k8s.StringMap{
-				// NOTE: Fixes the following error On Openshift 4.14
-				//   Warning  FailedCreate  daemonset-controller
-				//   Error creating: pods "kepler-exporter-ds-d6f28" is forbidden:
-				//   violates PodSecurity "restricted:latest":
-				//   privileged (container "kepler-exporter" must not set securityContext.privileged=true),
-				//   allowPrivilegeEscalation != false (container "kepler-exporter" must set
-				//   securityContext.allowPrivilegeEscalation=false),
-				"pod-security.kubernetes.io/enforce": "privileged",
-			}),
+			// NOTE: Fixes the following error On Openshift 4.14
+			//   Warning  FailedCreate  daemonset-controller
+			//   Error creating: pods "kepler-exporter-ds-d6f28" is forbidden:
+			//   violates PodSecurity "restricted:latest":
+			//   privileged (container "kepler-exporter" must not set securityContext.privileged=true),
+			//   allowPrivilegeEscalation != false (container "kepler-exporter" must set
+			//   securityContext.allowPrivilegeEscalation=false),
+			Labels: CommonLabels.Merge(PodSecurityLabels()),
 			//TODO: ensure in-cluster monitoring ignores this ns
 		},
 	}
 }
+
+// PodSecurityLabels returns the Pod Security Admission labels required by the
+// exporter; its host access (host PID, host path volumes) is permitted by the
+// privileged standard only, whatever its collection mode.
+// NOTE: the labels are applied with the namespace, which the operator owns
+// and deletes along with kepler, so there are no labels to restore on removal
+func PodSecurityLabels() k8s.StringMap {
+	return k8s.StringMap{
+		PodSecurityEnforceLabel: PodSecurityPrivileged,
+		// NOTE: avoids warnings for the privileged pods of kepler on
+		// clusters that warn or audit using a stricter standard
+		"pod-security.kubernetes.io/audit": PodSecurityPrivileged,
+		"pod-security.kubernetes.io/warn":  PodSecurityPrivileged,
+	}
+}
+
+// NetworkPolicyPorts returns the network policy ports for the ports of the
+// protocol
+func NetworkPolicyPorts(protocol corev1.Protocol, ports ...int32) []networkingv1.NetworkPolicyPort {
+	npp := []networkingv1.NetworkPolicyPort{}
+	for _, p := range ports {
+		npp = append(npp, networkingv1.NetworkPolicyPort{
+			Protocol: ptr.To(protocol),
+			Port:     ptr.To(intstr.FromInt32(p)),
+		})
+	}
+	return npp
+}
+
+// DNSEgressRule allows resolving names using the cluster DNS
+func DNSEgressRule() networkingv1.NetworkPolicyEgressRule {
+	// NOTE: the DNS pods on OpenShift listen on 5353
+	return networkingv1.NetworkPolicyEgressRule{
+		Ports: append(
+			NetworkPolicyPorts(corev1.ProtocolUDP, 53, 5353),
+			NetworkPolicyPorts(corev1.ProtocolTCP, 53, 5353)...,
+		),
+	}
+}
+
+// TCPEgressRule allows connecting to any destination on the TCP ports
+func TCPEgressRule(ports ...int32) networkingv1.NetworkPolicyEgressRule {
+	return networkingv1.NetworkPolicyEgressRule{
+		Ports: NetworkPolicyPorts(corev1.ProtocolTCP, ports...),
+	}
+}
+
+// NeedsPodDisruptionBudget returns true if an operand with the replicas has
+// to be protected by a PodDisruptionBudget
+func NeedsPodDisruptionBudget(replicas *int32) bool {
+	return replicas != nil && *replicas > 1
+}
+
+// NewPodDisruptionBudget returns a PodDisruptionBudget for the pods matching
+// the selector which keeps at least one pod available unless set in the spec
+func NewPodDisruptionBudget(name, namespace string, labels, selector k8s.StringMap, pdb *v1alpha1.PodDisruptionBudgetSpec) *policyv1.PodDisruptionBudget {
+	minAvailable := ptr.To(intstr.FromInt32(1))
+	if pdb != nil && pdb.MinAvailable != nil {
+		minAvailable = pdb.MinAvailable
+	}
+
+	return &policyv1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: policyv1.SchemeGroupVersion.String(),
+			Kind:       "PodDisruptionBudget",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable: minAvailable,
+			Selector:     &metav1.LabelSelector{MatchLabels: selector},
+		},
+	}
+}
+
+// ApplySecurityProfiles sets the seccomp profile of the pods and annotates the
+// pods with the AppArmor profile of each container
+func ApplySecurityProfiles(template *corev1.PodTemplateSpec, profiles *v1alpha1.SecurityProfilesSpec) {
+	if profiles == nil {
+		return
+	}
+
+	spec := &template.Spec
+	if profiles.SeccompProfile != nil {
+		if spec.SecurityContext == nil {
+			spec.SecurityContext = &corev1.PodSecurityContext{}
+		}
+		spec.SecurityContext.SeccompProfile = profiles.SeccompProfile
+	}
+
+	if profiles.AppArmorProfile == "" {
+		return
+	}
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for _, c := range containers {
+			template.Annotations[AppArmorAnnotationPrefix+c.Name] = profiles.AppArmorProfile
+		}
+	}
+}
+
+// ApplyReadOnlyRootFilesystem makes the root filesystem of the containers
+// read-only and mounts an emptyDir volume at each path a container writes to:
+// the scratch paths of the container by name and those of the spec. Paths a
+// container already mounts a volume at are left as is.
+func ApplyReadOnlyRootFilesystem(template *corev1.PodTemplateSpec, rofs *v1alpha1.ReadOnlyRootFilesystemSpec, scratchPaths map[string][]string) {
+	if !rofs.IsEnabled() {
+		return
+	}
+
+	spec := &template.Spec
+	volumes := map[string]bool{}
+	for i := range spec.Containers {
+		c := &spec.Containers[i]
+		if c.SecurityContext == nil {
+			c.SecurityContext = &corev1.SecurityContext{}
+		}
+		c.SecurityContext.ReadOnlyRootFilesystem = ptr.To(true)
+
+		paths := append(slices.Clone(scratchPaths[c.Name]), rofs.ScratchPaths...)
+		for _, path := range paths {
+			if slices.ContainsFunc(c.VolumeMounts, func(m corev1.VolumeMount) bool { return m.MountPath == path }) {
+				continue
+			}
+			name := scratchVolumeName(path)
+			c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{Name: name, MountPath: path})
+			if !volumes[name] {
+				volumes[name] = true
+				spec.Volumes = append(spec.Volumes, k8s.VolumeFromEmptyDir(name))
+			}
+		}
+	}
+}
+
+// scratchVolumeName returns the name of the volume mounted at the path,
+// e.g. scratch-var-lib-kepler for /var/lib/kepler
+func scratchVolumeName(path string) string {
+	name := strings.Trim(strings.ToLower(path), "/")
+	name = nonDNSLabelRe.ReplaceAllString(name, "-")
+	name = "scratch-" + name
+	if len(name) > 63 {
+		name = name[:63]
+	}
+	return strings.TrimRight(name, "-")
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consoleplugin
+
+import (
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
+
+	consolev1 "github.com/openshift/api/console/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	StableImage = "quay.io/sustainable_computing_io/kepler-console-plugin:v0.1.0"
+
+	Port = 9443
+
+	certSecretSuffix = "-cert"
+	certMountPath    = "/var/cert"
+)
+
+var (
+	// common labels for all resources of the console plugin
+	labels = components.CommonLabels.Merge(k8s.StringMap{
+		"app.kubernetes.io/component":  "console-plugin",
+		"sustainable-computing.io/app": "console-plugin",
+	})
+
+	podSelector = labels.Merge(k8s.StringMap{
+		"app.kubernetes.io/name": "console-plugin",
+	})
+)
+
+// Name returns the name of the console plugin and its deployment and service
+func Name(ki *v1alpha1.KeplerInternal) string {
+	return ki.Name + "-console-plugin"
+}
+
+// PodSecrets returns the names of the secrets used by the console plugin pods
+// which have to be redeployed when the secrets are rotated
+func PodSecrets(ki *v1alpha1.KeplerInternal) []string {
+	return []string{Name(ki) + certSecretSuffix}
+}
+
+func NewDeployment(ki *v1alpha1.KeplerInternal) *appsv1.Deployment {
+	name := Name(ki)
+	cp := ki.Spec.OpenShift.ConsolePlugin
+	replicas := ptr.To(int32(1))
+	if cp.Replicas != nil {
+		replicas = cp.Replicas
+	}
+
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+			Kind:       "Deployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ki.Namespace(),
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: podSelector},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: podSelector,
+				},
+				Spec: corev1.PodSpec{
+					AutomountServiceAccountToken: cp.AutomountServiceAccountToken,
+					Containers: []corev1.Container{{
+						Name:            "console-plugin",
+						Image:           cp.Image,
+						ImagePullPolicy: corev1.PullIfNotPresent,
+						Ports: []corev1.ContainerPort{{
+							ContainerPort: Port,
+							Name:          "https",
+							Protocol:      corev1.ProtocolTCP,
+						}},
+						SecurityContext: &corev1.SecurityContext{
+							AllowPrivilegeEscalation: ptr.To(false),
+							Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+						},
+						VolumeMounts: []corev1.VolumeMount{{
+							Name:      "cert",
+							MountPath: certMountPath,
+							ReadOnly:  true,
+						}},
+					}},
+					SecurityContext: &corev1.PodSecurityContext{
+						RunAsNonRoot:   ptr.To(true),
+						SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+					},
+					Volumes: []corev1.Volume{
+						k8s.VolumeFromSecret("cert", name+certSecretSuffix),
+					},
+				},
+			},
+		},
+	}
+}
+
+func NewService(ki *v1alpha1.KeplerInternal) *corev1.Service {
+	name := Name(ki)
+
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ki.Namespace(),
+			Labels:    labels,
+			Annotations: map[string]string{
+				// NOTE: the console requires plugins to be served over https
+				"service.beta.openshift.io/serving-cert-secret-name": name + certSecretSuffix,
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: podSelector,
+			Ports: []corev1.ServicePort{{
+				Name:       "https",
+				Port:       Port,
+				Protocol:   corev1.ProtocolTCP,
+				TargetPort: intstr.FromString("https"),
+			}},
+		},
+	}
+}
+
+// NewConsolePlugin returns the ConsolePlugin that registers the plugin served
+// by the service with the console; the plugin queries power metrics using
+// thanos-querier as the logged-in user
+func NewConsolePlugin(ki *v1alpha1.KeplerInternal) *consolev1.ConsolePlugin {
+	name := Name(ki)
+
+	return &consolev1.ConsolePlugin{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: consolev1.GroupVersion.String(),
+			Kind:       "ConsolePlugin",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+		Spec: consolev1.ConsolePluginSpec{
+			DisplayName: "Power Monitoring",
+			Backend: consolev1.ConsolePluginBackend{
+				Type: consolev1.Service,
+				Service: &consolev1.ConsolePluginService{
+					Name:      name,
+					Namespace: ki.Namespace(),
+					Port:      Port,
+					BasePath:  "/",
+				},
+			},
+			I18n: consolev1.ConsolePluginI18n{
+				LoadType: consolev1.Preload,
+			},
+			Proxy: []consolev1.ConsolePluginProxy{{
+				Alias: "thanos-querier",
+				Endpoint: consolev1.ConsolePluginProxyEndpoint{
+					Type: consolev1.ProxyTypeService,
+					Service: &consolev1.ConsolePluginProxyServiceConfig{
+						Name:      "thanos-querier",
+						Namespace: "openshift-monitoring",
+						Port:      9091,
+					},
+				},
+				Authorization: consolev1.UserToken,
+			}},
+		},
+	}
+}
+
+// NewPodDisruptionBudget returns the PodDisruptionBudget of the console
+// plugin which is required only when it runs more than one replica
+func NewPodDisruptionBudget(ki *v1alpha1.KeplerInternal) *policyv1.PodDisruptionBudget {
+	return components.NewPodDisruptionBudget(Name(ki), ki.Namespace(), labels, podSelector,
+		ki.Spec.OpenShift.ConsolePlugin.PodDisruptionBudget)
+}
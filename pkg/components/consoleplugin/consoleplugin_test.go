@@ -0,0 +1,86 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consoleplugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+func TestConsolePlugin(t *testing.T) {
+	ki := &v1alpha1.KeplerInternal{
+		ObjectMeta: metav1.ObjectMeta{Name: "kepler"},
+		Spec: v1alpha1.KeplerInternalSpec{
+			Exporter: v1alpha1.InternalExporterSpec{
+				Deployment: v1alpha1.InternalExporterDeploymentSpec{Namespace: "power-monitoring"},
+			},
+			OpenShift: v1alpha1.OpenShiftSpec{
+				Enabled:       true,
+				ConsolePlugin: v1alpha1.InternalConsolePluginSpec{Enabled: true, Image: "console-plugin:test"},
+			},
+		},
+	}
+
+	deploy := NewDeployment(ki)
+	svc := NewService(ki)
+	plugin := NewConsolePlugin(ki)
+
+	assert.Equal(t, "power-monitoring", deploy.Namespace)
+	assert.Equal(t, "console-plugin:test", deploy.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, map[string]string(deploy.Spec.Template.Labels), svc.Spec.Selector)
+
+	// NOTE: the serving certificate created for the service is mounted by the pods
+	certSecret := svc.Annotations["service.beta.openshift.io/serving-cert-secret-name"]
+	assert.Equal(t, certSecret, deploy.Spec.Template.Spec.Volumes[0].Secret.SecretName)
+
+	backend := plugin.Spec.Backend.Service
+	assert.Equal(t, svc.Name, backend.Name)
+	assert.Equal(t, svc.Namespace, backend.Namespace)
+	assert.Equal(t, svc.Spec.Ports[0].Port, backend.Port)
+}
+
+func TestPodDisruptionBudget(t *testing.T) {
+	minAvailable := intstr.FromString("50%")
+	ki := &v1alpha1.KeplerInternal{
+		ObjectMeta: metav1.ObjectMeta{Name: "kepler"},
+		Spec: v1alpha1.KeplerInternalSpec{
+			Exporter: v1alpha1.InternalExporterSpec{
+				Deployment: v1alpha1.InternalExporterDeploymentSpec{Namespace: "power-monitoring"},
+			},
+			OpenShift: v1alpha1.OpenShiftSpec{
+				Enabled: true,
+				ConsolePlugin: v1alpha1.InternalConsolePluginSpec{
+					Enabled:             true,
+					Replicas:            ptr.To(int32(2)),
+					PodDisruptionBudget: &v1alpha1.PodDisruptionBudgetSpec{MinAvailable: &minAvailable},
+				},
+			},
+		},
+	}
+
+	deploy := NewDeployment(ki)
+	pdb := NewPodDisruptionBudget(ki)
+
+	assert.Equal(t, int32(2), *deploy.Spec.Replicas)
+	assert.Equal(t, deploy.Spec.Selector.MatchLabels, pdb.Spec.Selector.MatchLabels)
+	assert.Equal(t, minAvailable, *pdb.Spec.MinAvailable)
+}
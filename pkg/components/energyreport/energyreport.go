@@ -0,0 +1,298 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package energyreport
+
+import (
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components/exporter"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// Command is the subcommand of the operator binary writing a report
+	Command = "report"
+
+	// TokenKey is the key of the bearer token in the secret of Prometheus
+	TokenKey = "token"
+
+	// AccessKeyIDKey and SecretAccessKeyKey are the keys of the credentials
+	// in the secret of the S3 bucket
+	AccessKeyIDKey     = "access_key_id"
+	SecretAccessKeyKey = "secret_access_key"
+
+	weeklySchedule  = "0 0 * * 1"
+	monthlySchedule = "0 0 1 * *"
+
+	serviceCAVolume = "service-ca"
+	serviceCAPath   = "/etc/energy-report-ca"
+)
+
+var (
+	// common labels for all resources of the energy report
+	labels = components.CommonLabels.Merge(k8s.StringMap{
+		"app.kubernetes.io/component":  "energy-report",
+		"sustainable-computing.io/app": "energy-report",
+	})
+)
+
+// Name returns the name of the CronJob, of the ConfigMap the reports are
+// written to and of the RBAC resources of the CronJob
+func Name(ki *v1alpha1.KeplerInternal) string {
+	return ki.Name + "-energy-report"
+}
+
+// Schedule returns the cron schedule of the reports
+func Schedule(ki *v1alpha1.KeplerInternal) string {
+	if spec(ki).Schedule == v1alpha1.MonthlyEnergyReport {
+		return monthlySchedule
+	}
+	return weeklySchedule
+}
+
+// NewServiceAccount returns the service account the reports are written by
+func NewServiceAccount(ki *v1alpha1.KeplerInternal) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "ServiceAccount",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      Name(ki),
+			Namespace: ki.Namespace(),
+			Labels:    labels,
+		},
+	}
+}
+
+// NewRole returns the role that allows the CronJob to write the ConfigMap of
+// the reports only
+func NewRole(d components.Detail, ki *v1alpha1.KeplerInternal) *rbacv1.Role {
+	role := &rbacv1.Role{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: rbacv1.SchemeGroupVersion.String(),
+			Kind:       "Role",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      Name(ki),
+			Namespace: ki.Namespace(),
+			Labels:    labels,
+		},
+	}
+	if d == components.Metadata {
+		return role
+	}
+
+	// NOTE: create cannot be restricted to a name as the name is not known
+	// when the request is authorized
+	role.Rules = []rbacv1.PolicyRule{{
+		APIGroups: []string{""},
+		Resources: []string{"configmaps"},
+		Verbs:     []string{"create"},
+	}, {
+		APIGroups:     []string{""},
+		Resources:     []string{"configmaps"},
+		ResourceNames: []string{Name(ki)},
+		Verbs:         []string{"get", "update"},
+	}}
+	return role
+}
+
+// NewRoleBinding returns the binding of the role to the service account of
+// the CronJob
+func NewRoleBinding(ki *v1alpha1.KeplerInternal) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: rbacv1.SchemeGroupVersion.String(),
+			Kind:       "RoleBinding",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      Name(ki),
+			Namespace: ki.Namespace(),
+			Labels:    labels,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     Name(ki),
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      "ServiceAccount",
+			Name:      Name(ki),
+			Namespace: ki.Namespace(),
+		}},
+	}
+}
+
+// NewCronJob returns the CronJob that runs the report command of the operator
+// image on the schedule in the spec
+func NewCronJob(d components.Detail, ki *v1alpha1.KeplerInternal) *batchv1.CronJob {
+	cj := &batchv1.CronJob{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: batchv1.SchemeGroupVersion.String(),
+			Kind:       "CronJob",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      Name(ki),
+			Namespace: ki.Namespace(),
+			Labels:    labels,
+		},
+	}
+	if d == components.Metadata {
+		return cj
+	}
+
+	er := spec(ki)
+	cj.Spec = batchv1.CronJobSpec{
+		Schedule:                   Schedule(ki),
+		ConcurrencyPolicy:          batchv1.ForbidConcurrent,
+		SuccessfulJobsHistoryLimit: ptr.To(int32(1)),
+		FailedJobsHistoryLimit:     ptr.To(int32(3)),
+		JobTemplate: batchv1.JobTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: labels},
+			Spec: batchv1.JobSpec{
+				BackoffLimit: ptr.To(int32(3)),
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec: corev1.PodSpec{
+						ServiceAccountName: Name(ki),
+						// NOTE: the token is used to write the ConfigMap only
+						AutomountServiceAccountToken: ptr.To(er.S3 == nil),
+						RestartPolicy:                corev1.RestartPolicyOnFailure,
+						Volumes:                      volumes(ki),
+						Containers: []corev1.Container{{
+							Name:            "energy-report",
+							Image:           er.Image,
+							ImagePullPolicy: corev1.PullIfNotPresent,
+							Args:            args(ki),
+							Env:             env(ki),
+							VolumeMounts:    volumeMounts(ki),
+							SecurityContext: &corev1.SecurityContext{
+								AllowPrivilegeEscalation: ptr.To(false),
+								ReadOnlyRootFilesystem:   ptr.To(true),
+								RunAsNonRoot:             ptr.To(true),
+								Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+							},
+						}},
+					},
+				},
+			},
+		},
+	}
+	return cj
+}
+
+// args returns the arguments of the report command
+func args(ki *v1alpha1.KeplerInternal) []string {
+	er := spec(ki)
+	format := er.Format
+	if format == "" {
+		format = v1alpha1.JSONEnergyReport
+	}
+	schedule := er.Schedule
+	if schedule == "" {
+		schedule = v1alpha1.WeeklyEnergyReport
+	}
+
+	args := []string{
+		Command,
+		"--prometheus-url=" + er.Prometheus.URL,
+		"--namespace=" + ki.Namespace(),
+		"--schedule=" + string(schedule),
+		"--format=" + string(format),
+	}
+	if ki.Spec.OpenShift.Enabled {
+		args = append(args, "--ca-file="+serviceCAPath+"/"+exporter.ServiceCAKey)
+	}
+	// NOTE: emissions are recorded by the carbon intensity rules only
+	if ki.Spec.CarbonIntensity.IsEnabled() {
+		args = append(args, "--emissions")
+	}
+
+	s3 := er.S3
+	if s3 == nil {
+		return append(args, "--configmap="+ki.Namespace()+"/"+Name(ki))
+	}
+	region := s3.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	return append(args,
+		"--s3-endpoint="+s3.Endpoint,
+		"--s3-region="+region,
+		"--s3-bucket="+s3.Bucket,
+		"--s3-prefix="+s3.Prefix,
+	)
+}
+
+// env returns the credentials of Prometheus and of the bucket read from the
+// secrets in the spec
+func env(ki *v1alpha1.KeplerInternal) []corev1.EnvVar {
+	er := spec(ki)
+	env := []corev1.EnvVar{}
+	if ref := er.Prometheus.SecretRef; ref != "" {
+		env = append(env, secretEnv("PROMETHEUS_TOKEN", ref, TokenKey))
+	}
+	if s3 := er.S3; s3 != nil {
+		env = append(env,
+			secretEnv("AWS_ACCESS_KEY_ID", s3.SecretRef, AccessKeyIDKey),
+			secretEnv("AWS_SECRET_ACCESS_KEY", s3.SecretRef, SecretAccessKeyKey),
+		)
+	}
+	return env
+}
+
+// volumes returns the service CA of OpenShift which signs the certificate of
+// thanos-querier and of any service in the cluster
+func volumes(ki *v1alpha1.KeplerInternal) []corev1.Volume {
+	if !ki.Spec.OpenShift.Enabled {
+		return nil
+	}
+	return []corev1.Volume{k8s.VolumeFromConfigMap(serviceCAVolume, exporter.ServiceCAConfigMap)}
+}
+
+func volumeMounts(ki *v1alpha1.KeplerInternal) []corev1.VolumeMount {
+	if !ki.Spec.OpenShift.Enabled {
+		return nil
+	}
+	return []corev1.VolumeMount{{Name: serviceCAVolume, MountPath: serviceCAPath, ReadOnly: true}}
+}
+
+func secretEnv(name, secret, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secret},
+				Key:                  key,
+			},
+		},
+	}
+}
+
+func spec(ki *v1alpha1.KeplerInternal) *v1alpha1.InternalEnergyReportSpec {
+	if ki.Spec.EnergyReport == nil {
+		return &v1alpha1.InternalEnergyReportSpec{}
+	}
+	return ki.Spec.EnergyReport
+}
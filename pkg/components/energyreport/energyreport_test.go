@@ -0,0 +1,136 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package energyreport
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newKeplerInternal(er v1alpha1.EnergyReportSpec) *v1alpha1.KeplerInternal {
+	return &v1alpha1.KeplerInternal{
+		ObjectMeta: metav1.ObjectMeta{Name: "kepler"},
+		Spec: v1alpha1.KeplerInternalSpec{
+			Exporter: v1alpha1.InternalExporterSpec{
+				Deployment: v1alpha1.InternalExporterDeploymentSpec{
+					Namespace: "power-monitoring",
+				},
+			},
+			EnergyReport: &v1alpha1.InternalEnergyReportSpec{
+				EnergyReportSpec: er,
+				Image:            "kepler-operator:test",
+			},
+		},
+	}
+}
+
+func TestCronJobConfigMap(t *testing.T) {
+	ki := newKeplerInternal(v1alpha1.EnergyReportSpec{
+		Enabled:    true,
+		Prometheus: v1alpha1.EnergyReportPrometheusSpec{URL: "https://thanos-querier:9091", SecretRef: "prometheus-token"},
+	})
+	ki.Spec.CarbonIntensity = &v1alpha1.InternalCarbonIntensitySpec{
+		CarbonIntensitySpec: v1alpha1.CarbonIntensitySpec{Enabled: true},
+	}
+
+	cj := NewCronJob(components.Full, ki)
+	assert.Equal(t, "0 0 * * 1", cj.Spec.Schedule)
+
+	pod := cj.Spec.JobTemplate.Spec.Template.Spec
+	assert.Equal(t, "kepler-energy-report", pod.ServiceAccountName)
+	assert.True(t, *pod.AutomountServiceAccountToken)
+
+	c := pod.Containers[0]
+	assert.Equal(t, "kepler-operator:test", c.Image)
+	assert.Equal(t, []string{
+		"report",
+		"--prometheus-url=https://thanos-querier:9091",
+		"--namespace=power-monitoring",
+		"--schedule=Weekly",
+		"--format=JSON",
+		"--emissions",
+		"--configmap=power-monitoring/kepler-energy-report",
+	}, c.Args)
+	assert.Len(t, c.Env, 1)
+	assert.Equal(t, "prometheus-token", c.Env[0].ValueFrom.SecretKeyRef.Name)
+	assert.Empty(t, pod.Volumes)
+
+	role := NewRole(components.Full, ki)
+	assert.Equal(t, []string{"kepler-energy-report"}, role.Rules[1].ResourceNames)
+}
+
+func TestCronJobS3(t *testing.T) {
+	ki := newKeplerInternal(v1alpha1.EnergyReportSpec{
+		Enabled:    true,
+		Schedule:   v1alpha1.MonthlyEnergyReport,
+		Format:     v1alpha1.CSVEnergyReport,
+		Prometheus: v1alpha1.EnergyReportPrometheusSpec{URL: "http://prometheus:9090"},
+		S3: &v1alpha1.EnergyReportS3Spec{
+			Endpoint:  "https://s3.eu-west-1.amazonaws.com",
+			Region:    "eu-west-1",
+			Bucket:    "sustainability",
+			Prefix:    "kepler/",
+			SecretRef: "s3-credentials",
+		},
+	})
+
+	cj := NewCronJob(components.Full, ki)
+	assert.Equal(t, "0 0 1 * *", cj.Spec.Schedule)
+
+	pod := cj.Spec.JobTemplate.Spec.Template.Spec
+	assert.False(t, *pod.AutomountServiceAccountToken)
+
+	c := pod.Containers[0]
+	assert.Equal(t, []string{
+		"report",
+		"--prometheus-url=http://prometheus:9090",
+		"--namespace=power-monitoring",
+		"--schedule=Monthly",
+		"--format=CSV",
+		"--s3-endpoint=https://s3.eu-west-1.amazonaws.com",
+		"--s3-region=eu-west-1",
+		"--s3-bucket=sustainability",
+		"--s3-prefix=kepler/",
+	}, c.Args)
+
+	env := map[string]string{}
+	for _, e := range c.Env {
+		env[e.Name] = e.ValueFrom.SecretKeyRef.Name + "/" + e.ValueFrom.SecretKeyRef.Key
+	}
+	assert.Equal(t, map[string]string{
+		"AWS_ACCESS_KEY_ID":     "s3-credentials/access_key_id",
+		"AWS_SECRET_ACCESS_KEY": "s3-credentials/secret_access_key",
+	}, env)
+}
+
+func TestCronJobOpenShiftServiceCA(t *testing.T) {
+	ki := newKeplerInternal(v1alpha1.EnergyReportSpec{
+		Enabled:    true,
+		Prometheus: v1alpha1.EnergyReportPrometheusSpec{URL: "https://thanos-querier.openshift-monitoring.svc:9091"},
+	})
+	ki.Spec.OpenShift.Enabled = true
+
+	pod := NewCronJob(components.Full, ki).Spec.JobTemplate.Spec.Template.Spec
+	assert.Equal(t, "openshift-service-ca.crt", pod.Volumes[0].ConfigMap.Name)
+	c := pod.Containers[0]
+	assert.Equal(t, "/etc/energy-report-ca", c.VolumeMounts[0].MountPath)
+	assert.Contains(t, c.Args, "--ca-file=/etc/energy-report-ca/service-ca.crt")
+}
@@ -24,9 +24,12 @@ import (
 	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
+	ContainerName = "estimator"
+
 	// NOTE: update tests/images.yaml when changing this image
 	StableImage          = "quay.io/sustainable_computing_io/kepler_model_server:v0.7.7"
 	waitForSocketCommand = "until [ -e /tmp/estimator.sock ]; do sleep 1; done && %s"
@@ -57,6 +60,22 @@ func NeedsEstimatorSidecar(es *v1alpha1.InternalEstimatorSpec) bool {
 	return false
 }
 
+// InitURLs returns the urls of the models the estimator sidecar downloads
+func InitURLs(es *v1alpha1.InternalEstimatorSpec) []string {
+	if !NeedsEstimatorSidecar(es) {
+		return nil
+	}
+	urls := []string{}
+	for _, config := range []*v1alpha1.EstimatorConfig{
+		es.Node.Total, es.Node.Components, es.Container.Total, es.Container.Components,
+	} {
+		if config != nil && config.SidecarEnabled && config.InitUrl != "" {
+			urls = append(urls, config.InitUrl)
+		}
+	}
+	return urls
+}
+
 // Container returns sidecar container
 func Container(image string) corev1.Container {
 	mounts := []corev1.VolumeMount{{
@@ -74,13 +93,17 @@ func Container(image string) corev1.Container {
 	return corev1.Container{
 		Image:           image,
 		ImagePullPolicy: corev1.PullIfNotPresent,
-		Name:            "estimator",
+		Name:            ContainerName,
 		VolumeMounts:    mounts,
 		Command:         []string{"python3.8"},
 		Args:            []string{"-u", "src/estimate/estimator.py"},
 	}
 }
 
+// ScratchPaths are the paths the estimator writes to; both are already
+// mounted as emptyDir volumes
+var ScratchPaths = []string{"/tmp", "/mnt"}
+
 // Volumes returns sidecar additional volumes
 func Volumes() []corev1.Volume {
 	return []corev1.Volume{
@@ -138,3 +161,65 @@ func ModelConfig(es *v1alpha1.InternalEstimatorSpec) string {
 
 	return builder.String()
 }
+
+func modelStatus(modelType string, spec *v1alpha1.EstimatorConfig) (v1alpha1.ModelStatus, bool) {
+	if spec == nil {
+		return v1alpha1.ModelStatus{}, false
+	}
+	model := v1alpha1.ModelStatus{Type: modelType, InitURL: spec.InitUrl}
+	if spec.Selector != nil {
+		model.Name = spec.Selector.ModelName
+	}
+	return model, model.Name != "" || model.InitURL != ""
+}
+
+// Models returns the models configured for each type of power estimated
+func Models(es *v1alpha1.InternalEstimatorSpec) []v1alpha1.ModelStatus {
+	if es == nil {
+		return nil
+	}
+
+	models := []v1alpha1.ModelStatus{}
+	for _, m := range []struct {
+		modelType string
+		spec      *v1alpha1.EstimatorConfig
+	}{
+		{"NODE_TOTAL", es.Node.Total},
+		{"NODE_COMPONENTS", es.Node.Components},
+		{"CONTAINER_TOTAL", es.Container.Total},
+		{"CONTAINER_COMPONENTS", es.Container.Components},
+	} {
+		if model, ok := modelStatus(m.modelType, m.spec); ok {
+			models = append(models, model)
+		}
+	}
+	return models
+}
+
+// SidecarStatus returns the number of pods in which the estimator sidecar is
+// ready along with the most recent error reported by the sidecars
+func SidecarStatus(pods []corev1.Pod) (int32, string) {
+	ready := int32(0)
+	lastError := ""
+	var lastErrorAt metav1.Time
+
+	for _, pod := range pods {
+		for _, c := range pod.Status.ContainerStatuses {
+			if c.Name != ContainerName {
+				continue
+			}
+			if c.Ready {
+				ready++
+			}
+
+			if w := c.State.Waiting; w != nil && w.Message != "" && lastErrorAt.IsZero() {
+				lastError = fmt.Sprintf("%s: %s: %s", pod.Name, w.Reason, w.Message)
+			}
+			if t := c.LastTerminationState.Terminated; t != nil && t.ExitCode != 0 && lastErrorAt.Before(&t.FinishedAt) {
+				lastErrorAt = t.FinishedAt
+				lastError = fmt.Sprintf("%s: %s: %s", pod.Name, t.Reason, t.Message)
+			}
+		}
+	}
+	return ready, lastError
+}
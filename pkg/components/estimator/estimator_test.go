@@ -18,11 +18,13 @@ package estimator
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func sidecarEnabledSpec() *v1alpha1.EstimatorConfig {
@@ -155,3 +157,113 @@ func TestModifiedContainer(t *testing.T) {
 		assert.Equal(t, exporterVolumeMounts[0].Name, "tmp")
 	})
 }
+
+func TestModels(t *testing.T) {
+
+	tt := []struct {
+		spec     *v1alpha1.InternalEstimatorSpec
+		models   []v1alpha1.ModelStatus
+		scenario string
+	}{
+		{
+			spec:     nil,
+			models:   nil,
+			scenario: "estimator not configured",
+		},
+		{
+			spec:     &v1alpha1.InternalEstimatorSpec{},
+			models:   []v1alpha1.ModelStatus{},
+			scenario: "no models configured",
+		},
+		{
+			spec: &v1alpha1.InternalEstimatorSpec{
+				Node: v1alpha1.EstimatorGroup{
+					Total: sidecarEnabledSpec(),
+					Components: &v1alpha1.EstimatorConfig{
+						Selector: &v1alpha1.ModelSelectorSpec{ModelName: "fake-model"},
+					},
+				},
+				Container: v1alpha1.EstimatorGroup{
+					Total: &v1alpha1.EstimatorConfig{SidecarEnabled: true},
+				},
+			},
+			models: []v1alpha1.ModelStatus{
+				{Type: "NODE_TOTAL", InitURL: "fake-url.zip"},
+				{Type: "NODE_COMPONENTS", Name: "fake-model"},
+			},
+			scenario: "models selected by name and init url",
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.models, Models(tc.spec))
+		})
+	}
+}
+
+func TestSidecarStatus(t *testing.T) {
+
+	earlier := metav1.NewTime(time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC))
+	later := metav1.NewTime(earlier.Add(time.Minute))
+
+	estimatorStatus := func(ready bool, state corev1.ContainerState, last corev1.ContainerState) corev1.ContainerStatus {
+		return corev1.ContainerStatus{Name: ContainerName, Ready: ready, State: state, LastTerminationState: last}
+	}
+	pod := func(name string, statuses ...corev1.ContainerStatus) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     corev1.PodStatus{ContainerStatuses: statuses},
+		}
+	}
+	terminated := func(at metav1.Time, msg string) corev1.ContainerState {
+		return corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			ExitCode: 1, Reason: "Error", Message: msg, FinishedAt: at,
+		}}
+	}
+	running := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+
+	tt := []struct {
+		pods      []corev1.Pod
+		ready     int32
+		lastError string
+		scenario  string
+	}{
+		{
+			pods:     nil,
+			scenario: "no pods",
+		},
+		{
+			pods: []corev1.Pod{
+				pod("kepler-a", corev1.ContainerStatus{Name: "kepler", Ready: true},
+					estimatorStatus(true, running, corev1.ContainerState{})),
+				pod("kepler-b", estimatorStatus(true, running, corev1.ContainerState{})),
+			},
+			ready:    2,
+			scenario: "all sidecars ready",
+		},
+		{
+			pods: []corev1.Pod{
+				pod("kepler-a", estimatorStatus(false, corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff", Message: "back-off"},
+				}, terminated(earlier, "model not found"))),
+				pod("kepler-b", estimatorStatus(true, running, terminated(later, "socket closed"))),
+			},
+			ready:     1,
+			lastError: "kepler-b: Error: socket closed",
+			scenario:  "most recent termination is reported",
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			ready, lastError := SidecarStatus(tc.pods)
+			assert.Equal(t, tc.ready, ready)
+			assert.Equal(t, tc.lastError, lastError)
+		})
+	}
+}
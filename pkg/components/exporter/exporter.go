@@ -18,18 +18,25 @@ package exporter
 
 import (
 	_ "embed"
+	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/components"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/components/estimator"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/components/modelserver"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/features"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
 
+	"github.com/cespare/xxhash/v2"
 	secv1 "github.com/openshift/api/security/v1"
 	monv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/common/model"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -42,18 +49,28 @@ import (
 const (
 	ServicePortName = "http"
 
+	// DefaultScrapeInterval is the interval at which the exporter is scraped
+	// unless it is set in the spec
+	DefaultScrapeInterval monv1.Duration = "3s"
+
 	overviewDashboardName = "power-monitoring-overview"
 	nsInfoDashboardName   = "power-monitoring-by-ns"
 	DashboardNs           = "openshift-config-managed"
 
-	RedfishArgs             = "-redfish-cred-file-path=/etc/redfish/redfish.csv"
-	RedfishCSV              = "redfish.csv"
-	RedfishSecretAnnotation = "kepler.system.sustainable.computing.io/redfish-secret-ref"
-	RedfishConfigHash       = "kepler.system.sustainable.computing.io/redfish-config-hash"
+	RedfishArgs       = "-redfish-cred-file-path=/etc/redfish/redfish.csv"
+	RedfishCSV        = "redfish.csv"
+	RedfishConfigHash = "kepler.system.sustainable.computing.io/redfish-config-hash"
+
+	// ConfigHashAnnotation is set on the pods of the kepler daemonset so that
+	// the pods are redeployed only when the kepler configuration changes
+	ConfigHashAnnotation = "kepler.system.sustainable.computing.io/config-hash"
 )
 
 const (
 	KeplerContainerIndex k8s.ContainerIndex = 0
+
+	// libvirtRunDir is where kepler looks up the virtual machines of libvirt
+	libvirtRunDir = "/var/run/libvirt"
 )
 
 var (
@@ -85,14 +102,15 @@ func NewDaemonSet(detail components.Detail, k *v1alpha1.KeplerInternal) *appsv1.
 	}
 
 	deployment := k.Spec.Exporter.Deployment.ExporterDeploymentSpec
-	nodeSelector := deployment.NodeSelector
+	nodeSelector := capableNodeSelector(k).Merge(deployment.NodeSelector)
 	tolerations := deployment.Tolerations
 	// NOTE: since 2 or more KeplerInternals can be deployed to the same namespace,
 	// we need to make sure that the pod selector of each of the DaemonSet
 	// create of each kepler is unique. Thus the daemonset name is added as
 	// label to the pod
 
-	exporterContainer := newExporterContainer(k.Name, k.DaemonsetName(), k.Spec.Exporter.Deployment)
+	exporterContainer := newExporterContainer(k.Name, k.DaemonsetName(), k.Spec.Exporter.Deployment,
+		k.Spec.Exporter.CollectionMode)
 	containers := []corev1.Container{exporterContainer}
 
 	var volumes = []corev1.Volume{
@@ -108,8 +126,19 @@ func NewDaemonSet(detail components.Detail, k *v1alpha1.KeplerInternal) *appsv1.
 		// add shared volumes
 		containers, volumes = addEstimatorSidecar(k.Spec.Estimator.Image, &exporterContainer, volumes)
 	}
+	if vms := k.Spec.Exporter.VirtualMachines; vms.IsEnabled() {
+		volumes = mountLibvirtRunDir(vms, &exporterContainer, volumes)
+		containers[KeplerContainerIndex] = exporterContainer
+	}
+	if k.Spec.Exporter.RBACProxy.IsEnabled() {
+		containers, volumes = addRBACProxySidecar(k, &exporterContainer, containers, volumes)
+	}
 
-	return &appsv1.DaemonSet{
+	// NOTE: only the data of the configmap is hashed, so the configmap
+	// itself is not built
+	annotations := podScrapeAnnotations(k).AddIfNotEmpty(ConfigHashAnnotation, ConfigHash(exporterConfig(k)))
+
+	ds := &appsv1.DaemonSet{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: appsv1.SchemeGroupVersion.String(),
 			Kind:       "DaemonSet",
@@ -123,9 +152,10 @@ func NewDaemonSet(detail components.Detail, k *v1alpha1.KeplerInternal) *appsv1.
 			Selector: &metav1.LabelSelector{MatchLabels: podSelector(k)},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      k.DaemonsetName(),
-					Namespace: k.Namespace(),
-					Labels:    podSelector(k),
+					Name:        k.DaemonsetName(),
+					Namespace:   k.Namespace(),
+					Labels:      podSelector(k),
+					Annotations: annotations,
 				},
 				Spec: corev1.PodSpec{
 					HostPID:            true,
@@ -139,6 +169,33 @@ func NewDaemonSet(detail components.Detail, k *v1alpha1.KeplerInternal) *appsv1.
 			}, // PodTemplateSpec
 		}, // Spec
 	}
+	if k.Spec.ModelVerification != nil && estimator.NeedsEstimatorSidecar(k.Spec.Estimator) {
+		components.AddModelDigestCheck(&ds.Spec.Template, k.Spec.Estimator.Image, k.Status.VerifiedModels,
+			estimator.InitURLs(k.Spec.Estimator)...)
+	}
+	components.ApplySecurityProfiles(&ds.Spec.Template, deployment.SecurityProfiles)
+	components.ApplyReadOnlyRootFilesystem(&ds.Spec.Template, deployment.ReadOnlyRootFilesystem, map[string][]string{
+		k.DaemonsetName():       keplerScratchPaths,
+		estimator.ContainerName: estimator.ScratchPaths,
+	})
+	return ds
+}
+
+// keplerScratchPaths are the paths kepler writes to: temporary files and the
+// power models it downloads
+var keplerScratchPaths = []string{"/tmp", "/var/lib/kepler/data"}
+
+// mountLibvirtRunDir mounts the libvirt run directory of the node which kepler
+// uses to map qemu processes to virtual machines
+func mountLibvirtRunDir(vms *v1alpha1.VirtualMachinesSpec, exporterContainer *corev1.Container, volumes []corev1.Volume) []corev1.Volume {
+	dir := vms.LibvirtRunDir
+	if dir == "" {
+		dir = libvirtRunDir
+	}
+	exporterContainer.VolumeMounts = append(exporterContainer.VolumeMounts,
+		corev1.VolumeMount{Name: "libvirt", MountPath: libvirtRunDir, ReadOnly: true},
+	)
+	return append(volumes, k8s.VolumeFromHost("libvirt", dir))
 }
 
 func MountRedfishSecretToDaemonSet(ds *appsv1.DaemonSet, secret *corev1.Secret, hash uint64) {
@@ -151,12 +208,92 @@ func MountRedfishSecretToDaemonSet(ds *appsv1.DaemonSet, secret *corev1.Secret,
 	spec.Volumes = append(spec.Volumes,
 		k8s.VolumeFromSecret("redfish-cred", secret.ObjectMeta.Name))
 
-	// NOTE: annotating the Pods with the secret's resource version
-	// forces pods to be redeployed if the secret change
-	ds.Spec.Template.Annotations = map[string]string{
-		RedfishSecretAnnotation: secret.ResourceVersion,
-		RedfishConfigHash:       strconv.FormatUint(hash, 10),
+	// NOTE: pods are redeployed when the secret changes since they are
+	// annotated with a hash of the secrets they use; see PodSecrets
+	if ds.Spec.Template.Annotations == nil {
+		ds.Spec.Template.Annotations = map[string]string{}
+	}
+	ds.Spec.Template.Annotations[RedfishConfigHash] = strconv.FormatUint(hash, 10)
+}
+
+// PodSecrets returns the names of the secrets used by the kepler pods which
+// have to be redeployed when the secrets are rotated
+func PodSecrets(k *v1alpha1.KeplerInternal) []string {
+	secrets := []string{}
+	if rf := k.Spec.Exporter.Redfish; rf != nil {
+		secrets = append(secrets, rf.SecretRef)
+	}
+	if k.Spec.Exporter.RBACProxy.IsEnabled() {
+		secrets = append(secrets, rbacProxyTLSSecret(k))
+	}
+	return secrets
+}
+
+// MountRedfishConfigToConfigMap adds the redfish configuration to the kepler
+// configmap
+func MountRedfishConfigToConfigMap(cm *corev1.ConfigMap, rf *v1alpha1.RedfishSpec) {
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data["REDFISH_PROBE_INTERVAL_IN_SECONDS"] = strconv.FormatFloat(rf.ProbeInterval.Duration.Seconds(), 'f', 0, 64)
+	cm.Data["REDFISH_SKIP_SSL_VERIFY"] = strconv.FormatBool(rf.SkipSSLVerify)
+}
+
+// RedfishSpecHash returns a hash of the redfish spec used to redeploy the
+// kepler pods when the spec changes
+func RedfishSpecHash(rf *v1alpha1.RedfishSpec) (uint64, error) {
+	data, err := json.Marshal(rf)
+	if err != nil {
+		return 0, err
+	}
+	return xxhash.Sum64(data), nil
+}
+
+// NewGrafanaDashboards returns the ConfigMaps carrying the kepler dashboards
+// that are loaded into Grafana by the dashboard sidecar
+func NewGrafanaDashboards(d components.Detail, k *v1alpha1.KeplerInternal) []*corev1.ConfigMap {
+	dashboards := map[string]string{
+		overviewDashboardName: overviewDashboardJson,
+		nsInfoDashboardName:   nsInfoDashboardJson,
+	}
+
+	cms := []*corev1.ConfigMap{}
+	for _, name := range []string{overviewDashboardName, nsInfoDashboardName} {
+		cm := &corev1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: corev1.SchemeGroupVersion.String(),
+				Kind:       "ConfigMap",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      k.Name + "-" + name,
+				Namespace: GrafanaDashboardsNamespace(k),
+				Labels:    grafanaDashboardLabels(k),
+			},
+		}
+		if d == components.Full {
+			cm.Data = map[string]string{name + ".json": dashboards[name]}
+		}
+		cms = append(cms, cm)
+	}
+	return cms
+}
+
+// GrafanaDashboardsNamespace returns the namespace of the grafana dashboards
+func GrafanaDashboardsNamespace(k *v1alpha1.KeplerInternal) string {
+	if g := k.Spec.Dashboards.Grafana; g != nil && g.Namespace != "" {
+		return g.Namespace
+	}
+	return k.Namespace()
+}
+
+// grafanaDashboardLabels returns the labels the dashboard sidecar selects
+// dashboards by along with the labels managed by the operator
+func grafanaDashboardLabels(k *v1alpha1.KeplerInternal) map[string]string {
+	sidecar := k8s.StringMap{"grafana_dashboard": "1"}
+	if g := k.Spec.Dashboards.Grafana; g != nil && len(g.Labels) != 0 {
+		sidecar = g.Labels
 	}
+	return sidecar.Merge(labels(k)).ToMap()
 }
 
 func openshiftDashboardObjectMeta(name string) metav1.ObjectMeta {
@@ -239,6 +376,22 @@ func NewConfigMap(d components.Detail, k *v1alpha1.KeplerInternal) *corev1.Confi
 		}
 	}
 
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      k.Name,
+			Namespace: k.Namespace(),
+			Labels:    labels(k).ToMap(),
+		},
+		Data: exporterConfig(k),
+	}
+}
+
+// exporterConfig returns the data of the kepler configmap
+func exporterConfig(k *v1alpha1.KeplerInternal) k8s.StringMap {
 	deployment := k.Spec.Exporter.Deployment.ExporterDeploymentSpec
 	bindAddress := "0.0.0.0:" + strconv.Itoa(int(deployment.Port))
 
@@ -252,7 +405,7 @@ func NewConfigMap(d components.Detail, k *v1alpha1.KeplerInternal) *corev1.Confi
 		"KEPLER_LOG_LEVEL":           "1",
 		"METRIC_PATH":                "/metrics",
 		"BIND_ADDRESS":               bindAddress,
-		"ENABLE_GPU":                 "true",
+		"ENABLE_GPU":                 strconv.FormatBool(features.Enabled(features.GPU)),
 		"ENABLE_QAT":                 "false",
 		"ENABLE_EBPF_CGROUPID":       "true",
 		"EXPOSE_HW_COUNTER_METRICS":  "true",
@@ -265,6 +418,19 @@ func NewConfigMap(d components.Detail, k *v1alpha1.KeplerInternal) *corev1.Confi
 		"MODEL_CONFIG":               modelConfig,
 	}
 
+	if k.Spec.Exporter.VirtualMachines.IsEnabled() {
+		// NOTE: virtual machine metrics are exported along with process metrics
+		exporterConfigMap["ENABLE_PROCESS_METRICS"] = "true"
+		exporterConfigMap["EXPOSE_VM_METRICS"] = "true"
+	}
+
+	switch k.Spec.Exporter.IdlePower {
+	case v1alpha1.IdlePowerSpread:
+		exporterConfigMap["EXPOSE_ESTIMATED_IDLE_POWER_METRICS"] = "true"
+	case v1alpha1.IdlePowerNode, v1alpha1.IdlePowerDrop:
+		exporterConfigMap["EXPOSE_ESTIMATED_IDLE_POWER_METRICS"] = "false"
+	}
+
 	ms := k.Spec.ModelServer
 	if ms != nil {
 		if ms.Enabled {
@@ -273,19 +439,7 @@ func NewConfigMap(d components.Detail, k *v1alpha1.KeplerInternal) *corev1.Confi
 		modelServerConfig := modelserver.ConfigForClient(k.ModelServerDeploymentName(), k.Namespace(), k.Spec.ModelServer)
 		exporterConfigMap = exporterConfigMap.Merge(modelServerConfig)
 	}
-
-	return &corev1.ConfigMap{
-		TypeMeta: metav1.TypeMeta{
-			APIVersion: corev1.SchemeGroupVersion.String(),
-			Kind:       "ConfigMap",
-		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      k.Name,
-			Namespace: k.Namespace(),
-			Labels:    labels(k).ToMap(),
-		},
-		Data: exporterConfigMap,
-	}
+	return exporterConfigMap
 }
 
 func NewClusterRole(c components.Detail, k *v1alpha1.KeplerInternal) *rbacv1.ClusterRole {
@@ -302,6 +456,15 @@ func NewClusterRole(c components.Detail, k *v1alpha1.KeplerInternal) *rbacv1.Clu
 		}
 	}
 
+	rules := []rbacv1.PolicyRule{{
+		APIGroups: []string{""},
+		Resources: []string{"nodes/metrics", "nodes/proxy", "nodes/stats", "pods"},
+		Verbs:     []string{"get", "watch", "list"},
+	}}
+	if k.Spec.Exporter.RBACProxy.IsEnabled() {
+		rules = append(rules, rbacProxyRules...)
+	}
+
 	return &rbacv1.ClusterRole{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: rbacv1.SchemeGroupVersion.String(),
@@ -311,11 +474,7 @@ func NewClusterRole(c components.Detail, k *v1alpha1.KeplerInternal) *rbacv1.Clu
 			Name:   k.Name,
 			Labels: labels(k),
 		},
-		Rules: []rbacv1.PolicyRule{{
-			APIGroups: []string{""},
-			Resources: []string{"nodes/metrics", "nodes/proxy", "nodes/stats", "pods"},
-			Verbs:     []string{"get", "watch", "list"},
-		}},
+		Rules: rules,
 	}
 }
 
@@ -370,7 +529,7 @@ func NewSCC(d components.Detail, ki *v1alpha1.KeplerInternal) *secv1.SecurityCon
 		}
 	}
 
-	return &secv1.SecurityContextConstraints{
+	scc := &secv1.SecurityContextConstraints{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: secv1.SchemeGroupVersion.String(),
 			Kind:       "SecurityContextConstraints",
@@ -381,13 +540,11 @@ func NewSCC(d components.Detail, ki *v1alpha1.KeplerInternal) *secv1.SecurityCon
 			Labels: labels(ki),
 		},
 
-		AllowPrivilegedContainer: true,
 		AllowHostDirVolumePlugin: true,
 		AllowHostIPC:             false,
 		AllowHostNetwork:         false,
 		AllowHostPID:             true,
 		AllowHostPorts:           false,
-		DefaultAddCapabilities:   []corev1.Capability{corev1.Capability("SYS_ADMIN")},
 
 		FSGroup: secv1.FSGroupStrategyOptions{
 			Type: secv1.FSGroupStrategyRunAsAny,
@@ -407,6 +564,16 @@ func NewSCC(d components.Detail, ki *v1alpha1.KeplerInternal) *secv1.SecurityCon
 			secv1.FSType("emptyDir"),
 			secv1.FSType("hostPath")},
 	}
+
+	// NOTE: the SCC permits exactly the capabilities of the collection mode so
+	// that it documents what the exporter needs
+	if mode := ki.Spec.Exporter.CollectionMode; isPrivileged(mode) {
+		scc.AllowPrivilegedContainer = true
+		scc.DefaultAddCapabilities = []corev1.Capability{capSysAdmin}
+	} else {
+		scc.AllowedCapabilities = Capabilities(mode)
+	}
+	return scc
 }
 
 func NewServiceAccount(ki *v1alpha1.KeplerInternal) *corev1.ServiceAccount {
@@ -425,6 +592,10 @@ func NewServiceAccount(ki *v1alpha1.KeplerInternal) *corev1.ServiceAccount {
 
 func NewService(k *v1alpha1.KeplerInternal) *corev1.Service {
 	deployment := k.Spec.Exporter.Deployment.ExporterDeploymentSpec
+	var appProtocol *string
+	if k.Spec.Exporter.RBACProxy.IsEnabled() {
+		appProtocol = ptr.To(MetricsScheme(k))
+	}
 
 	return &corev1.Service{
 		TypeMeta: metav1.TypeMeta{
@@ -432,17 +603,19 @@ func NewService(k *v1alpha1.KeplerInternal) *corev1.Service {
 			Kind:       "Service",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      k.Name,
-			Namespace: k.Namespace(),
-			Labels:    labels(k).ToMap(),
+			Name:        k.Name,
+			Namespace:   k.Namespace(),
+			Labels:      labels(k).ToMap(),
+			Annotations: prometheusScrapeAnnotations(k).Merge(rbacProxyServiceAnnotations(k)),
 		},
 		Spec: corev1.ServiceSpec{
 
 			ClusterIP: "None",
 			Selector:  podSelector(k),
 			Ports: []corev1.ServicePort{{
-				Name: ServicePortName,
-				Port: int32(deployment.Port),
+				Name:        ServicePortName,
+				AppProtocol: appProtocol,
+				Port:        int32(deployment.Port),
 				TargetPort: intstr.IntOrString{
 					Type:   intstr.Int,
 					IntVal: int32(deployment.Port),
@@ -452,8 +625,184 @@ func NewService(k *v1alpha1.KeplerInternal) *corev1.Service {
 	}
 }
 
+// EndpointsFromService returns the metrics endpoints exposed by the service
+func EndpointsFromService(svc *corev1.Service) []v1alpha1.Endpoint {
+	endpoints := []v1alpha1.Endpoint{}
+	for _, p := range svc.Spec.Ports {
+		if p.Name != ServicePortName {
+			continue
+		}
+		scheme := "http"
+		if p.AppProtocol != nil {
+			scheme = *p.AppProtocol
+		}
+		endpoints = append(endpoints, v1alpha1.Endpoint{
+			Kind:      "Service",
+			Name:      svc.Name,
+			Namespace: svc.Namespace,
+			URL:       fmt.Sprintf("%s://%s.%s.svc:%d/metrics", scheme, svc.Name, svc.Namespace, p.Port),
+		})
+	}
+	return endpoints
+}
+
 func NewServiceMonitor(k *v1alpha1.KeplerInternal) *monv1.ServiceMonitor {
-	relabelings := []*monv1.RelabelConfig{{
+	var extraLabels map[string]string
+	scrape := v1alpha1.ScrapeSpec{}
+	if sm := k.Spec.Exporter.ServiceMonitor; sm != nil {
+		extraLabels = sm.Labels
+		scrape = sm.ScrapeSpec
+	}
+
+	endpoint := monv1.Endpoint{
+		Port:                 ServicePortName,
+		Interval:             scrapeInterval(scrape),
+		ScrapeTimeout:        scrape.ScrapeTimeout,
+		Scheme:               MetricsScheme(k),
+		RelabelConfigs:       append(append(instanceRelabelings(), metricLabelsRelabelings(k)...), scrape.Relabelings...),
+		MetricRelabelConfigs: append(idlePowerRelabelings(k), scrape.MetricRelabelings...),
+	}
+	if k.Spec.Exporter.RBACProxy.IsEnabled() {
+		endpoint.TLSConfig = &monv1.TLSConfig{SafeTLSConfig: rbacProxyTLSConfig(k)}
+		if NeedsScrapeServiceAccount(k) {
+			endpoint.Authorization = scrapeAuthorization(k)
+		} else {
+			// NOTE: Prometheus authenticates with its own service account token
+			endpoint.BearerTokenFile = serviceAccountTokenFile
+		}
+	}
+
+	return &monv1.ServiceMonitor{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: monv1.SchemeGroupVersion.String(),
+			Kind:       "ServiceMonitor",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      k.Name,
+			Namespace: k.Namespace(),
+			Labels:    monitorLabels(k, extraLabels),
+		},
+		Spec: monv1.ServiceMonitorSpec{
+			Endpoints: []monv1.Endpoint{endpoint},
+			JobLabel:  "app.kubernetes.io/name",
+			Selector: metav1.LabelSelector{
+				MatchLabels: labels(k),
+			},
+		},
+	}
+}
+
+// NewPodMonitor returns a PodMonitor that scrapes the exporter pods directly
+// for Prometheus setups that do not select ServiceMonitors
+func NewPodMonitor(k *v1alpha1.KeplerInternal) *monv1.PodMonitor {
+	var extraLabels map[string]string
+	scrape := v1alpha1.ScrapeSpec{}
+	if pm := k.Spec.Exporter.PodMonitor; pm != nil {
+		extraLabels = pm.Labels
+		scrape = pm.ScrapeSpec
+	}
+
+	endpoint := monv1.PodMetricsEndpoint{
+		Port:                 ServicePortName,
+		Interval:             scrapeInterval(scrape),
+		ScrapeTimeout:        scrape.ScrapeTimeout,
+		Scheme:               MetricsScheme(k),
+		RelabelConfigs:       append(append(instanceRelabelings(), metricLabelsRelabelings(k)...), scrape.Relabelings...),
+		MetricRelabelConfigs: append(idlePowerRelabelings(k), scrape.MetricRelabelings...),
+	}
+	if k.Spec.Exporter.RBACProxy.IsEnabled() {
+		endpoint.TLSConfig = &monv1.PodMetricsEndpointTLSConfig{SafeTLSConfig: rbacProxyTLSConfig(k)}
+	}
+	if NeedsScrapeServiceAccount(k) {
+		endpoint.Authorization = scrapeAuthorization(k)
+	}
+
+	return &monv1.PodMonitor{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: monv1.SchemeGroupVersion.String(),
+			Kind:       "PodMonitor",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      k.Name,
+			Namespace: k.Namespace(),
+			Labels:    monitorLabels(k, extraLabels),
+		},
+		Spec: monv1.PodMonitorSpec{
+			PodMetricsEndpoints: []monv1.PodMetricsEndpoint{endpoint},
+			JobLabel:            "app.kubernetes.io/name",
+			Selector: metav1.LabelSelector{
+				MatchLabels: podSelector(k),
+			},
+		},
+	}
+}
+
+// scrapeInterval returns the interval in the spec or the default interval
+func scrapeInterval(scrape v1alpha1.ScrapeSpec) monv1.Duration {
+	if scrape.Interval == "" {
+		return DefaultScrapeInterval
+	}
+	return scrape.Interval
+}
+
+// ValidateScrape returns an error if the scrape timeout is greater than the
+// scrape interval, which Prometheus Operator rejects
+func ValidateScrape(scrape v1alpha1.ScrapeSpec) error {
+	if scrape.ScrapeTimeout == "" {
+		return nil
+	}
+	interval, err := model.ParseDuration(string(scrapeInterval(scrape)))
+	if err != nil {
+		return fmt.Errorf("invalid scrape interval %q: %w", scrapeInterval(scrape), err)
+	}
+	timeout, err := model.ParseDuration(string(scrape.ScrapeTimeout))
+	if err != nil {
+		return fmt.Errorf("invalid scrape timeout %q: %w", scrape.ScrapeTimeout, err)
+	}
+	if timeout > interval {
+		return fmt.Errorf("scrape timeout %s must not be greater than the scrape interval %s", timeout, interval)
+	}
+	return nil
+}
+
+// prometheusScrapeAnnotations returns the prometheus.io annotations used by
+// agents to discover the metrics endpoint of the exporter
+func prometheusScrapeAnnotations(k *v1alpha1.KeplerInternal) k8s.StringMap {
+	sa := k.Spec.Exporter.ScrapeAnnotations
+	if sa == nil || !sa.Prometheus {
+		return nil
+	}
+	annotations := k8s.StringMap{
+		"prometheus.io/scrape": "true",
+		"prometheus.io/port":   strconv.Itoa(int(k.Spec.Exporter.Deployment.Port)),
+		"prometheus.io/path":   "/metrics",
+	}
+	if k.Spec.Exporter.RBACProxy.IsEnabled() {
+		annotations["prometheus.io/scheme"] = MetricsScheme(k)
+	}
+	return annotations
+}
+
+// podScrapeAnnotations returns the prometheus.io and Datadog autodiscovery
+// annotations of the exporter pods
+func podScrapeAnnotations(k *v1alpha1.KeplerInternal) k8s.StringMap {
+	annotations := k8s.StringMap{}.Merge(prometheusScrapeAnnotations(k))
+	sa := k.Spec.Exporter.ScrapeAnnotations
+	if sa == nil || !sa.Datadog {
+		return annotations
+	}
+
+	// NOTE: see https://docs.datadoghq.com/containers/kubernetes/prometheus
+	check := fmt.Sprintf(`{"openmetrics":{"instances":[{`+
+		`"openmetrics_endpoint":"%s://%%%%host%%%%:%d/metrics","namespace":"kepler","metrics":["kepler_.*"]}]}}`,
+		MetricsScheme(k), k.Spec.Exporter.Deployment.Port)
+	annotations["ad.datadoghq.com/"+k.DaemonsetName()+".checks"] = check
+	return annotations
+}
+
+// instanceRelabelings sets the instance label to the node the exporter runs on
+func instanceRelabelings() []*monv1.RelabelConfig {
+	return []*monv1.RelabelConfig{{
 		Action:      "replace",
 		Regex:       "(.*)",
 		Replacement: "$1",
@@ -462,28 +811,501 @@ func NewServiceMonitor(k *v1alpha1.KeplerInternal) *monv1.ServiceMonitor {
 		},
 		TargetLabel: "instance",
 	}}
+}
 
-	return &monv1.ServiceMonitor{
+// metricLabelsRelabelings sets the cluster, region and extra labels in the
+// spec on the targets so that they are added to every metric
+func metricLabelsRelabelings(k *v1alpha1.KeplerInternal) []*monv1.RelabelConfig {
+	labels := k.Spec.Exporter.MetricLabels.Labels()
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	relabelings := make([]*monv1.RelabelConfig, 0, len(names))
+	for _, name := range names {
+		relabelings = append(relabelings, &monv1.RelabelConfig{
+			Action:      "replace",
+			Replacement: labels[name],
+			TargetLabel: name,
+		})
+	}
+	return relabelings
+}
+
+// idlePowerRelabelings drops the idle power metrics of containers and
+// processes when the idle power is dropped; the idle power of nodes is kept
+// since it is attributed to the nodes
+func idlePowerRelabelings(k *v1alpha1.KeplerInternal) []*monv1.RelabelConfig {
+	if k.Spec.Exporter.IdlePower != v1alpha1.IdlePowerDrop {
+		return nil
+	}
+	return []*monv1.RelabelConfig{{
+		Action:       "drop",
+		Regex:        "kepler_(container|process)_.*;idle",
+		SourceLabels: []monv1.LabelName{"__name__", "mode"},
+	}}
+}
+
+// monitorLabels returns the labels of a ServiceMonitor or PodMonitor which
+// includes the labels in the spec; the labels managed by the operator take
+// precedence
+func monitorLabels(k *v1alpha1.KeplerInternal, extra map[string]string) map[string]string {
+	if len(extra) == 0 {
+		return labels(k).ToMap()
+	}
+	return k8s.StringMap(extra).Merge(labels(k)).ToMap()
+}
+
+// NewAlertsPrometheusRule returns a PrometheusRule with alerts on the health
+// of the exporter and the metrics it exports
+func NewAlertsPrometheusRule(k *v1alpha1.KeplerInternal) *monv1.PrometheusRule {
+	// NOTE: selects the targets of both the service and pod monitors
+	selector := fmt.Sprintf(`namespace=%q, container=%q`, k.Namespace(), k.DaemonsetName())
+
+	return &monv1.PrometheusRule{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: monv1.SchemeGroupVersion.String(),
-			Kind:       "ServiceMonitor",
+			Kind:       "PrometheusRule",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      k.Name,
+			Name:      k.Name + "-alerts",
 			Namespace: k.Namespace(),
 			Labels:    labels(k).ToMap(),
 		},
-		Spec: monv1.ServiceMonitorSpec{
-			Endpoints: []monv1.Endpoint{{
-				Port:           ServicePortName,
-				Interval:       "3s",
-				Scheme:         "http",
-				RelabelConfigs: relabelings,
+		Spec: monv1.PrometheusRuleSpec{
+			Groups: []monv1.RuleGroup{{
+				Name: "kepler.alerts",
+				Rules: []monv1.Rule{
+					alert("KeplerExporterDown", "5m",
+						fmt.Sprintf(`up{%s} == 0`, selector),
+						"Kepler exporter is down",
+						"Kepler exporter {{ $labels.pod }} on {{ $labels.instance }} has not been scraped successfully for 5 minutes.",
+					),
+					alert("KeplerNodeMissingPowerMetrics", "15m",
+						fmt.Sprintf(`kepler_node_info{%s} unless on (instance) kepler_node_package_joules_total{%s}`, selector, selector),
+						"Kepler is not reporting the power consumed by a node",
+						"Kepler exporter on {{ $labels.instance }} does not export the power consumed by the node.",
+					),
+					alert("KeplerMetricsStale", "15m",
+						fmt.Sprintf(`sum by (instance) (changes(kepler_node_package_joules_total{%s}[10m])) == 0`, selector),
+						"Kepler power metrics are stale",
+						"Power consumed by {{ $labels.instance }} reported by Kepler has not changed for 10 minutes.",
+					),
+				},
 			}},
-			JobLabel: "app.kubernetes.io/name",
-			Selector: metav1.LabelSelector{
-				MatchLabels: labels(k),
+		},
+	}
+}
+
+// NewAggregationsPrometheusRule returns a PrometheusRule with recording rules
+// for the rate of energy consumed by namespace and by node so that dashboards
+// do not have to aggregate the per container metrics
+func NewAggregationsPrometheusRule(k *v1alpha1.KeplerInternal) *monv1.PrometheusRule {
+	ns := k.Namespace()
+	interval := monv1.Duration("30s")
+
+	rules := []monv1.Rule{}
+	for _, m := range []string{"joules", "package_joules", "dram_joules", "gpu_joules", "other_joules"} {
+		rules = append(rules,
+			monv1.Rule{
+				Record: fmt.Sprintf("namespace:kepler_container_%s:rate5m", m),
+				Expr: intstr.FromString(fmt.Sprintf(
+					`sum by (container_namespace) (rate(kepler_container_%s_total{namespace=%q}[5m]))`, m, ns)),
+			},
+			monv1.Rule{
+				Record: fmt.Sprintf("node:kepler_container_%s:rate5m", m),
+				Expr: intstr.FromString(fmt.Sprintf(
+					`sum by (instance) (rate(kepler_container_%s_total{namespace=%q}[5m]))`, m, ns)),
 			},
+		)
+	}
+
+	return &monv1.PrometheusRule{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: monv1.SchemeGroupVersion.String(),
+			Kind:       "PrometheusRule",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      k.Name + "-aggregations",
+			Namespace: ns,
+			Labels:    labels(k).ToMap(),
+		},
+		Spec: monv1.PrometheusRuleSpec{
+			Groups: []monv1.RuleGroup{{
+				Name:     "kepler.aggregations",
+				Interval: &interval,
+				Rules:    rules,
+			}},
+		},
+	}
+}
+
+// JoulesPerKWh converts the energy reported by kepler into kWh
+const JoulesPerKWh = 3600000
+
+// NewEnergyCostPrometheusRule returns a PrometheusRule with recording rules
+// for the price of a kWh on each node and the cost, per second, of the energy
+// consumed by pod and by namespace. Returns an error if no price is
+// configured or a tariff is invalid; prices are validated by the CRD.
+func NewEnergyCostPrometheusRule(d components.Detail, k *v1alpha1.KeplerInternal) (*monv1.PrometheusRule, error) {
+	ns := k.Namespace()
+	rule := &monv1.PrometheusRule{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: monv1.SchemeGroupVersion.String(),
+			Kind:       "PrometheusRule",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      k.Name + "-energy-cost",
+			Namespace: ns,
+			Labels:    labels(k).ToMap(),
+		},
+	}
+	if d == components.Metadata {
+		return rule, nil
+	}
+
+	price, err := nodePrice(k)
+	if err != nil {
+		return nil, err
+	}
+
+	interval := monv1.Duration("30s")
+	rule.Spec = monv1.PrometheusRuleSpec{
+		Groups: []monv1.RuleGroup{{
+			Name:     "kepler.energy-cost",
+			Interval: &interval,
+			Rules: []monv1.Rule{{
+				Record: "node:kepler_electricity_price:per_kwh",
+				Expr:   intstr.FromString(price),
+			}, {
+				Record: "pod:kepler_container_energy_cost:rate5m",
+				Expr: intstr.FromString(fmt.Sprintf(
+					`sum by (container_namespace, pod_name, instance) (rate(kepler_container_joules_total{namespace=%q}[5m]))`+
+						` / %d * on (instance) group_left (currency) node:kepler_electricity_price:per_kwh`, ns, JoulesPerKWh)),
+			}, {
+				Record: "namespace:kepler_container_energy_cost:rate5m",
+				Expr:   intstr.FromString(`sum by (container_namespace, currency) (pod:kepler_container_energy_cost:rate5m)`),
+			}},
+		}},
+	}
+	return rule, nil
+}
+
+// nodePrice returns the expression of the price of a kWh on each node
+// exporting kepler metrics, labelled by instance as the kepler metrics are
+// and by the currency of the price
+func nodePrice(k *v1alpha1.KeplerInternal) (string, error) {
+	ep := k.Spec.Exporter.ElectricityPrice
+	if ep == nil || (ep.PerKWh == "" && len(ep.Zones) == 0) {
+		return "", fmt.Errorf("electricity price requires a price per kWh or the prices of one or more zones")
+	}
+
+	price := nodeZoneValue(k.Namespace(), ep.PerKWh, ep.Zones)
+	tariffs, err := tariffPrices(k.Namespace(), ep, time.Now())
+	if err != nil {
+		return "", err
+	}
+	if len(tariffs) > 0 {
+		// NOTE: the prices of the tariffs, labelled by tariff, precede the
+		// flat and zone prices of the same instance during their windows
+		price = strings.Join(append(tariffs, "("+price+")"), " or on (instance) ")
+	}
+	return fmt.Sprintf(`label_replace(%s, "currency", %q, "", "")`, price, ep.Currency), nil
+}
+
+// tariffDays maps the days of the tariffs to the day_of_week of PromQL
+var tariffDays = map[v1alpha1.TariffDay]int{
+	"Sunday": 0, "Monday": 1, "Tuesday": 2, "Wednesday": 3, "Thursday": 4, "Friday": 5, "Saturday": 6,
+}
+
+// tariffPrices returns the expressions of the price of a kWh on each node
+// during the window of each tariff, labelled by the name of the tariff; the
+// expressions are empty outside the windows
+func tariffPrices(ns string, ep *v1alpha1.ElectricityPriceSpec, now time.Time) ([]string, error) {
+	local, err := localTime(ep, now)
+	if err != nil {
+		return nil, err
+	}
+	minute := fmt.Sprintf(`%s %% 86400 / 60`, local)
+
+	exprs := []string{}
+	for _, t := range ep.Tariffs {
+		start, err := minuteOfDay(t.Start)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start of tariff %q: %w", t.Name, err)
+		}
+		end, err := minuteOfDay(t.End)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end of tariff %q: %w", t.Name, err)
+		}
+
+		var window string
+		switch {
+		case start < end:
+			window = fmt.Sprintf(`%s >= %d < %d`, minute, start, end)
+		case start > end:
+			window = fmt.Sprintf(`(%s >= %d or %s < %d)`, minute, start, minute, end)
+		default:
+			return nil, fmt.Errorf("tariff %q starts and ends at %s; the window must not be empty", t.Name, t.Start)
+		}
+		if len(t.Days) > 0 {
+			days := []string{}
+			for _, d := range t.Days {
+				n, ok := tariffDays[d]
+				if !ok {
+					return nil, fmt.Errorf("invalid day %q of tariff %q", d, t.Name)
+				}
+				days = append(days, fmt.Sprintf(`day_of_week(%s) == %d`, local, n))
+			}
+			window = fmt.Sprintf(`%s and on () (%s)`, window, strings.Join(days, " or "))
+		}
+
+		exprs = append(exprs, fmt.Sprintf(
+			`label_replace(0 * max by (instance) (kepler_node_info{namespace=%q}) + %s, "tariff", %q, "", "") and on () (%s)`,
+			ns, t.PerKWh, t.Name, window))
+	}
+	return exprs, nil
+}
+
+// localTime returns the expression of the current unix time shifted by the
+// offset from UTC of the time zone the windows of the tariffs are in. The
+// offsets of an IANA time zone are rendered for each daylight saving time
+// period from the start of the year of now until the end of the next year;
+// the last offset applies afterwards until the rules are rendered again.
+func localTime(ep *v1alpha1.ElectricityPriceSpec, now time.Time) (string, error) {
+	if ep.TimeZone == "" {
+		return fmt.Sprintf(`vector(time() + %d)`, int64(ep.UTCOffsetMinutes)*60), nil
+	}
+	loc, err := time.LoadLocation(ep.TimeZone)
+	if err != nil {
+		return "", fmt.Errorf("invalid time zone %q: %w", ep.TimeZone, err)
+	}
+
+	offsets := []string{}
+	until := time.Date(now.Year()+2, time.January, 1, 0, 0, 0, 0, loc)
+	for t := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, loc); ; {
+		_, offset := t.Zone()
+		start, end := t.ZoneBounds()
+		window := []string{}
+		if len(offsets) > 0 {
+			window = append(window, fmt.Sprintf(`>= %d`, start.Unix()))
+		}
+		if !end.IsZero() && end.Before(until) {
+			window = append(window, fmt.Sprintf(`< %d`, end.Unix()))
+		}
+		if len(window) == 0 {
+			// NOTE: the time zone has a single offset
+			return fmt.Sprintf(`vector(time() + %d)`, offset), nil
+		}
+		offsets = append(offsets, fmt.Sprintf(`vector(%d) and on () vector(time()) %s`, offset, strings.Join(window, " ")))
+		if end.IsZero() || !end.Before(until) {
+			break
+		}
+		t = end
+	}
+	return fmt.Sprintf(`(vector(time()) + (%s))`, strings.Join(offsets, " or ")), nil
+}
+
+// minuteOfDay returns the minute of the day of a time formatted as HH:MM
+func minuteOfDay(hhmm string) (int, error) {
+	t, err := time.Parse("15:04", hhmm)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not formatted as HH:MM", hhmm)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// nodeZoneValue returns the expression of a value on each node exporting
+// kepler metrics, labelled by instance, read from zones by the
+// topology.kubernetes.io/zone label of the node; nodes in zones that are not
+// listed default to value unless it is empty
+func nodeZoneValue[T ~string](ns string, value T, zones map[string]T) string {
+	exprs := []string{}
+	for _, z := range sortedZones(zones) {
+		exprs = append(exprs, fmt.Sprintf(
+			`0 * max by (instance) (label_replace(kube_node_labels{label_topology_kubernetes_io_zone=%q}, "instance", "$1", "node", "(.*)")) + %s`,
+			z, zones[z]))
+	}
+	if value != "" {
+		exprs = append(exprs, fmt.Sprintf(`0 * max by (instance) (kepler_node_info{namespace=%q}) + %s`, ns, value))
+	}
+	return strings.Join(exprs, " or ")
+}
+
+func sortedZones[T any](zones map[string]T) []string {
+	names := make([]string, 0, len(zones))
+	for z := range zones {
+		names = append(names, z)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DefaultPUE is the PUE of the nodes in zones that are not listed unless a
+// factor is set in the spec
+const DefaultPUE = "1"
+
+// NewPUEPrometheusRule returns a PrometheusRule with recording rules for the
+// PUE of each node and the power drawn by the facility, i.e. the power of the
+// nodes scaled by their PUE, by node, by namespace, by zone if the PUE is set
+// per zone, and for the cluster; the factors are validated by the CRD.
+func NewPUEPrometheusRule(d components.Detail, k *v1alpha1.KeplerInternal) *monv1.PrometheusRule {
+	ns := k.Namespace()
+	rule := &monv1.PrometheusRule{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: monv1.SchemeGroupVersion.String(),
+			Kind:       "PrometheusRule",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      k.Name + "-pue",
+			Namespace: ns,
+			Labels:    labels(k).ToMap(),
+		},
+	}
+	if d == components.Metadata {
+		return rule
+	}
+
+	spec := k.Spec.Exporter.PUE
+	factor := v1alpha1.PUEFactor(DefaultPUE)
+	var zones map[string]v1alpha1.PUEFactor
+	if spec != nil {
+		if spec.Factor != "" {
+			factor = spec.Factor
+		}
+		zones = spec.Zones
+	}
+	rules := []monv1.Rule{{
+		Record: "node:kepler_pue:ratio",
+		Expr:   intstr.FromString(nodeZoneValue(ns, factor, zones)),
+	}, {
+		Record: "node:kepler_facility_power:watts",
+		Expr: intstr.FromString(fmt.Sprintf(
+			`sum by (instance) (rate(kepler_node_platform_joules_total{namespace=%q}[5m])) * on (instance) node:kepler_pue:ratio`, ns)),
+	}, {
+		Record: "namespace:kepler_facility_power:watts",
+		Expr: intstr.FromString(fmt.Sprintf(
+			`sum by (container_namespace) (sum by (container_namespace, instance) (rate(kepler_container_joules_total{namespace=%q}[5m]))`+
+				` * on (instance) group_left node:kepler_pue:ratio)`, ns)),
+	}, {
+		Record: "cluster:kepler_facility_power:watts",
+		Expr:   intstr.FromString(`sum(node:kepler_facility_power:watts)`),
+	}}
+	if len(zones) > 0 {
+		rules = append(rules, monv1.Rule{
+			Record: "zone:kepler_facility_power:watts",
+			Expr: intstr.FromString(`sum by (zone) (node:kepler_facility_power:watts * on (instance) group_left (zone) ` +
+				`max by (instance, zone) (label_replace(label_replace(kube_node_labels, ` +
+				`"instance", "$1", "node", "(.*)"), "zone", "$1", "label_topology_kubernetes_io_zone", "(.*)")))`),
+		})
+	}
+
+	interval := monv1.Duration("30s")
+	rule.Spec = monv1.PrometheusRuleSpec{
+		Groups: []monv1.RuleGroup{{
+			Name:     "kepler.pue",
+			Interval: &interval,
+			Rules:    rules,
+		}},
+	}
+	return rule
+}
+
+const (
+	// DefaultIdleCPUThreshold is the CPU usage, in cores, below which a pod
+	// is idle unless a threshold is set in the spec
+	DefaultIdleCPUThreshold = "0.05"
+
+	// DefaultIdleMinPowerWatts is the power above which the power of an idle
+	// pod is wasted unless a minimum is set in the spec
+	DefaultIdleMinPowerWatts = "1"
+)
+
+// NewIdleWastePrometheusRule returns a PrometheusRule with recording rules
+// for the power drawn by pods whose CPU usage is below the idle threshold, by
+// pod, by workload and by namespace, along with the energy they wasted over
+// the last day.
+func NewIdleWastePrometheusRule(d components.Detail, k *v1alpha1.KeplerInternal) *monv1.PrometheusRule {
+	ns := k.Namespace()
+	rule := &monv1.PrometheusRule{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: monv1.SchemeGroupVersion.String(),
+			Kind:       "PrometheusRule",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      k.Name + "-idle-waste",
+			Namespace: ns,
+			Labels:    labels(k).ToMap(),
+		},
+	}
+	if d == components.Metadata {
+		return rule
+	}
+
+	spec := k.Spec.Exporter.IdleWaste
+	cpu, minPower := DefaultIdleCPUThreshold, DefaultIdleMinPowerWatts
+	if spec != nil && spec.CPUThreshold != "" {
+		cpu = spec.CPUThreshold
+	}
+	if spec != nil && spec.MinPowerWatts != "" {
+		minPower = spec.MinPowerWatts
+	}
+
+	interval := monv1.Duration("1m")
+	rule.Spec = monv1.PrometheusRuleSpec{
+		Groups: []monv1.RuleGroup{{
+			Name:     "kepler.idle-waste",
+			Interval: &interval,
+			Rules: []monv1.Rule{{
+				Record: "pod:kepler_container_power:watts",
+				Expr: intstr.FromString(fmt.Sprintf(
+					`sum by (container_namespace, pod_name) (rate(kepler_container_joules_total{namespace=%q}[5m]))`, ns)),
+			}, {
+				// NOTE: the cAdvisor labels are renamed to the labels of kepler
+				Record: "pod:container_cpu_usage:cores",
+				Expr: intstr.FromString(`sum by (container_namespace, pod_name) (` +
+					`label_replace(label_replace(rate(container_cpu_usage_seconds_total{container!=""}[5m]), ` +
+					`"container_namespace", "$1", "namespace", "(.*)"), "pod_name", "$1", "pod", "(.*)"))`),
+			}, {
+				Record: "pod:kepler_idle_waste:watts",
+				Expr: intstr.FromString(fmt.Sprintf(
+					`pod:kepler_container_power:watts > %s and on (container_namespace, pod_name) pod:container_cpu_usage:cores < %s`,
+					minPower, cpu)),
+			}, {
+				// NOTE: replicas of a workload are grouped by the owner of the
+				// pods reported by kube-state-metrics
+				Record: "workload:kepler_idle_waste:watts",
+				Expr: intstr.FromString(`sum by (container_namespace, owner_kind, owner_name) (` +
+					`pod:kepler_idle_waste:watts * on (container_namespace, pod_name) group_left (owner_kind, owner_name) ` +
+					`max by (container_namespace, pod_name, owner_kind, owner_name) (` +
+					`label_replace(label_replace(kube_pod_owner, ` +
+					`"container_namespace", "$1", "namespace", "(.*)"), "pod_name", "$1", "pod", "(.*)")))`),
+			}, {
+				Record: "namespace:kepler_idle_waste:watts",
+				Expr:   intstr.FromString(`sum by (container_namespace) (pod:kepler_idle_waste:watts)`),
+			}, {
+				Record: "namespace:kepler_idle_waste:kwh_1d",
+				Expr: intstr.FromString(fmt.Sprintf(
+					`sum by (container_namespace) (sum_over_time(namespace:kepler_idle_waste:watts[1d])) * 60 / %d`, JoulesPerKWh)),
+			}},
+		}},
+	}
+	return rule
+}
+
+func alert(name string, duration monv1.Duration, expr, summary, description string) monv1.Rule {
+	return monv1.Rule{
+		Alert: name,
+		Expr:  intstr.IntOrString{Type: intstr.String, StrVal: expr},
+		For:   &duration,
+		Labels: map[string]string{
+			"severity": "warning",
+		},
+		Annotations: map[string]string{
+			"summary":     summary,
+			"description": description,
 		},
 	}
 }
@@ -609,11 +1431,54 @@ func labels(ki *v1alpha1.KeplerInternal) k8s.StringMap {
 	})
 }
 
-func newExporterContainer(kiName, dsName string, deployment v1alpha1.InternalExporterDeploymentSpec) corev1.Container {
+// ConfigHash returns a hash of the configuration of the exporter
+func ConfigHash(data map[string]string) string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	// NOTE: the hash is written as key=value lines; changing the format
+	// changes the hash and redeploys the pods of all existing daemonsets
+	h := xxhash.New()
+	for _, k := range keys {
+		_, _ = h.WriteString(k)
+		_, _ = h.WriteString("=")
+		_, _ = h.WriteString(data[k])
+		_, _ = h.WriteString("\n")
+	}
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// VersionFromImage returns the kepler version parsed from the tag of image,
+// e.g. quay.io/sustainable_computing_io/kepler:release-0.7.8 returns 0.7.8.
+// An empty string is returned if the image has no tag.
+func VersionFromImage(image string) string {
+	// drop digest, if any
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+
+	// the tag follows the last ":" after the last "/" since a registry may
+	// include a port e.g. localhost:5000/kepler
+	name := image[strings.LastIndex(image, "/")+1:]
+	i := strings.LastIndex(name, ":")
+	if i < 0 {
+		return ""
+	}
+
+	tag := name[i+1:]
+	tag = strings.TrimPrefix(tag, "release-")
+	return strings.TrimPrefix(tag, "v")
+}
+
+func newExporterContainer(kiName, dsName string, deployment v1alpha1.InternalExporterDeploymentSpec,
+	mode v1alpha1.CollectionMode) corev1.Container {
 	bindAddress := "0.0.0.0:" + strconv.Itoa(int(deployment.Port))
 	return corev1.Container{
 		Name:            dsName,
-		SecurityContext: &corev1.SecurityContext{Privileged: ptr.To(true)},
+		SecurityContext: securityContext(mode),
 		Image:           deployment.Image,
 		Command: []string{
 			"/usr/bin/kepler",
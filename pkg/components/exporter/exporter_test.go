@@ -1,16 +1,34 @@
 package exporter
 
 import (
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
+	monv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/components"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
 )
 
+// newInternal returns a kepler-internal deploying the exporter with the spec
+// to the kepler namespace
+func newInternal(exporter v1alpha1.InternalExporterSpec) *v1alpha1.KeplerInternal {
+	exporter.Deployment.Namespace = "kepler"
+	return &v1alpha1.KeplerInternal{
+		ObjectMeta: metav1.ObjectMeta{Name: "kepler-internal"},
+		Spec:       v1alpha1.KeplerInternalSpec{Exporter: exporter},
+	}
+}
+
 func TestNodeSelection(t *testing.T) {
 
 	tt := []struct {
@@ -182,7 +200,6 @@ func TestDaemonSet(t *testing.T) {
 				},
 			},
 			annotation: map[string]string{
-				"kepler.system.sustainable.computing.io/redfish-secret-ref":  "123",
 				"kepler.system.sustainable.computing.io/redfish-config-hash": "1337",
 			},
 			scenario: "redfish case",
@@ -218,8 +235,14 @@ func TestDaemonSet(t *testing.T) {
 			actualVolumes := k8s.VolumesFromDS(ds)
 			assert.Equal(t, tc.volumes, actualVolumes)
 
+			expectedAnnotation := map[string]string{
+				ConfigHashAnnotation: ConfigHash(NewConfigMap(components.Full, &k).Data),
+			}
+			for key, v := range tc.annotation {
+				expectedAnnotation[key] = v
+			}
 			actualAnnotation := k8s.AnnotationFromDS(ds)
-			assert.Equal(t, tc.annotation, actualAnnotation)
+			assert.Equal(t, expectedAnnotation, actualAnnotation)
 		})
 	}
 }
@@ -257,6 +280,156 @@ func TestSCCAllows(t *testing.T) {
 	}
 }
 
+func TestCollectionMode(t *testing.T) {
+	tt := []struct {
+		mode         v1alpha1.CollectionMode
+		capabilities []corev1.Capability
+		scenario     string
+	}{
+		{
+			mode:         v1alpha1.CollectionModeEBPF,
+			capabilities: []corev1.Capability{"BPF", "PERFMON", "DAC_READ_SEARCH"},
+			scenario:     "ebpf",
+		},
+		{
+			mode:         v1alpha1.CollectionModeEBPFLegacyKernel,
+			capabilities: []corev1.Capability{"SYS_ADMIN", "DAC_READ_SEARCH"},
+			scenario:     "ebpf on legacy kernel",
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			k := v1alpha1.KeplerInternal{
+				ObjectMeta: metav1.ObjectMeta{Name: "kepler-internal"},
+				Spec: v1alpha1.KeplerInternalSpec{
+					Exporter: v1alpha1.InternalExporterSpec{CollectionMode: tc.mode},
+				},
+			}
+			ds := NewDaemonSet(components.Full, &k)
+			sc := ds.Spec.Template.Spec.Containers[KeplerContainerIndex].SecurityContext
+			assert.False(t, *sc.Privileged)
+			assert.Equal(t, tc.capabilities, sc.Capabilities.Add)
+			assert.Equal(t, []corev1.Capability{"ALL"}, sc.Capabilities.Drop)
+
+			scc := NewSCC(components.Full, &k)
+			assert.Equal(t, tc.capabilities, scc.AllowedCapabilities)
+			assert.Empty(t, scc.DefaultAddCapabilities)
+		})
+	}
+
+	// NOTE: the exporter runs privileged unless an eBPF mode is opted into
+	for _, mode := range []v1alpha1.CollectionMode{"", v1alpha1.CollectionModePrivileged} {
+		k := v1alpha1.KeplerInternal{
+			ObjectMeta: metav1.ObjectMeta{Name: "kepler-internal"},
+			Spec: v1alpha1.KeplerInternalSpec{
+				Exporter: v1alpha1.InternalExporterSpec{CollectionMode: mode},
+			},
+		}
+		ds := NewDaemonSet(components.Full, &k)
+		sc := ds.Spec.Template.Spec.Containers[KeplerContainerIndex].SecurityContext
+		assert.True(t, *sc.Privileged)
+		assert.Nil(t, sc.Capabilities)
+	}
+}
+
+func TestSecurityProfiles(t *testing.T) {
+	k := v1alpha1.KeplerInternal{
+		ObjectMeta: metav1.ObjectMeta{Name: "kepler-internal"},
+		Spec: v1alpha1.KeplerInternalSpec{
+			Exporter: v1alpha1.InternalExporterSpec{
+				Deployment: v1alpha1.InternalExporterDeploymentSpec{
+					ExporterDeploymentSpec: v1alpha1.ExporterDeploymentSpec{
+						SecurityProfiles: &v1alpha1.SecurityProfilesSpec{
+							SeccompProfile: &corev1.SeccompProfile{
+								Type:             corev1.SeccompProfileTypeLocalhost,
+								LocalhostProfile: ptr.To("kepler.json"),
+							},
+							AppArmorProfile: "localhost/kepler",
+						},
+					},
+				},
+				RBACProxy: &v1alpha1.InternalRBACProxySpec{RBACProxySpec: v1alpha1.RBACProxySpec{Enabled: true}},
+			},
+		},
+	}
+
+	template := NewDaemonSet(components.Full, &k).Spec.Template
+	assert.Equal(t, corev1.SeccompProfileTypeLocalhost, template.Spec.SecurityContext.SeccompProfile.Type)
+	for _, c := range template.Spec.Containers {
+		assert.Equal(t, "localhost/kepler", template.Annotations[components.AppArmorAnnotationPrefix+c.Name])
+	}
+	assert.Len(t, template.Spec.Containers, 2)
+}
+
+func TestReadOnlyRootFilesystem(t *testing.T) {
+	k := v1alpha1.KeplerInternal{
+		ObjectMeta: metav1.ObjectMeta{Name: "kepler-internal"},
+		Spec: v1alpha1.KeplerInternalSpec{
+			Exporter: v1alpha1.InternalExporterSpec{
+				Deployment: v1alpha1.InternalExporterDeploymentSpec{
+					ExporterDeploymentSpec: v1alpha1.ExporterDeploymentSpec{
+						ReadOnlyRootFilesystem: &v1alpha1.ReadOnlyRootFilesystemSpec{Enabled: true},
+					},
+				},
+				RBACProxy: &v1alpha1.InternalRBACProxySpec{RBACProxySpec: v1alpha1.RBACProxySpec{Enabled: true}},
+			},
+			Estimator: &v1alpha1.InternalEstimatorSpec{
+				Node: v1alpha1.EstimatorGroup{Total: &v1alpha1.EstimatorConfig{SidecarEnabled: true}},
+			},
+		},
+	}
+	spec := NewDaemonSet(components.Full, &k).Spec.Template.Spec
+	assert.Len(t, spec.Containers, 3)
+	for _, c := range spec.Containers {
+		assert.True(t, *c.SecurityContext.ReadOnlyRootFilesystem, c.Name)
+	}
+
+	mounts := map[string]string{}
+	for _, m := range spec.Containers[KeplerContainerIndex].VolumeMounts {
+		mounts[m.MountPath] = m.Name
+	}
+	// NOTE: /tmp is shared with the estimator sidecar
+	assert.Equal(t, "tmp", mounts["/tmp"])
+	assert.Equal(t, "scratch-var-lib-kepler-data", mounts["/var/lib/kepler/data"])
+
+	volumes := []string{}
+	for _, v := range spec.Volumes {
+		volumes = append(volumes, v.Name)
+	}
+	assert.Contains(t, volumes, "scratch-var-lib-kepler-data")
+	assert.NotContains(t, volumes, "scratch-tmp")
+}
+
+func TestModelDigestCheck(t *testing.T) {
+	k := v1alpha1.KeplerInternal{
+		ObjectMeta: metav1.ObjectMeta{Name: "kepler-internal"},
+		Spec: v1alpha1.KeplerInternalSpec{
+			Estimator: &v1alpha1.InternalEstimatorSpec{
+				Image: "estimator:test",
+				Node: v1alpha1.EstimatorGroup{
+					Total: &v1alpha1.EstimatorConfig{SidecarEnabled: true, InitUrl: "https://models/total.zip"},
+				},
+			},
+			ModelVerification: &v1alpha1.ModelVerificationSpec{SecretRef: "cosign"},
+		},
+		Status: v1alpha1.KeplerInternalStatus{
+			VerifiedModels: []v1alpha1.VerifiedModel{{URL: "https://models/total.zip", Digest: "sha256:ab"}},
+		},
+	}
+	spec := NewDaemonSet(components.Full, &k).Spec.Template.Spec
+	assert.Len(t, spec.InitContainers, 1)
+	check := spec.InitContainers[0]
+	assert.Equal(t, components.ModelDigestCheckName, check.Name)
+	assert.Equal(t, "estimator:test", check.Image)
+	assert.Equal(t, []string{"https://models/total.zip", "sha256:ab"}, check.Args[2:])
+
+	k.Spec.ModelVerification = nil
+	spec = NewDaemonSet(components.Full, &k).Spec.Template.Spec
+	assert.Empty(t, spec.InitContainers)
+}
+
 func TestRecordingRuleName(t *testing.T) {
 	tt := []struct {
 		keplerName string
@@ -274,3 +447,1140 @@ func TestRecordingRuleName(t *testing.T) {
 		})
 	}
 }
+
+func TestVersionFromImage(t *testing.T) {
+	tt := []struct {
+		image    string
+		version  string
+		scenario string
+	}{
+		{"quay.io/sustainable_computing_io/kepler:release-0.7.8", "0.7.8", "release tag"},
+		{"quay.io/sustainable_computing_io/kepler:v0.7.8", "0.7.8", "semver tag"},
+		{"quay.io/sustainable_computing_io/kepler:latest", "latest", "latest tag"},
+		{"localhost:5000/kepler:v0.7.8", "0.7.8", "registry with port"},
+		{"localhost:5000/kepler", "", "registry with port and no tag"},
+		{"quay.io/sustainable_computing_io/kepler", "", "no tag"},
+		{"quay.io/sustainable_computing_io/kepler:v0.7.8@sha256:abcd", "0.7.8", "tag and digest"},
+		{"quay.io/sustainable_computing_io/kepler@sha256:abcd", "", "digest only"},
+		{"", "", "empty image"},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.version, VersionFromImage(tc.image))
+		})
+	}
+}
+
+func TestEndpointsFromService(t *testing.T) {
+	k := v1alpha1.KeplerInternal{
+		ObjectMeta: metav1.ObjectMeta{Name: "kepler"},
+		Spec: v1alpha1.KeplerInternalSpec{
+			Exporter: v1alpha1.InternalExporterSpec{
+				Deployment: v1alpha1.InternalExporterDeploymentSpec{
+					ExporterDeploymentSpec: v1alpha1.ExporterDeploymentSpec{Port: 9103},
+					Namespace:              "kepler-operator",
+				},
+			},
+		},
+	}
+	expected := []v1alpha1.Endpoint{{
+		Kind:      "Service",
+		Name:      "kepler",
+		Namespace: "kepler-operator",
+		URL:       "http://kepler.kepler-operator.svc:9103/metrics",
+	}}
+	assert.Equal(t, expected, EndpointsFromService(NewService(&k)))
+}
+
+func TestConfigHash(t *testing.T) {
+	config := map[string]string{"KEPLER_LOG_LEVEL": "1", "ENABLE_GPU": "true"}
+
+	assert.Equal(t, ConfigHash(config), ConfigHash(map[string]string{"ENABLE_GPU": "true", "KEPLER_LOG_LEVEL": "1"}))
+	assert.NotEqual(t, ConfigHash(config), ConfigHash(map[string]string{"KEPLER_LOG_LEVEL": "2", "ENABLE_GPU": "true"}))
+	assert.NotEqual(t, ConfigHash(config), ConfigHash(map[string]string{"KEPLER_LOG_LEVEL": "1"}))
+
+	// the hash of existing daemonsets must not change across releases
+	assert.Equal(t, "b612039dbba370a7", ConfigHash(config))
+}
+
+func TestConfigHashAnnotation(t *testing.T) {
+	withDeployment := func(port int32, nodeSelector map[string]string) *v1alpha1.KeplerInternal {
+		return newInternal(v1alpha1.InternalExporterSpec{
+			Deployment: v1alpha1.InternalExporterDeploymentSpec{
+				ExporterDeploymentSpec: v1alpha1.ExporterDeploymentSpec{
+					Port:         port,
+					NodeSelector: nodeSelector,
+				},
+			},
+		})
+	}
+	hash := func(ki *v1alpha1.KeplerInternal) string {
+		return k8s.AnnotationFromDS(NewDaemonSet(components.Full, ki))[ConfigHashAnnotation]
+	}
+
+	base := hash(withDeployment(9103, nil))
+	assert.NotEmpty(t, base)
+	assert.Equal(t, base, hash(withDeployment(9103, map[string]string{"node-role": "worker"})))
+	assert.NotEqual(t, base, hash(withDeployment(9104, nil)))
+}
+
+func TestServiceMonitorLabels(t *testing.T) {
+	tt := []struct {
+		spec     *v1alpha1.ServiceMonitorSpec
+		extra    map[string]string
+		scenario string
+	}{
+		{nil, nil, "default"},
+		{&v1alpha1.ServiceMonitorSpec{Enabled: true}, nil, "no labels"},
+		{
+			&v1alpha1.ServiceMonitorSpec{Enabled: true, Labels: map[string]string{"release": "kube-prometheus"}},
+			map[string]string{"release": "kube-prometheus"},
+			"labels",
+		},
+		{
+			&v1alpha1.ServiceMonitorSpec{Enabled: true, Labels: map[string]string{"app.kubernetes.io/component": "other"}},
+			nil,
+			"managed labels take precedence",
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			k := newInternal(v1alpha1.InternalExporterSpec{
+				ServiceMonitor: tc.spec,
+			})
+			sm := NewServiceMonitor(k)
+			assert.Equal(t, labels(k).Merge(tc.extra).ToMap(), sm.Labels)
+		})
+	}
+}
+
+func TestPodMonitor(t *testing.T) {
+	k := newInternal(v1alpha1.InternalExporterSpec{
+		PodMonitor: &v1alpha1.PodMonitorSpec{Enabled: true, Labels: map[string]string{"release": "kube-prometheus"}},
+	})
+	pm := NewPodMonitor(k)
+	assert.Equal(t, "kepler", pm.Namespace)
+	assert.Equal(t, "kube-prometheus", pm.Labels["release"])
+
+	// NOTE: the pod monitor must select the pods of the daemonset
+	ds := NewDaemonSet(components.Full, k)
+	assert.Equal(t, ds.Spec.Template.Labels, map[string]string(pm.Spec.Selector.MatchLabels))
+	assert.Equal(t, ServicePortName, pm.Spec.PodMetricsEndpoints[0].Port)
+}
+
+func TestMonitorRelabelings(t *testing.T) {
+	scrape := v1alpha1.ScrapeSpec{
+		Relabelings: []*monv1.RelabelConfig{{Action: "labeldrop", Regex: "pod"}},
+		MetricRelabelings: []*monv1.RelabelConfig{{
+			Action:       "drop",
+			SourceLabels: []monv1.LabelName{"__name__"},
+			Regex:        "kepler_container_.*_joules_total",
+		}},
+	}
+	k := newInternal(v1alpha1.InternalExporterSpec{
+		ServiceMonitor: &v1alpha1.ServiceMonitorSpec{Enabled: true, ScrapeSpec: scrape},
+		PodMonitor:     &v1alpha1.PodMonitorSpec{Enabled: true, ScrapeSpec: scrape},
+	})
+
+	// NOTE: relabelings in the spec are applied after the ones added by the operator
+	expected := append(instanceRelabelings(), scrape.Relabelings...)
+
+	sm := NewServiceMonitor(k).Spec.Endpoints[0]
+	assert.Equal(t, expected, sm.RelabelConfigs)
+	assert.Equal(t, scrape.MetricRelabelings, sm.MetricRelabelConfigs)
+
+	pm := NewPodMonitor(k).Spec.PodMetricsEndpoints[0]
+	assert.Equal(t, expected, pm.RelabelConfigs)
+	assert.Equal(t, scrape.MetricRelabelings, pm.MetricRelabelConfigs)
+}
+
+func TestMetricLabels(t *testing.T) {
+	k := newInternal(v1alpha1.InternalExporterSpec{
+		MetricLabels: &v1alpha1.MetricLabelsSpec{
+			ClusterName: "prod-1",
+			Region:      "eu-west-1",
+			Extra:       map[string]string{"env": "prod"},
+		},
+	})
+
+	expected := append(instanceRelabelings(), []*monv1.RelabelConfig{
+		{Action: "replace", Replacement: "prod-1", TargetLabel: "cluster"},
+		{Action: "replace", Replacement: "prod", TargetLabel: "env"},
+		{Action: "replace", Replacement: "eu-west-1", TargetLabel: "region"},
+	}...)
+	assert.Equal(t, expected, NewServiceMonitor(k).Spec.Endpoints[0].RelabelConfigs)
+	assert.Equal(t, expected, NewPodMonitor(k).Spec.PodMetricsEndpoints[0].RelabelConfigs)
+
+	assert.NoError(t, k.Spec.Exporter.MetricLabels.Validate())
+	for _, name := range []string{"instance", "cluster", "__address__", "team-a", "mode", "container_namespace", "pod_name"} {
+		labels := &v1alpha1.MetricLabelsSpec{Extra: map[string]string{name: "x"}}
+		assert.Error(t, labels.Validate(), name)
+	}
+}
+
+func TestIdlePower(t *testing.T) {
+	tt := []struct {
+		idlePower v1alpha1.IdlePowerAttribution
+		expose    string
+		dropped   bool
+		scenario  string
+	}{
+		{scenario: "default"},
+		{idlePower: v1alpha1.IdlePowerSpread, expose: "true", scenario: "spread"},
+		{idlePower: v1alpha1.IdlePowerNode, expose: "false", scenario: "node"},
+		{idlePower: v1alpha1.IdlePowerDrop, expose: "false", dropped: true, scenario: "drop"},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			k := newInternal(v1alpha1.InternalExporterSpec{
+				IdlePower: tc.idlePower,
+			})
+			cm := NewConfigMap(components.Full, k)
+			assert.Equal(t, tc.expose, cm.Data["EXPOSE_ESTIMATED_IDLE_POWER_METRICS"])
+
+			sm := NewServiceMonitor(k).Spec.Endpoints[0]
+			pm := NewPodMonitor(k).Spec.PodMetricsEndpoints[0]
+			if !tc.dropped {
+				assert.Empty(t, sm.MetricRelabelConfigs)
+				assert.Empty(t, pm.MetricRelabelConfigs)
+				return
+			}
+			drop := &monv1.RelabelConfig{
+				Action:       "drop",
+				Regex:        "kepler_(container|process)_.*;idle",
+				SourceLabels: []monv1.LabelName{"__name__", "mode"},
+			}
+			assert.Equal(t, []*monv1.RelabelConfig{drop}, sm.MetricRelabelConfigs)
+			assert.Equal(t, []*monv1.RelabelConfig{drop}, pm.MetricRelabelConfigs)
+
+			// NOTE: prometheus anchors the regex and joins the source labels with ;
+			re := regexp.MustCompile("^(?:" + drop.Regex + ")$")
+			assert.True(t, re.MatchString("kepler_container_joules_total;idle"))
+			assert.True(t, re.MatchString("kepler_process_package_joules_total;idle"))
+			assert.False(t, re.MatchString("kepler_container_joules_total;dynamic"))
+			assert.False(t, re.MatchString("kepler_node_platform_joules_total;idle"))
+		})
+	}
+}
+
+func TestValidateScrape(t *testing.T) {
+	tt := []struct {
+		scrape   v1alpha1.ScrapeSpec
+		valid    bool
+		scenario string
+	}{
+		{v1alpha1.ScrapeSpec{}, true, "default"},
+		{v1alpha1.ScrapeSpec{Interval: "1m", ScrapeTimeout: "1m"}, true, "timeout equals interval"},
+		{v1alpha1.ScrapeSpec{Interval: "1m", ScrapeTimeout: "90s"}, false, "timeout greater than interval"},
+		{v1alpha1.ScrapeSpec{ScrapeTimeout: "10s"}, false, "timeout greater than default interval"},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateScrape(tc.scrape)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, "must not be greater than the scrape interval")
+			}
+		})
+	}
+}
+
+func TestMonitorScrapeInterval(t *testing.T) {
+	tt := []struct {
+		scrape   v1alpha1.ScrapeSpec
+		interval monv1.Duration
+		timeout  monv1.Duration
+		scenario string
+	}{
+		{v1alpha1.ScrapeSpec{}, DefaultScrapeInterval, "", "default"},
+		{v1alpha1.ScrapeSpec{Interval: "1m"}, "1m", "", "interval"},
+		{v1alpha1.ScrapeSpec{Interval: "1m", ScrapeTimeout: "30s"}, "1m", "30s", "interval and timeout"},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			k := newInternal(v1alpha1.InternalExporterSpec{
+				ServiceMonitor: &v1alpha1.ServiceMonitorSpec{Enabled: true, ScrapeSpec: tc.scrape},
+				PodMonitor:     &v1alpha1.PodMonitorSpec{Enabled: true, ScrapeSpec: tc.scrape},
+			})
+			sm := NewServiceMonitor(k).Spec.Endpoints[0]
+			assert.Equal(t, tc.interval, sm.Interval)
+			assert.Equal(t, tc.timeout, sm.ScrapeTimeout)
+
+			pm := NewPodMonitor(k).Spec.PodMetricsEndpoints[0]
+			assert.Equal(t, tc.interval, pm.Interval)
+			assert.Equal(t, tc.timeout, pm.ScrapeTimeout)
+		})
+	}
+}
+
+func TestAlertsPrometheusRule(t *testing.T) {
+	k := newInternal(v1alpha1.InternalExporterSpec{
+		Alerts: &v1alpha1.AlertsSpec{Enabled: true},
+	})
+	rule := NewAlertsPrometheusRule(k)
+
+	// NOTE: alerts must not overwrite the recording rules
+	assert.NotEqual(t, NewPrometheusRule(k).Name, rule.Name)
+	assert.Equal(t, "kepler", rule.Namespace)
+
+	alerts := []string{}
+	for _, r := range rule.Spec.Groups[0].Rules {
+		alerts = append(alerts, r.Alert)
+		assert.Contains(t, r.Expr.StrVal, `namespace="kepler"`)
+	}
+	assert.ElementsMatch(t, []string{"KeplerExporterDown", "KeplerNodeMissingPowerMetrics", "KeplerMetricsStale"}, alerts)
+}
+
+func TestAggregationsPrometheusRule(t *testing.T) {
+	k := newInternal(v1alpha1.InternalExporterSpec{
+		Aggregations: &v1alpha1.AggregationsSpec{Enabled: true},
+	})
+	rule := NewAggregationsPrometheusRule(k)
+	assert.NotEqual(t, NewPrometheusRule(k).Name, rule.Name)
+	assert.NotEqual(t, NewAlertsPrometheusRule(k).Name, rule.Name)
+
+	records := map[string]string{}
+	for _, r := range rule.Spec.Groups[0].Rules {
+		records[r.Record] = r.Expr.StrVal
+	}
+	assert.Equal(t,
+		`sum by (container_namespace) (rate(kepler_container_joules_total{namespace="kepler"}[5m]))`,
+		records["namespace:kepler_container_joules:rate5m"])
+	assert.Equal(t,
+		`sum by (instance) (rate(kepler_container_joules_total{namespace="kepler"}[5m]))`,
+		records["node:kepler_container_joules:rate5m"])
+}
+
+func TestEnergyCostPrometheusRule(t *testing.T) {
+	withPrice := func(ep *v1alpha1.ElectricityPriceSpec) *v1alpha1.KeplerInternal {
+		return newInternal(v1alpha1.InternalExporterSpec{ElectricityPrice: ep})
+	}
+
+	rule, err := NewEnergyCostPrometheusRule(components.Full, withPrice(&v1alpha1.ElectricityPriceSpec{
+		Enabled:  true,
+		Currency: "EUR",
+		PerKWh:   "0.25",
+		Zones:    map[string]v1alpha1.PricePerKWh{"eu-west-1a": "0.31"},
+	}))
+	assert.NoError(t, err)
+
+	records := map[string]string{}
+	for _, r := range rule.Spec.Groups[0].Rules {
+		records[r.Record] = r.Expr.StrVal
+	}
+	assert.Equal(t,
+		`label_replace(`+
+			`0 * max by (instance) (label_replace(kube_node_labels{label_topology_kubernetes_io_zone="eu-west-1a"}, "instance", "$1", "node", "(.*)")) + 0.31`+
+			` or 0 * max by (instance) (kepler_node_info{namespace="kepler"}) + 0.25, "currency", "EUR", "", "")`,
+		records["node:kepler_electricity_price:per_kwh"])
+	assert.Equal(t,
+		`sum by (container_namespace, pod_name, instance) (rate(kepler_container_joules_total{namespace="kepler"}[5m]))`+
+			` / 3600000 * on (instance) group_left (currency) node:kepler_electricity_price:per_kwh`,
+		records["pod:kepler_container_energy_cost:rate5m"])
+	assert.Contains(t, records, "namespace:kepler_container_energy_cost:rate5m")
+
+	_, err = NewEnergyCostPrometheusRule(components.Full, withPrice(&v1alpha1.ElectricityPriceSpec{Enabled: true, Currency: "EUR"}))
+	assert.Error(t, err)
+}
+
+func TestTariffPrices(t *testing.T) {
+	ep := &v1alpha1.ElectricityPriceSpec{
+		Enabled:          true,
+		Currency:         "EUR",
+		PerKWh:           "0.25",
+		UTCOffsetMinutes: 60,
+		Tariffs: []v1alpha1.ElectricityTariffSpec{{
+			Name: "peak", Start: "07:00", End: "22:00", PerKWh: "0.35",
+			Days: []v1alpha1.TariffDay{"Monday", "Friday"},
+		}, {
+			Name: "off-peak", Start: "22:00", End: "07:00", PerKWh: "0.15",
+		}},
+	}
+
+	now := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+	exprs, err := tariffPrices("kepler", ep, now)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		`label_replace(0 * max by (instance) (kepler_node_info{namespace="kepler"}) + 0.35, "tariff", "peak", "", "")` +
+			` and on () (vector(time() + 3600) % 86400 / 60 >= 420 < 1320` +
+			` and on () (day_of_week(vector(time() + 3600)) == 1 or day_of_week(vector(time() + 3600)) == 5))`,
+		`label_replace(0 * max by (instance) (kepler_node_info{namespace="kepler"}) + 0.15, "tariff", "off-peak", "", "")` +
+			` and on () ((vector(time() + 3600) % 86400 / 60 >= 1320 or vector(time() + 3600) % 86400 / 60 < 420))`,
+	}, exprs)
+
+	k := newInternal(v1alpha1.InternalExporterSpec{
+		ElectricityPrice: ep,
+	})
+	price, err := nodePrice(k)
+	assert.NoError(t, err)
+	assert.Equal(t, `label_replace(`+exprs[0]+` or on (instance) `+exprs[1]+
+		` or on (instance) (0 * max by (instance) (kepler_node_info{namespace="kepler"}) + 0.25), "currency", "EUR", "", "")`, price)
+
+	t.Run("time zone", func(t *testing.T) {
+		ep := ep.DeepCopy()
+		ep.TimeZone = "Europe/Berlin"
+		exprs, err := tariffPrices("kepler", ep, now)
+		assert.NoError(t, err)
+		// NOTE: summer time of 2024 and 2025 starts at 01:00 UTC on the last
+		// Sunday of March and ends at 01:00 UTC on the last Sunday of October
+		local := `(vector(time()) + (` +
+			`vector(3600) and on () vector(time()) < 1711846800` +
+			` or vector(7200) and on () vector(time()) >= 1711846800 < 1729990800` +
+			` or vector(3600) and on () vector(time()) >= 1729990800 < 1743296400` +
+			` or vector(7200) and on () vector(time()) >= 1743296400 < 1761440400` +
+			` or vector(3600) and on () vector(time()) >= 1761440400))`
+		assert.Equal(t,
+			`label_replace(0 * max by (instance) (kepler_node_info{namespace="kepler"}) + 0.15, "tariff", "off-peak", "", "")`+
+				` and on () ((`+local+` % 86400 / 60 >= 1320 or `+local+` % 86400 / 60 < 420))`,
+			exprs[1])
+
+		ep.TimeZone = "UTC"
+		exprs, err = tariffPrices("kepler", ep, now)
+		assert.NoError(t, err)
+		assert.Contains(t, exprs[1], `vector(time() + 0) % 86400 / 60 >= 1320`)
+
+		ep.TimeZone = "Europe/Nowhere"
+		_, err = tariffPrices("kepler", ep, now)
+		assert.ErrorContains(t, err, "invalid time zone")
+	})
+
+	t.Run("invalid windows", func(t *testing.T) {
+		ep := ep.DeepCopy()
+		ep.Tariffs[0].Start = "24:00"
+		_, err = tariffPrices("kepler", ep, now)
+		assert.Error(t, err)
+
+		ep.Tariffs[0].Start = ep.Tariffs[0].End
+		_, err = tariffPrices("kepler", ep, now)
+		assert.ErrorContains(t, err, "the window must not be empty")
+	})
+}
+
+func TestIdleWastePrometheusRule(t *testing.T) {
+	k := newInternal(v1alpha1.InternalExporterSpec{
+		IdleWaste: &v1alpha1.IdleWasteSpec{Enabled: true},
+	})
+
+	rule := NewIdleWastePrometheusRule(components.Full, k)
+	records := map[string]string{}
+	for _, r := range rule.Spec.Groups[0].Rules {
+		records[r.Record] = r.Expr.StrVal
+	}
+	assert.Equal(t,
+		`pod:kepler_container_power:watts > 1 and on (container_namespace, pod_name) pod:container_cpu_usage:cores < 0.05`,
+		records["pod:kepler_idle_waste:watts"])
+	assert.Contains(t, records, "workload:kepler_idle_waste:watts")
+	assert.Contains(t, records, "namespace:kepler_idle_waste:kwh_1d")
+
+	k.Spec.Exporter.IdleWaste.CPUThreshold = "0.1"
+	k.Spec.Exporter.IdleWaste.MinPowerWatts = "2.5"
+	rule = NewIdleWastePrometheusRule(components.Full, k)
+	assert.Equal(t,
+		`pod:kepler_container_power:watts > 2.5 and on (container_namespace, pod_name) pod:container_cpu_usage:cores < 0.1`,
+		rule.Spec.Groups[0].Rules[2].Expr.StrVal)
+}
+
+func TestPUEPrometheusRule(t *testing.T) {
+	k := newInternal(v1alpha1.InternalExporterSpec{
+		PUE: &v1alpha1.PUESpec{Enabled: true},
+	})
+
+	rule := NewPUEPrometheusRule(components.Full, k)
+	assert.Equal(t, "kepler-internal-pue", rule.Name)
+	records := map[string]string{}
+	for _, r := range rule.Spec.Groups[0].Rules {
+		records[r.Record] = r.Expr.StrVal
+	}
+	assert.Equal(t, `0 * max by (instance) (kepler_node_info{namespace="kepler"}) + 1`, records["node:kepler_pue:ratio"])
+	assert.Equal(t,
+		`sum by (instance) (rate(kepler_node_platform_joules_total{namespace="kepler"}[5m])) * on (instance) node:kepler_pue:ratio`,
+		records["node:kepler_facility_power:watts"])
+	assert.Contains(t, records, "namespace:kepler_facility_power:watts")
+	assert.Contains(t, records, "cluster:kepler_facility_power:watts")
+	assert.NotContains(t, records, "zone:kepler_facility_power:watts")
+
+	k.Spec.Exporter.PUE.Factor = "1.2"
+	k.Spec.Exporter.PUE.Zones = map[string]v1alpha1.PUEFactor{"eu-west-1a": "1.4"}
+	rule = NewPUEPrometheusRule(components.Full, k)
+	records = map[string]string{}
+	for _, r := range rule.Spec.Groups[0].Rules {
+		records[r.Record] = r.Expr.StrVal
+	}
+	assert.Equal(t,
+		`0 * max by (instance) (label_replace(kube_node_labels{label_topology_kubernetes_io_zone="eu-west-1a"}, "instance", "$1", "node", "(.*)")) + 1.4`+
+			` or 0 * max by (instance) (kepler_node_info{namespace="kepler"}) + 1.2`,
+		records["node:kepler_pue:ratio"])
+	assert.Contains(t, records, "zone:kepler_facility_power:watts")
+}
+
+func TestOpenCost(t *testing.T) {
+	k := newInternal(v1alpha1.InternalExporterSpec{
+		OpenCost: &v1alpha1.OpenCostSpec{
+			Enabled:      true,
+			ScrapeConfig: &v1alpha1.OpenCostScrapeConfigSpec{Enabled: true, Namespace: "opencost"},
+		},
+	})
+
+	rule, err := NewOpenCostPrometheusRule(components.Full, k)
+	assert.NoError(t, err)
+	assert.Equal(t, "kepler-internal-opencost", rule.Name)
+	rules := rule.Spec.Groups[0].Rules
+	assert.Len(t, rules, 2)
+	assert.Equal(t, "container:kepler_power:watts", rules[0].Record)
+	assert.Equal(t,
+		`sum by (node) (label_replace(rate(kepler_node_platform_joules_total{namespace="kepler"}[5m]), "node", "$1", "instance", "(.*)"))`,
+		rules[1].Expr.StrVal)
+
+	k.Spec.Exporter.ElectricityPrice = &v1alpha1.ElectricityPriceSpec{Enabled: true, Currency: "EUR", PerKWh: "0.25"}
+	rule, err = NewOpenCostPrometheusRule(components.Full, k)
+	assert.NoError(t, err)
+	rules = rule.Spec.Groups[0].Rules
+	assert.Len(t, rules, 3)
+	assert.Equal(t, "container:kepler_energy_cost:hourly", rules[2].Record)
+	assert.Contains(t, rules[2].Expr.StrVal, `* on (node) group_left (currency) label_replace(`)
+
+	secret, err := NewOpenCostScrapeConfig(components.Full, k, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "opencost", secret.Namespace)
+	assert.Contains(t, secret.StringData[OpenCostScrapeConfigKey], "regex: kepler-internal;http")
+	assert.NotContains(t, secret.StringData[OpenCostScrapeConfigKey], "tls_config")
+	assert.Contains(t, secret.StringData[OpenCostRulesKey], "record: container:kepler_energy_cost:hourly")
+
+	k.Spec.Exporter.RBACProxy = &v1alpha1.InternalRBACProxySpec{RBACProxySpec: v1alpha1.RBACProxySpec{Enabled: true}}
+	secret, err = NewOpenCostScrapeConfig(components.Full, k, []byte("ca-bundle"))
+	assert.NoError(t, err)
+	cfg := secret.StringData[OpenCostScrapeConfigKey]
+	assert.Contains(t, cfg, "scheme: https")
+	assert.Contains(t, cfg, "server_name: kepler-internal.kepler.svc")
+	assert.Contains(t, cfg, "ca: ca-bundle")
+	assert.NotContains(t, cfg, "insecure_skip_verify")
+	assert.Equal(t, "kepler-internal-tls", MetricsCA(k).Secret.Name)
+
+	binding := NewOpenCostMetricsReaderBinding(components.Full, k)
+	assert.Equal(t, "kepler-internal-metrics-reader", binding.RoleRef.Name)
+	assert.Equal(t, "prometheus-server", binding.Subjects[0].Name)
+	assert.Equal(t, "opencost", binding.Subjects[0].Namespace)
+}
+
+func TestGrafanaDashboards(t *testing.T) {
+	tt := []struct {
+		spec      *v1alpha1.GrafanaDashboardsSpec
+		namespace string
+		sidecar   map[string]string
+		scenario  string
+	}{
+		{
+			&v1alpha1.GrafanaDashboardsSpec{Enabled: true},
+			"kepler",
+			map[string]string{"grafana_dashboard": "1"},
+			"default",
+		},
+		{
+			&v1alpha1.GrafanaDashboardsSpec{Enabled: true, Namespace: "monitoring", Labels: map[string]string{"dashboards": "kepler"}},
+			"monitoring",
+			map[string]string{"dashboards": "kepler"},
+			"namespace and labels",
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			k := newInternal(v1alpha1.InternalExporterSpec{})
+			k.Spec.Dashboards = v1alpha1.InternalDashboardsSpec{Grafana: tc.spec}
+			cms := NewGrafanaDashboards(components.Full, k)
+			assert.Len(t, cms, 2)
+			for _, cm := range cms {
+				assert.Equal(t, tc.namespace, cm.Namespace)
+				assert.Equal(t, labels(k).Merge(tc.sidecar).ToMap(), cm.Labels)
+				assert.Len(t, cm.Data, 1)
+			}
+		})
+	}
+}
+
+func TestGrafanaOperatorResources(t *testing.T) {
+	k := newInternal(v1alpha1.InternalExporterSpec{})
+	k.Spec.Dashboards = v1alpha1.InternalDashboardsSpec{Grafana: &v1alpha1.GrafanaDashboardsSpec{
+		Enabled:          true,
+		Kind:             v1alpha1.GrafanaOperatorDashboards,
+		Namespace:        "grafana",
+		InstanceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"dashboards": "grafana"}},
+		Datasource:       &v1alpha1.GrafanaDatasourceSpec{URL: "https://thanos-querier.openshift-monitoring.svc:9091", SecretRef: "grafana-token"},
+	}}
+
+	dashboards := NewGrafanaOperatorDashboards(components.Full, k)
+	assert.Len(t, dashboards, 2)
+	for _, d := range dashboards {
+		assert.Equal(t, GrafanaDashboardGVK, d.GroupVersionKind())
+		assert.Equal(t, "grafana", d.GetNamespace())
+		selector, _, _ := unstructured.NestedStringMap(d.Object, "spec", "instanceSelector", "matchLabels")
+		assert.Equal(t, map[string]string{"dashboards": "grafana"}, selector)
+		json, _, _ := unstructured.NestedString(d.Object, "spec", "json")
+		assert.NotEmpty(t, json)
+	}
+
+	ds := NewGrafanaDatasource(components.Full, k)
+	assert.Equal(t, GrafanaDatasourceGVK, ds.GroupVersionKind())
+	url, _, _ := unstructured.NestedString(ds.Object, "spec", "datasource", "url")
+	assert.Equal(t, "https://thanos-querier.openshift-monitoring.svc:9091", url)
+	valuesFrom, _, _ := unstructured.NestedSlice(ds.Object, "spec", "valuesFrom")
+	assert.Len(t, valuesFrom, 1)
+}
+
+func TestScrapeAnnotations(t *testing.T) {
+	tt := []struct {
+		spec     *v1alpha1.ScrapeAnnotationsSpec
+		pod      []string
+		svc      []string
+		scenario string
+	}{
+		{nil, nil, nil, "default"},
+		{
+			&v1alpha1.ScrapeAnnotationsSpec{Prometheus: true},
+			[]string{"prometheus.io/scrape", "prometheus.io/port", "prometheus.io/path"},
+			[]string{"prometheus.io/scrape", "prometheus.io/port", "prometheus.io/path"},
+			"prometheus",
+		},
+		{
+			&v1alpha1.ScrapeAnnotationsSpec{Datadog: true},
+			[]string{"ad.datadoghq.com/kepler-internal.checks"},
+			nil,
+			"datadog",
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			k := newInternal(v1alpha1.InternalExporterSpec{
+				Deployment: v1alpha1.InternalExporterDeploymentSpec{
+					ExporterDeploymentSpec: v1alpha1.ExporterDeploymentSpec{Port: 9103},
+				},
+				ScrapeAnnotations: tc.spec,
+			})
+			ds := NewDaemonSet(components.Full, k)
+			svc := NewService(k)
+
+			podAnnotations := ds.Spec.Template.Annotations
+			assert.Len(t, podAnnotations, len(tc.pod)+1) // config hash
+			for _, a := range tc.pod {
+				assert.Contains(t, podAnnotations, a)
+			}
+			assert.Len(t, svc.Annotations, len(tc.svc))
+			for _, a := range tc.svc {
+				assert.Contains(t, svc.Annotations, a)
+			}
+			if tc.spec != nil && tc.spec.Prometheus {
+				assert.Equal(t, "9103", podAnnotations["prometheus.io/port"])
+			}
+		})
+	}
+}
+
+func TestRBACProxy(t *testing.T) {
+	tt := []struct {
+		openshift    bool
+		tlsSecretRef string
+		certManager  *v1alpha1.CertManagerSpec
+		tlsSecret    string
+		scenario     string
+	}{
+		{tlsSecret: "kepler-internal-tls", scenario: "operator managed certificate"},
+		{openshift: true, tlsSecret: "kepler-internal-tls", scenario: "openshift serving certificate"},
+		{tlsSecretRef: "kepler-certs", tlsSecret: "kepler-certs", scenario: "certificate in spec"},
+		{
+			openshift:   true,
+			certManager: &v1alpha1.CertManagerSpec{IssuerRef: v1alpha1.IssuerReference{Name: "ca-issuer"}},
+			tlsSecret:   "kepler-internal-tls",
+			scenario:    "cert-manager certificate",
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			k := newInternal(v1alpha1.InternalExporterSpec{
+				Deployment: v1alpha1.InternalExporterDeploymentSpec{
+					ExporterDeploymentSpec: v1alpha1.ExporterDeploymentSpec{Port: 9103},
+				},
+				RBACProxy: &v1alpha1.InternalRBACProxySpec{
+					RBACProxySpec: v1alpha1.RBACProxySpec{
+						Enabled:      true,
+						TLSSecretRef: tc.tlsSecretRef,
+						CertManager:  tc.certManager,
+					},
+					Image: "kube-rbac-proxy:test",
+				},
+			})
+			k.Spec.OpenShift = v1alpha1.OpenShiftSpec{Enabled: tc.openshift}
+
+			ds := NewDaemonSet(components.Full, k)
+			containers := ds.Spec.Template.Spec.Containers
+			assert.Len(t, containers, 2)
+			assert.Contains(t, k8s.CommandFromDS(ds, KeplerContainerIndex), "127.0.0.1:9102")
+			assert.Empty(t, containers[KeplerContainerIndex].Ports)
+
+			proxy := containers[1]
+			assert.Equal(t, "kube-rbac-proxy:test", proxy.Image)
+			assert.Contains(t, proxy.Args, "--secure-listen-address=0.0.0.0:9103")
+			assert.Contains(t, proxy.Args, "--upstream=http://127.0.0.1:9102/")
+			assert.Equal(t, ServicePortName, proxy.Ports[0].Name)
+
+			tlsSecret := ""
+			for _, v := range ds.Spec.Template.Spec.Volumes {
+				if v.Secret != nil {
+					tlsSecret = v.Secret.SecretName
+				}
+			}
+			assert.Equal(t, tc.tlsSecret, tlsSecret)
+			assert.Equal(t, []string{tc.tlsSecret}, PodSecrets(k))
+
+			svc := NewService(k)
+			assert.Equal(t, "https://kepler-internal.kepler.svc:9103/metrics", EndpointsFromService(svc)[0].URL)
+			if tc.openshift && tc.certManager == nil {
+				assert.Equal(t, "kepler-internal-tls", svc.Annotations[servingCertAnnotation])
+			} else {
+				assert.NotContains(t, svc.Annotations, servingCertAnnotation)
+			}
+
+			endpoint := NewServiceMonitor(k).Spec.Endpoints[0]
+			assert.Equal(t, "https", endpoint.Scheme)
+			assert.Equal(t, serviceAccountTokenFile, endpoint.BearerTokenFile)
+			tls := endpoint.TLSConfig
+			assert.Equal(t, "kepler-internal.kepler.svc", tls.ServerName)
+			assert.False(t, tls.InsecureSkipVerify)
+			if tc.openshift && tc.certManager == nil {
+				assert.Equal(t, ServiceCAConfigMap, tls.CA.ConfigMap.Name)
+			} else {
+				assert.Equal(t, tc.tlsSecret, tls.CA.Secret.Name)
+			}
+
+			managed := !tc.openshift && tc.tlsSecretRef == "" && tc.certManager == nil
+			assert.Equal(t, managed, ManagesServingCert(k))
+			if managed {
+				assert.Equal(t, tc.tlsSecret, NewServingCertSecret(k).Name)
+				assert.Equal(t, "kepler-internal-ca", NewServingCASecret(k).Name)
+			}
+
+			rules := NewClusterRole(components.Full, k).Rules
+			assert.Subset(t, rules, rbacProxyRules)
+		})
+	}
+}
+
+func TestRBACProxyTokenAudiences(t *testing.T) {
+	tt := []struct {
+		audiences []string
+		arg       string
+		scenario  string
+	}{
+		{scenario: "any audience"},
+		{
+			audiences: []string{"https://kubernetes.default.svc", "kepler"},
+			arg:       "--auth-token-audiences=https://kubernetes.default.svc,kepler",
+			scenario:  "bound audiences",
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			k := v1alpha1.KeplerInternal{
+				ObjectMeta: metav1.ObjectMeta{Name: "kepler-internal"},
+				Spec: v1alpha1.KeplerInternalSpec{
+					Exporter: v1alpha1.InternalExporterSpec{
+						RBACProxy: &v1alpha1.InternalRBACProxySpec{
+							RBACProxySpec: v1alpha1.RBACProxySpec{Enabled: true, TokenAudiences: tc.audiences},
+						},
+					},
+				},
+			}
+			args := NewDaemonSet(components.Full, &k).Spec.Template.Spec.Containers[1].Args
+			audienceArgs := []string{}
+			for _, arg := range args {
+				if strings.HasPrefix(arg, "--auth-token-audiences") {
+					audienceArgs = append(audienceArgs, arg)
+				}
+			}
+			if tc.arg == "" {
+				assert.Empty(t, audienceArgs)
+				return
+			}
+			assert.Equal(t, []string{tc.arg}, audienceArgs)
+		})
+	}
+}
+
+func TestRBACProxyTLS(t *testing.T) {
+	k := v1alpha1.KeplerInternal{
+		ObjectMeta: metav1.ObjectMeta{Name: "kepler-internal"},
+		Spec: v1alpha1.KeplerInternalSpec{
+			Exporter: v1alpha1.InternalExporterSpec{
+				RBACProxy: &v1alpha1.InternalRBACProxySpec{
+					RBACProxySpec: v1alpha1.RBACProxySpec{Enabled: true},
+				},
+			},
+		},
+	}
+	tlsArgs := func() []string {
+		args := []string{}
+		for _, arg := range NewDaemonSet(components.Full, &k).Spec.Template.Spec.Containers[1].Args {
+			if strings.HasPrefix(arg, "--tls-min-version") || strings.HasPrefix(arg, "--tls-cipher-suites") {
+				args = append(args, arg)
+			}
+		}
+		return args
+	}
+	assert.Empty(t, tlsArgs())
+
+	k.Spec.Exporter.RBACProxy.TLSMinVersion = "VersionTLS12"
+	k.Spec.Exporter.RBACProxy.TLSCipherSuites = []string{
+		"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+		"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+	}
+	assert.Equal(t, []string{
+		"--tls-min-version=VersionTLS12",
+		"--tls-cipher-suites=TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+	}, tlsArgs())
+}
+
+func TestScrapeServiceAccount(t *testing.T) {
+	tt := []struct {
+		spec       *v1alpha1.ScrapeServiceAccountSpec
+		podMonitor *v1alpha1.PodMonitorSpec
+		enabled    bool
+		scenario   string
+	}{
+		{spec: nil, enabled: false, scenario: "not configured"},
+		{spec: &v1alpha1.ScrapeServiceAccountSpec{}, enabled: false, scenario: "disabled"},
+		{spec: &v1alpha1.ScrapeServiceAccountSpec{Enabled: true}, enabled: true, scenario: "enabled"},
+		{podMonitor: &v1alpha1.PodMonitorSpec{Enabled: true}, enabled: true, scenario: "pod monitor"},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			k := newInternal(v1alpha1.InternalExporterSpec{
+				Deployment: v1alpha1.InternalExporterDeploymentSpec{
+					ExporterDeploymentSpec: v1alpha1.ExporterDeploymentSpec{Port: 9103},
+				},
+				RBACProxy: &v1alpha1.InternalRBACProxySpec{
+					RBACProxySpec: v1alpha1.RBACProxySpec{
+						Enabled:              true,
+						ScrapeServiceAccount: tc.spec,
+					},
+				},
+				PodMonitor: tc.podMonitor,
+			})
+			assert.Equal(t, tc.enabled, NeedsScrapeServiceAccount(k))
+
+			smEndpoint := NewServiceMonitor(k).Spec.Endpoints[0]
+			pmEndpoint := NewPodMonitor(k).Spec.PodMetricsEndpoints[0]
+			if !tc.enabled {
+				assert.Equal(t, serviceAccountTokenFile, smEndpoint.BearerTokenFile)
+				assert.Nil(t, smEndpoint.Authorization)
+				assert.Nil(t, pmEndpoint.Authorization)
+				return
+			}
+
+			assert.Empty(t, smEndpoint.BearerTokenFile)
+			for _, auth := range []*monv1.SafeAuthorization{smEndpoint.Authorization, pmEndpoint.Authorization} {
+				assert.Equal(t, "Bearer", auth.Type)
+				assert.Equal(t, "kepler-internal-scraper-token", auth.Credentials.Name)
+				assert.Equal(t, "token", auth.Credentials.Key)
+			}
+
+			sa := NewScrapeServiceAccount(k)
+			secret := NewScrapeTokenSecret(k)
+			assert.Equal(t, "kepler", sa.Namespace)
+			assert.Equal(t, corev1.SecretTypeServiceAccountToken, secret.Type)
+			assert.Equal(t, sa.Name, secret.Annotations[corev1.ServiceAccountNameKey])
+
+			role := NewMetricsReaderClusterRole(components.Full, k)
+			assert.Equal(t, []string{"/metrics"}, role.Rules[0].NonResourceURLs)
+			assert.Equal(t, []string{"get"}, role.Rules[0].Verbs)
+
+			binding := NewMetricsReaderClusterRoleBinding(components.Full, k)
+			assert.Equal(t, role.Name, binding.RoleRef.Name)
+			assert.Equal(t, sa.Name, binding.Subjects[0].Name)
+			assert.Equal(t, sa.Namespace, binding.Subjects[0].Namespace)
+		})
+	}
+}
+
+func TestNetworkPolicy(t *testing.T) {
+	tt := []struct {
+		spec       *v1alpha1.NetworkPolicySpec
+		openshift  bool
+		namespaces []string
+		egress     int
+		scenario   string
+	}{
+		{
+			spec:       &v1alpha1.NetworkPolicySpec{Enabled: true},
+			namespaces: []string{"monitoring"},
+			egress:     2,
+			scenario:   "default monitoring namespace",
+		},
+		{
+			spec:       &v1alpha1.NetworkPolicySpec{Enabled: true},
+			openshift:  true,
+			namespaces: []string{"openshift-monitoring", "openshift-user-workload-monitoring"},
+			egress:     2,
+			scenario:   "openshift monitoring namespaces",
+		},
+		{
+			spec: &v1alpha1.NetworkPolicySpec{
+				Enabled:              true,
+				MonitoringNamespaces: []string{"prometheus"},
+				Egress: []networkingv1.NetworkPolicyEgressRule{
+					components.TCPEgressRule(8443),
+				},
+			},
+			namespaces: []string{"prometheus"},
+			egress:     3,
+			scenario:   "user defined namespaces and egress",
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			k := newInternal(v1alpha1.InternalExporterSpec{
+				Deployment: v1alpha1.InternalExporterDeploymentSpec{
+					ExporterDeploymentSpec: v1alpha1.ExporterDeploymentSpec{Port: 9103},
+				},
+			})
+			k.Spec.OpenShift = v1alpha1.OpenShiftSpec{Enabled: tc.openshift}
+			k.Spec.NetworkPolicy = tc.spec
+			np := NewNetworkPolicy(components.Full, k)
+			ds := NewDaemonSet(components.Full, k)
+
+			assert.Equal(t, ds.Spec.Selector.MatchLabels, np.Spec.PodSelector.MatchLabels)
+			ingress := np.Spec.Ingress[0]
+			assert.Equal(t, int32(9103), ingress.Ports[0].Port.IntVal)
+			assert.Equal(t, tc.namespaces, ingress.From[0].NamespaceSelector.MatchExpressions[0].Values)
+			assert.Len(t, np.Spec.Egress, tc.egress)
+		})
+	}
+}
+
+func TestVerticalPodAutoscaler(t *testing.T) {
+	tt := []struct {
+		spec       *v1alpha1.VerticalPodAutoscalerSpec
+		updateMode string
+		maxAllowed map[string]interface{}
+		scenario   string
+	}{
+		{
+			spec:       &v1alpha1.VerticalPodAutoscalerSpec{Enabled: true},
+			updateMode: "Off",
+			scenario:   "default update mode",
+		},
+		{
+			spec: &v1alpha1.VerticalPodAutoscalerSpec{
+				Enabled:    true,
+				UpdateMode: v1alpha1.VPAUpdateModeAuto,
+				MaxAllowed: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+			},
+			updateMode: "Auto",
+			maxAllowed: map[string]interface{}{"memory": "512Mi"},
+			scenario:   "auto with max allowed",
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			k := newInternal(v1alpha1.InternalExporterSpec{VerticalPodAutoscaler: tc.spec})
+			vpa := NewVerticalPodAutoscaler(components.Full, k)
+			ds := NewDaemonSet(components.Full, k)
+
+			target, _, _ := unstructured.NestedString(vpa.Object, "spec", "targetRef", "name")
+			assert.Equal(t, ds.Name, target)
+			mode, _, _ := unstructured.NestedString(vpa.Object, "spec", "updatePolicy", "updateMode")
+			assert.Equal(t, tc.updateMode, mode)
+
+			policies, _, _ := unstructured.NestedSlice(vpa.Object, "spec", "resourcePolicy", "containerPolicies")
+			policy := policies[0].(map[string]interface{})
+			assert.Equal(t, ds.Spec.Template.Spec.Containers[KeplerContainerIndex].Name, policy["containerName"])
+			if tc.maxAllowed != nil {
+				assert.Equal(t, tc.maxAllowed, policy["maxAllowed"])
+			} else {
+				assert.NotContains(t, policy, "maxAllowed")
+			}
+		})
+	}
+}
+
+func TestCertificate(t *testing.T) {
+	k := newInternal(v1alpha1.InternalExporterSpec{
+		RBACProxy: &v1alpha1.InternalRBACProxySpec{
+			RBACProxySpec: v1alpha1.RBACProxySpec{
+				Enabled: true,
+				CertManager: &v1alpha1.CertManagerSpec{
+					IssuerRef: v1alpha1.IssuerReference{Name: "ca-issuer", Kind: "ClusterIssuer"},
+				},
+			},
+		},
+	})
+	cert := NewCertificate(components.Full, k)
+
+	secret, _, _ := unstructured.NestedString(cert.Object, "spec", "secretName")
+	assert.Equal(t, "kepler-internal-tls", secret)
+	dnsNames, _, _ := unstructured.NestedStringSlice(cert.Object, "spec", "dnsNames")
+	assert.Contains(t, dnsNames, "kepler-internal.kepler.svc")
+	issuer, _, _ := unstructured.NestedStringMap(cert.Object, "spec", "issuerRef")
+	assert.Equal(t, map[string]string{"name": "ca-issuer", "kind": "ClusterIssuer", "group": "cert-manager.io"}, issuer)
+}
+
+func TestNodeFeatureDiscovery(t *testing.T) {
+	tt := []struct {
+		spec         *v1alpha1.NodeFeatureDiscoverySpec
+		nodeSelector map[string]string
+		scenario     string
+	}{
+		{
+			spec:         nil,
+			nodeSelector: map[string]string{"kubernetes.io/os": "linux"},
+			scenario:     "disabled",
+		},
+		{
+			spec:         &v1alpha1.NodeFeatureDiscoverySpec{Enabled: true},
+			nodeSelector: map[string]string{"kubernetes.io/os": "linux"},
+			scenario:     "labels only",
+		},
+		{
+			spec:         &v1alpha1.NodeFeatureDiscoverySpec{Enabled: true, RestrictToCapableNodes: true},
+			nodeSelector: map[string]string{"kubernetes.io/os": "linux", EBPFNodeLabel: "true"},
+			scenario:     "restricted to capable nodes",
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			k := newInternal(v1alpha1.InternalExporterSpec{NodeFeatureDiscovery: tc.spec})
+			ds := NewDaemonSet(components.Full, k)
+			assert.Equal(t, tc.nodeSelector, ds.Spec.Template.Spec.NodeSelector)
+
+			rule := NewNodeFeatureRule(components.Full, k)
+			assert.Equal(t, "", rule.GetNamespace())
+			rules, _, _ := unstructured.NestedSlice(rule.Object, "spec", "rules")
+			nodeLabels := map[string]interface{}{}
+			for _, r := range rules {
+				for l, v := range r.(map[string]interface{})["labels"].(map[string]interface{}) {
+					nodeLabels[l] = v
+				}
+			}
+			assert.Equal(t, map[string]interface{}{RAPLNodeLabel: "true", EBPFNodeLabel: "true"}, nodeLabels)
+		})
+	}
+}
+
+func TestVirtualMachines(t *testing.T) {
+	tt := []struct {
+		spec     *v1alpha1.VirtualMachinesSpec
+		hostPath string
+		scenario string
+	}{
+		{
+			spec:     nil,
+			scenario: "disabled",
+		},
+		{
+			spec:     &v1alpha1.VirtualMachinesSpec{Enabled: true},
+			hostPath: "/var/run/libvirt",
+			scenario: "default libvirt run dir",
+		},
+		{
+			spec:     &v1alpha1.VirtualMachinesSpec{Enabled: true, LibvirtRunDir: "/run/libvirt"},
+			hostPath: "/run/libvirt",
+			scenario: "custom libvirt run dir",
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			k := newInternal(v1alpha1.InternalExporterSpec{VirtualMachines: tc.spec})
+			ds := NewDaemonSet(components.Full, k)
+			cm := NewConfigMap(components.Full, k)
+
+			hostPath := ""
+			for _, v := range ds.Spec.Template.Spec.Volumes {
+				if v.Name == "libvirt" {
+					hostPath = v.HostPath.Path
+				}
+			}
+			assert.Equal(t, tc.hostPath, hostPath)
+
+			mounts := k8s.VolumeMountsFromDS(ds, KeplerContainerIndex)
+			if tc.hostPath == "" {
+				assert.NotContains(t, cm.Data, "EXPOSE_VM_METRICS")
+				assert.Equal(t, "false", cm.Data["ENABLE_PROCESS_METRICS"])
+				assert.NotContains(t, mounts, corev1.VolumeMount{Name: "libvirt", MountPath: "/var/run/libvirt", ReadOnly: true})
+				return
+			}
+			assert.Equal(t, "true", cm.Data["EXPOSE_VM_METRICS"])
+			assert.Equal(t, "true", cm.Data["ENABLE_PROCESS_METRICS"])
+			assert.Contains(t, mounts, corev1.VolumeMount{Name: "libvirt", MountPath: "/var/run/libvirt", ReadOnly: true})
+		})
+	}
+}
+
+func benchmarkInternal() *v1alpha1.KeplerInternal {
+	return &v1alpha1.KeplerInternal{
+		ObjectMeta: metav1.ObjectMeta{Name: "kepler-internal"},
+		Spec: v1alpha1.KeplerInternalSpec{
+			Exporter: v1alpha1.InternalExporterSpec{
+				Deployment: v1alpha1.InternalExporterDeploymentSpec{
+					ExporterDeploymentSpec: v1alpha1.ExporterDeploymentSpec{
+						Port:         9103,
+						NodeSelector: map[string]string{"node-role": "worker"},
+					},
+				},
+				RBACProxy: &v1alpha1.InternalRBACProxySpec{RBACProxySpec: v1alpha1.RBACProxySpec{Enabled: true}},
+			},
+			Estimator: &v1alpha1.InternalEstimatorSpec{
+				Node: v1alpha1.EstimatorGroup{Total: &v1alpha1.EstimatorConfig{SidecarEnabled: true}},
+			},
+		},
+	}
+}
+
+func BenchmarkNewDaemonSet(b *testing.B) {
+	ki := benchmarkInternal()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		NewDaemonSet(components.Full, ki)
+	}
+}
+
+func BenchmarkNewConfigMap(b *testing.B) {
+	ki := benchmarkInternal()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		NewConfigMap(components.Full, ki)
+	}
+}
+
+func BenchmarkConfigHash(b *testing.B) {
+	data := NewConfigMap(components.Full, benchmarkInternal()).Data
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ConfigHash(data)
+	}
+}
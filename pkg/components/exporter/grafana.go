@@ -0,0 +1,125 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporter
+
+import (
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// NOTE: grafana-operator types are not imported to avoid depending on the
+// operator; the resources are created only when they are selected in the spec
+
+var (
+	GrafanaDashboardGVK  = schema.GroupVersionKind{Group: "grafana.integreatly.org", Version: "v1beta1", Kind: "GrafanaDashboard"}
+	GrafanaDatasourceGVK = schema.GroupVersionKind{Group: "grafana.integreatly.org", Version: "v1beta1", Kind: "GrafanaDatasource"}
+)
+
+const (
+	grafanaDatasourceName = "kepler-prometheus"
+	grafanaTokenKey       = "token"
+)
+
+// NewGrafanaOperatorDashboards returns the GrafanaDashboards of grafana-operator
+// carrying the kepler dashboards
+func NewGrafanaOperatorDashboards(d components.Detail, k *v1alpha1.KeplerInternal) []*unstructured.Unstructured {
+	dashboards := map[string]string{
+		overviewDashboardName: overviewDashboardJson,
+		nsInfoDashboardName:   nsInfoDashboardJson,
+	}
+
+	ret := []*unstructured.Unstructured{}
+	for _, name := range []string{overviewDashboardName, nsInfoDashboardName} {
+		u := newGrafanaObject(GrafanaDashboardGVK, k, k.Name+"-"+name)
+		if d == components.Full {
+			u.Object["spec"] = map[string]interface{}{
+				"instanceSelector": grafanaInstanceSelector(k),
+				"json":             dashboards[name],
+			}
+		}
+		ret = append(ret, u)
+	}
+	return ret
+}
+
+// NewGrafanaDatasource returns the GrafanaDatasource of grafana-operator that
+// the kepler dashboards can select using their datasource variable
+func NewGrafanaDatasource(d components.Detail, k *v1alpha1.KeplerInternal) *unstructured.Unstructured {
+	u := newGrafanaObject(GrafanaDatasourceGVK, k, k.Name+"-prometheus")
+	if d == components.Metadata {
+		return u
+	}
+
+	ds := &v1alpha1.GrafanaDatasourceSpec{}
+	if g := k.Spec.Dashboards.Grafana; g != nil && g.Datasource != nil {
+		ds = g.Datasource
+	}
+	datasource := map[string]interface{}{
+		"name":   grafanaDatasourceName,
+		"type":   "prometheus",
+		"access": "proxy",
+		"url":    ds.URL,
+	}
+	spec := map[string]interface{}{
+		"instanceSelector": grafanaInstanceSelector(k),
+		"datasource":       datasource,
+	}
+	if ds.SecretRef != "" {
+		datasource["jsonData"] = map[string]interface{}{
+			"httpHeaderName1": "Authorization",
+		}
+		datasource["secureJsonData"] = map[string]interface{}{
+			"httpHeaderValue1": "Bearer ${" + grafanaTokenKey + "}",
+		}
+		spec["valuesFrom"] = []interface{}{map[string]interface{}{
+			"targetPath": "secureJsonData.httpHeaderValue1",
+			"valueFrom": map[string]interface{}{
+				"secretKeyRef": map[string]interface{}{
+					"name": ds.SecretRef,
+					"key":  grafanaTokenKey,
+				},
+			},
+		}}
+	}
+	u.Object["spec"] = spec
+	return u
+}
+
+func newGrafanaObject(gvk schema.GroupVersionKind, k *v1alpha1.KeplerInternal, name string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	u.SetName(name)
+	u.SetNamespace(GrafanaDashboardsNamespace(k))
+	u.SetLabels(labels(k).ToMap())
+	return u
+}
+
+// grafanaInstanceSelector returns the selector in the spec which defaults to
+// selecting all grafana instances
+func grafanaInstanceSelector(k *v1alpha1.KeplerInternal) map[string]interface{} {
+	selector := &metav1.LabelSelector{}
+	if g := k.Spec.Dashboards.Grafana; g != nil && g.InstanceSelector != nil {
+		selector = g.InstanceSelector
+	}
+	// NOTE: conversion of a LabelSelector to unstructured cannot fail
+	ret, _ := runtime.DefaultUnstructuredConverter.ToUnstructured(selector)
+	return ret
+}
@@ -0,0 +1,292 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporter
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	supportedArchitectures = map[string]bool{
+		"amd64": true,
+		"arm64": true,
+		"s390x": true,
+	}
+
+	// minimum kernel version that supports the eBPF features used by kepler
+	minKernelMajor, minKernelMinor = 4, 18
+
+	// container waiting reasons that indicate the pod will not become ready
+	// without an intervention
+	failingReasons = map[string]bool{
+		"CrashLoopBackOff":           true,
+		"ImagePullBackOff":           true,
+		"ErrImagePull":               true,
+		"CreateContainerConfigError": true,
+		"CreateContainerError":       true,
+		"RunContainerError":          true,
+	}
+)
+
+// InspectNode returns the reason kepler cannot run on the node, if any
+func InspectNode(node *corev1.Node) (v1alpha1.InvalidNode, bool) {
+	info := node.Status.NodeInfo
+	invalid := v1alpha1.InvalidNode{Name: node.Name}
+
+	if info.OperatingSystem != "" && info.OperatingSystem != "linux" {
+		invalid.Reason = v1alpha1.UnsupportedOS
+		invalid.Message = fmt.Sprintf("operating system %q is not supported", info.OperatingSystem)
+		return invalid, true
+	}
+
+	if info.Architecture != "" && !supportedArchitectures[info.Architecture] {
+		invalid.Reason = v1alpha1.UnsupportedArchitecture
+		invalid.Message = fmt.Sprintf("architecture %q is not supported", info.Architecture)
+		return invalid, true
+	}
+
+	if major, minor, ok := kernelVersion(info.KernelVersion); ok &&
+		(major < minKernelMajor || (major == minKernelMajor && minor < minKernelMinor)) {
+		invalid.Reason = v1alpha1.UnsupportedKernel
+		invalid.Message = fmt.Sprintf("kernel %s does not support eBPF; requires %d.%d or later",
+			info.KernelVersion, minKernelMajor, minKernelMinor)
+		return invalid, true
+	}
+
+	return v1alpha1.InvalidNode{}, false
+}
+
+// InspectPod returns the node on which the kepler pod is failing, if any
+func InspectPod(pod *corev1.Pod) (v1alpha1.InvalidNode, bool) {
+	if pod.Spec.NodeName == "" {
+		return v1alpha1.InvalidNode{}, false
+	}
+
+	invalid := v1alpha1.InvalidNode{
+		Name:   pod.Spec.NodeName,
+		Reason: v1alpha1.ExporterPodFailing,
+	}
+
+	if pod.Status.Phase == corev1.PodFailed {
+		invalid.Message = fmt.Sprintf("pod %s failed: %s", pod.Name, pod.Status.Message)
+		return invalid, true
+	}
+
+	for _, c := range pod.Status.ContainerStatuses {
+		if c.State.Waiting != nil && failingReasons[c.State.Waiting.Reason] {
+			invalid.Message = fmt.Sprintf("container %s of pod %s is in %s: %s",
+				c.Name, pod.Name, c.State.Waiting.Reason, c.State.Waiting.Message)
+			return invalid, true
+		}
+	}
+
+	return v1alpha1.InvalidNode{}, false
+}
+
+// InvalidNodes returns the nodes on which kepler cannot run along with the
+// nodes on which kepler pods are failing, sorted by name
+func InvalidNodes(nodes []corev1.Node, pods []corev1.Pod) []v1alpha1.InvalidNode {
+	found := map[string]v1alpha1.InvalidNode{}
+	for i := range nodes {
+		if invalid, ok := InspectNode(&nodes[i]); ok {
+			found[invalid.Name] = invalid
+		}
+	}
+	for i := range pods {
+		invalid, ok := InspectPod(&pods[i])
+		if _, exists := found[invalid.Name]; ok && !exists {
+			found[invalid.Name] = invalid
+		}
+	}
+
+	if len(found) == 0 {
+		return nil
+	}
+
+	invalidNodes := make([]v1alpha1.InvalidNode, 0, len(found))
+	for _, n := range found {
+		invalidNodes = append(invalidNodes, n)
+	}
+	sort.Slice(invalidNodes, func(i, j int) bool {
+		return invalidNodes[i].Name < invalidNodes[j].Name
+	})
+	return invalidNodes
+}
+
+// PodCreationBlocked returns a message explaining why pods of the daemonset
+// cannot be created when the creation is blocked by SecurityContextConstraints
+// or Pod Security admission, as reported by FailedCreate events.
+func PodCreationBlocked(ds *appsv1.DaemonSet, events []corev1.Event) (string, bool) {
+	// pods that have been created are not blocked
+	if ds.Status.CurrentNumberScheduled >= ds.Status.DesiredNumberScheduled {
+		return "", false
+	}
+
+	var latest *corev1.Event
+	for i := range events {
+		ev := &events[i]
+		if ev.Reason != "FailedCreate" || ev.InvolvedObject.Kind != "DaemonSet" ||
+			ev.InvolvedObject.Name != ds.Name || ev.InvolvedObject.Namespace != ds.Namespace {
+			continue
+		}
+		if !isSCCDenied(ev.Message) && !isPodSecurityDenied(ev.Message) {
+			continue
+		}
+		if latest == nil || latest.LastTimestamp.Before(&ev.LastTimestamp) {
+			latest = ev
+		}
+	}
+	if latest == nil {
+		return "", false
+	}
+
+	if isSCCDenied(latest.Message) {
+		return fmt.Sprintf("%s; ensure that the kepler SecurityContextConstraints allow the kepler service account", latest.Message), true
+	}
+	return fmt.Sprintf("%s; label namespace %q with pod-security.kubernetes.io/enforce=privileged", latest.Message, ds.Namespace), true
+}
+
+func isSCCDenied(msg string) bool {
+	return strings.Contains(msg, "security context constraint")
+}
+
+func isPodSecurityDenied(msg string) bool {
+	return strings.Contains(msg, "violates PodSecurity")
+}
+
+// PowerSources groups the nodes by architecture and returns the source of
+// power readings kepler is expected to use for each group. RAPL and the ACPI
+// power meter are only available on x86 and power is estimated otherwise.
+func PowerSources(nodes []corev1.Node, redfish bool) []v1alpha1.PowerSource {
+	groups := map[string]int32{}
+	for _, n := range nodes {
+		groups[n.Status.NodeInfo.Architecture]++
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+
+	sources := make([]v1alpha1.PowerSource, 0, len(groups))
+	for arch, count := range groups {
+		src := v1alpha1.PowerSource{
+			Architecture: arch,
+			Nodes:        count,
+			Components:   v1alpha1.EstimationPowerSource,
+			Platform:     v1alpha1.EstimationPowerSource,
+		}
+		if arch == "amd64" {
+			src.Components = v1alpha1.RAPLPowerSource
+			src.Platform = v1alpha1.ACPIPowerSource
+		}
+		if redfish {
+			src.Platform = v1alpha1.RedfishPowerSource
+		}
+		sources = append(sources, src)
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		return sources[i].Architecture < sources[j].Architecture
+	})
+	return sources
+}
+
+// Approximate number of series exported by kepler, used to warn before a
+// configuration overwhelms Prometheus; the numbers need not be exact
+const (
+	// seriesPerNode is the number of node level energy and info series
+	seriesPerNode = 60
+
+	// seriesPerWorkload is the number of energy series of a container or a
+	// process i.e. a series per component and mode
+	seriesPerWorkload = 14
+
+	// podsPerNode is the number of pods assumed to run on a node; nodes allow
+	// 110 pods by default but run far fewer on average
+	podsPerNode = 30
+
+	// processesPerContainer is the number of processes assumed to run in a
+	// container when process metrics are enabled
+	processesPerContainer = 5
+)
+
+// seriesPerMetricGroup is the number of series added per container or process
+// by each group of metrics that can be enabled in the kepler config
+var seriesPerMetricGroup = map[string]int64{
+	"EXPOSE_HW_COUNTER_METRICS":  4,
+	"EXPOSE_IRQ_COUNTER_METRICS": 3,
+	"EXPOSE_CGROUP_METRICS":      4,
+	"EXPOSE_KUBELET_METRICS":     2,
+}
+
+// EstimateSeries returns the approximate number of series kepler exports for
+// the nodes with the given configuration i.e. nodes × containers × enabled
+// metric groups. A node is assumed to run podsPerNode pods, or as many as it
+// allows if fewer, with a container each. Virtual machines are counted as the
+// processes they run.
+func EstimateSeries(config map[string]string, nodes []corev1.Node) int64 {
+	perWorkload := int64(seriesPerWorkload)
+	for key, series := range seriesPerMetricGroup {
+		if config[key] == "true" {
+			perWorkload += series
+		}
+	}
+
+	workloadsPerContainer := int64(1)
+	if config["ENABLE_PROCESS_METRICS"] == "true" {
+		workloadsPerContainer += processesPerContainer
+	}
+
+	total := int64(0)
+	for i := range nodes {
+		containers := min(nodes[i].Status.Allocatable.Pods().Value(), podsPerNode)
+		total += seriesPerNode + containers*workloadsPerContainer*perWorkload
+	}
+	return total
+}
+
+// kernelVersion parses major and minor version from kernel versions such as
+// 5.14.0-284.25.1.el9_2.x86_64
+func kernelVersion(v string) (int, int, bool) {
+	parts := strings.SplitN(v, ".", 3)
+	if len(parts) < 2 {
+		return 0, 0, false
+	}
+
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, false
+	}
+
+	// minor version may have a suffix e.g. 4.9-rc1
+	digits := strings.IndexFunc(parts[1], func(r rune) bool {
+		return r < '0' || r > '9'
+	})
+	if digits >= 0 {
+		parts[1] = parts[1][:digits]
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
@@ -0,0 +1,267 @@
+package exporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func node(name, os, arch, kernel string) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			NodeInfo: corev1.NodeSystemInfo{
+				OperatingSystem: os,
+				Architecture:    arch,
+				KernelVersion:   kernel,
+			},
+		},
+	}
+}
+
+func podWaiting(name, nodeName, reason string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       corev1.PodSpec{NodeName: nodeName},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name: "kepler",
+				State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: reason},
+				},
+			}},
+		},
+	}
+}
+
+func TestInspectNode(t *testing.T) {
+
+	tt := []struct {
+		node     corev1.Node
+		invalid  bool
+		reason   v1alpha1.InvalidNodeReason
+		scenario string
+	}{
+		{node("n1", "linux", "amd64", "5.14.0-284.25.1.el9_2.x86_64"), false, "", "supported node"},
+		{node("n1", "", "", ""), false, "", "no node info"},
+		{node("n1", "windows", "amd64", "10.0.17763.2114"), true, v1alpha1.UnsupportedOS, "windows node"},
+		{node("n1", "linux", "riscv64", "6.1.0"), true, v1alpha1.UnsupportedArchitecture, "unsupported architecture"},
+		{node("n1", "linux", "amd64", "3.10.0-1160.el7.x86_64"), true, v1alpha1.UnsupportedKernel, "old kernel"},
+		{node("n1", "linux", "amd64", "4.18.0-372.el8.x86_64"), false, "", "minimum kernel"},
+		{node("n1", "linux", "amd64", "4.9-rc1"), true, v1alpha1.UnsupportedKernel, "old release candidate kernel"},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			actual, invalid := InspectNode(&tc.node)
+			assert.Equal(t, tc.invalid, invalid)
+			assert.Equal(t, tc.reason, actual.Reason)
+		})
+	}
+}
+
+func TestInvalidNodes(t *testing.T) {
+
+	tt := []struct {
+		nodes    []corev1.Node
+		pods     []corev1.Pod
+		invalid  []v1alpha1.InvalidNode
+		scenario string
+	}{
+		{
+			nodes: []corev1.Node{
+				node("n1", "linux", "amd64", "5.14.0"),
+				node("n2", "linux", "amd64", "5.14.0"),
+			},
+			pods: []corev1.Pod{
+				podWaiting("kepler-a", "n1", "ContainerCreating"),
+			},
+			invalid:  nil,
+			scenario: "all nodes valid",
+		},
+		{
+			nodes: []corev1.Node{
+				node("n2", "linux", "amd64", "5.14.0"),
+				node("n1", "linux", "amd64", "3.10.0"),
+			},
+			pods: []corev1.Pod{
+				podWaiting("kepler-a", "n1", "CrashLoopBackOff"),
+				podWaiting("kepler-b", "n2", "ImagePullBackOff"),
+				podWaiting("kepler-c", "", "ImagePullBackOff"),
+			},
+			invalid: []v1alpha1.InvalidNode{{
+				Name:    "n1",
+				Reason:  v1alpha1.UnsupportedKernel,
+				Message: "kernel 3.10.0 does not support eBPF; requires 4.18 or later",
+			}, {
+				Name:    "n2",
+				Reason:  v1alpha1.ExporterPodFailing,
+				Message: "container kepler of pod kepler-b is in ImagePullBackOff: ",
+			}},
+			scenario: "unsupported kernel and failing pod",
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.invalid, InvalidNodes(tc.nodes, tc.pods))
+		})
+	}
+}
+
+func TestPowerSources(t *testing.T) {
+
+	nodes := []corev1.Node{
+		node("n1", "linux", "amd64", "5.14.0"),
+		node("n2", "linux", "arm64", "5.14.0"),
+		node("n3", "linux", "amd64", "5.14.0"),
+	}
+
+	tt := []struct {
+		nodes    []corev1.Node
+		redfish  bool
+		sources  []v1alpha1.PowerSource
+		scenario string
+	}{
+		{nil, false, nil, "no nodes"},
+		{
+			nodes: nodes,
+			sources: []v1alpha1.PowerSource{
+				{Architecture: "amd64", Nodes: 2, Components: v1alpha1.RAPLPowerSource, Platform: v1alpha1.ACPIPowerSource},
+				{Architecture: "arm64", Nodes: 1, Components: v1alpha1.EstimationPowerSource, Platform: v1alpha1.EstimationPowerSource},
+			},
+			scenario: "mixed architectures",
+		},
+		{
+			nodes:   nodes,
+			redfish: true,
+			sources: []v1alpha1.PowerSource{
+				{Architecture: "amd64", Nodes: 2, Components: v1alpha1.RAPLPowerSource, Platform: v1alpha1.RedfishPowerSource},
+				{Architecture: "arm64", Nodes: 1, Components: v1alpha1.EstimationPowerSource, Platform: v1alpha1.RedfishPowerSource},
+			},
+			scenario: "redfish configured",
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.sources, PowerSources(tc.nodes, tc.redfish))
+		})
+	}
+}
+
+func TestEstimateSeries(t *testing.T) {
+	withPods := func(n corev1.Node, pods string) corev1.Node {
+		n.Status.Allocatable = corev1.ResourceList{corev1.ResourcePods: resource.MustParse(pods)}
+		return n
+	}
+	nodes := []corev1.Node{
+		withPods(node("n1", "linux", "amd64", "5.14.0"), "110"),
+		withPods(node("n2", "linux", "amd64", "5.14.0"), "250"),
+		withPods(node("n3", "linux", "amd64", "5.14.0"), "10"),
+	}
+
+	tt := []struct {
+		scenario string
+		config   map[string]string
+		nodes    []corev1.Node
+		series   int64
+	}{
+		{"no nodes", map[string]string{}, nil, 0},
+		{"energy only", map[string]string{}, nodes, 3*60 + 70*14},
+		{
+			"all groups",
+			map[string]string{
+				"EXPOSE_HW_COUNTER_METRICS":  "true",
+				"EXPOSE_IRQ_COUNTER_METRICS": "true",
+				"EXPOSE_CGROUP_METRICS":      "true",
+				"EXPOSE_KUBELET_METRICS":     "true",
+			},
+			nodes,
+			3*60 + 70*27,
+		},
+		{
+			"process metrics",
+			map[string]string{"ENABLE_PROCESS_METRICS": "true"},
+			nodes,
+			3*60 + 70*6*14,
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.series, EstimateSeries(tc.config, tc.nodes))
+		})
+	}
+}
+
+func TestPodCreationBlocked(t *testing.T) {
+
+	ds := appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "kepler", Namespace: "kepler-operator"},
+		Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 2},
+	}
+	event := func(name, reason, msg string, at time.Time) corev1.Event {
+		return corev1.Event{
+			InvolvedObject: corev1.ObjectReference{Kind: "DaemonSet", Name: name, Namespace: "kepler-operator"},
+			Reason:         reason,
+			Message:        msg,
+			LastTimestamp:  metav1.NewTime(at),
+		}
+	}
+	now := time.Now()
+	sccMsg := `pods "kepler-" is forbidden: unable to validate against any security context constraint`
+	psaMsg := `pods "kepler-abcd" is forbidden: violates PodSecurity "restricted:latest": privileged`
+
+	tt := []struct {
+		ds       appsv1.DaemonSet
+		events   []corev1.Event
+		blocked  bool
+		contains string
+		scenario string
+	}{
+		{ds, nil, false, "", "no events"},
+		{ds, []corev1.Event{event("kepler", "FailedCreate", "quota exceeded", now)}, false, "", "unrelated failure"},
+		{ds, []corev1.Event{event("other", "FailedCreate", sccMsg, now)}, false, "", "other daemonset"},
+		{ds, []corev1.Event{event("kepler", "FailedCreate", sccMsg, now)}, true, "SecurityContextConstraints", "blocked by scc"},
+		{
+			ds, []corev1.Event{
+				event("kepler", "FailedCreate", sccMsg, now.Add(-time.Minute)),
+				event("kepler", "FailedCreate", psaMsg, now),
+			},
+			true, "pod-security.kubernetes.io/enforce=privileged", "latest blocked by pod security",
+		},
+		{
+			appsv1.DaemonSet{
+				ObjectMeta: ds.ObjectMeta,
+				Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 2, CurrentNumberScheduled: 2},
+			},
+			[]corev1.Event{event("kepler", "FailedCreate", sccMsg, now)},
+			false, "", "all pods created",
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			msg, blocked := PodCreationBlocked(&tc.ds, tc.events)
+			assert.Equal(t, tc.blocked, blocked)
+			assert.Contains(t, msg, tc.contains)
+		})
+	}
+}
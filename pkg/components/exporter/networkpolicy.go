@@ -0,0 +1,106 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporter
+
+import (
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	openshiftMonitoringNamespaces = []string{"openshift-monitoring", "openshift-user-workload-monitoring"}
+	defaultMonitoringNamespaces   = []string{"monitoring"}
+)
+
+// NewNetworkPolicy returns a NetworkPolicy that allows the exporter pods to be
+// scraped only from the monitoring namespaces and the deployment namespace and
+// to connect only to the kube-apiserver, kubelet, model server and redfish
+func NewNetworkPolicy(d components.Detail, k *v1alpha1.KeplerInternal) *networkingv1.NetworkPolicy {
+	np := &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: networkingv1.SchemeGroupVersion.String(),
+			Kind:       "NetworkPolicy",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      k.Name,
+			Namespace: k.Namespace(),
+			Labels:    labels(k),
+		},
+	}
+	if d == components.Metadata {
+		return np
+	}
+
+	spec := k.Spec.NetworkPolicy
+	if spec == nil {
+		spec = &v1alpha1.NetworkPolicySpec{}
+	}
+
+	egress := []networkingv1.NetworkPolicyEgressRule{
+		components.DNSEgressRule(),
+		// NOTE: kube-apiserver, redfish endpoints and model downloads use 443
+		// and kube-apiserver listens on 6443 on some clusters
+		components.TCPEgressRule(443, 6443, 10250),
+	}
+	if ms := k.Spec.ModelServer; ms != nil && ms.Enabled {
+		egress = append(egress, networkingv1.NetworkPolicyEgressRule{
+			To:    []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{}}},
+			Ports: components.NetworkPolicyPorts(corev1.ProtocolTCP, int32(ms.Port)),
+		})
+	}
+	egress = append(egress, spec.Egress...)
+
+	np.Spec = networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{MatchLabels: podSelector(k)},
+		PolicyTypes: []networkingv1.PolicyType{
+			networkingv1.PolicyTypeIngress,
+			networkingv1.PolicyTypeEgress,
+		},
+		Ingress: []networkingv1.NetworkPolicyIngressRule{{
+			From: []networkingv1.NetworkPolicyPeer{{
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{
+						Key:      corev1.LabelMetadataName,
+						Operator: metav1.LabelSelectorOpIn,
+						Values:   monitoringNamespaces(k),
+					}},
+				},
+			}, {
+				// NOTE: collectors and agents deployed along with kepler
+				PodSelector: &metav1.LabelSelector{},
+			}},
+			Ports: components.NetworkPolicyPorts(corev1.ProtocolTCP, k.Spec.Exporter.Deployment.Port),
+		}},
+		Egress: egress,
+	}
+	return np
+}
+
+// monitoringNamespaces returns the namespaces allowed to scrape the exporter
+func monitoringNamespaces(k *v1alpha1.KeplerInternal) []string {
+	if np := k.Spec.NetworkPolicy; np != nil && len(np.MonitoringNamespaces) != 0 {
+		return np.MonitoringNamespaces
+	}
+	if k.Spec.OpenShift.Enabled {
+		return openshiftMonitoringNamespaces
+	}
+	return defaultMonitoringNamespaces
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporter
+
+import (
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// NOTE: Node Feature Discovery types are not imported to avoid depending on
+// NFD; the rule is created only when it is enabled
+
+var NodeFeatureRuleGVK = schema.GroupVersionKind{Group: "nfd.k8s-sigs.io", Version: "v1alpha1", Kind: "NodeFeatureRule"}
+
+const (
+	// RAPLNodeLabel is set by Node Feature Discovery on nodes where RAPL is
+	// available
+	RAPLNodeLabel = "feature.node.kubernetes.io/kepler-rapl"
+
+	// EBPFNodeLabel is set by Node Feature Discovery on nodes where the kernel
+	// supports the eBPF programs of kepler
+	EBPFNodeLabel = "feature.node.kubernetes.io/kepler-ebpf"
+)
+
+// NewNodeFeatureRule returns a NodeFeatureRule that labels the nodes with RAPL
+// and eBPF support
+func NewNodeFeatureRule(d components.Detail, k *v1alpha1.KeplerInternal) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(NodeFeatureRuleGVK)
+	u.SetName(k.Name)
+	u.SetLabels(labels(k).ToMap())
+	if d == components.Metadata {
+		return u
+	}
+
+	u.Object["spec"] = map[string]interface{}{
+		"rules": []interface{}{
+			map[string]interface{}{
+				"name":   "kepler rapl",
+				"labels": map[string]interface{}{RAPLNodeLabel: "true"},
+				// NOTE: RAPL is exposed by the powercap driver on both intel and amd
+				"matchFeatures": []interface{}{
+					nodeFeature("kernel.loadedmodule", map[string]interface{}{
+						"intel_rapl_common": map[string]interface{}{"op": "Exists"},
+					}),
+				},
+			},
+			map[string]interface{}{
+				"name":   "kepler ebpf",
+				"labels": map[string]interface{}{EBPFNodeLabel: "true"},
+				"matchFeatures": []interface{}{
+					nodeFeature("kernel.config", map[string]interface{}{
+						"BPF_SYSCALL": map[string]interface{}{"op": "In", "value": []interface{}{"y"}},
+					}),
+					nodeFeature("kernel.version", map[string]interface{}{
+						"major": map[string]interface{}{"op": "Gt", "value": []interface{}{"4"}},
+					}),
+				},
+			},
+		},
+	}
+	return u
+}
+
+func nodeFeature(feature string, expressions map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"feature":          feature,
+		"matchExpressions": expressions,
+	}
+}
+
+// capableNodeSelector returns the node selector that restricts the exporter
+// to the nodes labelled by Node Feature Discovery if enabled in the spec
+func capableNodeSelector(k *v1alpha1.KeplerInternal) k8s.StringMap {
+	nfd := k.Spec.Exporter.NodeFeatureDiscovery
+	if !nfd.IsEnabled() || !nfd.RestrictToCapableNodes {
+		return nil
+	}
+	return k8s.StringMap{EBPFNodeLabel: "true"}
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporter
+
+import (
+	"fmt"
+
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components"
+
+	monv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// OpenCostScrapeConfigKey is the key of the scrape config of the exporter
+	// in the secret for the Prometheus of OpenCost
+	OpenCostScrapeConfigKey = "scrape-config.yaml"
+
+	// OpenCostRulesKey is the key of the recording rules in the secret for the
+	// Prometheus of OpenCost
+	OpenCostRulesKey = "rules.yaml"
+)
+
+func openCostName(k *v1alpha1.KeplerInternal) string {
+	return k.Name + "-opencost"
+}
+
+// NewOpenCostPrometheusRule returns a PrometheusRule recording the power of
+// containers and nodes, and the hourly cost of the energy of containers if an
+// electricity price is configured, labelled by namespace, pod, container and
+// node as the metrics OpenCost allocates costs by. Returns an error if the
+// electricity price cannot be configured, see NewEnergyCostPrometheusRule.
+func NewOpenCostPrometheusRule(d components.Detail, k *v1alpha1.KeplerInternal) (*monv1.PrometheusRule, error) {
+	rule := &monv1.PrometheusRule{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: monv1.SchemeGroupVersion.String(),
+			Kind:       "PrometheusRule",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      openCostName(k),
+			Namespace: k.Namespace(),
+			Labels:    labels(k).ToMap(),
+		},
+	}
+	if d == components.Metadata {
+		return rule, nil
+	}
+
+	group, err := openCostRuleGroup(k)
+	if err != nil {
+		return nil, err
+	}
+	rule.Spec = monv1.PrometheusRuleSpec{Groups: []monv1.RuleGroup{group}}
+	return rule, nil
+}
+
+// openCostRuleGroup returns the recording rules of the OpenCost label schema
+func openCostRuleGroup(k *v1alpha1.KeplerInternal) (monv1.RuleGroup, error) {
+	ns := k.Namespace()
+	interval := monv1.Duration("30s")
+	group := monv1.RuleGroup{
+		Name:     "kepler.opencost",
+		Interval: &interval,
+		Rules: []monv1.Rule{{
+			Record: "container:kepler_power:watts",
+			Expr: intstr.FromString(fmt.Sprintf(
+				`sum by (namespace, pod, container, node) (`+
+					`label_replace(label_replace(label_replace(label_replace(`+
+					`rate(kepler_container_joules_total{namespace=%q}[5m]), `+
+					`"namespace", "$1", "container_namespace", "(.*)"), `+
+					`"pod", "$1", "pod_name", "(.*)"), `+
+					`"container", "$1", "container_name", "(.*)"), `+
+					`"node", "$1", "instance", "(.*)"))`, ns)),
+		}, {
+			Record: "node:kepler_power:watts",
+			Expr: intstr.FromString(fmt.Sprintf(
+				`sum by (node) (label_replace(rate(kepler_node_platform_joules_total{namespace=%q}[5m]), "node", "$1", "instance", "(.*)"))`, ns)),
+		}},
+	}
+
+	if !k.Spec.Exporter.ElectricityPrice.IsEnabled() {
+		return group, nil
+	}
+	price, err := nodePrice(k)
+	if err != nil {
+		return monv1.RuleGroup{}, err
+	}
+	group.Rules = append(group.Rules, monv1.Rule{
+		Record: "container:kepler_energy_cost:hourly",
+		Expr: intstr.FromString(fmt.Sprintf(
+			`container:kepler_power:watts / 1000 * on (node) group_left (currency) label_replace(%s, "node", "$1", "instance", "(.*)")`, price)),
+	})
+	return group, nil
+}
+
+// NewOpenCostScrapeConfig returns the secret, in the namespace of the
+// Prometheus of OpenCost, holding the scrape config of the exporter and the
+// recording rules of the OpenCost label schema. The certificate served by
+// kube-rbac-proxy is verified with ca, which is copied from MetricsCA as the
+// CA is not available in the namespace of OpenCost. Returns an error if the
+// electricity price cannot be configured.
+func NewOpenCostScrapeConfig(d components.Detail, k *v1alpha1.KeplerInternal, ca []byte) (*corev1.Secret, error) {
+	sc := k.Spec.Exporter.OpenCost.ScrapeConfig
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      openCostName(k),
+			Namespace: sc.Namespace,
+			Labels:    labels(k).ToMap(),
+		},
+	}
+	if d == components.Metadata {
+		return secret, nil
+	}
+
+	scrapeConfig, err := yaml.Marshal(map[string]any{"scrape_configs": []map[string]any{openCostScrapeConfig(k, ca)}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal scrape config: %w", err)
+	}
+
+	group, err := openCostRuleGroup(k)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := yaml.Marshal(map[string]any{"groups": []monv1.RuleGroup{group}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal rules: %w", err)
+	}
+
+	secret.Type = corev1.SecretTypeOpaque
+	secret.StringData = map[string]string{
+		OpenCostScrapeConfigKey: string(scrapeConfig),
+		OpenCostRulesKey:        string(rules),
+	}
+	return secret, nil
+}
+
+// openCostScrapeConfig returns the Prometheus scrape config discovering the
+// endpoints of the exporter service; the targets are labelled by instance as
+// the ServiceMonitor does and scraped at the interval of the ServiceMonitor
+func openCostScrapeConfig(k *v1alpha1.KeplerInternal, ca []byte) map[string]any {
+	scrape := v1alpha1.ScrapeSpec{}
+	if sm := k.Spec.Exporter.ServiceMonitor; sm != nil {
+		scrape = sm.ScrapeSpec
+	}
+	relabelings := []map[string]any{{
+		"source_labels": []string{"__meta_kubernetes_service_name", "__meta_kubernetes_endpoint_port_name"},
+		"regex":         k.Name + ";" + ServicePortName,
+		"action":        "keep",
+	}, {
+		"source_labels": []string{"__meta_kubernetes_pod_node_name"},
+		"target_label":  "instance",
+	}, {
+		"source_labels": []string{"__meta_kubernetes_namespace"},
+		"target_label":  "namespace",
+	}}
+	for _, r := range metricLabelsRelabelings(k) {
+		relabelings = append(relabelings, map[string]any{
+			"target_label": r.TargetLabel,
+			"replacement":  r.Replacement,
+		})
+	}
+
+	job := map[string]any{
+		"job_name":        k.Name,
+		"scrape_interval": string(scrapeInterval(scrape)),
+		"scheme":          MetricsScheme(k),
+		"kubernetes_sd_configs": []map[string]any{{
+			"role":       "endpoints",
+			"namespaces": map[string]any{"names": []string{k.Namespace()}},
+		}},
+		"relabel_configs": relabelings,
+	}
+	if k.Spec.Exporter.RBACProxy.IsEnabled() {
+		// NOTE: the service account of the Prometheus of OpenCost is allowed
+		// to get the metrics of the exporter by NewOpenCostMetricsReaderBinding
+		job["authorization"] = map[string]any{"credentials_file": serviceAccountTokenFile}
+		job["tls_config"] = map[string]any{
+			"server_name": ServingCertDNSNames(k)[0],
+			"ca":          string(ca),
+		}
+	}
+	return job
+}
+
+// NewOpenCostMetricsReaderBinding returns the binding that authorizes the
+// service account of the Prometheus of OpenCost to scrape the exporter
+// through kube-rbac-proxy
+func NewOpenCostMetricsReaderBinding(d components.Detail, k *v1alpha1.KeplerInternal) *rbacv1.ClusterRoleBinding {
+	sa, ns := v1alpha1.DefaultOpenCostServiceAccount, ""
+	if sc := k.Spec.Exporter.OpenCost.ScrapeConfig; sc != nil {
+		ns = sc.Namespace
+		if sc.ServiceAccount != "" {
+			sa = sc.ServiceAccount
+		}
+	}
+	return NewMetricsReaderBinding(d, k, openCostName(k)+"-metrics-reader", sa, ns)
+}
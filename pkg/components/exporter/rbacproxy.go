@@ -0,0 +1,434 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
+
+	monv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// RBACProxyImage is the kube-rbac-proxy image used unless one is set in
+	// the spec
+	RBACProxyImage = "quay.io/brancz/kube-rbac-proxy:v0.16.0"
+
+	rbacProxyContainerName = "kube-rbac-proxy"
+	rbacProxyTLSVolume     = "kube-rbac-proxy-tls"
+	rbacProxyTLSPath       = "/etc/tls/private"
+
+	// rbacProxyUpstreamPort is the port the exporter listens on localhost
+	// when its metrics are served by kube-rbac-proxy
+	rbacProxyUpstreamPort int32 = 9102
+
+	servingCertAnnotation = "service.beta.openshift.io/serving-cert-secret-name"
+
+	// ServiceCAConfigMap is the configmap OpenShift injects the service CA
+	// into, under ServiceCAKey, in every namespace
+	ServiceCAConfigMap = "openshift-service-ca.crt"
+	ServiceCAKey       = "service-ca.crt"
+
+	serviceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+	// MetricsCAKey is the key of the CA in the volume returned by
+	// NewMetricsCAVolume
+	MetricsCAKey = "ca.crt"
+)
+
+// NOTE: cert-manager types are not imported to avoid depending on cert-manager
+
+var CertificateGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+
+// rbacProxyRules are the rules kube-rbac-proxy needs to authenticate and
+// authorize the requests for the metrics
+var rbacProxyRules = []rbacv1.PolicyRule{{
+	APIGroups: []string{"authentication.k8s.io"},
+	Resources: []string{"tokenreviews"},
+	Verbs:     []string{"create"},
+}, {
+	APIGroups: []string{"authorization.k8s.io"},
+	Resources: []string{"subjectaccessreviews"},
+	Verbs:     []string{"create"},
+}}
+
+// MetricsScheme returns the scheme of the metrics endpoint of the exporter
+func MetricsScheme(k *v1alpha1.KeplerInternal) string {
+	if k.Spec.Exporter.RBACProxy.IsEnabled() {
+		return "https"
+	}
+	return "http"
+}
+
+// rbacProxyTLSSecret returns the name of the secret with the certificate
+// served by kube-rbac-proxy
+func rbacProxyTLSSecret(k *v1alpha1.KeplerInternal) string {
+	if proxy := k.Spec.Exporter.RBACProxy; proxy.TLSSecretRef != "" {
+		return proxy.TLSSecretRef
+	}
+	return k.Name + "-tls"
+}
+
+// ManagesServingCert returns true if the certificate served by kube-rbac-proxy
+// is issued by a CA managed by the operator, i.e. if it is neither set in the
+// spec nor issued by cert-manager or the service CA of OpenShift
+func ManagesServingCert(k *v1alpha1.KeplerInternal) bool {
+	proxy := k.Spec.Exporter.RBACProxy
+	return proxy.IsEnabled() && proxy.TLSSecretRef == "" && proxy.CertManager == nil && !k.Spec.OpenShift.Enabled
+}
+
+// NewServingCASecret returns the secret with the CA managed by the operator;
+// the CA is not mounted by any pod
+func NewServingCASecret(k *v1alpha1.KeplerInternal) *corev1.Secret {
+	return newTLSSecret(k.Name+"-ca", k)
+}
+
+// NewServingCertSecret returns the secret with the certificate issued by the
+// CA managed by the operator
+func NewServingCertSecret(k *v1alpha1.KeplerInternal) *corev1.Secret {
+	return newTLSSecret(rbacProxyTLSSecret(k), k)
+}
+
+func newTLSSecret(name string, k *v1alpha1.KeplerInternal) *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: k.Namespace(),
+			Labels:    labels(k).ToMap(),
+		},
+		Type: corev1.SecretTypeTLS,
+	}
+}
+
+// ServingCertDNSNames returns the DNS names of the metrics service that the
+// certificate served by kube-rbac-proxy is issued for
+func ServingCertDNSNames(k *v1alpha1.KeplerInternal) []string {
+	svc := fmt.Sprintf("%s.%s.svc", k.Name, k.Namespace())
+	return []string{svc, svc + ".cluster.local"}
+}
+
+// upstreamPort returns the port the exporter listens on localhost which must
+// differ from the port kube-rbac-proxy listens on
+func upstreamPort(port int32) int32 {
+	if port == rbacProxyUpstreamPort {
+		return port + 1
+	}
+	return rbacProxyUpstreamPort
+}
+
+// addRBACProxySidecar binds the exporter to localhost and adds kube-rbac-proxy
+// which serves the metrics of the exporter on the port in the spec
+func addRBACProxySidecar(k *v1alpha1.KeplerInternal, exporterContainer *corev1.Container, containers []corev1.Container, volumes []corev1.Volume) ([]corev1.Container, []corev1.Volume) {
+	port := k.Spec.Exporter.Deployment.Port
+	upstream := upstreamPort(port)
+
+	for i, arg := range exporterContainer.Command {
+		if arg == "-address" {
+			exporterContainer.Command[i+1] = "127.0.0.1:" + strconv.Itoa(int(upstream))
+		}
+	}
+	exporterContainer.Ports = nil
+	// NOTE: health checks are not authorized by kube-rbac-proxy; see --ignore-paths
+	exporterContainer.LivenessProbe.HTTPGet.Scheme = corev1.URISchemeHTTPS
+	containers[KeplerContainerIndex] = *exporterContainer
+
+	proxy := corev1.Container{
+		Name:            rbacProxyContainerName,
+		Image:           k.Spec.Exporter.RBACProxy.Image,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Args: []string{
+			"--secure-listen-address=0.0.0.0:" + strconv.Itoa(int(port)),
+			fmt.Sprintf("--upstream=http://127.0.0.1:%d/", upstream),
+			"--ignore-paths=/healthz",
+		},
+		Ports: []corev1.ContainerPort{{
+			ContainerPort: port,
+			Name:          ServicePortName,
+		}},
+	}
+
+	// NOTE: kube-rbac-proxy reloads the certificate when it changes, so it is
+	// rotated without restarting the pods
+	proxy.Args = append(proxy.Args,
+		"--tls-cert-file="+rbacProxyTLSPath+"/"+corev1.TLSCertKey,
+		"--tls-private-key-file="+rbacProxyTLSPath+"/"+corev1.TLSPrivateKeyKey,
+	)
+	if version := k.Spec.Exporter.RBACProxy.TLSMinVersion; version != "" {
+		proxy.Args = append(proxy.Args, "--tls-min-version="+version)
+	}
+	if suites := k.Spec.Exporter.RBACProxy.TLSCipherSuites; len(suites) != 0 {
+		proxy.Args = append(proxy.Args, "--tls-cipher-suites="+strings.Join(suites, ","))
+	}
+	if audiences := k.Spec.Exporter.RBACProxy.TokenAudiences; len(audiences) != 0 {
+		proxy.Args = append(proxy.Args, "--auth-token-audiences="+strings.Join(audiences, ","))
+	}
+	proxy.VolumeMounts = []corev1.VolumeMount{{
+		Name:      rbacProxyTLSVolume,
+		MountPath: rbacProxyTLSPath,
+		ReadOnly:  true,
+	}}
+	volumes = append(volumes, k8s.VolumeFromSecret(rbacProxyTLSVolume, rbacProxyTLSSecret(k)))
+	return append(containers, proxy), volumes
+}
+
+// rbacProxyServiceAnnotations returns the annotation that makes OpenShift
+// create the serving certificate of kube-rbac-proxy
+func rbacProxyServiceAnnotations(k *v1alpha1.KeplerInternal) k8s.StringMap {
+	proxy := k.Spec.Exporter.RBACProxy
+	if !proxy.IsEnabled() || proxy.TLSSecretRef != "" || proxy.CertManager != nil || !k.Spec.OpenShift.Enabled {
+		return nil
+	}
+	return k8s.StringMap{servingCertAnnotation: rbacProxyTLSSecret(k)}
+}
+
+// rbacProxyTLSConfig returns the TLS configuration used by Prometheus to
+// verify the certificate served by kube-rbac-proxy
+func rbacProxyTLSConfig(k *v1alpha1.KeplerInternal) monv1.SafeTLSConfig {
+	proxy := k.Spec.Exporter.RBACProxy
+	tls := monv1.SafeTLSConfig{
+		ServerName: ServingCertDNSNames(k)[0],
+	}
+
+	if k.Spec.OpenShift.Enabled && proxy.TLSSecretRef == "" && proxy.CertManager == nil {
+		tls.CA = monv1.SecretOrConfigMap{
+			ConfigMap: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: ServiceCAConfigMap},
+				Key:                  ServiceCAKey,
+			},
+		}
+		return tls
+	}
+
+	// NOTE: the CA bundle of the secret includes the previous CA while the CA
+	// managed by the operator is rotated
+	tls.CA = monv1.SecretOrConfigMap{
+		Secret: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: rbacProxyTLSSecret(k)},
+			Key:                  "ca.crt",
+		},
+	}
+	return tls
+}
+
+// MetricsCA returns the ConfigMap or Secret, in the namespace of the exporter,
+// with the CA that issued the certificate served by kube-rbac-proxy
+func MetricsCA(k *v1alpha1.KeplerInternal) monv1.SecretOrConfigMap {
+	return rbacProxyTLSConfig(k).CA
+}
+
+// NewMetricsCAVolume returns the volume with the CA that issued the
+// certificate served by kube-rbac-proxy for components in the namespace of
+// the exporter that scrape it; the CA is the one Prometheus is configured
+// with by rbacProxyTLSConfig
+func NewMetricsCAVolume(name string, k *v1alpha1.KeplerInternal) corev1.Volume {
+	if tls := rbacProxyTLSConfig(k); tls.CA.ConfigMap != nil {
+		return corev1.Volume{
+			Name: name,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: tls.CA.ConfigMap.LocalObjectReference,
+					Items:                []corev1.KeyToPath{{Key: tls.CA.ConfigMap.Key, Path: MetricsCAKey}},
+				},
+			},
+		}
+	}
+	volume := k8s.VolumeFromSecret(name, rbacProxyTLSSecret(k))
+	volume.Secret.Items = []corev1.KeyToPath{{Key: "ca.crt", Path: MetricsCAKey}}
+	return volume
+}
+
+// NewCertificate returns the cert-manager Certificate of the metrics endpoint
+// served by kube-rbac-proxy
+func NewCertificate(d components.Detail, k *v1alpha1.KeplerInternal) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(CertificateGVK)
+	u.SetName(k.Name)
+	u.SetNamespace(k.Namespace())
+	u.SetLabels(labels(k).ToMap())
+	if d == components.Metadata {
+		return u
+	}
+
+	issuer := v1alpha1.IssuerReference{}
+	if proxy := k.Spec.Exporter.RBACProxy; proxy != nil && proxy.CertManager != nil {
+		issuer = proxy.CertManager.IssuerRef
+	}
+	if issuer.Kind == "" {
+		issuer.Kind = "Issuer"
+	}
+	if issuer.Group == "" {
+		issuer.Group = CertificateGVK.Group
+	}
+
+	dnsNames := []interface{}{}
+	for _, name := range ServingCertDNSNames(k) {
+		dnsNames = append(dnsNames, name)
+	}
+	u.Object["spec"] = map[string]interface{}{
+		"secretName": rbacProxyTLSSecret(k),
+		"dnsNames":   dnsNames,
+		"usages":     []interface{}{"server auth"},
+		"issuerRef": map[string]interface{}{
+			"name":  issuer.Name,
+			"kind":  issuer.Kind,
+			"group": issuer.Group,
+		},
+	}
+	return u
+}
+
+// NeedsScrapeServiceAccount returns true if the exporter is scraped with the
+// token of a service account created by the operator.
+// NOTE: PodMonitors cannot scrape with the service account token of
+// Prometheus, which ServiceMonitors read from bearerTokenFile, so the scrape
+// service account is needed whenever the PodMonitor is enabled
+func NeedsScrapeServiceAccount(k *v1alpha1.KeplerInternal) bool {
+	proxy := k.Spec.Exporter.RBACProxy
+	return proxy.IsEnabled() && (proxy.ScrapeServiceAccount.IsEnabled() || k.Spec.Exporter.PodMonitor.IsEnabled())
+}
+
+func scrapeServiceAccountName(k *v1alpha1.KeplerInternal) string {
+	return k.Name + "-scraper"
+}
+
+func scrapeTokenSecretName(k *v1alpha1.KeplerInternal) string {
+	return k.Name + "-scraper-token"
+}
+
+func metricsReaderName(k *v1alpha1.KeplerInternal) string {
+	return k.Name + "-metrics-reader"
+}
+
+// NewScrapeServiceAccount returns the service account that scrapes the exporter
+func NewScrapeServiceAccount(k *v1alpha1.KeplerInternal) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "ServiceAccount",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      scrapeServiceAccountName(k),
+			Namespace: k.Namespace(),
+			Labels:    labels(k).ToMap(),
+		},
+	}
+}
+
+// NewScrapeTokenSecret returns the secret into which kubernetes populates the
+// long-lived token of the scrape service account referred to by the monitors
+func NewScrapeTokenSecret(k *v1alpha1.KeplerInternal) *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      scrapeTokenSecretName(k),
+			Namespace: k.Namespace(),
+			Labels:    labels(k).ToMap(),
+			Annotations: map[string]string{
+				corev1.ServiceAccountNameKey: scrapeServiceAccountName(k),
+			},
+		},
+		Type: corev1.SecretTypeServiceAccountToken,
+	}
+}
+
+// NewMetricsReaderClusterRole returns the role that authorizes getting the
+// metrics served by kube-rbac-proxy
+func NewMetricsReaderClusterRole(d components.Detail, k *v1alpha1.KeplerInternal) *rbacv1.ClusterRole {
+	role := &rbacv1.ClusterRole{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: rbacv1.SchemeGroupVersion.String(),
+			Kind:       "ClusterRole",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   metricsReaderName(k),
+			Labels: labels(k),
+		},
+	}
+	if d == components.Full {
+		// NOTE: kube-rbac-proxy authorizes requests for non-resource urls by
+		// the path of the request
+		role.Rules = []rbacv1.PolicyRule{{
+			NonResourceURLs: []string{"/metrics"},
+			Verbs:           []string{"get"},
+		}}
+	}
+	return role
+}
+
+// NewMetricsReaderClusterRoleBinding binds the metrics reader role to the
+// scrape service account
+func NewMetricsReaderClusterRoleBinding(d components.Detail, k *v1alpha1.KeplerInternal) *rbacv1.ClusterRoleBinding {
+	return NewMetricsReaderBinding(d, k, metricsReaderName(k), scrapeServiceAccountName(k), k.Namespace())
+}
+
+// NewMetricsReaderBinding returns the binding with the given name that binds
+// the metrics reader role to the service account of a component scraping the
+// exporter through kube-rbac-proxy
+func NewMetricsReaderBinding(d components.Detail, k *v1alpha1.KeplerInternal, name, sa, ns string) *rbacv1.ClusterRoleBinding {
+	binding := &rbacv1.ClusterRoleBinding{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: rbacv1.SchemeGroupVersion.String(),
+			Kind:       "ClusterRoleBinding",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels(k),
+		},
+	}
+	if d == components.Full {
+		binding.RoleRef = rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     metricsReaderName(k),
+		}
+		binding.Subjects = []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      sa,
+			Namespace: ns,
+		}}
+	}
+	return binding
+}
+
+// scrapeAuthorization returns the authorization of the monitors using the
+// token of the scrape service account
+func scrapeAuthorization(k *v1alpha1.KeplerInternal) *monv1.SafeAuthorization {
+	return &monv1.SafeAuthorization{
+		Type: "Bearer",
+		Credentials: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: scrapeTokenSecretName(k)},
+			Key:                  corev1.ServiceAccountTokenKey,
+		},
+	}
+}
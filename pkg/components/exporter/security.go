@@ -0,0 +1,73 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporter
+
+import (
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// capBPF allows loading the eBPF programs of kepler
+	capBPF corev1.Capability = "BPF"
+	// capPerfmon allows attaching the eBPF programs to tracepoints and
+	// reading the hardware counters
+	capPerfmon corev1.Capability = "PERFMON"
+	// capSysAdmin covers both BPF and PERFMON on kernels older than 5.8
+	capSysAdmin corev1.Capability = "SYS_ADMIN"
+	// capDACReadSearch allows reading the RAPL energy counters, which are
+	// readable by root only, and /proc of processes of other users
+	capDACReadSearch corev1.Capability = "DAC_READ_SEARCH"
+)
+
+// Capabilities returns the capabilities the exporter needs in the collection
+// mode; all other capabilities are dropped. Returns nil for the privileged
+// mode, which is also used when no mode is set, in which the exporter has all
+// capabilities.
+func Capabilities(mode v1alpha1.CollectionMode) []corev1.Capability {
+	switch mode {
+	case v1alpha1.CollectionModeEBPF:
+		return []corev1.Capability{capBPF, capPerfmon, capDACReadSearch}
+	case v1alpha1.CollectionModeEBPFLegacyKernel:
+		return []corev1.Capability{capSysAdmin, capDACReadSearch}
+	default:
+		return nil
+	}
+}
+
+// isPrivileged returns true if the exporter runs as a privileged container in
+// the collection mode
+func isPrivileged(mode v1alpha1.CollectionMode) bool {
+	return Capabilities(mode) == nil
+}
+
+// securityContext returns the security context of the exporter container
+// granting only the capabilities of the collection mode
+func securityContext(mode v1alpha1.CollectionMode) *corev1.SecurityContext {
+	if isPrivileged(mode) {
+		return &corev1.SecurityContext{Privileged: ptr.To(true)}
+	}
+	return &corev1.SecurityContext{
+		Privileged: ptr.To(false),
+		Capabilities: &corev1.Capabilities{
+			Add:  Capabilities(mode),
+			Drop: []corev1.Capability{"ALL"},
+		},
+	}
+}
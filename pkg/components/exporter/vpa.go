@@ -0,0 +1,87 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporter
+
+import (
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// NOTE: the autoscaler types are not imported to avoid depending on the
+// autoscaler module; the resource is created only when it is enabled
+
+var VerticalPodAutoscalerGVK = schema.GroupVersionKind{Group: "autoscaling.k8s.io", Version: "v1", Kind: "VerticalPodAutoscaler"}
+
+// NewVerticalPodAutoscaler returns a VerticalPodAutoscaler which recommends
+// the resources of the kepler container of the exporter DaemonSet
+func NewVerticalPodAutoscaler(d components.Detail, k *v1alpha1.KeplerInternal) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(VerticalPodAutoscalerGVK)
+	u.SetName(k.Name)
+	u.SetNamespace(k.Namespace())
+	u.SetLabels(labels(k).ToMap())
+	if d == components.Metadata {
+		return u
+	}
+
+	vpa := k.Spec.Exporter.VerticalPodAutoscaler
+	if vpa == nil {
+		vpa = &v1alpha1.VerticalPodAutoscalerSpec{}
+	}
+	updateMode := vpa.UpdateMode
+	if updateMode == "" {
+		updateMode = v1alpha1.VPAUpdateModeOff
+	}
+
+	policy := map[string]interface{}{
+		"containerName": k.DaemonsetName(),
+	}
+	if len(vpa.MinAllowed) != 0 {
+		policy["minAllowed"] = resourceList(vpa.MinAllowed)
+	}
+	if len(vpa.MaxAllowed) != 0 {
+		policy["maxAllowed"] = resourceList(vpa.MaxAllowed)
+	}
+
+	u.Object["spec"] = map[string]interface{}{
+		"targetRef": map[string]interface{}{
+			"apiVersion": appsv1.SchemeGroupVersion.String(),
+			"kind":       "DaemonSet",
+			"name":       k.DaemonsetName(),
+		},
+		"updatePolicy": map[string]interface{}{
+			"updateMode": string(updateMode),
+		},
+		"resourcePolicy": map[string]interface{}{
+			"containerPolicies": []interface{}{policy},
+		},
+	}
+	return u
+}
+
+func resourceList(rl corev1.ResourceList) map[string]interface{} {
+	ret := map[string]interface{}{}
+	for name, q := range rl {
+		ret[string(name)] = q.String()
+	}
+	return ret
+}
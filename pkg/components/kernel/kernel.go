@@ -0,0 +1,341 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kernel
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	setupContainerName = "kernel-setup"
+	hostRootPath       = "/host"
+	modulesEnv         = "KERNEL_MODULES"
+	modulesLoadPath    = "/etc/modules-load.d/kepler.conf"
+
+	// NOTE: annotations set by the Machine Config Operator on the nodes
+	mcStateAnnotation         = "machineconfiguration.openshift.io/state"
+	mcCurrentConfigAnnotation = "machineconfiguration.openshift.io/currentConfig"
+	mcDesiredConfigAnnotation = "machineconfiguration.openshift.io/desiredConfig"
+	mcStateDone               = "Done"
+
+	mcRoleLabel    = "machineconfiguration.openshift.io/role"
+	nodeRolePrefix = "node-role.kubernetes.io/"
+)
+
+// MachineConfigGVK is the MachineConfig of the Machine Config Operator; the
+// types are not imported to avoid depending on the operator
+var MachineConfigGVK = schema.GroupVersionKind{Group: "machineconfiguration.openshift.io", Version: "v1", Kind: "MachineConfig"}
+
+// MachineConfigPoolGVK is the pool of nodes a MachineConfig is rendered for
+var MachineConfigPoolGVK = schema.GroupVersionKind{Group: "machineconfiguration.openshift.io", Version: "v1", Kind: "MachineConfigPool"}
+
+// MachineConfigAnnotations are the annotations of the nodes the status of the
+// MachineConfigs is derived from
+var MachineConfigAnnotations = []string{mcStateAnnotation, mcCurrentConfigAnnotation, mcDesiredConfigAnnotation}
+
+var (
+	// DefaultModules are the kernel modules that expose RAPL
+	DefaultModules = []string{"intel_rapl_common", "intel_rapl_msr"}
+
+	// roles are the MachineConfigPools a MachineConfig is created for
+	roles = []string{"master", "worker"}
+
+	labels = components.CommonLabels.Merge(k8s.StringMap{
+		"app.kubernetes.io/component":  "kernel-setup",
+		"sustainable-computing.io/app": "kernel-setup",
+	})
+
+	// setupScript loads the kernel modules and checks that the eBPF programs
+	// of kepler can be loaded; the reason of a failure is written to the
+	// termination log so that it can be reported in the status
+	setupScript = strings.Join([]string{
+		`for m in $` + modulesEnv + `; do`,
+		`  if ! chroot ` + hostRootPath + ` modprobe "$m"; then`,
+		`    echo "failed to load kernel module $m" > /dev/termination-log; exit 1`,
+		`  fi`,
+		`done`,
+		`release=$(uname -r)`,
+		`if [ ! -e ` + hostRootPath + `/sys/kernel/btf/vmlinux ] && ` +
+			`[ ! -d ` + hostRootPath + `/lib/modules/$release/build ] && ` +
+			`[ ! -d ` + hostRootPath + `/usr/src/kernels/$release ]; then`,
+		`  echo "neither BTF nor kernel headers found for kernel $release" > /dev/termination-log; exit 1`,
+		`fi`,
+	}, "\n")
+)
+
+// Name returns the name of the resources ensuring the kernel prerequisites
+func Name(ki *v1alpha1.KeplerInternal) string {
+	return ki.Name + "-kernel-setup"
+}
+
+func machineConfigName(ki *v1alpha1.KeplerInternal, role string) string {
+	return fmt.Sprintf("50-%s-%s", Name(ki), role)
+}
+
+// Roles returns the MachineConfigPools the MachineConfigs are created for
+func Roles() []string {
+	return roles
+}
+
+func podSelector(ki *v1alpha1.KeplerInternal) k8s.StringMap {
+	return labels.Merge(k8s.StringMap{
+		"app.kubernetes.io/name":                     "kernel-setup",
+		"operator.sustainable-computing.io/internal": ki.Name,
+	})
+}
+
+// Modules returns the kernel modules to load on the nodes
+func Modules(ki *v1alpha1.KeplerInternal) []string {
+	if spec := ki.Spec.Exporter.KernelPrerequisites; spec != nil && len(spec.Modules) != 0 {
+		return spec.Modules
+	}
+	return DefaultModules
+}
+
+// NewDaemonSet returns a privileged DaemonSet that loads the kernel modules
+// and checks BTF or the kernel headers once per node; the pods become ready
+// only if the prerequisites are met
+func NewDaemonSet(d components.Detail, ki *v1alpha1.KeplerInternal) *appsv1.DaemonSet {
+	ds := &appsv1.DaemonSet{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+			Kind:       "DaemonSet",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      Name(ki),
+			Namespace: ki.Namespace(),
+			Labels:    labels,
+		},
+	}
+	if d == components.Metadata {
+		return ds
+	}
+
+	deployment := ki.Spec.Exporter.Deployment
+	image := deployment.Image
+	var automount *bool
+	if spec := ki.Spec.Exporter.KernelPrerequisites; spec != nil {
+		automount = spec.AutomountServiceAccountToken
+	}
+	ds.Spec = appsv1.DaemonSetSpec{
+		Selector: &metav1.LabelSelector{MatchLabels: podSelector(ki)},
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: podSelector(ki),
+			},
+			Spec: corev1.PodSpec{
+				HostPID:            true,
+				NodeSelector:       k8s.StringMap{"kubernetes.io/os": "linux"}.Merge(deployment.NodeSelector),
+				ServiceAccountName: ki.Name,
+				Tolerations:        deployment.Tolerations,
+				// NOTE: the setup does not use the Kubernetes API
+				AutomountServiceAccountToken: automount,
+				// NOTE: the setup runs once per node in an init container and
+				// the pod then idles so that the DaemonSet does not restart it
+				InitContainers: []corev1.Container{{
+					Name:            setupContainerName,
+					Image:           image,
+					ImagePullPolicy: corev1.PullIfNotPresent,
+					Command:         []string{"/bin/sh", "-c", setupScript},
+					Env: []corev1.EnvVar{{
+						Name:  modulesEnv,
+						Value: strings.Join(Modules(ki), " "),
+					}},
+					SecurityContext: &corev1.SecurityContext{Privileged: ptr.To(true)},
+					VolumeMounts: []corev1.VolumeMount{{
+						Name:      "host",
+						MountPath: hostRootPath,
+						ReadOnly:  true,
+					}},
+					TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+				}},
+				Containers: []corev1.Container{{
+					Name:            "pause",
+					Image:           image,
+					ImagePullPolicy: corev1.PullIfNotPresent,
+					Command:         []string{"/bin/sh", "-c", "sleep infinity"},
+				}},
+				Volumes: []corev1.Volume{
+					k8s.VolumeFromHost("host", "/"),
+				},
+			},
+		},
+	}
+	return ds
+}
+
+// NewMachineConfigs returns the MachineConfigs that load the kernel modules on
+// the master and worker nodes of OpenShift; BTF is always present on RHCOS
+func NewMachineConfigs(d components.Detail, ki *v1alpha1.KeplerInternal) []*unstructured.Unstructured {
+	data := "data:," + url.PathEscape(strings.Join(Modules(ki), "\n")+"\n")
+
+	mcs := make([]*unstructured.Unstructured, 0, len(roles))
+	for _, role := range roles {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(MachineConfigGVK)
+		u.SetName(machineConfigName(ki, role))
+		u.SetLabels(labels.Merge(k8s.StringMap{mcRoleLabel: role}).ToMap())
+		if d == components.Full {
+			u.Object["spec"] = map[string]interface{}{
+				"config": map[string]interface{}{
+					"ignition": map[string]interface{}{"version": "3.2.0"},
+					"storage": map[string]interface{}{
+						"files": []interface{}{map[string]interface{}{
+							"path":      modulesLoadPath,
+							"mode":      int64(0o644),
+							"overwrite": true,
+							"contents":  map[string]interface{}{"source": data},
+						}},
+					},
+				},
+			}
+		}
+		mcs = append(mcs, u)
+	}
+	return mcs
+}
+
+// StatusFromPods returns per node whether the kernel prerequisites are met as
+// reported by the pods of the setup DaemonSet
+func StatusFromPods(pods []corev1.Pod) []v1alpha1.NodeKernelStatus {
+	status := []v1alpha1.NodeKernelStatus{}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		node := v1alpha1.NodeKernelStatus{Name: pod.Spec.NodeName, Ready: podReady(pod)}
+		if !node.Ready {
+			node.Message = setupMessage(pod)
+		}
+		status = append(status, node)
+	}
+	return sortedStatus(status)
+}
+
+// StatusFromNodes returns per node whether the MachineConfig of its pool has
+// been applied by the Machine Config Operator. A node is ready once it runs
+// the configuration the pool rendered with the MachineConfig; until the pool
+// has rendered it, the configuration the node runs does not include it.
+func StatusFromNodes(ki *v1alpha1.KeplerInternal, nodes []corev1.Node, pools []unstructured.Unstructured) []v1alpha1.NodeKernelStatus {
+	rendered := renderedConfigs(ki, pools)
+
+	status := []v1alpha1.NodeKernelStatus{}
+	for i := range nodes {
+		n := &nodes[i]
+		role, ok := nodeRole(n)
+		if !ok {
+			continue
+		}
+		state := n.Annotations[mcStateAnnotation]
+		config, isRendered := rendered[role]
+		node := v1alpha1.NodeKernelStatus{
+			Name:  n.Name,
+			Ready: isRendered && state == mcStateDone && n.Annotations[mcCurrentConfigAnnotation] == config,
+		}
+		switch {
+		case node.Ready:
+		case !isRendered:
+			node.Message = fmt.Sprintf("waiting for machine config pool %s to render the machine config; "+
+				"the node reboots once it is applied", role)
+		default:
+			node.Message = fmt.Sprintf("machine config is being applied, which reboots the node; state: %q", state)
+		}
+		status = append(status, node)
+	}
+	return sortedStatus(status)
+}
+
+// renderedConfigs returns per role the configuration its pool rendered with
+// the MachineConfig of kepler-internal
+func renderedConfigs(ki *v1alpha1.KeplerInternal, pools []unstructured.Unstructured) map[string]string {
+	rendered := map[string]string{}
+	for i := range pools {
+		pool := &pools[i]
+		name, _, _ := unstructured.NestedString(pool.Object, "spec", "configuration", "name")
+		sources, _, _ := unstructured.NestedSlice(pool.Object, "spec", "configuration", "source")
+		for _, s := range sources {
+			if src, ok := s.(map[string]interface{}); ok && src["name"] == machineConfigName(ki, pool.GetName()) {
+				rendered[pool.GetName()] = name
+			}
+		}
+	}
+	return rendered
+}
+
+// nodeRole returns the role of the pool the node belongs to; masters that
+// are also workers belong to the master pool
+func nodeRole(n *corev1.Node) (string, bool) {
+	for _, role := range roles {
+		if _, ok := n.Labels[nodeRolePrefix+role]; ok {
+			return role, true
+		}
+	}
+	return "", false
+}
+
+func podReady(pod *corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// setupMessage returns why the setup of the pod has not succeeded
+func setupMessage(pod *corev1.Pod) string {
+	for _, cs := range pod.Status.InitContainerStatuses {
+		if cs.Name != setupContainerName {
+			continue
+		}
+		if t := cs.LastTerminationState.Terminated; t != nil && t.Message != "" {
+			return strings.TrimSpace(t.Message)
+		}
+		if t := cs.State.Terminated; t != nil && t.Message != "" {
+			return strings.TrimSpace(t.Message)
+		}
+		if w := cs.State.Waiting; w != nil {
+			return w.Reason
+		}
+	}
+	return "kernel setup is in progress"
+}
+
+func sortedStatus(status []v1alpha1.NodeKernelStatus) []v1alpha1.NodeKernelStatus {
+	if len(status) == 0 {
+		return nil
+	}
+	sort.Slice(status, func(i, j int) bool {
+		return status[i].Name < status[j].Name
+	})
+	return status
+}
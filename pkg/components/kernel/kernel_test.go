@@ -0,0 +1,182 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kernel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newKeplerInternal(spec *v1alpha1.KernelPrerequisitesSpec) *v1alpha1.KeplerInternal {
+	return &v1alpha1.KeplerInternal{
+		ObjectMeta: metav1.ObjectMeta{Name: "kepler"},
+		Spec: v1alpha1.KeplerInternalSpec{
+			Exporter: v1alpha1.InternalExporterSpec{
+				Deployment: v1alpha1.InternalExporterDeploymentSpec{
+					Namespace: "power-monitoring",
+					Image:     "kepler:test",
+				},
+				KernelPrerequisites: spec,
+			},
+		},
+	}
+}
+
+func TestDaemonSet(t *testing.T) {
+	tt := []struct {
+		spec     *v1alpha1.KernelPrerequisitesSpec
+		modules  string
+		scenario string
+	}{
+		{
+			spec:     &v1alpha1.KernelPrerequisitesSpec{Enabled: true},
+			modules:  "intel_rapl_common intel_rapl_msr",
+			scenario: "default modules",
+		},
+		{
+			spec:     &v1alpha1.KernelPrerequisitesSpec{Enabled: true, Modules: []string{"amd_energy"}},
+			modules:  "amd_energy",
+			scenario: "custom modules",
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			ds := NewDaemonSet(components.Full, newKeplerInternal(tc.spec))
+			assert.Equal(t, "kepler-kernel-setup", ds.Name)
+			assert.Equal(t, "power-monitoring", ds.Namespace)
+
+			spec := ds.Spec.Template.Spec
+			assert.Len(t, spec.InitContainers, 1)
+			setup := spec.InitContainers[0]
+			assert.Equal(t, "kepler:test", setup.Image)
+			assert.True(t, *setup.SecurityContext.Privileged)
+			assert.Equal(t, []corev1.EnvVar{{Name: modulesEnv, Value: tc.modules}}, setup.Env)
+			assert.Equal(t, ds.Spec.Selector.MatchLabels, ds.Spec.Template.Labels)
+		})
+	}
+}
+
+func TestMachineConfigs(t *testing.T) {
+	mcs := NewMachineConfigs(components.Full, newKeplerInternal(&v1alpha1.KernelPrerequisitesSpec{Enabled: true}))
+	assert.Len(t, mcs, 2)
+
+	for i, role := range []string{"master", "worker"} {
+		mc := mcs[i]
+		assert.Equal(t, "50-kepler-kernel-setup-"+role, mc.GetName())
+		assert.Equal(t, role, mc.GetLabels()["machineconfiguration.openshift.io/role"])
+
+		files, _, _ := unstructured.NestedSlice(mc.Object, "spec", "config", "storage", "files")
+		file := files[0].(map[string]interface{})
+		assert.Equal(t, "/etc/modules-load.d/kepler.conf", file["path"])
+		source, _, _ := unstructured.NestedString(file, "contents", "source")
+		assert.Equal(t, "data:,intel_rapl_common%0Aintel_rapl_msr%0A", source)
+	}
+}
+
+func TestStatusFromPods(t *testing.T) {
+	pods := []corev1.Pod{{
+		Spec: corev1.PodSpec{NodeName: "node-b"},
+		Status: corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{{
+				Name: setupContainerName,
+				LastTerminationState: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Message: "failed to load kernel module intel_rapl_msr\n"},
+				},
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			}},
+		},
+	}, {
+		Spec: corev1.PodSpec{NodeName: "node-a"},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}, {
+		// not scheduled yet
+		Spec: corev1.PodSpec{},
+	}}
+
+	assert.Equal(t, []v1alpha1.NodeKernelStatus{
+		{Name: "node-a", Ready: true},
+		{Name: "node-b", Message: "failed to load kernel module intel_rapl_msr"},
+	}, StatusFromPods(pods))
+	assert.Nil(t, StatusFromPods(nil))
+}
+
+func TestStatusFromNodes(t *testing.T) {
+	node := func(name, role, state, current, desired string) corev1.Node {
+		return corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"node-role.kubernetes.io/" + role: ""},
+			Annotations: map[string]string{
+				mcStateAnnotation:         state,
+				mcCurrentConfigAnnotation: current,
+				mcDesiredConfigAnnotation: desired,
+			},
+		}}
+	}
+	pool := func(role, rendered string, sources ...string) unstructured.Unstructured {
+		source := []interface{}{}
+		for _, s := range sources {
+			source = append(source, map[string]interface{}{"kind": "MachineConfig", "name": s})
+		}
+		u := unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"configuration": map[string]interface{}{"name": rendered, "source": source},
+			},
+		}}
+		u.SetGroupVersionKind(MachineConfigPoolGVK)
+		u.SetName(role)
+		return u
+	}
+	ki := newKeplerInternal(&v1alpha1.KernelPrerequisitesSpec{Enabled: true})
+	nodes := []corev1.Node{
+		node("worker-0", "worker", "Working", "rendered-worker-1", "rendered-worker-2"),
+		node("master-0", "master", "Done", "rendered-master-2", "rendered-master-2"),
+		node("infra-0", "infra", "Done", "rendered-infra-1", "rendered-infra-1"),
+	}
+
+	t.Run("rendered", func(t *testing.T) {
+		pools := []unstructured.Unstructured{
+			pool("master", "rendered-master-2", "00-master", "50-kepler-kernel-setup-master"),
+			pool("worker", "rendered-worker-2", "00-worker", "50-kepler-kernel-setup-worker"),
+		}
+		assert.Equal(t, []v1alpha1.NodeKernelStatus{
+			{Name: "master-0", Ready: true},
+			{Name: "worker-0", Message: `machine config is being applied, which reboots the node; state: "Working"`},
+		}, StatusFromNodes(ki, nodes, pools))
+	})
+
+	t.Run("not rendered", func(t *testing.T) {
+		// NOTE: the nodes are up to date with the configuration rendered
+		// before the MachineConfig was created
+		pools := []unstructured.Unstructured{
+			pool("master", "rendered-master-2", "00-master"),
+		}
+		assert.Equal(t, []v1alpha1.NodeKernelStatus{
+			{Name: "master-0", Message: "waiting for machine config pool master to render the machine config; the node reboots once it is applied"},
+			{Name: "worker-0", Message: "waiting for machine config pool worker to render the machine config; the node reboots once it is applied"},
+		}, StatusFromNodes(ki, nodes, pools))
+	})
+}
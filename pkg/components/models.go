@@ -0,0 +1,71 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ModelDigestCheckName is the name of the init container checking the digests
+// of the model archives downloaded by a pod
+const ModelDigestCheckName = "check-model-digests"
+
+// modelDigestCheck is the python script run by the digest check; its
+// arguments are pairs of url and the digest the archive at url must have
+const modelDigestCheck = `import hashlib, sys, urllib.request
+for url, digest in zip(sys.argv[1::2], sys.argv[2::2]):
+    h = hashlib.sha256()
+    with urllib.request.urlopen(url) as resp:
+        for chunk in iter(lambda: resp.read(1 << 20), b""):
+            h.update(chunk)
+    if "sha256:" + h.hexdigest() != digest:
+        sys.exit("%s: digest sha256:%s does not match the verified digest %s" % (url, h.hexdigest(), digest))
+`
+
+// AddModelDigestCheck adds an init container, running the python image of the
+// component, that fails the pod if any of the model archives at urls no longer
+// has the digest recorded when its signature was verified. Archives are
+// verified by the operator before they are deployed, so the check keeps
+// archives replaced since then from being loaded by the pod. URLs that have
+// not been verified are ignored.
+// NOTE: the digests are part of the pod template, so pods are rolled out
+// whenever an archive is verified with a new digest
+func AddModelDigestCheck(pod *corev1.PodTemplateSpec, image string, models []v1alpha1.VerifiedModel, urls ...string) {
+	digests := map[string]string{}
+	for _, m := range models {
+		digests[m.URL] = m.Digest
+	}
+
+	args := []string{"-c", modelDigestCheck}
+	for _, url := range urls {
+		if digest, ok := digests[url]; ok {
+			args = append(args, url, digest)
+		}
+	}
+	if len(args) == 2 {
+		return
+	}
+
+	pod.Spec.InitContainers = append(pod.Spec.InitContainers, corev1.Container{
+		Name:            ModelDigestCheckName,
+		Image:           image,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Command:         []string{"python3.8"},
+		Args:            args,
+	})
+}
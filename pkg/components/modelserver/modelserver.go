@@ -25,6 +25,8 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -34,6 +36,8 @@ const (
 	PVCNameSuffix   = "-pvc"
 	ConfigMapSuffix = "-cm"
 	ServiceSuffix   = "-svc"
+
+	serverContainerName = "server-api"
 )
 
 const (
@@ -53,7 +57,14 @@ var (
 	})
 )
 
-func NewDeployment(deployName string, ms *v1alpha1.InternalModelServerSpec, namespace string) *appsv1.Deployment {
+// scratchPaths are the paths the model server writes to: temporary files and
+// the models it trains, which are stored in the /mnt volume
+var scratchPaths = []string{"/tmp", "/mnt"}
+
+// NewDeployment returns the deployment of the model server; the digest of the
+// pipeline it downloads is checked by the pods if it is one of the verified
+// models
+func NewDeployment(deployName string, ms *v1alpha1.InternalModelServerSpec, namespace string, verified ...v1alpha1.VerifiedModel) *appsv1.Deployment {
 	pvcName := deployName + PVCNameSuffix
 	configMapName := deployName + ConfigMapSuffix
 	var storage corev1.Volume
@@ -80,7 +91,7 @@ func NewDeployment(deployName string, ms *v1alpha1.InternalModelServerSpec, name
 	containers := []corev1.Container{{
 		Image:           ms.Image,
 		ImagePullPolicy: corev1.PullIfNotPresent,
-		Name:            "server-api",
+		Name:            serverContainerName,
 		Ports: []corev1.ContainerPort{{
 			ContainerPort: int32(port),
 			Name:          "http",
@@ -90,7 +101,7 @@ func NewDeployment(deployName string, ms *v1alpha1.InternalModelServerSpec, name
 		Args:         []string{"-u", "src/server/model_server.py"},
 	}}
 
-	return &appsv1.Deployment{
+	deploy := &appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: appsv1.SchemeGroupVersion.String(),
 			Kind:       "Deployment",
@@ -102,6 +113,7 @@ func NewDeployment(deployName string, ms *v1alpha1.InternalModelServerSpec, name
 		},
 
 		Spec: appsv1.DeploymentSpec{
+			Replicas: ms.Replicas,
 			Selector: &metav1.LabelSelector{
 				MatchLabels: podSelector,
 			},
@@ -110,12 +122,19 @@ func NewDeployment(deployName string, ms *v1alpha1.InternalModelServerSpec, name
 					Labels: podSelector,
 				},
 				Spec: corev1.PodSpec{
-					Containers: containers,
-					Volumes:    volumes,
+					AutomountServiceAccountToken: ms.AutomountServiceAccountToken,
+					Containers:                   containers,
+					Volumes:                      volumes,
 				},
 			},
 		},
 	}
+	components.AddModelDigestCheck(&deploy.Spec.Template, ms.Image, verified, ms.PipelineURL)
+	components.ApplySecurityProfiles(&deploy.Spec.Template, ms.SecurityProfiles)
+	components.ApplyReadOnlyRootFilesystem(&deploy.Spec.Template, ms.ReadOnlyRootFilesystem, map[string][]string{
+		serverContainerName: scratchPaths,
+	})
+	return deploy
 }
 
 func NewService(deployName string, ms *v1alpha1.InternalModelServerSpec, namespace string) *corev1.Service {
@@ -143,9 +162,14 @@ func NewService(deployName string, ms *v1alpha1.InternalModelServerSpec, namespa
 	}
 }
 
+// ServerURL returns the url of the model server used by the exporter
+func ServerURL(deployName, deployNamespace string, ms *v1alpha1.InternalModelServerSpec) string {
+	return defaultIfEmpty(ms.URL, serverUrl(deployName, deployNamespace, *ms))
+}
+
 func ConfigForClient(deployName, deployNamespace string, ms *v1alpha1.InternalModelServerSpec) k8s.StringMap {
 	msConfig := k8s.StringMap{
-		"MODEL_SERVER_URL": defaultIfEmpty(ms.URL, serverUrl(deployName, deployNamespace, *ms)),
+		"MODEL_SERVER_URL": ServerURL(deployName, deployNamespace, ms),
 	}
 	msConfig = msConfig.AddIfNotEmpty("MODEL_SERVER_REQ_PATH", ms.RequestPath)
 	msConfig = msConfig.AddIfNotEmpty("MODEL_SERVER_MODEL_LIST_PATH", ms.ListPath)
@@ -190,6 +214,49 @@ func NewConfigMap(deployName string, d components.Detail, ms *v1alpha1.InternalM
 	}
 }
 
+// NewNetworkPolicy returns a NetworkPolicy that allows the model server to be
+// reached only from the deployment namespace and to download models
+func NewNetworkPolicy(deployName string, d components.Detail, ms *v1alpha1.InternalModelServerSpec, namespace string) *networkingv1.NetworkPolicy {
+	np := &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: networkingv1.SchemeGroupVersion.String(),
+			Kind:       "NetworkPolicy",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deployName,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+	}
+	if d == components.Metadata {
+		return np
+	}
+
+	np.Spec = networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{MatchLabels: podSelector},
+		PolicyTypes: []networkingv1.PolicyType{
+			networkingv1.PolicyTypeIngress,
+			networkingv1.PolicyTypeEgress,
+		},
+		Ingress: []networkingv1.NetworkPolicyIngressRule{{
+			From:  []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{}}},
+			Ports: components.NetworkPolicyPorts(corev1.ProtocolTCP, int32(ms.Port)),
+		}},
+		Egress: []networkingv1.NetworkPolicyEgressRule{
+			components.DNSEgressRule(),
+			// NOTE: initial models are downloaded from the pipeline url
+			components.TCPEgressRule(80, 443),
+		},
+	}
+	return np
+}
+
+// NewPodDisruptionBudget returns the PodDisruptionBudget of the model server
+// which is required only when it runs more than one replica
+func NewPodDisruptionBudget(deployName string, ms *v1alpha1.InternalModelServerSpec, namespace string) *policyv1.PodDisruptionBudget {
+	return components.NewPodDisruptionBudget(deployName, namespace, labels, podSelector, ms.PodDisruptionBudget)
+}
+
 func NewPVC(deployName string, namespace string, pvcSpec *corev1.PersistentVolumeClaimSpec) *corev1.PersistentVolumeClaim {
 	pvcName := deployName + PVCNameSuffix
 	return &corev1.PersistentVolumeClaim{
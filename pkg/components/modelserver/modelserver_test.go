@@ -17,11 +17,17 @@ limitations under the License.
 package modelserver
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/components"
+
+	configv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 )
 
 func TestConfigMap(t *testing.T) {
@@ -77,6 +83,35 @@ func TestConfigMap(t *testing.T) {
 
 }
 
+func TestServerURL(t *testing.T) {
+
+	tt := []struct {
+		spec     *v1alpha1.InternalModelServerSpec
+		url      string
+		scenario string
+	}{
+		{
+			spec:     &v1alpha1.InternalModelServerSpec{Port: 8100},
+			url:      "http://kepler-model-server-svc.kepler-operator.svc.cluster.local:8100",
+			scenario: "default case",
+		},
+		{
+			spec:     &v1alpha1.InternalModelServerSpec{Port: 8100, URL: "fake-url"},
+			url:      "fake-url",
+			scenario: "user defined url",
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			actual := ServerURL("kepler-model-server", "kepler-operator", tc.spec)
+			assert.Equal(t, tc.url, actual)
+		})
+	}
+}
+
 func TestService(t *testing.T) {
 
 	tt := []struct {
@@ -142,3 +177,255 @@ func TestServerAPIContainer(t *testing.T) {
 	}
 
 }
+
+func TestNetworkPolicy(t *testing.T) {
+	ms := &v1alpha1.InternalModelServerSpec{Port: 8100}
+	deploy := NewDeployment("kepler-model-server", ms, "kepler")
+	np := NewNetworkPolicy("kepler-model-server", components.Full, ms, "kepler")
+
+	assert.Equal(t, "kepler", np.Namespace)
+	assert.Equal(t, deploy.Spec.Selector.MatchLabels, np.Spec.PodSelector.MatchLabels)
+	assert.Equal(t, int32(8100), np.Spec.Ingress[0].Ports[0].Port.IntVal)
+	assert.Len(t, np.Spec.PolicyTypes, 2)
+}
+
+func TestPodDisruptionBudget(t *testing.T) {
+	tt := []struct {
+		replicas *int32
+		needed   bool
+		scenario string
+	}{
+		{replicas: nil, needed: false, scenario: "default replicas"},
+		{replicas: ptr.To(int32(1)), needed: false, scenario: "single replica"},
+		{replicas: ptr.To(int32(3)), needed: true, scenario: "multiple replicas"},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			ms := &v1alpha1.InternalModelServerSpec{Replicas: tc.replicas}
+			deploy := NewDeployment("kepler-model-server", ms, "kepler")
+			pdb := NewPodDisruptionBudget("kepler-model-server", ms, "kepler")
+
+			assert.Equal(t, tc.needed, components.NeedsPodDisruptionBudget(deploy.Spec.Replicas))
+			assert.Equal(t, deploy.Spec.Selector.MatchLabels, pdb.Spec.Selector.MatchLabels)
+			assert.Equal(t, intstr.FromInt32(1), *pdb.Spec.MinAvailable)
+		})
+	}
+}
+
+func TestSecurityProfiles(t *testing.T) {
+	tt := []struct {
+		profiles    *v1alpha1.SecurityProfilesSpec
+		seccomp     *corev1.SeccompProfile
+		annotations map[string]string
+		scenario    string
+	}{
+		{scenario: "no profiles"},
+		{
+			profiles: &v1alpha1.SecurityProfilesSpec{
+				SeccompProfile:  &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+				AppArmorProfile: "runtime/default",
+			},
+			seccomp:     &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+			annotations: map[string]string{components.AppArmorAnnotationPrefix + "server-api": "runtime/default"},
+			scenario:    "runtime default profiles",
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			ms := &v1alpha1.InternalModelServerSpec{SecurityProfiles: tc.profiles}
+			template := NewDeployment("kepler-model-server", ms, "kepler").Spec.Template
+
+			assert.Equal(t, tc.annotations, template.Annotations)
+			if tc.seccomp == nil {
+				assert.Nil(t, template.Spec.SecurityContext)
+				return
+			}
+			assert.Equal(t, tc.seccomp, template.Spec.SecurityContext.SeccompProfile)
+		})
+	}
+}
+
+func TestAutomountServiceAccountToken(t *testing.T) {
+	tt := []struct {
+		automount *bool
+		scenario  string
+	}{
+		{automount: nil, scenario: "default"},
+		{automount: ptr.To(false), scenario: "disabled"},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			ms := &v1alpha1.InternalModelServerSpec{AutomountServiceAccountToken: tc.automount}
+			spec := NewDeployment("kepler-model-server", ms, "kepler").Spec.Template.Spec
+			assert.Equal(t, tc.automount, spec.AutomountServiceAccountToken)
+		})
+	}
+}
+
+func TestMountClusterProxy(t *testing.T) {
+	tt := []struct {
+		proxy    *configv1.Proxy
+		env      []corev1.EnvVar
+		scenario string
+	}{
+		{
+			proxy:    nil,
+			env:      nil,
+			scenario: "no proxy object",
+		},
+		{
+			proxy:    &configv1.Proxy{},
+			env:      nil,
+			scenario: "proxy not configured",
+		},
+		{
+			proxy: &configv1.Proxy{
+				Status: configv1.ProxyStatus{
+					HTTPSProxy: "http://proxy.example.com:3128",
+					NoProxy:    ".cluster.local,.svc",
+				},
+			},
+			env: []corev1.EnvVar{
+				{Name: "HTTPS_PROXY", Value: "http://proxy.example.com:3128"},
+				{Name: "NO_PROXY", Value: ".cluster.local,.svc"},
+				{Name: "REQUESTS_CA_BUNDLE", Value: "/etc/kepler/trusted-ca/tls-ca-bundle.pem"},
+				{Name: "SSL_CERT_FILE", Value: "/etc/kepler/trusted-ca/tls-ca-bundle.pem"},
+			},
+			scenario: "https proxy",
+		},
+		{
+			proxy: &configv1.Proxy{
+				Spec: configv1.ProxySpec{TrustedCA: configv1.ConfigMapNameReference{Name: "user-ca-bundle"}},
+			},
+			env: []corev1.EnvVar{
+				{Name: "REQUESTS_CA_BUNDLE", Value: "/etc/kepler/trusted-ca/tls-ca-bundle.pem"},
+				{Name: "SSL_CERT_FILE", Value: "/etc/kepler/trusted-ca/tls-ca-bundle.pem"},
+			},
+			scenario: "trusted ca only",
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			ms := &v1alpha1.InternalModelServerSpec{PipelineURL: "https://models/pipeline.zip"}
+			verified := v1alpha1.VerifiedModel{URL: "https://models/pipeline.zip", Digest: "sha256:ab"}
+			deploy := NewDeployment("kepler-model-server", ms, "kepler", verified)
+			volumes := len(deploy.Spec.Template.Spec.Volumes)
+
+			MountClusterProxyToDeployment(deploy, tc.proxy)
+
+			spec := deploy.Spec.Template.Spec
+			assert.Equal(t, tc.env, spec.Containers[0].Env)
+			// the init container checking the digest downloads the model too
+			assert.Equal(t, tc.env, spec.InitContainers[0].Env)
+			if tc.env == nil {
+				assert.Len(t, spec.Volumes, volumes)
+				return
+			}
+			ca := spec.Volumes[len(spec.Volumes)-1]
+			assert.Equal(t, "kepler-model-server-trusted-ca", ca.ConfigMap.Name)
+			assert.Equal(t, "ca-bundle.crt", ca.ConfigMap.Items[0].Key)
+		})
+	}
+}
+
+func TestTrustedCAConfigMap(t *testing.T) {
+	cm := NewTrustedCAConfigMap("kepler-model-server", "kepler")
+	assert.Equal(t, "kepler-model-server-trusted-ca", cm.Name)
+	assert.Equal(t, "true", cm.Labels[InjectTrustedCABundleLabel])
+	assert.Empty(t, cm.Data)
+}
+
+func TestReadOnlyRootFilesystem(t *testing.T) {
+	tt := []struct {
+		rofs     *v1alpha1.ReadOnlyRootFilesystemSpec
+		mounts   map[string]string
+		scenario string
+	}{
+		{scenario: "not configured"},
+		{rofs: &v1alpha1.ReadOnlyRootFilesystemSpec{}, scenario: "disabled"},
+		{
+			rofs:     &v1alpha1.ReadOnlyRootFilesystemSpec{Enabled: true},
+			mounts:   map[string]string{"/tmp": "scratch-tmp", "/mnt": "mnt"},
+			scenario: "enabled",
+		},
+		{
+			rofs: &v1alpha1.ReadOnlyRootFilesystemSpec{Enabled: true, ScratchPaths: []string{"/home/user/.cache"}},
+			mounts: map[string]string{
+				"/tmp":              "scratch-tmp",
+				"/mnt":              "mnt",
+				"/home/user/.cache": "scratch-home-user-cache",
+			},
+			scenario: "additional scratch paths",
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			ms := &v1alpha1.InternalModelServerSpec{ReadOnlyRootFilesystem: tc.rofs}
+			spec := NewDeployment("kepler-model-server", ms, "kepler").Spec.Template.Spec
+			server := spec.Containers[0]
+			if tc.mounts == nil {
+				assert.Nil(t, server.SecurityContext)
+				return
+			}
+			assert.True(t, *server.SecurityContext.ReadOnlyRootFilesystem)
+
+			mounts := map[string]string{}
+			for _, m := range server.VolumeMounts {
+				mounts[m.MountPath] = m.Name
+			}
+			for path, name := range tc.mounts {
+				assert.Equal(t, name, mounts[path], path)
+				assert.True(t, slices.ContainsFunc(spec.Volumes, func(v corev1.Volume) bool { return v.Name == name }), name)
+			}
+		})
+	}
+}
+
+func TestModelDigestCheck(t *testing.T) {
+	tt := []struct {
+		verified []v1alpha1.VerifiedModel
+		args     []string
+		scenario string
+	}{
+		{scenario: "not verified"},
+		{
+			verified: []v1alpha1.VerifiedModel{{URL: "https://models/other.zip", Digest: "sha256:00"}},
+			scenario: "other model verified",
+		},
+		{
+			verified: []v1alpha1.VerifiedModel{{URL: "https://models/pipeline.zip", Digest: "sha256:ab"}},
+			args:     []string{"https://models/pipeline.zip", "sha256:ab"},
+			scenario: "pipeline verified",
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			ms := &v1alpha1.InternalModelServerSpec{
+				Image:       "model-server:test",
+				PipelineURL: "https://models/pipeline.zip",
+			}
+			spec := NewDeployment("kepler-model-server", ms, "kepler", tc.verified...).Spec.Template.Spec
+			if tc.args == nil {
+				assert.Empty(t, spec.InitContainers)
+				return
+			}
+			assert.Len(t, spec.InitContainers, 1)
+			check := spec.InitContainers[0]
+			assert.Equal(t, components.ModelDigestCheckName, check.Name)
+			assert.Equal(t, "model-server:test", check.Image)
+			assert.Equal(t, tc.args, check.Args[2:])
+		})
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package modelserver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
+
+	configv1 "github.com/openshift/api/config/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	TrustedCASuffix = "-trusted-ca"
+
+	// InjectTrustedCABundleLabel makes the Cluster Network Operator of
+	// OpenShift inject the trusted CA bundle of the cluster into a configmap
+	InjectTrustedCABundleLabel = "config.openshift.io/inject-trusted-cabundle"
+
+	// ClusterProxyName is the name of the cluster-wide Proxy of OpenShift
+	ClusterProxyName = "cluster"
+
+	trustedCABundleKey = "ca-bundle.crt"
+	trustedCAVolume    = "trusted-ca"
+	trustedCAPath      = "/etc/kepler/trusted-ca"
+	trustedCAFile      = "tls-ca-bundle.pem"
+)
+
+// NewTrustedCAConfigMap returns the configmap into which OpenShift injects the
+// trusted CA bundle used by the model server to download models
+func NewTrustedCAConfigMap(deployName string, namespace string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deployName + TrustedCASuffix,
+			Namespace: namespace,
+			Labels: labels.Merge(k8s.StringMap{
+				InjectTrustedCABundleLabel: "true",
+			}),
+		},
+	}
+}
+
+// MountClusterProxy reads the cluster-wide proxy of OpenShift and mounts it to
+// the model server deployment; the deployment is left as is if there is no
+// cluster-wide proxy
+func MountClusterProxy(ctx context.Context, c client.Reader, deploy *appsv1.Deployment) error {
+	proxy := &configv1.Proxy{}
+	if err := c.Get(ctx, types.NamespacedName{Name: ClusterProxyName}, proxy); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to get the cluster proxy: %w", err)
+		}
+		return nil
+	}
+	MountClusterProxyToDeployment(deploy, proxy)
+	return nil
+}
+
+// MountClusterProxyToDeployment sets the proxy env of the cluster-wide proxy
+// in all containers of the model server, including the init containers that
+// download the models, and mounts the trusted CA bundle so that models can be
+// downloaded behind a proxy
+func MountClusterProxyToDeployment(deploy *appsv1.Deployment, proxy *configv1.Proxy) {
+	if proxy == nil {
+		return
+	}
+
+	env := []corev1.EnvVar{}
+	for _, e := range []corev1.EnvVar{
+		{Name: "HTTP_PROXY", Value: proxy.Status.HTTPProxy},
+		{Name: "HTTPS_PROXY", Value: proxy.Status.HTTPSProxy},
+		{Name: "NO_PROXY", Value: proxy.Status.NoProxy},
+	} {
+		if e.Value != "" {
+			env = append(env, e)
+		}
+	}
+	if len(env) == 0 && proxy.Spec.TrustedCA.Name == "" {
+		return
+	}
+
+	// NOTE: python requests does not use the CA bundle of the system
+	caFile := trustedCAPath + "/" + trustedCAFile
+	env = append(env,
+		corev1.EnvVar{Name: "REQUESTS_CA_BUNDLE", Value: caFile},
+		corev1.EnvVar{Name: "SSL_CERT_FILE", Value: caFile},
+	)
+
+	spec := &deploy.Spec.Template.Spec
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for i := range containers {
+			c := &containers[i]
+			c.Env = append(c.Env, env...)
+			c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+				Name:      trustedCAVolume,
+				MountPath: trustedCAPath,
+				ReadOnly:  true,
+			})
+		}
+	}
+
+	volume := k8s.VolumeFromConfigMap(trustedCAVolume, deploy.Name+TrustedCASuffix)
+	volume.ConfigMap.Items = []corev1.KeyToPath{{Key: trustedCABundleKey, Path: trustedCAFile}}
+	spec.Volumes = append(spec.Volumes, volume)
+}
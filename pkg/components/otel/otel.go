@@ -0,0 +1,278 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otel
+
+import (
+	"fmt"
+
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components/exporter"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	StableImage = "ghcr.io/open-telemetry/opentelemetry-collector-releases/opentelemetry-collector-contrib:0.96.0"
+
+	configKey  = "collector.yaml"
+	authEnv    = "OTLP_AUTHORIZATION"
+	authKey    = "authorization"
+	configPath = "/etc/otelcol"
+)
+
+// CollectorGVK is the OpenTelemetryCollector of the OpenTelemetry Operator;
+// the types are not imported to avoid depending on the operator
+var CollectorGVK = schema.GroupVersionKind{Group: "opentelemetry.io", Version: "v1alpha1", Kind: "OpenTelemetryCollector"}
+
+var (
+	// common labels for all resources of the collector
+	labels = components.CommonLabels.Merge(k8s.StringMap{
+		"app.kubernetes.io/component":  "otel-collector",
+		"sustainable-computing.io/app": "otel-collector",
+	})
+)
+
+// Name returns the name of the collector and its resources
+func Name(ki *v1alpha1.KeplerInternal) string {
+	return ki.Name + "-otel-collector"
+}
+
+func podSelector(ki *v1alpha1.KeplerInternal) k8s.StringMap {
+	return labels.Merge(k8s.StringMap{
+		"app.kubernetes.io/name":                     "otel-collector",
+		"operator.sustainable-computing.io/internal": ki.Name,
+	})
+}
+
+// Config returns the configuration of the collector which scrapes all the
+// exporter pods behind the headless service of kepler and exports the metrics
+// to the OTLP endpoint in the spec
+func Config(ki *v1alpha1.KeplerInternal) (string, error) {
+	otel := spec(ki)
+	svc := exporter.NewService(ki)
+
+	exporterName := "otlp"
+	if otel.Protocol == v1alpha1.OTLPHTTP {
+		exporterName = "otlphttp"
+	}
+	otlp := map[string]interface{}{
+		"endpoint": otel.Endpoint,
+		"tls":      map[string]interface{}{"insecure": otel.Insecure},
+	}
+	if otel.AuthSecretRef != "" {
+		otlp["headers"] = map[string]interface{}{
+			"Authorization": "${env:" + authEnv + "}",
+		}
+	}
+
+	cfg := map[string]interface{}{
+		"receivers": map[string]interface{}{
+			"prometheus": map[string]interface{}{
+				"config": map[string]interface{}{
+					"scrape_configs": []interface{}{map[string]interface{}{
+						"job_name":        "kepler",
+						"scrape_interval": string(exporter.DefaultScrapeInterval),
+						// NOTE: the service is headless, so its name resolves to all the exporter pods
+						"dns_sd_configs": []interface{}{map[string]interface{}{
+							"names": []string{fmt.Sprintf("%s.%s.svc", svc.Name, svc.Namespace)},
+							"type":  "A",
+							"port":  svc.Spec.Ports[0].Port,
+						}},
+					}},
+				},
+			},
+		},
+		"processors": map[string]interface{}{
+			"batch": map[string]interface{}{},
+		},
+		"exporters": map[string]interface{}{
+			exporterName: otlp,
+		},
+		"service": map[string]interface{}{
+			"pipelines": map[string]interface{}{
+				"metrics": map[string]interface{}{
+					"receivers":  []string{"prometheus"},
+					"processors": []string{"batch"},
+					"exporters":  []string{exporterName},
+				},
+			},
+		},
+	}
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal collector config: %w", err)
+	}
+	return string(data), nil
+}
+
+// NewConfigMap returns the configmap holding the configuration of the collector
+func NewConfigMap(d components.Detail, ki *v1alpha1.KeplerInternal) (*corev1.ConfigMap, error) {
+	cm := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      Name(ki),
+			Namespace: ki.Namespace(),
+			Labels:    labels,
+		},
+	}
+	if d == components.Metadata {
+		return cm, nil
+	}
+
+	cfg, err := Config(ki)
+	if err != nil {
+		return nil, err
+	}
+	cm.Data = map[string]string{configKey: cfg}
+	return cm, nil
+}
+
+// NewDeployment returns the deployment of the collector; the pods are
+// redeployed when the configuration in cm changes
+func NewDeployment(ki *v1alpha1.KeplerInternal, cm *corev1.ConfigMap) *appsv1.Deployment {
+	name := Name(ki)
+	otel := spec(ki)
+
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+			Kind:       "Deployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ki.Namespace(),
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To(int32(1)),
+			Selector: &metav1.LabelSelector{MatchLabels: podSelector(ki)},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: podSelector(ki),
+					Annotations: map[string]string{
+						exporter.ConfigHashAnnotation: exporter.ConfigHash(cm.Data),
+					},
+				},
+				Spec: corev1.PodSpec{
+					AutomountServiceAccountToken: otel.AutomountServiceAccountToken,
+					Containers: []corev1.Container{{
+						Name:            "otel-collector",
+						Image:           otel.Image,
+						ImagePullPolicy: corev1.PullIfNotPresent,
+						Args:            []string{"--config=" + configPath + "/" + configKey},
+						Env:             authEnvVars(otel),
+						VolumeMounts: []corev1.VolumeMount{{
+							Name:      "config",
+							MountPath: configPath,
+							ReadOnly:  true,
+						}},
+					}},
+					Volumes: []corev1.Volume{
+						k8s.VolumeFromConfigMap("config", name),
+					},
+				},
+			},
+		},
+	}
+}
+
+// NewCollector returns an OpenTelemetryCollector of the OpenTelemetry Operator
+// with the same configuration as the collector deployed by the operator
+func NewCollector(d components.Detail, ki *v1alpha1.KeplerInternal) (*unstructured.Unstructured, error) {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(CollectorGVK)
+	u.SetName(Name(ki))
+	u.SetNamespace(ki.Namespace())
+	u.SetLabels(labels)
+	if d == components.Metadata {
+		return u, nil
+	}
+
+	cfg, err := Config(ki)
+	if err != nil {
+		return nil, err
+	}
+	collector := map[string]interface{}{
+		"mode":   "deployment",
+		"config": cfg,
+	}
+
+	otel := spec(ki)
+	if otel.Image != "" {
+		collector["image"] = otel.Image
+	}
+	if env := authEnvVars(otel); len(env) != 0 {
+		envs := []interface{}{}
+		for _, e := range env {
+			envs = append(envs, map[string]interface{}{
+				"name": e.Name,
+				"valueFrom": map[string]interface{}{
+					"secretKeyRef": map[string]interface{}{
+						"name": e.ValueFrom.SecretKeyRef.Name,
+						"key":  e.ValueFrom.SecretKeyRef.Key,
+					},
+				},
+			})
+		}
+		collector["env"] = envs
+	}
+	u.Object["spec"] = collector
+	return u, nil
+}
+
+// PodSecrets returns the names of the secrets used by the collector pods
+// which have to be redeployed when the secrets are rotated
+func PodSecrets(ki *v1alpha1.KeplerInternal) []string {
+	if otel := spec(ki); otel.AuthSecretRef != "" {
+		return []string{otel.AuthSecretRef}
+	}
+	return nil
+}
+
+func authEnvVars(otel *v1alpha1.InternalOpenTelemetrySpec) []corev1.EnvVar {
+	if otel.AuthSecretRef == "" {
+		return nil
+	}
+	return []corev1.EnvVar{{
+		Name: authEnv,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: otel.AuthSecretRef},
+				Key:                  authKey,
+			},
+		},
+	}}
+}
+
+func spec(ki *v1alpha1.KeplerInternal) *v1alpha1.InternalOpenTelemetrySpec {
+	if ki.Spec.OpenTelemetry == nil {
+		return &v1alpha1.InternalOpenTelemetrySpec{}
+	}
+	return ki.Spec.OpenTelemetry
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newKeplerInternal(otel v1alpha1.OpenTelemetrySpec) *v1alpha1.KeplerInternal {
+	return &v1alpha1.KeplerInternal{
+		ObjectMeta: metav1.ObjectMeta{Name: "kepler"},
+		Spec: v1alpha1.KeplerInternalSpec{
+			Exporter: v1alpha1.InternalExporterSpec{
+				Deployment: v1alpha1.InternalExporterDeploymentSpec{
+					Namespace:              "power-monitoring",
+					ExporterDeploymentSpec: v1alpha1.ExporterDeploymentSpec{Port: 9103},
+				},
+			},
+			OpenTelemetry: &v1alpha1.InternalOpenTelemetrySpec{
+				OpenTelemetrySpec: otel,
+				Image:             "otel-collector:test",
+			},
+		},
+	}
+}
+
+func TestConfig(t *testing.T) {
+	tt := []struct {
+		spec     v1alpha1.OpenTelemetrySpec
+		contains []string
+		excludes []string
+		scenario string
+	}{
+		{
+			spec: v1alpha1.OpenTelemetrySpec{Enabled: true, Endpoint: "otlp.example.com:4317"},
+			contains: []string{
+				"endpoint: otlp.example.com:4317",
+				"- kepler.power-monitoring.svc",
+				"port: 9103",
+				"- otlp\n",
+			},
+			excludes: []string{"otlphttp", "Authorization"},
+			scenario: "grpc exporter by default",
+		},
+		{
+			spec: v1alpha1.OpenTelemetrySpec{
+				Enabled:  true,
+				Endpoint: "https://otlp.example.com",
+				Protocol: v1alpha1.OTLPHTTP,
+			},
+			contains: []string{"otlphttp:", "- otlphttp"},
+			scenario: "http exporter",
+		},
+		{
+			spec: v1alpha1.OpenTelemetrySpec{
+				Enabled:       true,
+				Endpoint:      "otlp.example.com:4317",
+				AuthSecretRef: "otlp-auth",
+			},
+			contains: []string{"Authorization: ${env:OTLP_AUTHORIZATION}"},
+			scenario: "authorization header from secret",
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			cfg, err := Config(newKeplerInternal(tc.spec))
+			assert.NoError(t, err)
+			for _, s := range tc.contains {
+				assert.Contains(t, cfg, s)
+			}
+			for _, s := range tc.excludes {
+				assert.NotContains(t, cfg, s)
+			}
+		})
+	}
+}
+
+func TestDeployment(t *testing.T) {
+	ki := newKeplerInternal(v1alpha1.OpenTelemetrySpec{
+		Enabled:       true,
+		Endpoint:      "otlp.example.com:4317",
+		AuthSecretRef: "otlp-auth",
+	})
+
+	cm, err := NewConfigMap(components.Full, ki)
+	assert.NoError(t, err)
+	deploy := NewDeployment(ki, cm)
+
+	assert.Equal(t, "power-monitoring", deploy.Namespace)
+	assert.Equal(t, cm.Name, deploy.Spec.Template.Spec.Volumes[0].ConfigMap.Name)
+
+	container := deploy.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "otel-collector:test", container.Image)
+	assert.Equal(t, "otlp-auth", container.Env[0].ValueFrom.SecretKeyRef.Name)
+}
+
+func TestCollector(t *testing.T) {
+	ki := newKeplerInternal(v1alpha1.OpenTelemetrySpec{
+		Enabled:       true,
+		Kind:          v1alpha1.OperatorCollector,
+		Endpoint:      "otlp.example.com:4317",
+		AuthSecretRef: "otlp-auth",
+	})
+
+	cfg, err := Config(ki)
+	assert.NoError(t, err)
+	collector, err := NewCollector(components.Full, ki)
+	assert.NoError(t, err)
+
+	actual, _, _ := unstructured.NestedString(collector.Object, "spec", "config")
+	assert.Equal(t, cfg, actual)
+	image, _, _ := unstructured.NestedString(collector.Object, "spec", "image")
+	assert.Equal(t, "otel-collector:test", image)
+	env, _, _ := unstructured.NestedSlice(collector.Object, "spec", "env")
+	assert.Len(t, env, 1)
+}
+
+func TestDisabled(t *testing.T) {
+	ki := newKeplerInternal(v1alpha1.OpenTelemetrySpec{})
+	ki.Spec.OpenTelemetry = nil
+	assert.False(t, ki.Spec.OpenTelemetry.IsEnabled())
+
+	cm, err := NewConfigMap(components.Metadata, ki)
+	assert.NoError(t, err)
+	assert.Equal(t, "kepler-otel-collector", cm.Name)
+}
@@ -0,0 +1,282 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package telegraf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components/exporter"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	StableImage = "docker.io/library/telegraf:1.30-alpine"
+
+	// TokenKey is the key of the token of InfluxDB in the secret
+	TokenKey = "token"
+
+	// ConnectionKey is the key of the connection string of TimescaleDB in
+	// the secret
+	ConnectionKey = "connection"
+
+	configKey  = "telegraf.conf"
+	configPath = "/etc/telegraf"
+	caPath     = "/etc/telegraf-ca"
+	secretEnv  = "TELEGRAF_OUTPUT_SECRET"
+
+	serviceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+	defaultInterval = "30s"
+	defaultBucket   = "kepler"
+)
+
+var (
+	// common labels for all resources of telegraf
+	labels = components.CommonLabels.Merge(k8s.StringMap{
+		"app.kubernetes.io/component":  "telegraf",
+		"sustainable-computing.io/app": "telegraf",
+	})
+)
+
+// Name returns the name of the telegraf deployment and its resources
+func Name(ki *v1alpha1.KeplerInternal) string {
+	return ki.Name + "-telegraf"
+}
+
+func podSelector(ki *v1alpha1.KeplerInternal) k8s.StringMap {
+	return labels.Merge(k8s.StringMap{
+		"app.kubernetes.io/name":                     "telegraf",
+		"operator.sustainable-computing.io/internal": ki.Name,
+	})
+}
+
+// Config returns the configuration of telegraf which scrapes all the exporter
+// pods behind the headless service of kepler and writes the metrics to the
+// database in the spec; the secret is read from the environment. The metrics
+// served by kube-rbac-proxy are scraped with the token of the service account
+// of telegraf which is bound to the metrics reader role.
+func Config(ki *v1alpha1.KeplerInternal) (string, error) {
+	t := spec(ki)
+	svc := exporter.NewService(ki)
+
+	interval := string(t.Interval)
+	if interval == "" {
+		interval = defaultInterval
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[agent]\n  interval = %s\n  flush_interval = %s\n  omit_hostname = true\n\n",
+		strconv.Quote(interval), strconv.Quote(interval))
+
+	// NOTE: kubernetes_services resolves the name of the headless service to
+	// all the exporter pods. With metric_version 2 the measurement of all
+	// metrics is prometheus and the metric names are fields, so the kepler
+	// metrics are selected by field.
+	fmt.Fprintf(&b, "[[inputs.prometheus]]\n  kubernetes_services = [%s]\n  metric_version = 2\n  fieldinclude = [\"kepler_*\"]\n",
+		strconv.Quote(fmt.Sprintf("%s://%s.%s.svc:%d/metrics", exporter.MetricsScheme(ki), svc.Name, svc.Namespace, svc.Spec.Ports[0].Port)))
+	if ki.Spec.Exporter.RBACProxy.IsEnabled() {
+		// NOTE: the pods are scraped by ip, so the name of the service the
+		// certificate is issued for is verified instead
+		fmt.Fprintf(&b, "  bearer_token = %s\n  tls_ca = %s\n  tls_server_name = %s\n",
+			strconv.Quote(serviceAccountTokenFile), strconv.Quote(caPath+"/"+exporter.MetricsCAKey),
+			strconv.Quote(exporter.ServingCertDNSNames(ki)[0]))
+	}
+	b.WriteString("\n")
+
+	switch output(t) {
+	case v1alpha1.InfluxDBOutput:
+		if t.URL == "" {
+			return "", fmt.Errorf("url is required for the InfluxDB output")
+		}
+		bucket := t.Bucket
+		if bucket == "" {
+			bucket = defaultBucket
+		}
+		fmt.Fprintf(&b, "[[outputs.influxdb_v2]]\n  urls = [%s]\n  token = \"${%s}\"\n  organization = %s\n  bucket = %s\n",
+			strconv.Quote(t.URL), secretEnv, strconv.Quote(t.Organization), strconv.Quote(bucket))
+	case v1alpha1.TimescaleOutput:
+		// NOTE: the tables are created as hypertables partitioned by time
+		fmt.Fprintf(&b, "[[outputs.postgresql]]\n  connection = \"${%s}\"\n  create_templates = [\n"+
+			"    '''CREATE TABLE {{ .table }} ({{ .columns }})''',\n"+
+			"    '''SELECT create_hypertable({{ .table|quoteLiteral }}, 'time', chunk_time_interval => INTERVAL '1d')''',\n"+
+			"  ]\n", secretEnv)
+	default:
+		return "", fmt.Errorf("unsupported output %q", t.Output)
+	}
+	return b.String(), nil
+}
+
+// NewConfigMap returns the configmap holding the configuration of telegraf
+func NewConfigMap(d components.Detail, ki *v1alpha1.KeplerInternal) (*corev1.ConfigMap, error) {
+	cm := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      Name(ki),
+			Namespace: ki.Namespace(),
+			Labels:    labels,
+		},
+	}
+	if d == components.Metadata {
+		return cm, nil
+	}
+
+	cfg, err := Config(ki)
+	if err != nil {
+		return nil, err
+	}
+	cm.Data = map[string]string{configKey: cfg}
+	return cm, nil
+}
+
+// NewServiceAccount returns the service account of telegraf which is bound to
+// the metrics reader role when the metrics are served by kube-rbac-proxy
+func NewServiceAccount(ki *v1alpha1.KeplerInternal) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "ServiceAccount",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      Name(ki),
+			Namespace: ki.Namespace(),
+			Labels:    labels,
+		},
+	}
+}
+
+// NewMetricsReaderBinding returns the binding that authorizes telegraf to
+// scrape the metrics served by kube-rbac-proxy
+func NewMetricsReaderBinding(d components.Detail, ki *v1alpha1.KeplerInternal) *rbacv1.ClusterRoleBinding {
+	return exporter.NewMetricsReaderBinding(d, ki, Name(ki)+"-metrics-reader", Name(ki), ki.Namespace())
+}
+
+// NewDeployment returns the deployment of telegraf; the pods are redeployed
+// when the configuration in cm changes
+func NewDeployment(ki *v1alpha1.KeplerInternal, cm *corev1.ConfigMap) *appsv1.Deployment {
+	name := Name(ki)
+	t := spec(ki)
+
+	key := TokenKey
+	if output(t) == v1alpha1.TimescaleOutput {
+		key = ConnectionKey
+	}
+
+	mounts := []corev1.VolumeMount{{
+		Name:      "config",
+		MountPath: configPath,
+		ReadOnly:  true,
+	}}
+	volumes := []corev1.Volume{
+		k8s.VolumeFromConfigMap("config", name),
+	}
+	// NOTE: telegraf scrapes kepler through its service and uses the
+	// Kubernetes API only to authenticate to kube-rbac-proxy
+	rbacProxy := ki.Spec.Exporter.RBACProxy.IsEnabled()
+	if rbacProxy {
+		mounts = append(mounts, corev1.VolumeMount{Name: "metrics-ca", MountPath: caPath, ReadOnly: true})
+		volumes = append(volumes, exporter.NewMetricsCAVolume("metrics-ca", ki))
+	}
+
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+			Kind:       "Deployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ki.Namespace(),
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To(int32(1)),
+			Selector: &metav1.LabelSelector{MatchLabels: podSelector(ki)},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: podSelector(ki),
+					Annotations: map[string]string{
+						exporter.ConfigHashAnnotation: exporter.ConfigHash(cm.Data),
+					},
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName:           name,
+					AutomountServiceAccountToken: ptr.To(rbacProxy),
+					Containers: []corev1.Container{{
+						Name:            "telegraf",
+						Image:           t.Image,
+						ImagePullPolicy: corev1.PullIfNotPresent,
+						Args:            []string{"--config=" + configPath + "/" + configKey},
+						Env: []corev1.EnvVar{{
+							Name: secretEnv,
+							ValueFrom: &corev1.EnvVarSource{
+								SecretKeyRef: &corev1.SecretKeySelector{
+									LocalObjectReference: corev1.LocalObjectReference{Name: t.SecretRef},
+									Key:                  key,
+								},
+							},
+						}},
+						SecurityContext: &corev1.SecurityContext{
+							AllowPrivilegeEscalation: ptr.To(false),
+							Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+						},
+						VolumeMounts: mounts,
+					}},
+					SecurityContext: &corev1.PodSecurityContext{
+						RunAsNonRoot:   ptr.To(true),
+						SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+					},
+					Volumes: volumes,
+				},
+			},
+		},
+	}
+}
+
+// PodSecrets returns the names of the secrets used by the telegraf pods which
+// have to be redeployed when the secrets are rotated
+func PodSecrets(ki *v1alpha1.KeplerInternal) []string {
+	if t := spec(ki); t.SecretRef != "" {
+		return []string{t.SecretRef}
+	}
+	return nil
+}
+
+func output(t *v1alpha1.InternalTelegrafSpec) v1alpha1.TelegrafOutput {
+	if t.Output == "" {
+		return v1alpha1.InfluxDBOutput
+	}
+	return t.Output
+}
+
+func spec(ki *v1alpha1.KeplerInternal) *v1alpha1.InternalTelegrafSpec {
+	if ki.Spec.Telegraf == nil {
+		return &v1alpha1.InternalTelegrafSpec{}
+	}
+	return ki.Spec.Telegraf
+}
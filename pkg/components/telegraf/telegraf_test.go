@@ -0,0 +1,241 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package telegraf
+
+import (
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newKeplerInternal(telegraf v1alpha1.TelegrafSpec) *v1alpha1.KeplerInternal {
+	return &v1alpha1.KeplerInternal{
+		ObjectMeta: metav1.ObjectMeta{Name: "kepler"},
+		Spec: v1alpha1.KeplerInternalSpec{
+			Exporter: v1alpha1.InternalExporterSpec{
+				Deployment: v1alpha1.InternalExporterDeploymentSpec{
+					Namespace:              "power-monitoring",
+					ExporterDeploymentSpec: v1alpha1.ExporterDeploymentSpec{Port: 9103},
+				},
+			},
+			Telegraf: &v1alpha1.InternalTelegrafSpec{
+				TelegrafSpec: telegraf,
+				Image:        "telegraf:test",
+			},
+		},
+	}
+}
+
+func TestConfig(t *testing.T) {
+	tt := []struct {
+		spec     v1alpha1.TelegrafSpec
+		contains []string
+		excludes []string
+		scenario string
+	}{
+		{
+			spec: v1alpha1.TelegrafSpec{
+				Enabled:      true,
+				URL:          "http://influxdb.monitoring.svc:8086",
+				Organization: "green",
+				SecretRef:    "influxdb",
+			},
+			contains: []string{
+				`interval = "30s"`,
+				`kubernetes_services = ["http://kepler.power-monitoring.svc:9103/metrics"]`,
+				"[[outputs.influxdb_v2]]",
+				`urls = ["http://influxdb.monitoring.svc:8086"]`,
+				`token = "${TELEGRAF_OUTPUT_SECRET}"`,
+				`organization = "green"`,
+				`bucket = "kepler"`,
+			},
+			excludes: []string{"outputs.postgresql"},
+			scenario: "influxdb by default",
+		},
+		{
+			spec: v1alpha1.TelegrafSpec{
+				Enabled:   true,
+				Output:    v1alpha1.TimescaleOutput,
+				SecretRef: "timescale",
+				Interval:  "1m",
+			},
+			contains: []string{
+				`interval = "1m"`,
+				"[[outputs.postgresql]]",
+				`connection = "${TELEGRAF_OUTPUT_SECRET}"`,
+				"create_hypertable",
+			},
+			excludes: []string{"outputs.influxdb_v2"},
+			scenario: "timescale",
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			cfg, err := Config(newKeplerInternal(tc.spec))
+			assert.NoError(t, err)
+			for _, s := range tc.contains {
+				assert.Contains(t, cfg, s)
+			}
+			for _, s := range tc.excludes {
+				assert.NotContains(t, cfg, s)
+			}
+		})
+	}
+}
+
+// inputSettings returns the settings of the prometheus input in cfg
+func inputSettings(t *testing.T, cfg string) map[string]string {
+	settings := map[string]string{}
+	section := ""
+	for _, line := range strings.Split(cfg, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			section = line
+			continue
+		}
+		if key, value, ok := strings.Cut(line, " = "); ok && section == "[[inputs.prometheus]]" {
+			settings[key] = value
+		}
+	}
+	assert.NotEmpty(t, settings, "prometheus input not found")
+	return settings
+}
+
+// passes returns true if a metric scraped by the prometheus input with the
+// settings is kept by its filters
+func passes(t *testing.T, settings map[string]string, measurement, field string) bool {
+	matches := func(filter, name string) bool {
+		for _, pattern := range strings.Split(strings.Trim(filter, "[]"), ",") {
+			ok, err := path.Match(strings.Trim(strings.TrimSpace(pattern), `"`), name)
+			assert.NoError(t, err)
+			if ok {
+				return true
+			}
+		}
+		return false
+	}
+	if filter, ok := settings["namepass"]; ok && !matches(filter, measurement) {
+		return false
+	}
+	if filter, ok := settings["fieldinclude"]; ok && !matches(filter, field) {
+		return false
+	}
+	return true
+}
+
+func TestConfigSelectsKeplerMetrics(t *testing.T) {
+	cfg, err := Config(newKeplerInternal(v1alpha1.TelegrafSpec{
+		Enabled: true,
+		URL:     "http://influxdb.monitoring.svc:8086",
+	}))
+	assert.NoError(t, err)
+
+	settings := inputSettings(t, cfg)
+	assert.Equal(t, "2", settings["metric_version"])
+
+	// NOTE: with metric_version 2 the measurement of every metric is
+	// prometheus and the name of the metric is the field
+	assert.True(t, passes(t, settings, "prometheus", "kepler_container_joules_total"))
+	assert.True(t, passes(t, settings, "prometheus", "kepler_node_platform_joules_total"))
+	assert.False(t, passes(t, settings, "prometheus", "go_goroutines"))
+	assert.False(t, passes(t, settings, "prometheus", "process_cpu_seconds_total"))
+}
+
+func TestConfigRBACProxy(t *testing.T) {
+	ki := newKeplerInternal(v1alpha1.TelegrafSpec{
+		Enabled: true,
+		URL:     "http://influxdb.monitoring.svc:8086",
+	})
+	ki.Spec.Exporter.RBACProxy = &v1alpha1.InternalRBACProxySpec{RBACProxySpec: v1alpha1.RBACProxySpec{Enabled: true}}
+
+	cfg, err := Config(ki)
+	assert.NoError(t, err)
+	settings := inputSettings(t, cfg)
+	assert.Equal(t, `["https://kepler.power-monitoring.svc:9103/metrics"]`, settings["kubernetes_services"])
+	assert.Equal(t, `"/var/run/secrets/kubernetes.io/serviceaccount/token"`, settings["bearer_token"])
+	assert.Equal(t, `"/etc/telegraf-ca/ca.crt"`, settings["tls_ca"])
+	assert.Equal(t, `"kepler.power-monitoring.svc"`, settings["tls_server_name"])
+
+	cm, err := NewConfigMap(components.Full, ki)
+	assert.NoError(t, err)
+	deploy := NewDeployment(ki, cm)
+	pod := deploy.Spec.Template.Spec
+	assert.True(t, *pod.AutomountServiceAccountToken)
+	assert.Equal(t, Name(ki), pod.ServiceAccountName)
+	assert.Equal(t, "kepler-tls", pod.Volumes[1].Secret.SecretName)
+	assert.Equal(t, "/etc/telegraf-ca", pod.Containers[0].VolumeMounts[1].MountPath)
+
+	binding := NewMetricsReaderBinding(components.Full, ki)
+	assert.Equal(t, "kepler-metrics-reader", binding.RoleRef.Name)
+	assert.Equal(t, Name(ki), binding.Subjects[0].Name)
+	assert.Equal(t, "power-monitoring", binding.Subjects[0].Namespace)
+}
+
+func TestConfigInfluxDBWithoutURL(t *testing.T) {
+	_, err := Config(newKeplerInternal(v1alpha1.TelegrafSpec{Enabled: true, SecretRef: "influxdb"}))
+	assert.Error(t, err)
+}
+
+func TestDeployment(t *testing.T) {
+	tt := []struct {
+		output   v1alpha1.TelegrafOutput
+		key      string
+		scenario string
+	}{
+		{output: v1alpha1.InfluxDBOutput, key: TokenKey, scenario: "influxdb token"},
+		{output: v1alpha1.TimescaleOutput, key: ConnectionKey, scenario: "timescale connection"},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			ki := newKeplerInternal(v1alpha1.TelegrafSpec{
+				Enabled:   true,
+				Output:    tc.output,
+				URL:       "http://influxdb.monitoring.svc:8086",
+				SecretRef: "telegraf-output",
+			})
+
+			cm, err := NewConfigMap(components.Full, ki)
+			assert.NoError(t, err)
+			deploy := NewDeployment(ki, cm)
+
+			assert.Equal(t, "power-monitoring", deploy.Namespace)
+			assert.Equal(t, cm.Name, deploy.Spec.Template.Spec.Volumes[0].ConfigMap.Name)
+
+			assert.False(t, *deploy.Spec.Template.Spec.AutomountServiceAccountToken)
+			assert.True(t, *deploy.Spec.Template.Spec.SecurityContext.RunAsNonRoot)
+
+			container := deploy.Spec.Template.Spec.Containers[0]
+			assert.Equal(t, "telegraf:test", container.Image)
+			assert.False(t, *container.SecurityContext.AllowPrivilegeEscalation)
+			ref := container.Env[0].ValueFrom.SecretKeyRef
+			assert.Equal(t, "telegraf-output", ref.Name)
+			assert.Equal(t, tc.key, ref.Key)
+			assert.Equal(t, []string{"telegraf-output"}, PodSecrets(ki))
+		})
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package viewer
+
+import (
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components/consoleplugin"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AggregationLabel selects the ClusterRoles aggregated into the
+// power-metrics-viewer ClusterRole; platform teams may label their own
+// ClusterRoles to extend what the viewers can read
+const AggregationLabel = "sustainable-computing.io/aggregate-to-power-metrics-viewer"
+
+// Labels returns the labels of all resources of the power-metrics-viewer;
+// the RoleBindings are pruned by these labels
+func Labels(ki *v1alpha1.KeplerInternal) k8s.StringMap {
+	return components.CommonLabels.Merge(k8s.StringMap{
+		"app.kubernetes.io/component":                "power-metrics-viewer",
+		"operator.sustainable-computing.io/internal": ki.Name,
+		"app.kubernetes.io/part-of":                  ki.Name,
+	})
+}
+
+// Name returns the name of the power-metrics-viewer ClusterRole and of the
+// RoleBindings to it
+func Name(ki *v1alpha1.KeplerInternal) string {
+	return ki.Name + "-power-metrics-viewer"
+}
+
+// NewClusterRoles returns the aggregated power-metrics-viewer ClusterRole
+// followed by the ClusterRoles aggregated into it
+func NewClusterRoles(d components.Detail, ki *v1alpha1.KeplerInternal) []*rbacv1.ClusterRole {
+	viewer := newClusterRole(ki, Name(ki), Labels(ki))
+	metrics := newClusterRole(ki, Name(ki)+"-metrics", Labels(ki).Merge(k8s.StringMap{AggregationLabel: "true"}))
+	dashboards := newClusterRole(ki, Name(ki)+"-dashboards", Labels(ki).Merge(k8s.StringMap{AggregationLabel: "true"}))
+	if d == components.Metadata {
+		return []*rbacv1.ClusterRole{viewer, metrics, dashboards}
+	}
+
+	// NOTE: the rules of the viewer are filled in by the aggregation controller
+	viewer.AggregationRule = &rbacv1.AggregationRule{
+		ClusterRoleSelectors: []metav1.LabelSelector{{
+			MatchLabels: map[string]string{AggregationLabel: "true"},
+		}},
+	}
+
+	// NOTE: the tenancy port of thanos-querier and the OpenShift console
+	// authorize queries for the metrics of a namespace by access to the pod
+	// metrics of the namespace
+	metrics.Rules = []rbacv1.PolicyRule{{
+		APIGroups: []string{"metrics.k8s.io"},
+		Resources: []string{"pods"},
+		Verbs:     []string{"get", "list", "watch"},
+	}, {
+		APIGroups: []string{""},
+		Resources: []string{"pods"},
+		Verbs:     []string{"get", "list", "watch"},
+	}}
+
+	dashboards.Rules = []rbacv1.PolicyRule{{
+		APIGroups: []string{v1alpha1.GroupVersion.Group},
+		Resources: []string{"keplers", "keplerinternals"},
+		Verbs:     []string{"get", "list", "watch"},
+	}, {
+		APIGroups: []string{"grafana.integreatly.org"},
+		Resources: []string{"grafanadashboards"},
+		Verbs:     []string{"get", "list", "watch"},
+	}, {
+		APIGroups:     []string{"console.openshift.io"},
+		Resources:     []string{"consoleplugins"},
+		ResourceNames: []string{consoleplugin.Name(ki)},
+		Verbs:         []string{"get"},
+	}}
+	return []*rbacv1.ClusterRole{viewer, metrics, dashboards}
+}
+
+func newClusterRole(ki *v1alpha1.KeplerInternal, name string, labels k8s.StringMap) *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: rbacv1.SchemeGroupVersion.String(),
+			Kind:       "ClusterRole",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+	}
+}
+
+// NewRoleBindings returns a RoleBinding to the power-metrics-viewer
+// ClusterRole for each binding in the spec
+func NewRoleBindings(ki *v1alpha1.KeplerInternal) []*rbacv1.RoleBinding {
+	spec := ki.Spec.MetricsViewer
+	if !spec.IsEnabled() {
+		return nil
+	}
+
+	bindings := make([]*rbacv1.RoleBinding, 0, len(spec.Bindings))
+	for _, b := range spec.Bindings {
+		bindings = append(bindings, &rbacv1.RoleBinding{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: rbacv1.SchemeGroupVersion.String(),
+				Kind:       "RoleBinding",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name(ki),
+				Namespace: b.Namespace,
+				Labels:    Labels(ki),
+			},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "ClusterRole",
+				Name:     Name(ki),
+			},
+			Subjects: b.Subjects,
+		})
+	}
+	return bindings
+}
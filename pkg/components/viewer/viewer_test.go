@@ -0,0 +1,91 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package viewer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestClusterRoles(t *testing.T) {
+	ki := &v1alpha1.KeplerInternal{ObjectMeta: metav1.ObjectMeta{Name: "kepler-internal"}}
+	roles := NewClusterRoles(components.Full, ki)
+	assert.Len(t, roles, 3)
+
+	viewer := roles[0]
+	assert.Equal(t, "kepler-internal-power-metrics-viewer", viewer.Name)
+	assert.Empty(t, viewer.Rules)
+	selector := viewer.AggregationRule.ClusterRoleSelectors[0].MatchLabels
+
+	for _, role := range roles[1:] {
+		assert.NotEmpty(t, role.Rules, role.Name)
+		assert.Subset(t, role.Labels, selector, role.Name)
+	}
+	assert.NotContains(t, viewer.Labels, AggregationLabel)
+}
+
+func TestRoleBindings(t *testing.T) {
+	team := []rbacv1.Subject{{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: "team-a"}}
+	tt := []struct {
+		spec       *v1alpha1.MetricsViewerSpec
+		namespaces []string
+		scenario   string
+	}{
+		{scenario: "not configured"},
+		{
+			spec:     &v1alpha1.MetricsViewerSpec{Bindings: []v1alpha1.MetricsViewerBinding{{Namespace: "a", Subjects: team}}},
+			scenario: "disabled",
+		},
+		{
+			spec: &v1alpha1.MetricsViewerSpec{Enabled: true, Bindings: []v1alpha1.MetricsViewerBinding{
+				{Namespace: "a", Subjects: team},
+				{Namespace: "b", Subjects: team},
+			}},
+			namespaces: []string{"a", "b"},
+			scenario:   "bindings",
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			ki := &v1alpha1.KeplerInternal{
+				ObjectMeta: metav1.ObjectMeta{Name: "kepler-internal"},
+				Spec:       v1alpha1.KeplerInternalSpec{MetricsViewer: tc.spec},
+			}
+			namespaces := []string{}
+			for _, rb := range NewRoleBindings(ki) {
+				namespaces = append(namespaces, rb.Namespace)
+				assert.Equal(t, Name(ki), rb.RoleRef.Name)
+				assert.Equal(t, "ClusterRole", rb.RoleRef.Kind)
+				assert.Equal(t, team, rb.Subjects)
+				assert.Equal(t, Labels(ki).ToMap(), rb.Labels)
+			}
+			if tc.namespaces == nil {
+				assert.Empty(t, namespaces)
+				return
+			}
+			assert.Equal(t, tc.namespaces, namespaces)
+		})
+	}
+}
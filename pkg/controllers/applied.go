@@ -0,0 +1,177 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// appliedState is the state of a kepler-internal that its resources are
+// built from; the status is left out except for the verified models
+type appliedState struct {
+	generation  int64
+	annotations map[string]string
+	labels      map[string]string
+	verified    []v1alpha1.VerifiedModel
+}
+
+func newAppliedState(ki *v1alpha1.KeplerInternal) appliedState {
+	return appliedState{
+		generation:  ki.Generation,
+		annotations: ki.Annotations,
+		labels:      ki.Labels,
+		verified:    ki.Status.VerifiedModels,
+	}
+}
+
+func (s appliedState) equal(o appliedState) bool {
+	return s.generation == o.generation &&
+		equality.Semantic.DeepEqual(s.annotations, o.annotations) &&
+		equality.Semantic.DeepEqual(s.labels, o.labels) &&
+		equality.Semantic.DeepEqual(s.verified, o.verified)
+}
+
+// appliedStates records the state of each kepler-internal whose resources
+// were applied successfully so that the reconciles of a kepler-internal that
+// has not changed since, e.g. for changes of nodes and pods, only update its
+// status. The state is forgotten whenever a resource owned by the
+// kepler-internal or an object its resources are built from changes, so that
+// drifts are still reverted.
+type appliedStates struct {
+	mu     sync.Mutex
+	states map[string]appliedState
+	// forgotten counts the changes of each kepler-internal so that a state is
+	// not recorded if a change was enqueued while its resources were applied
+	forgotten map[string]uint64
+}
+
+func newAppliedStates() *appliedStates {
+	return &appliedStates{
+		states:    map[string]appliedState{},
+		forgotten: map[string]uint64{},
+	}
+}
+
+// changes returns a token to pass to record once the resources of the
+// kepler-internal have been applied
+func (a *appliedStates) changes(name string) uint64 {
+	if a == nil {
+		return 0
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.forgotten[name]
+}
+
+// record records the state the resources of ki were applied for unless its
+// state was forgotten since changes was called
+func (a *appliedStates) record(ki *v1alpha1.KeplerInternal, changes uint64) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.forgotten[ki.Name] != changes {
+		return
+	}
+	a.states[ki.Name] = newAppliedState(ki)
+}
+
+// isApplied returns true if the resources of ki have been applied for its
+// current state and nothing has changed since
+func (a *appliedStates) isApplied(ki *v1alpha1.KeplerInternal) bool {
+	if a == nil {
+		return false
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	state, ok := a.states[ki.Name]
+	return ok && state.equal(newAppliedState(ki))
+}
+
+func (a *appliedStates) forget(name string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.states, name)
+	a.forgotten[name]++
+}
+
+// remove drops the state of a kepler-internal that has been deleted
+func (a *appliedStates) remove(name string) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.states, name)
+	delete(a.forgotten, name)
+}
+
+// forgetting returns a handler that forgets the state of the kepler-internal
+// objects enqueued by h
+func (a *appliedStates) forgetting(h handler.EventHandler) handler.EventHandler {
+	return handler.Funcs{
+		CreateFunc: func(ctx context.Context, e event.CreateEvent, q workqueue.RateLimitingInterface) {
+			h.Create(ctx, e, forgettingQueue{RateLimitingInterface: q, states: a})
+		},
+		UpdateFunc: func(ctx context.Context, e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+			h.Update(ctx, e, forgettingQueue{RateLimitingInterface: q, states: a})
+		},
+		DeleteFunc: func(ctx context.Context, e event.DeleteEvent, q workqueue.RateLimitingInterface) {
+			h.Delete(ctx, e, forgettingQueue{RateLimitingInterface: q, states: a})
+		},
+		GenericFunc: func(ctx context.Context, e event.GenericEvent, q workqueue.RateLimitingInterface) {
+			h.Generic(ctx, e, forgettingQueue{RateLimitingInterface: q, states: a})
+		},
+	}
+}
+
+// forgettingQueue forgets the state of the kepler-internal objects before they
+// are added to the queue
+type forgettingQueue struct {
+	workqueue.RateLimitingInterface
+	states *appliedStates
+}
+
+func (q forgettingQueue) forget(item interface{}) {
+	if req, ok := item.(reconcile.Request); ok {
+		q.states.forget(req.Name)
+	}
+}
+
+func (q forgettingQueue) Add(item interface{}) {
+	q.forget(item)
+	q.RateLimitingInterface.Add(item)
+}
+
+func (q forgettingQueue) AddAfter(item interface{}, d time.Duration) {
+	q.forget(item)
+	q.RateLimitingInterface.AddAfter(item, d)
+}
+
+func (q forgettingQueue) AddRateLimited(item interface{}) {
+	q.forget(item)
+	q.RateLimitingInterface.AddRateLimited(item)
+}
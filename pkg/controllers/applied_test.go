@@ -0,0 +1,93 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+func TestAppliedStates(t *testing.T) {
+	ki := &v1alpha1.KeplerInternal{ObjectMeta: metav1.ObjectMeta{Name: "kepler-internal", Generation: 1}}
+	applied := newAppliedStates()
+	assert.False(t, applied.isApplied(ki))
+
+	applied.record(ki, applied.changes(ki.Name))
+	assert.True(t, applied.isApplied(ki))
+
+	t.Run("changed", func(t *testing.T) {
+		changed := ki.DeepCopy()
+		changed.Generation = 2
+		assert.False(t, applied.isApplied(changed))
+
+		changed = ki.DeepCopy()
+		changed.Annotations = map[string]string{v1alpha1.PausedAnnotation: "true"}
+		assert.False(t, applied.isApplied(changed))
+
+		changed = ki.DeepCopy()
+		changed.Status.VerifiedModels = []v1alpha1.VerifiedModel{{URL: "https://models/model.zip"}}
+		assert.False(t, applied.isApplied(changed))
+	})
+
+	t.Run("status", func(t *testing.T) {
+		changed := ki.DeepCopy()
+		changed.Status.ObservedGeneration = 1
+		assert.True(t, applied.isApplied(changed))
+	})
+
+	t.Run("forgotten while applying", func(t *testing.T) {
+		applied := newAppliedStates()
+		changes := applied.changes(ki.Name)
+		applied.forget(ki.Name)
+		applied.record(ki, changes)
+		assert.False(t, applied.isApplied(ki))
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		var applied *appliedStates
+		applied.record(ki, applied.changes(ki.Name))
+		assert.False(t, applied.isApplied(ki))
+	})
+}
+
+func TestAppliedStatesForgetting(t *testing.T) {
+	ki := &v1alpha1.KeplerInternal{ObjectMeta: metav1.ObjectMeta{Name: "kepler-internal", Generation: 1}}
+	applied := newAppliedStates()
+	applied.record(ki, applied.changes(ki.Name))
+
+	q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer q.ShutDown()
+
+	// the changes of objects that are not mapped to the kepler-internal do
+	// not forget its state
+	other := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other"}}
+	h := applied.forgetting(&handler.EnqueueRequestForObject{})
+	h.Update(context.TODO(), event.UpdateEvent{ObjectOld: other, ObjectNew: other}, q)
+	assert.True(t, applied.isApplied(ki))
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: ki.Name}}
+	h.Update(context.TODO(), event.UpdateEvent{ObjectOld: cm, ObjectNew: cm}, q)
+	assert.False(t, applied.isApplied(ki))
+	assert.Equal(t, 2, q.Len())
+}
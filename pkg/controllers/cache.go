@@ -0,0 +1,70 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/reconciler"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TrimCachedObject is a cache transform that drops the fields of objects
+// the operator never reads before they are stored in the cache. The managed
+// fields, which often take up more memory than the rest of the object, are
+// reduced to the entry of the last change so that the updater can still tell
+// who modified an object that drifted.
+//
+// NOTE: objects that are updated rather than patched or applied are left
+// as-is since an update sends the managed fields and the status back to the
+// api server
+func TrimCachedObject(obj interface{}) (interface{}, error) {
+	switch obj.(type) {
+	case *v1alpha1.Kepler, *v1alpha1.KeplerInternal, *unstructured.Unstructured:
+		return obj, nil
+	}
+
+	o, ok := obj.(client.Object)
+	if !ok {
+		return obj, nil
+	}
+
+	if latest := reconciler.LastUpdate(o); latest != nil {
+		entry := *latest
+		entry.FieldsV1 = nil
+		o.SetManagedFields([]metav1.ManagedFieldsEntry{entry})
+	} else {
+		o.SetManagedFields(nil)
+	}
+
+	// NOTE: nodes are watched in full since their status is read, but the
+	// images and volumes, which grow with the workloads of a node, are not
+	if node, ok := o.(*corev1.Node); ok {
+		node.Status.Images = nil
+		node.Status.VolumesInUse = nil
+		node.Status.VolumesAttached = nil
+	}
+
+	if annotations := o.GetAnnotations(); annotations != nil {
+		if _, ok := annotations[corev1.LastAppliedConfigAnnotation]; ok {
+			delete(annotations, corev1.LastAppliedConfigAnnotation)
+			o.SetAnnotations(annotations)
+		}
+	}
+	return o, nil
+}
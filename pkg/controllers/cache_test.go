@@ -0,0 +1,48 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTrimCachedNode(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "worker-0",
+			Labels: map[string]string{"kubernetes.io/os": "linux"},
+		},
+		Status: corev1.NodeStatus{
+			NodeInfo:        corev1.NodeSystemInfo{KernelVersion: "5.14.0"},
+			Images:          []corev1.ContainerImage{{Names: []string{"quay.io/kepler:v1"}}},
+			VolumesInUse:    []corev1.UniqueVolumeName{"kubernetes.io/csi/pvc-0"},
+			VolumesAttached: []corev1.AttachedVolume{{Name: "kubernetes.io/csi/pvc-0"}},
+		},
+	}
+
+	obj, err := TrimCachedObject(node)
+	assert.NoError(t, err)
+	trimmed := obj.(*corev1.Node)
+	assert.Empty(t, trimmed.Status.Images)
+	assert.Empty(t, trimmed.Status.VolumesInUse)
+	assert.Empty(t, trimmed.Status.VolumesAttached)
+	assert.Equal(t, "5.14.0", trimmed.Status.NodeInfo.KernelVersion)
+	assert.Equal(t, map[string]string{"kubernetes.io/os": "linux"}, trimmed.Labels)
+}
@@ -15,7 +15,20 @@ limitations under the License.
 */
 package controllers
 
-import "github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/registry"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// KubeVirtAPIGroup is served by the cluster if KubeVirt is installed; virtual
+// machine metrics are not enabled automatically since they require the
+// process metrics of kepler which multiply the number of series
+const KubeVirtAPIGroup = "kubevirt.io"
 
 // Config holds configuration shared across all controllers. This struct
 // should be initialized in main
@@ -23,16 +36,93 @@ var (
 	Config = struct {
 		Image   string
 		Cluster k8s.Cluster
+
+		// OperatorCondition is the OLM OperatorCondition of the operator and is
+		// empty when the operator is not installed by OLM
+		OperatorCondition types.NamespacedName
+
+		// EnableUserWorkloadMonitoring allows the operator to enable User
+		// Workload Monitoring on OpenShift so that kepler metrics are collected
+		EnableUserWorkloadMonitoring bool
+
+		// APIGroups are the api groups served by the cluster, e.g. by add-ons
+		// such as KubeVirt or the Prometheus Operator; nil if not discovered
+		APIGroups *k8s.APIGroups
+
+		// SeriesWarningThreshold is the number of metric series above which
+		// the HighCardinality condition is set; 0 disables the condition
+		SeriesWarningThreshold int64
+
+		// Shard is the partition of Kepler and KeplerInternal objects
+		// reconciled by this replica of the operator
+		Shard Shard
+
+		// WatchNamespaces are the namespaces other than KeplerDeploymentNS
+		// that kepler can be deployed to
+		WatchNamespaces []string
 	}{
 		Image:   "",
 		Cluster: k8s.Kubernetes,
 	}
 
 	InternalConfig = struct {
-		ModelServerImage string
-		EstimatorImage   string
+		ModelServerImage     string
+		EstimatorImage       string
+		ConsolePluginImage   string
+		OTelCollectorImage   string
+		RBACProxyImage       string
+		CarbonIntensityImage string
+		EnergyReportImage    string
+		TelegrafImage        string
+
+		// RBACProxyTLSMinVersion and RBACProxyTLSCipherSuites are the TLS
+		// settings of kube-rbac-proxy unless set in the spec
+		RBACProxyTLSMinVersion   string
+		RBACProxyTLSCipherSuites []string
 	}{
-		ModelServerImage: "",
-		EstimatorImage:   "",
+		ModelServerImage:     "",
+		EstimatorImage:       "",
+		ConsolePluginImage:   "",
+		OTelCollectorImage:   "",
+		RBACProxyImage:       "",
+		CarbonIntensityImage: "",
+		EnergyReportImage:    "",
+		TelegrafImage:        "",
 	}
 )
+
+// OperandImages returns the images deployed by the operator keyed by the flag
+// they are configured with
+func OperandImages() map[string]string {
+	return map[string]string{
+		"kepler.image":                    Config.Image,
+		"estimator.image":                 InternalConfig.EstimatorImage,
+		"model-server.image":              InternalConfig.ModelServerImage,
+		"console-plugin.image":            InternalConfig.ConsolePluginImage,
+		"otel-collector.image":            InternalConfig.OTelCollectorImage,
+		"kube-rbac-proxy.image":           InternalConfig.RBACProxyImage,
+		"carbon-intensity-exporter.image": InternalConfig.CarbonIntensityImage,
+		"energy-report.image":             InternalConfig.EnergyReportImage,
+		"telegraf.image":                  InternalConfig.TelegrafImage,
+	}
+}
+
+// ValidateDigestImages returns an error listing the operand images that are
+// not referenced by digest; mirrors of disconnected clusters are looked up by
+// digest only
+func ValidateDigestImages() error {
+	flags := []string{}
+	for flag := range OperandImages() {
+		flags = append(flags, flag)
+	}
+	sort.Strings(flags)
+
+	errs := []error{}
+	images := OperandImages()
+	for _, flag := range flags {
+		if image := images[flag]; !registry.HasDigest(image) {
+			errs = append(errs, fmt.Errorf("%s %q is not referenced by digest", flag, image))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// EventSource is the component name used to record events
+const EventSource = "kepler-operator"
+
+// Reasons used for events that are not condition transitions
+const (
+	EventReconcileFailed = "ReconcileFailed"
+)
+
+// healthyStatus is the status of a condition type when all is well
+var healthyStatus = map[string]metav1.ConditionStatus{
+	v1alpha1.Available:   metav1.ConditionTrue,
+	v1alpha1.Reconciled:  metav1.ConditionTrue,
+	v1alpha1.Progressing: metav1.ConditionFalse,
+	v1alpha1.Degraded:    metav1.ConditionFalse,
+}
+
+// recordConditionTransitions emits an event on obj for every condition in
+// latest whose status differs from the one in previous
+func recordConditionTransitions(recorder record.EventRecorder, obj runtime.Object, previous, latest []metav1.Condition) {
+	for _, c := range latest {
+		if prev := meta.FindStatusCondition(previous, c.Type); prev != nil && prev.Status == c.Status {
+			continue
+		}
+
+		eventType := corev1.EventTypeWarning
+		if healthy, ok := healthyStatus[c.Type]; ok && healthy == c.Status {
+			eventType = corev1.EventTypeNormal
+		}
+		recorder.Eventf(obj, eventType, c.Reason, "%s is %s: %s", c.Type, c.Status, c.Message)
+	}
+}
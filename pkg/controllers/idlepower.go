@@ -0,0 +1,161 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components"
+)
+
+const (
+	// IdlePowerConfigMap is the name of the ConfigMap, in the namespace kepler
+	// is deployed in, holding the idle power of each node in watts keyed by
+	// the name of the node
+	IdlePowerConfigMap = "kepler-idle-power"
+
+	// IdlePowerCalibratedAnnotation is the time the idle power was last
+	// calibrated at
+	IdlePowerCalibratedAnnotation = "kepler.system.sustainable.computing.io/calibrated-at"
+
+	// nodeIdlePowerQuery returns the lowest 5m power of each node over the
+	// calibration window, which is taken as its idle power. The power is the
+	// sum of the idle and dynamic power, so the power is only taken while
+	// both are exported; the dynamic power alone drops to about 0 on idle
+	// nodes.
+	nodeIdlePowerQuery = `min_over_time((` +
+		`sum by (instance) (rate(kepler_node_platform_joules_total{namespace=%q}[5m]))` +
+		` and on (instance) count by (instance) (kepler_node_platform_joules_total{namespace=%q, mode="idle"})` +
+		` and on (instance) count by (instance) (kepler_node_platform_joules_total{namespace=%q, mode="dynamic"})` +
+		`)[%s:5m])`
+)
+
+// IdlePowerCalibrator periodically records the idle power measured on each
+// node in the idle power ConfigMap and in the
+// kepler_operator_node_idle_power_watts metric so that dashboards can split
+// the power of the nodes into their idle and dynamic power
+type IdlePowerCalibrator struct {
+	Client     client.Client
+	Prometheus *PrometheusClient
+
+	// Namespace kepler is deployed in, of the idle power ConfigMap
+	Namespace string
+
+	// Interval between calibrations
+	Interval time.Duration
+
+	// Window over which the lowest power of a node is taken as its idle power
+	Window time.Duration
+}
+
+// Validate returns an error if the settings of the calibrator are invalid
+func (c *IdlePowerCalibrator) Validate() error {
+	if err := c.Prometheus.Validate(); err != nil {
+		return err
+	}
+	if c.Interval <= 0 {
+		return fmt.Errorf("interval %s must be positive", c.Interval)
+	}
+	if c.Window < 5*time.Minute {
+		return fmt.Errorf("window %s must be at least 5m", c.Window)
+	}
+	return nil
+}
+
+// Start calibrates the idle power every interval until ctx is done
+func (c *IdlePowerCalibrator) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("idle-power")
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+	for {
+		if err := c.calibrate(ctx, time.Now()); err != nil {
+			logger.Error(err, "failed to calibrate idle power")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection returns true so that the ConfigMap is written by the
+// leader only
+func (c *IdlePowerCalibrator) NeedLeaderElection() bool {
+	return true
+}
+
+// calibrate queries the idle power of the nodes at now and records it
+func (c *IdlePowerCalibrator) calibrate(ctx context.Context, now time.Time) error {
+	q, err := c.Prometheus.Querier()
+	if err != nil {
+		return err
+	}
+	ns := c.Namespace
+	query := fmt.Sprintf(nodeIdlePowerQuery, ns, ns, ns, promDuration(c.Window))
+	idle, err := q.Vector(ctx, query, "instance", now)
+	if err != nil {
+		return fmt.Errorf("idle power query failed: %w", err)
+	}
+
+	// NOTE: nodes that no longer report power are dropped from the metric
+	nodeIdlePower.Reset()
+	data := make(map[string]string, len(idle))
+	for node, watts := range idle {
+		// NOTE: nodes without a platform power meter, e.g. VMs, report 0
+		if watts <= 0 {
+			continue
+		}
+		nodeIdlePower.WithLabelValues(node).Set(watts)
+		data[node] = formatWatts(watts)
+	}
+
+	cm := &corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: c.Namespace, Name: IdlePowerConfigMap}
+	if err := c.Client.Get(ctx, key, cm); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        IdlePowerConfigMap,
+				Namespace:   c.Namespace,
+				Labels:      components.CommonLabels,
+				Annotations: map[string]string{IdlePowerCalibratedAnnotation: now.UTC().Format(time.RFC3339)},
+			},
+			Data: data,
+		}
+		return c.Client.Create(ctx, cm)
+	}
+
+	// NOTE: the ConfigMap is patched as the managed fields of cached objects
+	// are trimmed
+	patch := client.MergeFrom(cm.DeepCopy())
+	if cm.Annotations == nil {
+		cm.Annotations = map[string]string{}
+	}
+	cm.Annotations[IdlePowerCalibratedAnnotation] = now.UTC().Format(time.RFC3339)
+	cm.Data = data
+	return c.Client.Patch(ctx, cm, patch)
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestIdlePowerCalibrator(t *testing.T) {
+	queries := []string{}
+	idle := map[string]float64{"node-a": 80, "node-b": 0}
+	srv := newPrometheus(t, func(query string) map[string]float64 {
+		queries = append(queries, query)
+		return idle
+	})
+
+	c := fake.NewClientBuilder().WithScheme(newScheme(t)).Build()
+	cal := &IdlePowerCalibrator{
+		Client:     c,
+		Prometheus: &PrometheusClient{URL: srv.URL},
+		Namespace:  "power-monitoring",
+		Interval:   time.Hour,
+		Window:     24 * time.Hour,
+	}
+	assert.NoError(t, cal.Validate())
+
+	ctx := context.Background()
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, cal.calibrate(ctx, start))
+	assert.Contains(t, queries[0], `[86400s:5m]`)
+	assert.Contains(t, queries[0], `kepler_node_platform_joules_total{namespace="power-monitoring", mode="idle"}`)
+
+	cm := corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: "power-monitoring", Name: IdlePowerConfigMap}
+	assert.NoError(t, c.Get(ctx, key, &cm))
+	// NOTE: node-b has no platform power meter
+	assert.Equal(t, map[string]string{"node-a": "80.0"}, cm.Data)
+	assert.Equal(t, "2024-03-01T00:00:00Z", cm.Annotations[IdlePowerCalibratedAnnotation])
+	assert.Equal(t, 80.0, testutil.ToFloat64(nodeIdlePower.WithLabelValues("node-a")))
+
+	idle = map[string]float64{"node-b": 60}
+	assert.NoError(t, cal.calibrate(ctx, start.Add(time.Hour)))
+	assert.NoError(t, c.Get(ctx, key, &cm))
+	assert.Equal(t, map[string]string{"node-b": "60.0"}, cm.Data)
+	assert.Equal(t, "2024-03-01T01:00:00Z", cm.Annotations[IdlePowerCalibratedAnnotation])
+	assert.Equal(t, 1, testutil.CollectAndCount(nodeIdlePower))
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components/carbon"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components/estimator"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/features"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/registry"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// operandImage refers to the image of an operand in the spec of kepler-internal
+type operandImage struct {
+	name     string
+	image    *string
+	fallback string
+}
+
+// operandImages returns the images of the operands that are deployed for the
+// kepler-internal; images not set in the spec default to the fallback
+func operandImages(ki *v1alpha1.KeplerInternal) []operandImage {
+	spec := &ki.Spec
+	images := []operandImage{
+		{name: "kepler", image: &spec.Exporter.Deployment.Image, fallback: Config.Image},
+	}
+	if proxy := spec.Exporter.RBACProxy; proxy.IsEnabled() {
+		images = append(images, operandImage{"kube-rbac-proxy", &proxy.Image, InternalConfig.RBACProxyImage})
+	}
+	if es := spec.Estimator; estimator.NeedsEstimatorSidecar(es) && features.Enabled(features.Estimator) {
+		images = append(images, operandImage{"estimator", &es.Image, InternalConfig.EstimatorImage})
+	}
+	if ms := spec.ModelServer; ms != nil && ms.Enabled && features.Enabled(features.ModelServer) {
+		images = append(images, operandImage{"model-server", &ms.Image, InternalConfig.ModelServerImage})
+	}
+	if otel := spec.OpenTelemetry; otel.IsEnabled() {
+		images = append(images, operandImage{"otel-collector", &otel.Image, InternalConfig.OTelCollectorImage})
+	}
+	if ci := spec.CarbonIntensity; carbon.NeedsExporter(ki) && features.Enabled(features.CarbonIntensity) {
+		images = append(images, operandImage{"carbon-intensity-exporter", &ci.Image, InternalConfig.CarbonIntensityImage})
+	}
+	if er := spec.EnergyReport; er.IsEnabled() {
+		images = append(images, operandImage{"energy-report", &er.Image, InternalConfig.EnergyReportImage})
+	}
+	if t := spec.Telegraf; t.IsEnabled() {
+		images = append(images, operandImage{"telegraf", &t.Image, InternalConfig.TelegrafImage})
+	}
+	if cp := &spec.OpenShift.ConsolePlugin; Config.Cluster == k8s.OpenShift && cp.Enabled {
+		images = append(images, operandImage{"console-plugin", &cp.Image, InternalConfig.ConsolePluginImage})
+	}
+	return images
+}
+
+// pinImages resolves the tags of the operand images to digests. Images
+// pinned before, as recorded in the status, are not resolved again so that
+// pushing a tag again does not change the deployed image. Returns the pinned
+// images and an error listing the images that could not be resolved;
+// nothing must be deployed in that case.
+func pinImages(ctx context.Context, resolver registry.Resolver, ki *v1alpha1.KeplerInternal) ([]v1alpha1.ImageStatus, error) {
+	pinned := map[string]v1alpha1.ImageStatus{}
+	for _, s := range ki.Status.Images {
+		pinned[s.Name] = s
+	}
+
+	images := []v1alpha1.ImageStatus{}
+	errs := []error{}
+	for _, op := range operandImages(ki) {
+		image := *op.image
+		if image == "" {
+			image = op.fallback
+		}
+
+		if registry.HasDigest(image) {
+			name, digest, _ := strings.Cut(image, "@")
+			images = append(images, v1alpha1.ImageStatus{Name: op.name, Image: name, Digest: digest})
+			continue
+		}
+
+		status, ok := pinned[op.name]
+		if !ok || status.Image != image {
+			digest, err := resolver.Resolve(ctx, image)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", op.name, err))
+				continue
+			}
+			status = v1alpha1.ImageStatus{Name: op.name, Image: image, Digest: digest}
+		}
+		images = append(images, status)
+	}
+
+	if len(errs) != 0 {
+		return images, fmt.Errorf("refusing to deploy images that cannot be resolved to a digest: %w", errors.Join(errs...))
+	}
+	return images, nil
+}
+
+// checkImageDigests returns an invalidSpecError listing the operand images
+// set in the spec that are not referenced by digest; the images the operator
+// falls back to are checked on start up
+func checkImageDigests(ki *v1alpha1.KeplerInternal) error {
+	var specErrs specErrors
+	for _, op := range operandImages(ki) {
+		if image := *op.image; image != "" && !registry.HasDigest(image) {
+			specErrs.add(op.name, fmt.Errorf("image %q is not referenced by digest", image))
+		}
+	}
+	return specErrs.err()
+}
+
+// withPinnedImages returns a copy of the kepler-internal with the operand
+// images replaced by the digests they are pinned to
+func withPinnedImages(ki *v1alpha1.KeplerInternal, images []v1alpha1.ImageStatus) *v1alpha1.KeplerInternal {
+	pinned := map[string]v1alpha1.ImageStatus{}
+	for _, s := range images {
+		pinned[s.Name] = s
+	}
+
+	ki = ki.DeepCopy()
+	for _, op := range operandImages(ki) {
+		if s, ok := pinned[op.name]; ok {
+			*op.image = registry.Pin(s.Image, s.Digest)
+		}
+	}
+	return ki
+}
+
+// PullSecretsKeychain returns the keychain with the credentials of the image
+// pull secrets, of type kubernetes.io/dockerconfigjson, in the namespace
+func PullSecretsKeychain(c client.Reader, ns string, names []string) registry.Keychain {
+	return func(ctx context.Context) (map[string]registry.Credentials, error) {
+		keychain := map[string]registry.Credentials{}
+		for _, name := range names {
+			secret := corev1.Secret{}
+			if err := c.Get(ctx, types.NamespacedName{Namespace: ns, Name: name}, &secret); err != nil {
+				return nil, fmt.Errorf("failed to get pull secret %s/%s: %w", ns, name, err)
+			}
+			if secret.Type != corev1.SecretTypeDockerConfigJson {
+				return nil, fmt.Errorf("pull secret %s/%s is not of type %s", ns, name, corev1.SecretTypeDockerConfigJson)
+			}
+			creds, err := registry.ParseDockerConfig(secret.Data[corev1.DockerConfigJsonKey])
+			if err != nil {
+				return nil, fmt.Errorf("pull secret %s/%s: %w", ns, name, err)
+			}
+			// NOTE: the first secret listed wins, as for imagePullSecrets
+			for host, c := range creds {
+				if _, ok := keychain[host]; !ok {
+					keychain[host] = c
+				}
+			}
+		}
+		return keychain, nil
+	}
+}
+
+// updateImagesStatus records the digests the operand images are pinned to.
+// Returns true if the status has changed.
+func (r KeplerInternalReconciler) updateImagesStatus(ki *v1alpha1.KeplerInternal, images []v1alpha1.ImageStatus) bool {
+	// NOTE: images are not pinned if the reconcile is paused, so the digests
+	// pinned before are kept
+	if r.ImageResolver != nil && images == nil {
+		return false
+	}
+	if equality.Semantic.DeepEqual(ki.Status.Images, images) {
+		return false
+	}
+	ki.Status.Images = images
+	return true
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/registry"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeResolver resolves the images to the digests and counts the resolves
+type fakeResolver struct {
+	digests  map[string]string
+	resolved []string
+}
+
+func (r *fakeResolver) Resolve(_ context.Context, image string) (string, error) {
+	r.resolved = append(r.resolved, image)
+	if digest, ok := r.digests[image]; ok {
+		return digest, nil
+	}
+	return "", fmt.Errorf("manifest unknown")
+}
+
+func TestPinImages(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+	pinnedDigest := "sha256:" + strings.Repeat("b", 64)
+	proxyImage := "quay.io/brancz/kube-rbac-proxy@sha256:" + strings.Repeat("c", 64)
+
+	ki := &v1alpha1.KeplerInternal{
+		Spec: v1alpha1.KeplerInternalSpec{
+			Exporter: v1alpha1.InternalExporterSpec{
+				Deployment: v1alpha1.InternalExporterDeploymentSpec{
+					Image: "quay.io/kepler:v1",
+				},
+				RBACProxy: &v1alpha1.InternalRBACProxySpec{
+					RBACProxySpec: v1alpha1.RBACProxySpec{Enabled: true},
+					Image:         proxyImage,
+				},
+			},
+		},
+	}
+	resolver := &fakeResolver{digests: map[string]string{"quay.io/kepler:v1": digest}}
+
+	images, err := pinImages(context.TODO(), resolver, ki)
+	assert.NoError(t, err)
+	assert.Equal(t, []v1alpha1.ImageStatus{
+		{Name: "kepler", Image: "quay.io/kepler:v1", Digest: digest},
+		{Name: "kube-rbac-proxy", Image: "quay.io/brancz/kube-rbac-proxy", Digest: "sha256:" + strings.Repeat("c", 64)},
+	}, images)
+	assert.Equal(t, "quay.io/kepler:v1", ki.Spec.Exporter.Deployment.Image, "spec must not be changed")
+
+	desired := withPinnedImages(ki, images)
+	assert.Equal(t, "quay.io/kepler:v1@"+digest, desired.Spec.Exporter.Deployment.Image)
+	assert.Equal(t, proxyImage, desired.Spec.Exporter.RBACProxy.Image)
+	assert.Equal(t, "quay.io/kepler:v1", ki.Spec.Exporter.Deployment.Image)
+
+	t.Run("pinned images are not resolved again", func(t *testing.T) {
+		resolver.resolved = nil
+		ki := ki.DeepCopy()
+		ki.Status.Images = []v1alpha1.ImageStatus{{Name: "kepler", Image: "quay.io/kepler:v1", Digest: pinnedDigest}}
+		images, err := pinImages(context.TODO(), resolver, ki)
+		assert.NoError(t, err)
+		assert.Empty(t, resolver.resolved)
+		assert.Equal(t, pinnedDigest, images[0].Digest)
+	})
+
+	t.Run("unresolved images", func(t *testing.T) {
+		ki := ki.DeepCopy()
+		ki.Spec.Exporter.Deployment.Image = "quay.io/kepler:unknown"
+		_, err := pinImages(context.TODO(), resolver, ki)
+		assert.ErrorContains(t, err, "kepler: manifest unknown")
+	})
+}
+
+func TestCheckImageDigests(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+	ki := &v1alpha1.KeplerInternal{
+		Spec: v1alpha1.KeplerInternalSpec{
+			Exporter: v1alpha1.InternalExporterSpec{
+				RBACProxy: &v1alpha1.InternalRBACProxySpec{
+					RBACProxySpec: v1alpha1.RBACProxySpec{Enabled: true},
+					Image:         "quay.io/brancz/kube-rbac-proxy:v0.16.0",
+				},
+			},
+		},
+	}
+	err := checkImageDigests(ki)
+	assert.True(t, isInvalidSpecError(err))
+	assert.ErrorContains(t, err, `kube-rbac-proxy: image "quay.io/brancz/kube-rbac-proxy:v0.16.0" is not referenced by digest`)
+
+	ki.Spec.Exporter.RBACProxy.Image = "quay.io/brancz/kube-rbac-proxy@" + digest
+	assert.NoError(t, checkImageDigests(ki), "images not set in the spec are checked on start up")
+	assert.NoError(t, checkImageDigests(withPinnedImages(ki, []v1alpha1.ImageStatus{
+		{Name: "kepler", Image: "quay.io/kepler:v1", Digest: digest},
+	})))
+}
+
+func TestPullSecretsKeychain(t *testing.T) {
+	auth := base64.StdEncoding.EncodeToString([]byte("robot:secret"))
+	secrets := []*corev1.Secret{{
+		ObjectMeta: metav1.ObjectMeta{Name: "quay", Namespace: "kepler-operator"},
+		Type:       corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			corev1.DockerConfigJsonKey: []byte(`{"auths":{"quay.io":{"auth":"` + auth + `"}}}`),
+		},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Name: "opaque", Namespace: "kepler-operator"},
+		Type:       corev1.SecretTypeOpaque,
+	}}
+	c := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(secrets[0], secrets[1]).Build()
+
+	keychain, err := PullSecretsKeychain(c, "kepler-operator", []string{"quay"})(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]registry.Credentials{"quay.io": {Username: "robot", Password: "secret"}}, keychain)
+
+	_, err = PullSecretsKeychain(c, "kepler-operator", []string{"opaque"})(context.TODO())
+	assert.Error(t, err)
+	_, err = PullSecretsKeychain(c, "kepler-operator", []string{"missing"})(context.TODO())
+	assert.Error(t, err)
+}
@@ -2,20 +2,29 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"time"
 
 	"github.com/go-logr/logr"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
 
 	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/components"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/reconciler"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -34,7 +43,21 @@ var (
 // KeplerReconciler reconciles a Kepler object
 type KeplerReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// MaxConcurrentReconciles is the maximum number of concurrent reconciles;
+	// defaults to 1
+	MaxConcurrentReconciles int
+
+	// RateLimiter limits how frequently failed reconciles are retried;
+	// defaults to the controller-runtime rate limiter
+	RateLimiter ratelimiter.RateLimiter
+
+	// ResyncInterval is the interval at which the periodic resyncs held back
+	// behind the changes made by users are moved to the queue; defaults to
+	// 100ms
+	ResyncInterval time.Duration
 
 	logger logr.Logger
 }
@@ -42,10 +65,21 @@ type KeplerReconciler struct {
 // Owned resource
 //+kubebuilder:rbac:groups=kepler.system.sustainable.computing.io,resources=*,verbs=*
 
+// RBAC for recording events
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *KeplerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.Kepler{}).
+		For(&v1alpha1.Kepler{}, builder.WithPredicates(specChanged, Config.Shard.Predicate())).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: r.MaxConcurrentReconciles,
+			RateLimiter:             r.RateLimiter,
+		}).
+		// periodic resyncs are filtered out by specChanged and queued behind
+		// the changes made by users instead
+		Watches(&v1alpha1.Kepler{}, newResyncHandler(r.ResyncInterval),
+			builder.WithPredicates(isResync, Config.Shard.Predicate())).
 		Owns(&v1alpha1.KeplerInternal{},
 			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
 		Complete(r)
@@ -60,7 +94,10 @@ func (r *KeplerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.13.0/pkg/reconcile
-func (r *KeplerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+//
+// NOTE: Reconcile uses a value receiver so that concurrent reconciles work on
+// their own copy of the reconciler and do not share the logger
+func (r KeplerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	// TODO: remove these keys from the log
 	// "controller": "kepler", "controllerGroup": "kepler.system.sustainable.computing.io",
 	// "controllerKind": "Kepler", "Kepler": {"name":"kepler"},
@@ -70,6 +107,7 @@ func (r *KeplerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 
 	logger.Info("Start of  reconcile")
 	defer logger.Info("End of reconcile")
+	defer observeReconcileDuration("kepler", time.Now())
 
 	kepler, err := r.getKepler(ctx, req)
 	if err != nil {
@@ -81,23 +119,60 @@ func (r *KeplerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	if kepler == nil {
 		// no kepler found , so stop here
 		logger.V(6).Info("Kepler Nil")
+		// the timestamp of the last successful reconcile of a removed
+		// object is no longer exposed
+		lastSuccessfulReconcile.DeleteLabelValues("kepler", req.Name)
+		return ctrl.Result{}, nil
+	}
+
+	if !Config.Shard.Owns(kepler) {
+		logger.V(3).Info("kepler belongs to another shard; skipping", "shard", Config.Shard.Index)
 		return ctrl.Result{}, nil
 	}
 
 	// NOTE: validating webhook should ensure that this isn't possible, however,
 	// if the webhook is removed, we should mark the instance as invalid.
 	if kepler.Name != v1alpha1.KeplerInstanceName {
-		return r.setInvalidStatus(ctx, req)
+		return r.setInvalidStatus(ctx, req, "Only a single instance of Kepler named kepler is reconciled")
+	}
+
+	// NOTE: kepler can only be deployed to the namespaces cached by the
+	// operator as the resources deployed are watched to revert drifts
+	if err := validateNamespace(kepler); err != nil && kepler.DeletionTimestamp.IsZero() {
+		return r.setInvalidStatus(ctx, req, err.Error())
+	}
+
+	if v1alpha1.IsPaused(kepler) && kepler.DeletionTimestamp.IsZero() {
+		logger.Info("reconciliation is paused; only updating status",
+			"annotation", v1alpha1.PausedAnnotation)
+		updateErr := r.updateStatus(ctx, req, nil)
+		r.updateUpgradeable(ctx, req)
+		return ctrl.Result{}, updateErr
+	}
+
+	if r.isReconciled(ctx, kepler) {
+		logger.V(3).Info("generation has already been reconciled; only updating status",
+			"generation", kepler.Generation)
+		updateErr := r.updateStatus(ctx, req, nil)
+		r.updateUpgradeable(ctx, req)
+		return ctrl.Result{}, updateErr
 	}
 
 	logger.V(6).Info("Running sub reconcilers", "kepler", kepler.Spec)
 
 	result, recErr := r.runKeplerReconcilers(ctx, kepler)
+	if recErr != nil {
+		r.Recorder.Event(kepler, corev1.EventTypeWarning, EventReconcileFailed, recErr.Error())
+	}
 	updateErr := r.updateStatus(ctx, req, recErr)
+	r.updateUpgradeable(ctx, req)
 
 	if recErr != nil {
 		return result, recErr
 	}
+	if updateErr == nil {
+		lastSuccessfulReconcile.WithLabelValues("kepler", kepler.Name).SetToCurrentTime()
+	}
 	return result, updateErr
 }
 
@@ -137,24 +212,87 @@ func (r KeplerReconciler) updateStatus(ctx context.Context, req ctrl.Request, re
 			return nil
 		}
 
+		base := k.DeepCopy()
+		previous := &base.Status
+
 		// NOTE: although, this copies the internal status, the observed generation
 		// should be set to kepler's current generation to indicate that the
 		// current generation has been "observed"
 		k.Status = v1alpha1.KeplerStatus{
-			Exporter: internal.Status.Exporter,
+			ObservedGeneration:  k.Generation,
+			Exporter:            internal.Status.Exporter,
+			KeplerVersion:       internal.Status.KeplerVersion,
+			InvalidNodes:        internal.Status.InvalidNodes,
+			Endpoints:           internal.Status.Endpoints,
+			PowerSources:        internal.Status.PowerSources,
+			KernelPrerequisites: internal.Status.KernelPrerequisites,
+			Images:              internal.Status.Images,
+			Conditions:          internal.Status.Conditions,
+		}
+		if internal.Spec.Estimator != nil {
+			k.Status.Estimator = internal.Status.Estimator.DeepCopy()
+		}
+		if ms := internal.Spec.ModelServer; ms != nil && ms.Enabled {
+			k.Status.ModelServer = internal.Status.ModelServer.DeepCopy()
 		}
-		for i := range k.Status.Exporter.Conditions {
-			k.Status.Exporter.Conditions[i].ObservedGeneration = k.Generation
+		for i := range k.Status.Conditions {
+			k.Status.Conditions[i].ObservedGeneration = k.Generation
 		}
-		return r.Client.Status().Update(ctx, k)
+		if equality.Semantic.DeepEqual(previous, &k.Status) {
+			r.logger.V(6).Info("no changes to existing status; skipping update")
+			return nil
+		}
+		if err := patchStatus(ctx, r.Client, k, base); err != nil {
+			return err
+		}
+		recordConditionTransitions(r.Recorder, k, previous.Conditions, k.Status.Conditions)
+		return nil
 	})
 }
 
+// updateUpgradeable prevents OLM from upgrading the operator while kepler is
+// being rolled out
+func (r KeplerReconciler) updateUpgradeable(ctx context.Context, req ctrl.Request) {
+	k, _ := r.getKepler(ctx, req)
+	if k == nil {
+		return
+	}
+
+	rollingOut := meta.IsStatusConditionTrue(k.Status.Conditions, v1alpha1.Progressing)
+	if err := updateUpgradeable(ctx, r.Client, rollingOut); err != nil {
+		r.logger.Error(err, "failed to update operator condition", "upgradeable", !rollingOut)
+	}
+}
+
+// isReconciled returns true if the current generation of kepler has been
+// successfully reconciled and the kepler-internal it owns is up-to-date, so
+// that re-applying it can be skipped.
+func (r KeplerReconciler) isReconciled(ctx context.Context, k *v1alpha1.Kepler) bool {
+	if !k.DeletionTimestamp.IsZero() || k.Status.ObservedGeneration != k.Generation {
+		return false
+	}
+
+	if !meta.IsStatusConditionTrue(k.Status.Conditions, v1alpha1.Reconciled) {
+		return false
+	}
+
+	internal, _ := r.getInternalForKepler(ctx, k)
+	if internal == nil || !internal.DeletionTimestamp.IsZero() {
+		return false
+	}
+
+	// NOTE: the api server defaults fields that are not set by the operator, so
+	// compare only the fields set in the desired object
+	desired := newKeplerInternal(components.Full, k)
+	return equality.Semantic.DeepDerivative(desired.Spec, internal.Spec) &&
+		equality.Semantic.DeepDerivative(desired.Annotations, internal.Annotations)
+}
+
 // returns true (i.e. status has changed ) if any of the Conditions'
 // ObservedGeneration is equal to the current generation
 func hasInternalStatusChanged(internal *v1alpha1.KeplerInternal) bool {
-	for i := range internal.Status.Exporter.Conditions {
-		if internal.Status.Exporter.Conditions[i].ObservedGeneration == internal.Generation {
+	for i := range internal.Status.Conditions {
+		if internal.Status.Conditions[i].ObservedGeneration == internal.Generation {
 			return true
 		}
 	}
@@ -211,7 +349,8 @@ func (r KeplerReconciler) reconcilersForKepler(k *v1alpha1.Kepler) []reconciler.
 	return rs
 }
 
-func (r KeplerReconciler) setInvalidStatus(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+// setInvalidStatus marks kepler as invalid for the reason given in msg
+func (r KeplerReconciler) setInvalidStatus(ctx context.Context, req ctrl.Request, msg string) (ctrl.Result, error) {
 
 	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
 		invalidKepler, _ := r.getKepler(ctx, req)
@@ -220,29 +359,60 @@ func (r KeplerReconciler) setInvalidStatus(ctx context.Context, req ctrl.Request
 			return nil
 		}
 
-		now := metav1.Now()
-		invalidKepler.Status.Exporter.Conditions = []v1alpha1.Condition{{
+		base := invalidKepler.DeepCopy()
+		invalidKepler.Status.ObservedGeneration = invalidKepler.Generation
+		invalidKepler.Status.Conditions = nil
+		meta.SetStatusCondition(&invalidKepler.Status.Conditions, metav1.Condition{
 			Type:               v1alpha1.Reconciled,
-			Status:             v1alpha1.ConditionFalse,
+			Status:             metav1.ConditionFalse,
 			ObservedGeneration: invalidKepler.Generation,
-			LastTransitionTime: now,
 			Reason:             v1alpha1.InvalidKeplerResource,
-			Message:            "Only a single instance of Kepler named kepler is reconciled",
-		}, {
+			Message:            msg,
+		})
+		meta.SetStatusCondition(&invalidKepler.Status.Conditions, metav1.Condition{
 			Type:               v1alpha1.Available,
-			Status:             v1alpha1.ConditionUnknown,
+			Status:             metav1.ConditionUnknown,
+			ObservedGeneration: invalidKepler.Generation,
+			Reason:             v1alpha1.InvalidKeplerResource,
+			Message:            "This instance of Kepler is invalid",
+		})
+		meta.SetStatusCondition(&invalidKepler.Status.Conditions, metav1.Condition{
+			Type:               v1alpha1.Degraded,
+			Status:             metav1.ConditionTrue,
 			ObservedGeneration: invalidKepler.Generation,
-			LastTransitionTime: now,
 			Reason:             v1alpha1.InvalidKeplerResource,
 			Message:            "This instance of Kepler is invalid",
-		}}
-		return r.Client.Status().Update(ctx, invalidKepler)
+		})
+		if err := patchStatus(ctx, r.Client, invalidKepler, base); err != nil {
+			return err
+		}
+		r.Recorder.Event(invalidKepler, corev1.EventTypeWarning, v1alpha1.InvalidKeplerResource, msg)
+		return nil
 	})
 
 	// retry only on error
 	return ctrl.Result{}, err
 }
 
+// deploymentNamespace returns the namespace kepler is deployed to
+func deploymentNamespace(k *v1alpha1.Kepler) string {
+	if k.Spec.Namespace != "" {
+		return k.Spec.Namespace
+	}
+	return KeplerDeploymentNS
+}
+
+// validateNamespace returns an error if kepler is deployed to a namespace
+// that is not watched by the operator
+func validateNamespace(k *v1alpha1.Kepler) error {
+	ns := deploymentNamespace(k)
+	if ns == KeplerDeploymentNS || slices.Contains(Config.WatchNamespaces, ns) {
+		return nil
+	}
+	return fmt.Errorf("namespace %q is not watched by the operator; it must be %q or one of %v",
+		ns, KeplerDeploymentNS, Config.WatchNamespaces)
+}
+
 func newKeplerInternal(d components.Detail, k *v1alpha1.Kepler) *v1alpha1.KeplerInternal {
 
 	if d == components.Metadata {
@@ -268,16 +438,122 @@ func newKeplerInternal(d components.Detail, k *v1alpha1.Kepler) *v1alpha1.Kepler
 				Deployment: v1alpha1.InternalExporterDeploymentSpec{
 					ExporterDeploymentSpec: k.Spec.Exporter.Deployment,
 					Image:                  Config.Image,
-					Namespace:              KeplerDeploymentNS,
+					Namespace:              deploymentNamespace(k),
 				},
-				Redfish: k.Spec.Exporter.Redfish,
+				Redfish:               k.Spec.Exporter.Redfish,
+				CollectionMode:        k.Spec.Exporter.CollectionMode,
+				IdlePower:             k.Spec.Exporter.IdlePower,
+				MetricLabels:          k.Spec.Exporter.MetricLabels,
+				ServiceMonitor:        k.Spec.Exporter.ServiceMonitor,
+				PodMonitor:            k.Spec.Exporter.PodMonitor,
+				ScrapeAnnotations:     k.Spec.Exporter.ScrapeAnnotations,
+				Alerts:                k.Spec.Exporter.Alerts,
+				Aggregations:          k.Spec.Exporter.Aggregations,
+				ElectricityPrice:      k.Spec.Exporter.ElectricityPrice,
+				IdleWaste:             k.Spec.Exporter.IdleWaste,
+				PUE:                   k.Spec.Exporter.PUE,
+				OpenCost:              k.Spec.Exporter.OpenCost,
+				RBACProxy:             internalRBACProxy(k.Spec.Exporter.RBACProxy),
+				VerticalPodAutoscaler: k.Spec.Exporter.VerticalPodAutoscaler,
+				NodeFeatureDiscovery:  k.Spec.Exporter.NodeFeatureDiscovery,
+				KernelPrerequisites:   k.Spec.Exporter.KernelPrerequisites,
+				VirtualMachines:       k.Spec.Exporter.VirtualMachines,
 			},
 			OpenShift: v1alpha1.OpenShiftSpec{
 				Enabled: isOpenShift,
 				Dashboard: v1alpha1.DashboardSpec{
-					Enabled: isOpenShift,
+					Enabled: isOpenShift && k.Spec.Dashboards.OpenShift.IsEnabled(),
 				},
+				ConsolePlugin: internalConsolePlugin(isOpenShift, k.Spec.ConsolePlugin),
+			},
+			Dashboards: v1alpha1.InternalDashboardsSpec{
+				Grafana: k.Spec.Dashboards.Grafana,
 			},
+			OpenTelemetry:   internalOpenTelemetry(k.Spec.OpenTelemetry),
+			CarbonIntensity: internalCarbonIntensity(k.Spec.CarbonIntensity),
+			EnergyReport:    internalEnergyReport(k.Spec.EnergyReport),
+			Telegraf:        internalTelegraf(k.Spec.Telegraf),
+			NetworkPolicy:   k.Spec.NetworkPolicy,
+			MetricsViewer:   k.Spec.MetricsViewer,
 		},
 	}
 }
+
+func internalConsolePlugin(isOpenShift bool, cp *v1alpha1.ConsolePluginSpec) v1alpha1.InternalConsolePluginSpec {
+	if cp == nil {
+		return v1alpha1.InternalConsolePluginSpec{}
+	}
+	return v1alpha1.InternalConsolePluginSpec{
+		Enabled:             isOpenShift && cp.Enabled,
+		Replicas:            cp.Replicas,
+		PodDisruptionBudget: cp.PodDisruptionBudget,
+
+		AutomountServiceAccountToken: cp.AutomountServiceAccountToken,
+	}
+}
+
+func internalRBACProxy(proxy *v1alpha1.RBACProxySpec) *v1alpha1.InternalRBACProxySpec {
+	if proxy == nil {
+		return nil
+	}
+	internal := &v1alpha1.InternalRBACProxySpec{RBACProxySpec: *proxy}
+	defaultRBACProxy(internal)
+	return internal
+}
+
+// defaultRBACProxy sets the image and TLS settings of kube-rbac-proxy that are
+// not set in the spec to those of the operator
+func defaultRBACProxy(proxy *v1alpha1.InternalRBACProxySpec) {
+	if proxy == nil {
+		return
+	}
+	if proxy.Image == "" {
+		proxy.Image = InternalConfig.RBACProxyImage
+	}
+	if proxy.TLSMinVersion == "" {
+		proxy.TLSMinVersion = InternalConfig.RBACProxyTLSMinVersion
+	}
+	if len(proxy.TLSCipherSuites) == 0 {
+		proxy.TLSCipherSuites = InternalConfig.RBACProxyTLSCipherSuites
+	}
+}
+
+func internalOpenTelemetry(otel *v1alpha1.OpenTelemetrySpec) *v1alpha1.InternalOpenTelemetrySpec {
+	if otel == nil {
+		return nil
+	}
+	return &v1alpha1.InternalOpenTelemetrySpec{
+		OpenTelemetrySpec: *otel,
+		Image:             InternalConfig.OTelCollectorImage,
+	}
+}
+
+func internalCarbonIntensity(ci *v1alpha1.CarbonIntensitySpec) *v1alpha1.InternalCarbonIntensitySpec {
+	if ci == nil {
+		return nil
+	}
+	return &v1alpha1.InternalCarbonIntensitySpec{
+		CarbonIntensitySpec: *ci,
+		Image:               InternalConfig.CarbonIntensityImage,
+	}
+}
+
+func internalEnergyReport(er *v1alpha1.EnergyReportSpec) *v1alpha1.InternalEnergyReportSpec {
+	if er == nil {
+		return nil
+	}
+	return &v1alpha1.InternalEnergyReportSpec{
+		EnergyReportSpec: *er,
+		Image:            InternalConfig.EnergyReportImage,
+	}
+}
+
+func internalTelegraf(t *v1alpha1.TelegrafSpec) *v1alpha1.InternalTelegrafSpec {
+	if t == nil {
+		return nil
+	}
+	return &v1alpha1.InternalTelegrafSpec{
+		TelegrafSpec: *t,
+		Image:        InternalConfig.TelegrafImage,
+	}
+}
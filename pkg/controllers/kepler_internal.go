@@ -3,31 +3,56 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/components"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components/carbon"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components/consoleplugin"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components/energyreport"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components/estimator"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/components/exporter"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components/kernel"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/components/modelserver"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components/otel"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components/telegraf"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components/viewer"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/features"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/reconciler"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/registry"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
+	"k8s.io/component-base/featuregate"
+	"k8s.io/utils/ptr"
 
+	configv1 "github.com/openshift/api/config/v1"
 	secv1 "github.com/openshift/api/security/v1"
+	monv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -37,7 +62,37 @@ import (
 // KeplerInternalReconciler reconciles a Kepler object
 type KeplerInternalReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// APIReader reads objects that are not cached by the operator such as the
+	// cluster monitoring config; defaults to Client
+	APIReader client.Reader
+
+	// MaxConcurrentReconciles is the maximum number of concurrent reconciles;
+	// defaults to 1
+	MaxConcurrentReconciles int
+
+	// RateLimiter limits how frequently failed reconciles are retried;
+	// defaults to the controller-runtime rate limiter
+	RateLimiter ratelimiter.RateLimiter
+
+	// ResyncInterval is the interval at which the periodic resyncs held back
+	// behind the changes made by users are moved to the queue; defaults to
+	// 100ms
+	ResyncInterval time.Duration
+
+	// ImageResolver resolves the tags of the operand images to digests when
+	// set, so that the operands are deployed by digest
+	ImageResolver registry.Resolver
+
+	// DigestOnlyImages refuses to deploy the operand images set in the spec
+	// unless they are referenced by digest, e.g. pinned by ImageResolver
+	DigestOnlyImages bool
+
+	// applied records the state of the kepler-internal objects whose
+	// resources are up-to-date
+	applied *appliedStates
 
 	logger logr.Logger
 }
@@ -47,74 +102,279 @@ type KeplerInternalReconciler struct {
 //+kubebuilder:rbac:groups=core,resources=services;configmaps;serviceaccounts;persistentvolumeclaims,verbs=list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=*,verbs=*
 
+// configmaps are not cached and are read directly from the api server
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get
+
 // RBAC for running Kepler exporter
 //+kubebuilder:rbac:groups=apps,resources=daemonsets;deployments,verbs=list;watch;create;update;patch;delete
-//+kubebuilder:rbac:groups=core,resources=secrets,verbs=list;watch
+//+kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=list;watch;get;create;update;patch;delete
 //+kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,verbs=list;watch;create;update;patch;delete;use
-//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;prometheusrules,verbs=list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;podmonitors;prometheusrules,verbs=list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=console.openshift.io,resources=consoleplugins,verbs=list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;create;update;patch;delete
+//+kubebuilder:rbac:groups=config.openshift.io,resources=proxies,verbs=get;list;watch
+//+kubebuilder:rbac:groups=machineconfiguration.openshift.io,resources=machineconfigs,verbs=get;create;update;patch;delete
+//+kubebuilder:rbac:groups=machineconfiguration.openshift.io,resources=machineconfigpools,verbs=get
+//+kubebuilder:rbac:groups=nfd.k8s-sigs.io,resources=nodefeaturerules,verbs=get;create;update;patch;delete
+//+kubebuilder:rbac:groups=autoscaling.k8s.io,resources=verticalpodautoscalers,verbs=get;create;update;patch;delete
+//+kubebuilder:rbac:groups=opentelemetry.io,resources=opentelemetrycollectors,verbs=get;create;update;patch;delete
+//+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanadashboards;grafanadatasources,verbs=get;create;update;patch;delete
+
+// RBAC for enabling user workload monitoring; create cannot be restricted by
+// resourceNames, so the cluster monitoring config is created using the
+// create on configmaps granted above when it does not exist
+//+kubebuilder:rbac:groups=core,resources=configmaps,resourceNames=cluster-monitoring-config,verbs=get;update
 
 // RBAC required by Kepler exporter
 //+kubebuilder:rbac:groups=core,resources=nodes/metrics;nodes/proxy;nodes/stats,verbs=get;list;watch
 
+// RBAC required by kube-rbac-proxy in front of the exporter metrics
+//+kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+//+kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// RBAC for inspecting nodes that kepler cannot run on
+//+kubebuilder:rbac:groups=core,resources=nodes;pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=list;watch
+
+// podSecretsIndex indexes kepler-internal by the namespaced names of the
+// secrets used by the pods it deploys
+const podSecretsIndex = "podSecrets"
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *KeplerInternalReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
-	// We only want to trigger a reconciliation when the generation
-	// of a child changes. Until we need to update our the status for our own objects,
-	// we can save CPU cycles by avoiding reconciliations triggered by
-	// child status changes.
-	//
-	// TODO: consider using ResourceVersionChanged predicate for resources that support it
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(),
+		&v1alpha1.KeplerInternal{}, podSecretsIndex, indexPodSecrets); err != nil {
+		return err
+	}
+
+	// Resources owned by kepler-internal are reconciled whenever they change
+	// so that changes made out-of-band are reverted; the updater tells drifts
+	// apart from changes that leave the applied fields as-is
+	drifted := builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})
 
-	genChanged := builder.WithPredicates(predicate.GenerationChangedPredicate{})
+	// workloads are reconciled only when their rollout changes the status of
+	// kepler-internal
+	rolledOut := builder.WithPredicates(rolloutChanged, predicate.ResourceVersionChangedPredicate{})
+
+	// NOTE: the resources of a kepler-internal are applied again only when
+	// they or the objects they are built from change; the changes of the
+	// nodes, pods and events only change its status
+	r.applied = newAppliedStates()
+	owner := r.applied.forgetting(handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(),
+		&v1alpha1.KeplerInternal{}, handler.OnlyControllerOwner()))
 
 	c := ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.KeplerInternal{}).
-		Owns(&corev1.ConfigMap{}, genChanged).
-		Owns(&corev1.ServiceAccount{}, genChanged).
-		Owns(&corev1.Service{}, genChanged).
-		Owns(&appsv1.DaemonSet{}, builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
-		Owns(&rbacv1.ClusterRoleBinding{}, genChanged).
-		Owns(&rbacv1.ClusterRole{}, genChanged)
+		For(&v1alpha1.KeplerInternal{}, builder.WithPredicates(
+			predicate.Or(specChanged, modelsVerified), Config.Shard.Predicate())).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: r.MaxConcurrentReconciles,
+			RateLimiter:             r.RateLimiter,
+		}).
+		// periodic resyncs are filtered out by specChanged and queued behind
+		// the changes made by users instead
+		Watches(&v1alpha1.KeplerInternal{}, r.applied.forgetting(newResyncHandler(r.ResyncInterval)),
+			builder.WithPredicates(isResync, Config.Shard.Predicate())).
+		// NOTE: only the metadata of configmaps is watched since a change in
+		// the resourceVersion is all that is needed to detect a drift
+		Watches(&corev1.ConfigMap{}, owner, builder.OnlyMetadata, drifted).
+		Watches(&corev1.ServiceAccount{}, owner, drifted).
+		Watches(&corev1.Service{}, owner, drifted).
+		Watches(&appsv1.DaemonSet{}, owner, rolledOut).
+		Watches(&appsv1.Deployment{}, owner, rolledOut).
+		Watches(&batchv1.CronJob{}, owner, drifted).
+		Watches(&networkingv1.NetworkPolicy{}, owner, drifted).
+		Watches(&policyv1.PodDisruptionBudget{}, owner, drifted).
+		Watches(&rbacv1.ClusterRoleBinding{}, owner, drifted).
+		Watches(&rbacv1.ClusterRole{}, owner, drifted).
+		Watches(&rbacv1.Role{}, owner, drifted).
+		Watches(&rbacv1.RoleBinding{}, owner, drifted)
+
+	// pods that cannot be created do not change the status of the daemonset,
+	// so watch for the FailedCreate events instead
+	c = c.Watches(&corev1.Event{},
+		handler.EnqueueRequestsFromMapFunc(r.mapDaemonSetEventToRequests),
+		builder.WithPredicates(predicate.NewPredicateFuncs(isDaemonSetFailedCreate)),
+	)
 
+	// the invalid nodes, power sources and kernel prerequisites in the status
+	// are derived from the nodes and the pods of kepler-internal
+	c = c.Watches(&corev1.Node{},
+		handler.EnqueueRequestsFromMapFunc(r.mapToAllRequests),
+		builder.WithPredicates(nodeChanged),
+	)
+	c = c.Watches(&corev1.Pod{},
+		handler.EnqueueRequestsFromMapFunc(mapPodToRequests),
+		builder.WithPredicates(podChanged),
+	)
+
+	// kepler-internal is paused when the kepler with the same name is paused,
+	// so reconcile it when the annotations of the kepler change
+	c = c.Watches(&v1alpha1.Kepler{},
+		r.applied.forgetting(&handler.EnqueueRequestForObject{}),
+		builder.WithPredicates(predicate.AnnotationChangedPredicate{}),
+	)
+
+	// NOTE: only the metadata of secrets is watched since a change in the
+	// resourceVersion is all that is needed to roll out the pods using them
 	c = c.Watches(&corev1.Secret{},
-		handler.EnqueueRequestsFromMapFunc(r.mapSecretToRequests),
+		r.applied.forgetting(handler.EnqueueRequestsFromMapFunc(r.mapSecretToRequests)),
+		builder.OnlyMetadata,
 		builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
 	)
 
+	// resources of add-ons installed after kepler-internal was reconciled are
+	// created once their api is discovered
+	if groups := Config.APIGroups; groups != nil {
+		c = c.WatchesRawSource(&source.Channel{Source: groups.Changes()},
+			r.applied.forgetting(handler.EnqueueRequestsFromMapFunc(r.mapToAllRequests)))
+	}
+
 	if Config.Cluster == k8s.OpenShift {
-		c = c.Owns(&secv1.SecurityContextConstraints{}, genChanged)
+		c = c.Watches(&secv1.SecurityContextConstraints{}, owner, drifted)
+
+		// the model server downloads models using the cluster-wide proxy
+		c = c.Watches(&configv1.Proxy{},
+			r.applied.forgetting(handler.EnqueueRequestsFromMapFunc(r.mapProxyToRequests)),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		)
 	}
 	return c.Complete(r)
 }
 
-// mapSecretToRequests returns the reconcile requests for kepler-internal objects for which an associated redfish secret has been changed.
-func (r *KeplerInternalReconciler) mapSecretToRequests(ctx context.Context, object client.Object) []reconcile.Request {
+// mapProxyToRequests returns the reconcile requests for all kepler-internal
+// objects that deploy a model server when the cluster-wide proxy changes
+func (r *KeplerInternalReconciler) mapProxyToRequests(ctx context.Context, object client.Object) []reconcile.Request {
+	if object.GetName() != modelserver.ClusterProxyName {
+		return nil
+	}
+
+	kis := v1alpha1.KeplerInternalList{}
+	if err := r.Client.List(ctx, &kis); err != nil {
+		r.logger.Error(err, "failed to list keplerinternals")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, ki := range kis.Items {
+		if ms := ki.Spec.ModelServer; ms != nil && ms.Enabled {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: ki.Name},
+			})
+		}
+	}
+	return requests
+}
+
+// mapToAllRequests returns the reconcile requests for all kepler-internal
+// objects
+func (r *KeplerInternalReconciler) mapToAllRequests(ctx context.Context, _ client.Object) []reconcile.Request {
+	kis := v1alpha1.KeplerInternalList{}
+	if err := r.Client.List(ctx, &kis); err != nil {
+		r.logger.Error(err, "failed to list keplerinternals")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, ki := range kis.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: ki.Name},
+		})
+	}
+	return requests
+}
+
+// internalLabel is set to the name of the kepler-internal on the pods it deploys
+const internalLabel = "operator.sustainable-computing.io/internal"
+
+// mapPodToRequests returns the reconcile request for the kepler-internal that
+// deployed the pod
+func mapPodToRequests(ctx context.Context, object client.Object) []reconcile.Request {
+	name, ok := object.GetLabels()[internalLabel]
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name}}}
+}
+
+func isDaemonSetFailedCreate(object client.Object) bool {
+	ev, ok := object.(*corev1.Event)
+	return ok && ev.Reason == "FailedCreate" && ev.InvolvedObject.Kind == "DaemonSet"
+}
+
+// mapDaemonSetEventToRequests returns the reconcile request for the
+// kepler-internal that owns the daemonset the event is about; events about
+// other daemonsets are ignored
+func (r *KeplerInternalReconciler) mapDaemonSetEventToRequests(ctx context.Context, object client.Object) []reconcile.Request {
+	ev, ok := object.(*corev1.Event)
+	if !ok {
+		return nil
+	}
+
+	// NOTE: only the daemonsets managed by the operator are cached
+	ds := appsv1.DaemonSet{}
+	key := types.NamespacedName{Name: ev.InvolvedObject.Name, Namespace: ev.InvolvedObject.Namespace}
+	if err := r.Client.Get(ctx, key, &ds); err != nil {
+		return nil
+	}
+	owner := metav1.GetControllerOf(&ds)
+	if owner == nil || owner.Kind != "KeplerInternal" ||
+		owner.APIVersion != v1alpha1.GroupVersion.String() {
+		return nil
+	}
+	return []reconcile.Request{{
+		NamespacedName: types.NamespacedName{Name: owner.Name},
+	}}
+}
 
-	secret, ok := object.(*corev1.Secret)
+// indexPodSecrets returns the namespaced names of the secrets used by the
+// pods the kepler-internal deploys, so that the pods are rolled out when the
+// secrets are rotated
+func indexPodSecrets(object client.Object) []string {
+	ki, ok := object.(*v1alpha1.KeplerInternal)
 	if !ok {
 		return nil
 	}
 
+	secrets := exporter.PodSecrets(ki)
+	if ot := ki.Spec.OpenTelemetry; ot.IsEnabled() && ot.Kind != v1alpha1.OperatorCollector {
+		secrets = append(secrets, otel.PodSecrets(ki)...)
+	}
+	if carbon.NeedsExporter(ki) {
+		secrets = append(secrets, carbon.PodSecrets(ki)...)
+	}
+	if ki.Spec.Telegraf.IsEnabled() {
+		secrets = append(secrets, telegraf.PodSecrets(ki)...)
+	}
+	if ki.Spec.OpenShift.Enabled && ki.Spec.OpenShift.ConsolePlugin.Enabled {
+		secrets = append(secrets, consoleplugin.PodSecrets(ki)...)
+	}
+
+	keys := make([]string, 0, len(secrets))
+	for _, name := range secrets {
+		keys = append(keys, types.NamespacedName{Namespace: ki.Namespace(), Name: name}.String())
+	}
+	return keys
+}
+
+// mapSecretToRequests returns the reconcile requests for kepler-internal
+// objects whose pods use the secret that has changed
+func (r *KeplerInternalReconciler) mapSecretToRequests(ctx context.Context, object client.Object) []reconcile.Request {
 	ks := v1alpha1.KeplerInternalList{}
-	if err := r.List(ctx, &ks); err != nil {
+	if err := r.List(ctx, &ks, client.MatchingFields{
+		podSecretsIndex: client.ObjectKeyFromObject(object).String(),
+	}); err != nil {
 		return nil
 	}
 
 	requests := []reconcile.Request{}
 	for _, ki := range ks.Items {
-		ex := ki.Spec.Exporter
-
-		if ex.Redfish == nil {
-			continue
-		}
-
-		if ex.Redfish.SecretRef == secret.GetName() &&
-			ex.Deployment.Namespace == secret.GetNamespace() {
-			requests = append(requests, reconcile.Request{
-				NamespacedName: types.NamespacedName{Name: ki.ObjectMeta.Name, Namespace: ki.ObjectMeta.Namespace},
-			})
-		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: ki.ObjectMeta.Name, Namespace: ki.ObjectMeta.Namespace},
+		})
 	}
 	return requests
 }
@@ -128,12 +388,16 @@ func (r *KeplerInternalReconciler) mapSecretToRequests(ctx context.Context, obje
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.13.0/pkg/reconcile
-func (r *KeplerInternalReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+//
+// NOTE: Reconcile uses a value receiver so that concurrent reconciles work on
+// their own copy of the reconciler and do not share the logger
+func (r KeplerInternalReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 	r.logger = logger
 
 	logger.Info("Start of reconcile")
 	defer logger.Info("End of reconcile")
+	defer observeReconcileDuration("kepler-internal", time.Now())
 
 	ki, err := r.getInternal(ctx, req)
 	if err != nil {
@@ -145,31 +409,121 @@ func (r *KeplerInternalReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	if ki == nil {
 		// no kepler-internal found , so stop here
 		logger.V(6).Info("Kepler Nil")
+		// the timestamp of the last successful reconcile of a removed
+		// object is no longer exposed
+		lastSuccessfulReconcile.DeleteLabelValues("kepler-internal", req.Name)
+		r.applied.remove(req.Name)
 		return ctrl.Result{}, nil
 	}
 
+	// NOTE: requests for objects of other shards are enqueued by the watches
+	// of resources owned by them
+	if !Config.Shard.Owns(ki) {
+		logger.V(3).Info("kepler-internal belongs to another shard; skipping", "shard", Config.Shard.Index)
+		return ctrl.Result{}, nil
+	}
+
+	if r.isPaused(ctx, ki) && ki.DeletionTimestamp.IsZero() {
+		logger.Info("reconciliation is paused; only updating status",
+			"annotation", v1alpha1.PausedAnnotation)
+		return ctrl.Result{}, r.updateStatus(ctx, req, nil, nil)
+	}
+
+	if r.isReconciled(ki) {
+		logger.V(3).Info("resources are up-to-date with the generation; only updating status",
+			"generation", ki.Generation)
+		return ctrl.Result{}, r.updateStatus(ctx, req, nil, nil)
+	}
+
 	logger.V(6).Info("Running sub reconcilers", "kepler-internal", ki.Spec)
 
-	result, recErr := r.runReconcilers(ctx, ki)
-	updateErr := r.updateStatus(ctx, req, recErr)
+	changes := r.applied.changes(ki.Name)
+	var result ctrl.Result
+	var recErr error
+	var images []v1alpha1.ImageStatus
+	desired := ki
+	if r.ImageResolver != nil && ki.DeletionTimestamp.IsZero() {
+		images, recErr = pinImages(ctx, r.ImageResolver, ki)
+		desired = withPinnedImages(ki, images)
+	}
+	if recErr == nil && r.DigestOnlyImages && ki.DeletionTimestamp.IsZero() {
+		recErr = checkImageDigests(desired)
+	}
+	if recErr == nil && ki.Spec.ModelVerification != nil && ki.DeletionTimestamp.IsZero() {
+		recErr = checkModelsVerified(ki)
+	}
+	if recErr == nil {
+		result, recErr = r.runReconcilers(ctx, desired)
+	}
+	if recErr != nil {
+		r.Recorder.Event(ki, corev1.EventTypeWarning, EventReconcileFailed, recErr.Error())
+	} else if ki.DeletionTimestamp.IsZero() && result.IsZero() {
+		// NOTE: reconciles requeued to wait for resources must apply them again
+		r.applied.record(ki, changes)
+	}
+	updateErr := r.updateStatus(ctx, req, recErr, images)
 
+	if isInvalidSpecError(recErr) {
+		// NOTE: retrying does not help since the spec is reconciled again once
+		// it has been fixed
+		return result, reconcile.TerminalError(recErr)
+	}
 	if recErr != nil {
 		return result, recErr
 	}
+	if updateErr == nil {
+		lastSuccessfulReconcile.WithLabelValues("kepler-internal", ki.Name).SetToCurrentTime()
+	}
 	return result, updateErr
 }
 
+// isReconciled returns true if the current generation of kepler-internal has
+// been successfully reconciled and neither it nor its resources have changed
+// since, so that re-applying the resources can be skipped
+func (r KeplerInternalReconciler) isReconciled(ki *v1alpha1.KeplerInternal) bool {
+	if !ki.DeletionTimestamp.IsZero() || ki.Status.ObservedGeneration != ki.Generation {
+		return false
+	}
+	reconciled := meta.FindStatusCondition(ki.Status.Conditions, v1alpha1.Reconciled)
+	if reconciled == nil || reconciled.Status != metav1.ConditionTrue || reconciled.ObservedGeneration != ki.Generation {
+		return false
+	}
+	return r.applied.isApplied(ki)
+}
+
 func (r KeplerInternalReconciler) runReconcilers(ctx context.Context, ki *v1alpha1.KeplerInternal) (ctrl.Result, error) {
 
-	reconcilers := r.reconcilersForInternal(ki)
+	reconcilers, specErr := r.reconcilersForInternal(ki)
 	r.logger.V(6).Info("reconcilers ...", "count", len(reconcilers))
 
-	return reconciler.Runner{
+	// NOTE: the components that can be configured are reconciled even if the
+	// spec of others is invalid
+	result, err := reconciler.Runner{
 		Reconcilers: reconcilers,
 		Client:      r.Client,
 		Scheme:      r.Scheme,
 		Logger:      r.logger,
+		Recorder:    r.Recorder,
 	}.Run(ctx)
+	if err != nil {
+		return result, err
+	}
+	return result, specErr
+}
+
+// isPaused returns true if either the kepler-internal or the kepler that
+// owns it has been paused. The kepler is checked as well since a paused
+// kepler does not propagate its annotations to the kepler-internal.
+func (r KeplerInternalReconciler) isPaused(ctx context.Context, ki *v1alpha1.KeplerInternal) bool {
+	if v1alpha1.IsPaused(ki) {
+		return true
+	}
+
+	k := v1alpha1.Kepler{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: ki.Name}, &k); err != nil {
+		return false
+	}
+	return v1alpha1.IsPaused(&k)
 }
 
 func (r KeplerInternalReconciler) getInternal(ctx context.Context, req ctrl.Request) (*v1alpha1.KeplerInternal, error) {
@@ -188,7 +542,7 @@ func (r KeplerInternalReconciler) getInternal(ctx context.Context, req ctrl.Requ
 	return &ki, nil
 }
 
-func (r KeplerInternalReconciler) updateStatus(ctx context.Context, req ctrl.Request, recErr error) error {
+func (r KeplerInternalReconciler) updateStatus(ctx context.Context, req ctrl.Request, recErr error, images []v1alpha1.ImageStatus) error {
 	logger := r.logger.WithValues("keplerinternal", req.Name, "action", "update-status")
 	logger.V(3).Info("Start of status update")
 	defer logger.V(3).Info("End of status update")
@@ -203,122 +557,190 @@ func (r KeplerInternalReconciler) updateStatus(ctx context.Context, req ctrl.Req
 			return nil
 		}
 
-		// sanitize the conditions so that all types are present and the order is predictable
-		ki.Status.Exporter.Conditions = sanitizeConditions(ki.Status.Exporter.Conditions)
-
+		base := ki.DeepCopy()
+		previous := base.Status.Conditions
 		{
-			now := metav1.Now()
-			reconciledChanged := r.updateReconciledStatus(ctx, ki, recErr, now)
-			availableChanged := r.updateAvailableStatus(ctx, ki, recErr, now)
-			logger.V(6).Info("conditions updated", "reconciled", reconciledChanged, "available", availableChanged)
-
-			if !reconciledChanged && !availableChanged {
+			generationChanged := ki.Status.ObservedGeneration != ki.Generation
+			ki.Status.ObservedGeneration = ki.Generation
+
+			reconciledChanged := r.updateReconciledStatus(ctx, ki, recErr)
+			exporterChanged := r.updateExporterStatus(ctx, ki, recErr)
+			monitoringChanged := r.updateMonitoringStatus(ctx, ki)
+			securityChanged := r.updateSecurityStatus(ctx, ki)
+			kernelChanged := r.updateKernelStatus(ctx, ki)
+			imagesChanged := r.updateImagesStatus(ki, images)
+			featuresChanged := updateFeatureStatus(ki)
+			logger.V(6).Info("conditions updated", "generation", generationChanged,
+				"reconciled", reconciledChanged, "exporter", exporterChanged, "monitoring", monitoringChanged,
+				"security", securityChanged, "kernel", kernelChanged, "images", imagesChanged,
+				"features", featuresChanged)
+
+			if !generationChanged && !reconciledChanged && !exporterChanged && !monitoringChanged &&
+				!securityChanged && !kernelChanged && !imagesChanged && !featuresChanged {
 				logger.V(6).Info("no changes to existing status; skipping update")
 				return nil
 			}
 		}
 
-		return r.Client.Status().Update(ctx, ki)
-
-	})
-}
-
-func sanitizeConditions(conditions []v1alpha1.Condition) []v1alpha1.Condition {
-	required := map[v1alpha1.ConditionType]bool{
-		v1alpha1.Reconciled: false,
-		v1alpha1.Available:  false,
-	}
-
-	if len(conditions) == len(required) {
-		return conditions
-	}
-
-	if len(conditions) == 0 {
-		return []v1alpha1.Condition{{
-			Type:   v1alpha1.Reconciled,
-			Status: v1alpha1.ConditionFalse,
-		}, {
-			Type:   v1alpha1.Available,
-			Status: v1alpha1.ConditionFalse,
-		}}
-	}
-
-	for _, c := range conditions {
-		required[c.Type] = true
-	}
-
-	for t, present := range required {
-		if !present {
-			conditions = append(conditions, v1alpha1.Condition{
-				Type:   t,
-				Status: v1alpha1.ConditionFalse,
-			})
+		if err := patchStatus(ctx, r.Client, ki, base); err != nil {
+			return err
 		}
-	}
-	return conditions
+		recordConditionTransitions(r.Recorder, ki, previous, ki.Status.Conditions)
+		return nil
+	})
 }
 
-func (r KeplerInternalReconciler) updateReconciledStatus(ctx context.Context, ki *v1alpha1.KeplerInternal, recErr error, time metav1.Time) bool {
+func (r KeplerInternalReconciler) updateReconciledStatus(ctx context.Context, ki *v1alpha1.KeplerInternal, recErr error) bool {
 
-	reconciled := v1alpha1.Condition{
+	reconciled := metav1.Condition{
 		Type:               v1alpha1.Reconciled,
-		Status:             v1alpha1.ConditionTrue,
+		Status:             metav1.ConditionTrue,
 		ObservedGeneration: ki.Generation,
 		Reason:             v1alpha1.ReconcileComplete,
 		Message:            "Reconcile succeeded",
 	}
 
 	if recErr != nil {
-		reconciled.Status = v1alpha1.ConditionFalse
+		reconciled.Status = metav1.ConditionFalse
 		reconciled.Reason = v1alpha1.ReconcileError
+		if isInvalidSpecError(recErr) {
+			reconciled.Reason = v1alpha1.InvalidSpec
+		}
 		reconciled.Message = recErr.Error()
+	} else if r.isPaused(ctx, ki) {
+		reconciled.Status = metav1.ConditionFalse
+		reconciled.Reason = v1alpha1.ReconcilePaused
+		reconciled.Message = fmt.Sprintf("Reconciliation is paused; remove the %s annotation to resume",
+			v1alpha1.PausedAnnotation)
 	}
 
-	return updateCondition(ki.Status.Exporter.Conditions, reconciled, time)
+	return updateCondition(&ki.Status.Conditions, reconciled)
 }
 
-func findCondition(conditions []v1alpha1.Condition, t v1alpha1.ConditionType) *v1alpha1.Condition {
-	for i, c := range conditions {
-		if c.Type == t {
-			return &conditions[i]
-		}
+// updateMonitoringStatus updates the MetricsCollected condition on OpenShift
+// where the metrics of kepler are collected only if User Workload Monitoring
+// is enabled. Returns true if the condition has been updated.
+func (r KeplerInternalReconciler) updateMonitoringStatus(ctx context.Context, ki *v1alpha1.KeplerInternal) bool {
+	if Config.Cluster != k8s.OpenShift {
+		return false
 	}
-	return nil
+
+	collected := metav1.Condition{
+		Type:               v1alpha1.MetricsCollected,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: ki.Generation,
+		Reason:             v1alpha1.UserWorkloadMonitoringEnabled,
+		Message:            "User Workload Monitoring is enabled",
+	}
+
+	enabled, err := reconciler.UserWorkloadMonitoringEnabled(ctx, r.apiReader())
+	switch {
+	case err != nil:
+		collected.Status = metav1.ConditionUnknown
+		collected.Reason = v1alpha1.UserWorkloadMonitoringUnknown
+		collected.Message = fmt.Sprintf("Failed to determine if User Workload Monitoring is enabled: %v", err)
+	case !enabled:
+		collected.Status = metav1.ConditionFalse
+		collected.Reason = v1alpha1.UserWorkloadMonitoringDisabled
+		collected.Message = fmt.Sprintf("Kepler metrics are not collected since User Workload Monitoring is disabled; "+
+			"set enableUserWorkload: true in %s/%s", reconciler.ClusterMonitoringNS, reconciler.ClusterMonitoringConfig)
+	}
+	return updateCondition(&ki.Status.Conditions, collected)
 }
 
-// returns true if the condition has been updated
-func updateCondition(conditions []v1alpha1.Condition, latest v1alpha1.Condition, time metav1.Time) bool {
+func (r KeplerInternalReconciler) apiReader() client.Reader {
+	if r.APIReader != nil {
+		return r.APIReader
+	}
+	return r.Client
+}
 
-	old := findCondition(conditions, latest.Type)
-	if old == nil {
-		panic("old condition not found; this should never happen after sanitizeConditions")
+// updateSecurityStatus updates the PodSecurityConfigured condition with the
+// security policy that permits the kepler pods on the platform
+func (r KeplerInternalReconciler) updateSecurityStatus(ctx context.Context, ki *v1alpha1.KeplerInternal) bool {
+	configured := metav1.Condition{
+		Type:               v1alpha1.PodSecurityConfigured,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: ki.Generation,
 	}
 
-	if old.ObservedGeneration == latest.ObservedGeneration &&
-		old.Status == latest.Status &&
-		old.Reason == latest.Reason &&
-		old.Message == latest.Message {
-		return false
+	if Config.Cluster == k8s.OpenShift {
+		configured.Reason = v1alpha1.SCCConfigured
+		configured.Message = fmt.Sprintf("SecurityContextConstraints %s permit %s", ki.Name, ki.FQServiceAccountName())
+
+		scc := secv1.SecurityContextConstraints{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: ki.Name}, &scc); err != nil {
+			configured.Status = metav1.ConditionUnknown
+			configured.Reason = v1alpha1.PodSecurityUnknown
+			configured.Message = fmt.Sprintf("Failed to get SecurityContextConstraints %s: %v", ki.Name, err)
+			if errors.IsNotFound(err) {
+				configured.Status = metav1.ConditionFalse
+				configured.Reason = v1alpha1.SCCNotFound
+				configured.Message = fmt.Sprintf("SecurityContextConstraints %s not found", ki.Name)
+			}
+		}
+		return updateCondition(&ki.Status.Conditions, configured)
+	}
+
+	configured.Reason = v1alpha1.PodSecurityLabelsApplied
+	configured.Message = fmt.Sprintf("Namespace %s enforces the %s Pod Security Standard",
+		ki.Namespace(), components.PodSecurityPrivileged)
+
+	ns := corev1.Namespace{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: ki.Namespace()}, &ns); err != nil {
+		configured.Status = metav1.ConditionUnknown
+		configured.Reason = v1alpha1.PodSecurityUnknown
+		configured.Message = fmt.Sprintf("Failed to get namespace %s: %v", ki.Namespace(), err)
+	} else if level := ns.Labels[components.PodSecurityEnforceLabel]; level != components.PodSecurityPrivileged {
+		configured.Status = metav1.ConditionFalse
+		configured.Reason = v1alpha1.PodSecurityLabelsOverridden
+		configured.Message = fmt.Sprintf("Namespace %s enforces the %q Pod Security Standard; kepler requires %s=%s",
+			ki.Namespace(), level, components.PodSecurityEnforceLabel, components.PodSecurityPrivileged)
 	}
+	return updateCondition(&ki.Status.Conditions, configured)
+}
 
-	old.ObservedGeneration = latest.ObservedGeneration
-	old.Status = latest.Status
-	old.Reason = latest.Reason
-	old.Message = latest.Message
-	// NOTE: last transition time changes only if the status changes
-	old.LastTransitionTime = time
-	return true
+// patchStatus writes all status changes made to obj since base in a single
+// merge patch. Unlike an update, the patch carries no resourceVersion and
+// therefore does not conflict with writes made by others in the meantime.
+func patchStatus(ctx context.Context, c client.Client, obj, base client.Object) error {
+	return c.Status().Patch(ctx, obj, client.MergeFrom(base))
+}
+
+// returns true if the condition has been updated
+// NOTE: last transition time changes only if the status changes
+func updateCondition(conditions *[]metav1.Condition, latest metav1.Condition) bool {
+	return meta.SetStatusCondition(conditions, latest)
 }
 
-func (r KeplerInternalReconciler) updateAvailableStatus(ctx context.Context, ki *v1alpha1.KeplerInternal, recErr error, time metav1.Time) bool {
+// updateExporterStatus updates the Available, Progressing and Degraded
+// conditions along with the exporter, estimator and model-server status.
+// Returns true if any of the conditions or the exporter status has been updated.
+func (r KeplerInternalReconciler) updateExporterStatus(ctx context.Context, ki *v1alpha1.KeplerInternal, recErr error) bool {
 	// get daemonset owned by kepler
 	dset := appsv1.DaemonSet{}
 	key := types.NamespacedName{Name: ki.DaemonsetName(), Namespace: ki.Namespace()}
 	if err := r.Client.Get(ctx, key, &dset); err != nil {
-		return updateCondition(ki.Status.Exporter.Conditions, availableConditionForGetError(err), time)
+		available := availableConditionForGetError(err)
+		available.ObservedGeneration = ki.Generation
+		progressing := metav1.Condition{
+			Type:               v1alpha1.Progressing,
+			Status:             metav1.ConditionUnknown,
+			ObservedGeneration: ki.Generation,
+			Reason:             available.Reason,
+			Message:            available.Message,
+		}
+		ki.Status.InvalidNodes = nil
+		degraded := degradedCondition(ki, recErr, "")
+
+		availableChanged := updateCondition(&ki.Status.Conditions, available)
+		progressingChanged := updateCondition(&ki.Status.Conditions, progressing)
+		degradedChanged := updateCondition(&ki.Status.Conditions, degraded)
+		return availableChanged || progressingChanged || degradedChanged
 	}
 
 	ds := dset.Status
+	prevExporter := ki.Status.Exporter
 	ki.Status.Exporter.NumberMisscheduled = ds.NumberMisscheduled
 	ki.Status.Exporter.CurrentNumberScheduled = ds.CurrentNumberScheduled
 	ki.Status.Exporter.DesiredNumberScheduled = ds.DesiredNumberScheduled
@@ -326,18 +748,50 @@ func (r KeplerInternalReconciler) updateAvailableStatus(ctx context.Context, ki
 	ki.Status.Exporter.UpdatedNumberScheduled = ds.UpdatedNumberScheduled
 	ki.Status.Exporter.NumberAvailable = ds.NumberAvailable
 	ki.Status.Exporter.NumberUnavailable = ds.NumberUnavailable
+	ki.Status.Exporter.Image = k8s.ImageFromDS(&dset, exporter.KeplerContainerIndex)
+
+	prevVersion := ki.Status.KeplerVersion
+	ki.Status.KeplerVersion = exporter.VersionFromImage(ki.Status.Exporter.Image)
 
 	available := availableCondition(&dset)
+	available.ObservedGeneration = ki.Generation
 
-	if recErr == nil {
-		available.ObservedGeneration = ki.Generation
-	} else {
-		// failure to reconcile is a Degraded condition
-		available.Status = v1alpha1.ConditionDegraded
-		available.Reason = v1alpha1.ReconcileError
+	progressing := progressingCondition(&dset)
+	progressing.ObservedGeneration = ki.Generation
+
+	r.updateConfigRevision(ctx, ki, progressing)
+
+	// rollout progress changes without affecting any of the conditions
+	exporterChanged := !equality.Semantic.DeepEqual(prevExporter, ki.Status.Exporter) ||
+		prevVersion != ki.Status.KeplerVersion
+
+	pods := r.exporterPods(ctx, &dset)
+
+	prevEndpoints := ki.Status.Endpoints
+	ki.Status.Endpoints = r.endpoints(ctx, ki)
+	endpointsChanged := !equality.Semantic.DeepEqual(prevEndpoints, ki.Status.Endpoints)
+
+	nodes, nodesErr := r.exporterNodes(ctx, &dset)
+
+	prevInvalidNodes := ki.Status.InvalidNodes
+	prevPowerSources := ki.Status.PowerSources
+	if nodesErr == nil {
+		ki.Status.InvalidNodes = exporter.InvalidNodes(nodes, pods)
+		ki.Status.PowerSources = exporter.PowerSources(nodes, ki.Spec.Exporter.Redfish != nil)
 	}
+	invalidNodesChanged := !equality.Semantic.DeepEqual(prevInvalidNodes, ki.Status.InvalidNodes)
+	powerSourcesChanged := !equality.Semantic.DeepEqual(prevPowerSources, ki.Status.PowerSources)
+	cardinalityChanged := nodesErr == nil && updateCardinalityStatus(ki, nodes)
+
+	blocked, _ := exporter.PodCreationBlocked(&dset, r.daemonSetEvents(ctx, &dset))
 
-	updated := updateCondition(ki.Status.Exporter.Conditions, available, time)
+	// failure to reconcile, pods that cannot be created or nodes that kepler
+	// cannot run on is a Degraded condition
+	degraded := degradedCondition(ki, recErr, blocked)
+
+	availableChanged := updateCondition(&ki.Status.Conditions, available)
+	progressingChanged := updateCondition(&ki.Status.Conditions, progressing)
+	degradedChanged := updateCondition(&ki.Status.Conditions, degraded)
 
 	estimatorStatus := v1alpha1.EstimatorStatus{
 		Status: v1alpha1.DeploymentNotInstalled,
@@ -348,55 +802,346 @@ func (r KeplerInternalReconciler) updateAvailableStatus(ctx context.Context, ki
 		if ds.NumberReady == ds.DesiredNumberScheduled {
 			estimatorStatus.Status = v1alpha1.DeploymentRunning
 		}
+		estimatorStatus.NumberReady, estimatorStatus.LastError = estimator.SidecarStatus(pods)
+		estimatorStatus.Models = estimator.Models(ki.Spec.Estimator)
 	}
 
+	prevEstimator := ki.Status.Estimator
 	ki.Status.Estimator = estimatorStatus
+	estimatorChanged := !equality.Semantic.DeepEqual(prevEstimator, ki.Status.Estimator)
+
+	prevModelServer := ki.Status.ModelServer
+	ki.Status.ModelServer = r.modelServerStatus(ctx, ki)
+	modelServerChanged := !equality.Semantic.DeepEqual(prevModelServer, ki.Status.ModelServer)
+	conditionsChanged := availableChanged || progressingChanged || degradedChanged
+	return conditionsChanged || exporterChanged || estimatorChanged || modelServerChanged ||
+		invalidNodesChanged || powerSourcesChanged || endpointsChanged || cardinalityChanged
+}
 
-	// update model server status
-	modelServerStatus := v1alpha1.ModelServerStatus{
-		Status: v1alpha1.DeploymentNotInstalled,
+// updateCardinalityStatus updates the number of series kepler is estimated to
+// produce on the nodes and the HighCardinality condition. Returns true if
+// either has been updated.
+func updateCardinalityStatus(ki *v1alpha1.KeplerInternal, nodes []corev1.Node) bool {
+	prev := ki.Status.Exporter.EstimatedSeries
+	series := exporter.EstimateSeries(exporter.NewConfigMap(components.Full, ki).Data, nodes)
+	ki.Status.Exporter.EstimatedSeries = series
+
+	threshold := Config.SeriesWarningThreshold
+	if threshold <= 0 {
+		return meta.RemoveStatusCondition(&ki.Status.Conditions, v1alpha1.HighCardinality) || prev != series
 	}
-	if ki.Spec.ModelServer != nil {
-		key = types.NamespacedName{Name: ki.ModelServerDeploymentName(), Namespace: ki.Namespace()}
-		deploy := appsv1.Deployment{}
-		if err := r.Client.Get(ctx, key, &deploy); err == nil {
-			modelServerStatus.Status = v1alpha1.DeploymentNotReady
-			if deploy.Status.ReadyReplicas > 0 {
-				modelServerStatus.Status = v1alpha1.DeploymentRunning
-			}
+
+	cardinality := metav1.Condition{
+		Type:               v1alpha1.HighCardinality,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: ki.Generation,
+		Reason:             v1alpha1.SeriesWithinThreshold,
+		Message:            fmt.Sprintf("kepler is estimated to produce %d series; threshold is %d", series, threshold),
+	}
+	if series > threshold {
+		cardinality.Status = metav1.ConditionTrue
+		cardinality.Reason = v1alpha1.SeriesAboveThreshold
+		cardinality.Message = fmt.Sprintf("kepler is estimated to produce %d series which exceeds the threshold of %d; "+
+			"consider disabling process and virtual machine metrics or the hardware counter, IRQ, cgroup and kubelet metrics", series, threshold)
+	}
+	return updateCondition(&ki.Status.Conditions, cardinality) || prev != series
+}
+
+// disabledFeatures returns the features configured in the spec of ki that
+// are disabled by the feature gates and are therefore ignored
+func disabledFeatures(ki *v1alpha1.KeplerInternal) []featuregate.Feature {
+	configured := []struct {
+		feature    featuregate.Feature
+		configured bool
+	}{
+		{features.Estimator, ki.Spec.Estimator != nil},
+		{features.ModelServer, ki.Spec.ModelServer != nil},
+		{features.CarbonIntensity, ki.Spec.CarbonIntensity != nil},
+	}
+
+	disabled := []featuregate.Feature{}
+	for _, c := range configured {
+		if c.configured && !features.Enabled(c.feature) {
+			disabled = append(disabled, c.feature)
+		}
+	}
+	return disabled
+}
+
+// updateFeatureStatus sets the FeatureDisabled condition if some of the
+// features configured in the spec are disabled by the feature gates and
+// removes it otherwise. Returns true if the condition has been updated.
+func updateFeatureStatus(ki *v1alpha1.KeplerInternal) bool {
+	disabled := disabledFeatures(ki)
+	if len(disabled) == 0 {
+		return meta.RemoveStatusCondition(&ki.Status.Conditions, v1alpha1.FeatureDisabled)
+	}
+
+	names := make([]string, 0, len(disabled))
+	gates := make([]string, 0, len(disabled))
+	for _, f := range disabled {
+		names = append(names, string(f))
+		gates = append(gates, string(f)+"=true")
+	}
+	return updateCondition(&ki.Status.Conditions, metav1.Condition{
+		Type:               v1alpha1.FeatureDisabled,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: ki.Generation,
+		Reason:             v1alpha1.FeatureGateDisabled,
+		Message: fmt.Sprintf("features %s are configured in the spec but disabled by the feature gates of the operator "+
+			"and are ignored; enable them using --feature-gates=%s", strings.Join(names, ", "), strings.Join(gates, ",")),
+	})
+}
+
+// updateKernelStatus reports per node whether the kernel prerequisites are
+// met when they are managed by the operator
+func (r KeplerInternalReconciler) updateKernelStatus(ctx context.Context, ki *v1alpha1.KeplerInternal) bool {
+	prev := ki.Status.KernelPrerequisites
+	ki.Status.KernelPrerequisites = r.kernelStatus(ctx, ki)
+	return !equality.Semantic.DeepEqual(prev, ki.Status.KernelPrerequisites)
+}
+
+func (r KeplerInternalReconciler) kernelStatus(ctx context.Context, ki *v1alpha1.KeplerInternal) []v1alpha1.NodeKernelStatus {
+	if !ki.Spec.Exporter.KernelPrerequisites.IsEnabled() {
+		return nil
+	}
+
+	if Config.Cluster == k8s.OpenShift {
+		nodes := corev1.NodeList{}
+		if err := r.Client.List(ctx, &nodes); err != nil {
+			r.logger.Error(err, "failed to list nodes")
+			return ki.Status.KernelPrerequisites
 		}
+		return kernel.StatusFromNodes(ki, nodes.Items, r.machineConfigPools(ctx))
+	}
+
+	dset := appsv1.DaemonSet{}
+	key := types.NamespacedName{Name: kernel.Name(ki), Namespace: ki.Namespace()}
+	if err := r.Client.Get(ctx, key, &dset); err != nil {
+		return nil
+	}
+	return kernel.StatusFromPods(r.exporterPods(ctx, &dset))
+}
+
+// machineConfigPools returns the pools the MachineConfigs are rendered for;
+// pools that cannot be read are left out
+func (r KeplerInternalReconciler) machineConfigPools(ctx context.Context) []unstructured.Unstructured {
+	pools := []unstructured.Unstructured{}
+	for _, role := range kernel.Roles() {
+		pool := unstructured.Unstructured{}
+		pool.SetGroupVersionKind(kernel.MachineConfigPoolGVK)
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: role}, &pool); err != nil {
+			r.logger.Error(err, "failed to get machine config pool", "pool", role)
+			continue
+		}
+		pools = append(pools, pool)
+	}
+	return pools
+}
+
+// endpoints returns the endpoints exposing kepler metrics
+func (r KeplerInternalReconciler) endpoints(ctx context.Context, ki *v1alpha1.KeplerInternal) []v1alpha1.Endpoint {
+	svc := corev1.Service{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: ki.Name, Namespace: ki.Namespace()}, &svc); err != nil {
+		return nil
+	}
+	return exporter.EndpointsFromService(&svc)
+}
+
+// updateConfigRevision records the revision of the exporter configuration and
+// the time at which the rollout of the revision completed
+func (r KeplerInternalReconciler) updateConfigRevision(ctx context.Context, ki *v1alpha1.KeplerInternal, progressing metav1.Condition) {
+	cm := corev1.ConfigMap{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: ki.Name, Namespace: ki.Namespace()}, &cm); err != nil {
+		return
+	}
+
+	status := &ki.Status.Exporter
+	if revision := exporter.ConfigHash(cm.Data); revision != status.ConfigRevision {
+		status.ConfigRevision = revision
+		status.ConfigRolledOutTime = nil
 	}
-	ki.Status.ModelServer = modelServerStatus
-	return updated
+
+	if status.ConfigRolledOutTime == nil && progressing.Status == metav1.ConditionFalse {
+		now := metav1.Now()
+		status.ConfigRolledOutTime = &now
+	}
+}
+
+// exporterPods returns the pods of the kepler daemonset
+func (r KeplerInternalReconciler) exporterPods(ctx context.Context, dset *appsv1.DaemonSet) []corev1.Pod {
+	if dset.Spec.Selector == nil {
+		return nil
+	}
+
+	pods := corev1.PodList{}
+	if err := r.Client.List(ctx, &pods,
+		client.InNamespace(dset.Namespace),
+		client.MatchingLabels(dset.Spec.Selector.MatchLabels)); err != nil {
+		r.logger.Error(err, "failed to list kepler pods")
+		return nil
+	}
+	return pods.Items
+}
+
+// daemonSetEvents returns the events in the namespace of the daemonset
+func (r KeplerInternalReconciler) daemonSetEvents(ctx context.Context, dset *appsv1.DaemonSet) []corev1.Event {
+	events := corev1.EventList{}
+	if err := r.Client.List(ctx, &events, client.InNamespace(dset.Namespace)); err != nil {
+		r.logger.Error(err, "failed to list events")
+		return nil
+	}
+	return events.Items
 }
 
-func availableConditionForGetError(err error) v1alpha1.Condition {
+// exporterNodes returns the nodes selected by the kepler daemonset
+func (r KeplerInternalReconciler) exporterNodes(ctx context.Context, dset *appsv1.DaemonSet) ([]corev1.Node, error) {
+	nodes := corev1.NodeList{}
+	if err := r.Client.List(ctx, &nodes, client.MatchingLabels(k8s.NodeSelectorFromDS(dset))); err != nil {
+		r.logger.Error(err, "failed to list nodes")
+		return nil, err
+	}
+	return nodes.Items, nil
+}
+
+func (r KeplerInternalReconciler) modelServerStatus(ctx context.Context, ki *v1alpha1.KeplerInternal) v1alpha1.ModelServerStatus {
+	ms := ki.Spec.ModelServer
+	if ms == nil || !ms.Enabled {
+		return v1alpha1.ModelServerStatus{Status: v1alpha1.DeploymentNotInstalled}
+	}
+
+	status := v1alpha1.ModelServerStatus{
+		Status: v1alpha1.DeploymentNotInstalled,
+		URL:    modelserver.ServerURL(ki.ModelServerDeploymentName(), ki.Namespace(), ms),
+		Models: estimator.Models(ki.Spec.Estimator),
+	}
+
+	key := types.NamespacedName{Name: ki.ModelServerDeploymentName(), Namespace: ki.Namespace()}
+	deploy := appsv1.Deployment{}
+	if err := r.Client.Get(ctx, key, &deploy); err != nil {
+		return status
+	}
+
+	status.Status = v1alpha1.DeploymentNotReady
+	status.ReadyReplicas = deploy.Status.ReadyReplicas
+	if len(deploy.Spec.Template.Spec.Containers) > 0 {
+		status.Image = deploy.Spec.Template.Spec.Containers[0].Image
+	}
+	if deploy.Status.ReadyReplicas > 0 {
+		status.Status = v1alpha1.DeploymentRunning
+	}
+	return status
+}
+
+func availableConditionForGetError(err error) metav1.Condition {
 	if errors.IsNotFound(err) {
-		return v1alpha1.Condition{
+		return metav1.Condition{
 			Type:    v1alpha1.Available,
-			Status:  v1alpha1.ConditionFalse,
+			Status:  metav1.ConditionFalse,
 			Reason:  v1alpha1.DaemonSetNotFound,
 			Message: err.Error(),
 		}
 	}
 
-	return v1alpha1.Condition{
+	return metav1.Condition{
 		Type:    v1alpha1.Available,
-		Status:  v1alpha1.ConditionUnknown,
+		Status:  metav1.ConditionUnknown,
 		Reason:  v1alpha1.DaemonSetError,
 		Message: err.Error(),
 	}
 
 }
 
-func availableCondition(dset *appsv1.DaemonSet) v1alpha1.Condition {
+func degradedCondition(ki *v1alpha1.KeplerInternal, recErr error, podCreationBlocked string) metav1.Condition {
+	if recErr != nil {
+		reason := v1alpha1.ReconcileError
+		switch {
+		case isModelVerificationError(recErr):
+			reason = v1alpha1.ModelVerificationFailed
+		case isInvalidSpecError(recErr):
+			reason = v1alpha1.InvalidSpec
+		case errors.IsForbidden(recErr):
+			reason = v1alpha1.PermissionDenied
+		case meta.IsNoMatchError(recErr):
+			reason = v1alpha1.APINotAvailable
+		}
+		return metav1.Condition{
+			Type:               v1alpha1.Degraded,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: ki.Generation,
+			Reason:             reason,
+			Message:            recErr.Error(),
+		}
+	}
+
+	if podCreationBlocked != "" {
+		return metav1.Condition{
+			Type:               v1alpha1.Degraded,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: ki.Generation,
+			Reason:             v1alpha1.SCCNotPermitted,
+			Message:            podCreationBlocked,
+		}
+	}
+
+	if n := len(ki.Status.InvalidNodes); n > 0 {
+		return metav1.Condition{
+			Type:               v1alpha1.Degraded,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: ki.Generation,
+			Reason:             v1alpha1.InvalidNodesFound,
+			Message:            fmt.Sprintf("kepler cannot run on %d node(s); see status.invalidNodes", n),
+		}
+	}
+
+	return metav1.Condition{
+		Type:               v1alpha1.Degraded,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: ki.Generation,
+		Reason:             v1alpha1.ReconcileComplete,
+		Message:            "Reconcile succeeded",
+	}
+}
+
+func progressingCondition(dset *appsv1.DaemonSet) metav1.Condition {
+	ds := dset.Status
+	dsName := dset.Namespace + "/" + dset.Name
+
+	c := metav1.Condition{Type: v1alpha1.Progressing}
+
+	if gen, ogen := dset.Generation, ds.ObservedGeneration; gen > ogen {
+		c.Status = metav1.ConditionTrue
+		c.Reason = v1alpha1.DaemonSetOutOfSync
+		c.Message = fmt.Sprintf(
+			"Generation %d of kepler daemonset %q is out of sync with the observed generation: %d",
+			gen, dsName, ogen)
+		return c
+	}
+
+	if ds.UpdatedNumberScheduled < ds.DesiredNumberScheduled ||
+		ds.NumberAvailable < ds.DesiredNumberScheduled {
+		c.Status = metav1.ConditionTrue
+		c.Reason = v1alpha1.DaemonSetRolloutInProgress
+		c.Message = fmt.Sprintf(
+			"Waiting for kepler daemonset %q rollout to finish: %d updated and %d available out of %d",
+			dsName, ds.UpdatedNumberScheduled, ds.NumberAvailable, ds.DesiredNumberScheduled)
+		return c
+	}
+
+	c.Status = metav1.ConditionFalse
+	c.Reason = v1alpha1.DaemonSetRolloutComplete
+	c.Message = fmt.Sprintf("Rollout of kepler daemonset %q is complete", dsName)
+	return c
+}
+
+func availableCondition(dset *appsv1.DaemonSet) metav1.Condition {
 	ds := dset.Status
 	dsName := dset.Namespace + "/" + dset.Name
 
 	if gen, ogen := dset.Generation, ds.ObservedGeneration; gen > ogen {
-		return v1alpha1.Condition{
+		return metav1.Condition{
 			Type:   v1alpha1.Available,
-			Status: v1alpha1.ConditionUnknown,
+			Status: metav1.ConditionUnknown,
 			Reason: v1alpha1.DaemonSetOutOfSync,
 			Message: fmt.Sprintf(
 				"Generation %d of kepler daemonset %q is out of sync with the observed generation: %d",
@@ -404,7 +1149,7 @@ func availableCondition(dset *appsv1.DaemonSet) v1alpha1.Condition {
 		}
 	}
 
-	c := v1alpha1.Condition{Type: v1alpha1.Available}
+	c := metav1.Condition{Type: v1alpha1.Available}
 
 	// NumberReady: The number of nodes that should be running the daemon pod and
 	// have one or more of the daemon pod running with a Ready Condition.
@@ -412,7 +1157,7 @@ func availableCondition(dset *appsv1.DaemonSet) v1alpha1.Condition {
 	// DesiredNumberScheduled: The total number of nodes that should be running
 	// the daemon pod (including nodes correctly running the daemon pod).
 	if ds.NumberReady == 0 || ds.DesiredNumberScheduled == 0 {
-		c.Status = v1alpha1.ConditionFalse
+		c.Status = metav1.ConditionFalse
 		c.Reason = v1alpha1.DaemonSetPodsNotRunning
 		c.Message = fmt.Sprintf("Kepler daemonset %q is not rolled out to any node; check nodeSelector and tolerations", dsName)
 		return c
@@ -424,7 +1169,7 @@ func availableCondition(dset *appsv1.DaemonSet) v1alpha1.Condition {
 	// the daemon pod (including nodes correctly running the daemon pod).
 
 	if ds.UpdatedNumberScheduled < ds.DesiredNumberScheduled {
-		c.Status = v1alpha1.ConditionUnknown
+		c.Status = metav1.ConditionUnknown
 		c.Reason = v1alpha1.DaemonSetRolloutInProgress
 		c.Message = fmt.Sprintf(
 			"Waiting for kepler daemonset %q rollout to finish: %d out of %d new pods have been updated",
@@ -437,7 +1182,7 @@ func availableCondition(dset *appsv1.DaemonSet) v1alpha1.Condition {
 	// least spec.minReadySeconds)
 
 	if ds.NumberAvailable < ds.DesiredNumberScheduled {
-		c.Status = v1alpha1.ConditionUnknown
+		c.Status = metav1.ConditionUnknown
 		c.Reason = v1alpha1.DaemonSetPartiallyAvailable
 		c.Message = fmt.Sprintf("Rollout of kepler daemonset %q is in progress: %d of %d updated pods are available",
 			dsName, ds.NumberAvailable, ds.DesiredNumberScheduled)
@@ -448,21 +1193,25 @@ func availableCondition(dset *appsv1.DaemonSet) v1alpha1.Condition {
 	// pod and have none of the daemon pod running and available (ready for at
 	// least spec.minReadySeconds)
 	if ds.NumberUnavailable > 0 {
-		c.Status = v1alpha1.ConditionFalse
-		c.Reason = v1alpha1.DaemonSetPartiallyAvailable
+		c.Status = metav1.ConditionFalse
+		c.Reason = v1alpha1.DaemonSetNotAvailable
 		c.Message = fmt.Sprintf("Waiting for kepler daemonset %q to rollout on %d nodes", dsName, ds.NumberUnavailable)
 		return c
 	}
 
-	c.Status = v1alpha1.ConditionTrue
+	c.Status = metav1.ConditionTrue
 	c.Reason = v1alpha1.DaemonSetReady
 	c.Message = fmt.Sprintf("Kepler daemonset %q is deployed to all nodes and available; ready %d/%d",
 		dsName, ds.NumberReady, ds.DesiredNumberScheduled)
 	return c
 }
 
-func (r KeplerInternalReconciler) reconcilersForInternal(ki *v1alpha1.KeplerInternal) []reconciler.Reconciler {
+// reconcilersForInternal returns the reconcilers of the kepler-internal and
+// an invalidSpecError listing the components that cannot be configured from
+// its spec, which are left out
+func (r KeplerInternalReconciler) reconcilersForInternal(ki *v1alpha1.KeplerInternal) ([]reconciler.Reconciler, error) {
 	rs := []reconciler.Reconciler{}
+	var specErrs specErrors
 
 	cleanup := !ki.DeletionTimestamp.IsZero()
 	if !cleanup {
@@ -474,6 +1223,20 @@ func (r KeplerInternalReconciler) reconcilersForInternal(ki *v1alpha1.KeplerInte
 			Logger:   r.logger,
 		})
 	}
+	// NOTE: features that are disabled are treated as if they are not
+	// configured, so that the resources deployed for them are removed
+	if ki.Spec.Estimator != nil && !features.Enabled(features.Estimator) {
+		r.logger.Info("estimator is disabled by feature gate; ignoring", "feature", features.Estimator)
+		ki.Spec.Estimator = nil
+	}
+	if ki.Spec.ModelServer != nil && !features.Enabled(features.ModelServer) {
+		r.logger.Info("model server is disabled by feature gate; ignoring", "feature", features.ModelServer)
+		ki.Spec.ModelServer = nil
+	}
+	if ki.Spec.CarbonIntensity != nil && !features.Enabled(features.CarbonIntensity) {
+		r.logger.Info("carbon intensity is disabled by feature gate; ignoring", "feature", features.CarbonIntensity)
+		ki.Spec.CarbonIntensity = nil
+	}
 
 	if ki.Spec.Estimator != nil {
 		if ki.Spec.Estimator.Image == "" {
@@ -481,7 +1244,11 @@ func (r KeplerInternalReconciler) reconcilersForInternal(ki *v1alpha1.KeplerInte
 		}
 	}
 
-	rs = append(rs, exporterReconcilers(ki, Config.Cluster)...)
+	defaultRBACProxy(ki.Spec.Exporter.RBACProxy)
+
+	reconcilers, errs := exporterReconcilers(ki, Config.Cluster)
+	rs = append(rs, reconcilers...)
+	specErrs = append(specErrs, errs...)
 
 	if ki.Spec.ModelServer != nil && ki.Spec.ModelServer.Enabled {
 		if ki.Spec.ModelServer.Image == "" {
@@ -489,10 +1256,47 @@ func (r KeplerInternalReconciler) reconcilersForInternal(ki *v1alpha1.KeplerInte
 		}
 		reconcilers, err := modelServerInternalReconcilers(ki)
 		if err != nil {
-			r.logger.Info(fmt.Sprintf("cannot init model server reconciler from config: %v", err))
+			specErrs.add("model server", err)
 		} else {
 			rs = append(rs, reconcilers...)
 		}
+	} else if !cleanup {
+		// remove the model server that may have been deployed before it was disabled
+		rs = append(rs, modelServerCleanupReconcilers(ki)...)
+	}
+
+	if reconcilers, err := openTelemetryReconcilers(ki); err != nil {
+		specErrs.add("opentelemetry collector", err)
+	} else {
+		rs = append(rs, reconcilers...)
+	}
+
+	if reconcilers, err := carbonIntensityReconcilers(ki); err != nil {
+		specErrs.add("carbon intensity", err)
+	} else {
+		rs = append(rs, reconcilers...)
+	}
+
+	rs = append(rs, energyReportReconcilers(ki)...)
+
+	if reconcilers, err := telegrafReconcilers(ki); err != nil {
+		specErrs.add("telegraf", err)
+	} else {
+		rs = append(rs, reconcilers...)
+	}
+
+	rs = append(rs, kernelReconcilers(ki, Config.Cluster)...)
+	rs = append(rs, metricsViewerReconcilers(ki)...)
+
+	if Config.Cluster == k8s.OpenShift {
+		rs = append(rs, consolePluginReconcilers(ki)...)
+	}
+
+	if Config.Cluster == k8s.OpenShift && Config.EnableUserWorkloadMonitoring && !cleanup {
+		rs = append(rs, reconciler.UserWorkloadMonitoringEnabler{
+			Reader:  r.apiReader(),
+			OnError: reconciler.Requeue,
+		})
 	}
 
 	if cleanup {
@@ -503,17 +1307,277 @@ func (r KeplerInternalReconciler) reconcilersForInternal(ki *v1alpha1.KeplerInte
 		})
 	}
 
-	// WARN: only run finalizer if theren't any errors
-	// this bug 🐛 must be FIXED
+	// NOTE: deleters requeue on error, so the finalizer is removed only after
+	// all resources have been deleted
 	rs = append(rs, reconciler.Finalizer{
 		Resource:  ki,
 		Finalizer: Finalizer,
 		Logger:    r.logger,
 	})
-	return rs
+	return rs, specErrs.err()
+}
+
+// kernelReconcilers ensures the kernel prerequisites using MachineConfigs on
+// OpenShift and a setup daemonset on other platforms and removes the resources
+// of the other platform or those created before it was disabled
+func kernelReconcilers(ki *v1alpha1.KeplerInternal, cluster k8s.Cluster) []reconciler.Reconciler {
+	enabled := ki.Spec.Exporter.KernelPrerequisites.IsEnabled() && ki.DeletionTimestamp.IsZero()
+	if !enabled {
+		rs := resourceReconcilers(deleteResource, kernel.NewDaemonSet(components.Metadata, ki))
+		return append(rs, resourceReconcilers(deleteResource, machineConfigs(components.Metadata, ki)...)...)
+	}
+
+	updateResource := newUpdaterWithOwner(ki)
+	if cluster == k8s.OpenShift {
+		rs := resourceReconcilers(deleteResource, kernel.NewDaemonSet(components.Metadata, ki))
+		return append(rs, resourceReconcilers(updateResource, machineConfigs(components.Full, ki)...)...)
+	}
+	rs := resourceReconcilers(deleteResource, machineConfigs(components.Metadata, ki)...)
+	return append(rs, resourceReconcilers(updateResource, kernel.NewDaemonSet(components.Full, ki))...)
+}
+
+// metricsViewerReconcilers creates the power-metrics-viewer ClusterRoles and
+// the RoleBindings of the spec and removes the RoleBindings no longer in it
+func metricsViewerReconcilers(ki *v1alpha1.KeplerInternal) []reconciler.Reconciler {
+	enabled := ki.Spec.MetricsViewer.IsEnabled() && ki.DeletionTimestamp.IsZero()
+	if !enabled {
+		rs := resourceReconcilers(deleteResource, toObjects(viewer.NewClusterRoles(components.Metadata, ki))...)
+		return append(rs, viewerBindingsPruner(ki, nil))
+	}
+
+	updateResource := newUpdaterWithOwner(ki)
+	bindings := toObjects(viewer.NewRoleBindings(ki))
+	rs := resourceReconcilers(updateResource, toObjects(viewer.NewClusterRoles(components.Full, ki))...)
+	rs = append(rs, resourceReconcilers(updateResource, bindings...)...)
+	return append(rs, viewerBindingsPruner(ki, bindings))
+}
+
+func viewerBindingsPruner(ki *v1alpha1.KeplerInternal, desired []client.Object) reconciler.Reconciler {
+	return reconciler.Pruner{
+		List:    &rbacv1.RoleBindingList{},
+		Labels:  viewer.Labels(ki).ToMap(),
+		Desired: desired,
+		OnError: reconciler.Requeue,
+	}
+}
+
+func machineConfigs(d components.Detail, ki *v1alpha1.KeplerInternal) []client.Object {
+	objs := []client.Object{}
+	for _, mc := range kernel.NewMachineConfigs(d, ki) {
+		objs = append(objs, mc)
+	}
+	return objs
+}
+
+// openTelemetryReconcilers deploys the collector of the kind selected in the
+// spec and removes the collectors that may have been deployed before they were
+// disabled or their kind was changed
+func openTelemetryReconcilers(ki *v1alpha1.KeplerInternal) ([]reconciler.Reconciler, error) {
+	otelSpec := ki.Spec.OpenTelemetry
+	enabled := otelSpec.IsEnabled() && ki.DeletionTimestamp.IsZero()
+	if enabled && otelSpec.Image == "" {
+		otelSpec.Image = InternalConfig.OTelCollectorImage
+	}
+
+	detail := components.Metadata
+	if enabled {
+		detail = components.Full
+	}
+	cm, err := otel.NewConfigMap(detail, ki)
+	if err != nil {
+		return nil, err
+	}
+	collector, err := otel.NewCollector(detail, ki)
+	if err != nil {
+		return nil, err
+	}
+	deploy := otel.NewDeployment(ki, cm)
+	deployed := []client.Object{cm, deploy}
+
+	if !enabled {
+		rs := resourceReconcilers(deleteResource, deployed...)
+		return append(rs, resourceReconcilers(deleteResource, collector)...), nil
+	}
+
+	updateResource := newUpdaterWithOwner(ki)
+	if otelSpec.Kind == v1alpha1.OperatorCollector {
+		rs := resourceReconcilers(deleteResource, deployed...)
+		return append(rs, resourceReconcilers(updateResource, collector)...), nil
+	}
+	rs := resourceReconcilers(updateResource, cm)
+	rs = append(rs, reconciler.SecretRollout{Owner: ki, Resource: deploy, Secrets: otel.PodSecrets(ki)})
+	return append(rs, resourceReconcilers(deleteResource, collector)...), nil
+}
+
+// openCostScrapeConfigReconcilers creates the scrape config secret for the
+// Prometheus of OpenCost and, if the exporter is scraped through
+// kube-rbac-proxy, copies the CA of its serving certificate into the scrape
+// config and authorizes the service account of the Prometheus of OpenCost
+func openCostScrapeConfigReconcilers(ki *v1alpha1.KeplerInternal) ([]reconciler.Reconciler, error) {
+	oc := ki.Spec.Exporter.OpenCost
+	if !oc.ScrapeConfigEnabled() {
+		secret, _ := exporter.NewOpenCostScrapeConfig(components.Metadata, ki, nil)
+		return resourceReconcilers(deleteResource, secret,
+			exporter.NewOpenCostMetricsReaderBinding(components.Metadata, ki)), nil
+	}
+	secret, err := exporter.NewOpenCostScrapeConfig(components.Full, ki, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ki.Spec.Exporter.RBACProxy.IsEnabled() {
+		return append(resourceReconcilers(newUpdaterWithOwner(ki), secret),
+			resourceReconcilers(deleteResource, exporter.NewOpenCostMetricsReaderBinding(components.Metadata, ki))...), nil
+	}
+	// NOTE: the scrape config is only created once the CA is issued
+	return append(resourceReconcilers(newUpdaterWithOwner(ki), exporter.NewOpenCostMetricsReaderBinding(components.Full, ki)),
+		reconciler.CABundleUpdater{
+			Owner:     ki,
+			Namespace: ki.Namespace(),
+			CA:        exporter.MetricsCA(ki),
+			Resource: func(ca []byte) (client.Object, error) {
+				return exporter.NewOpenCostScrapeConfig(components.Full, ki, ca)
+			},
+			OnError: reconciler.Requeue,
+		}), nil
 }
 
-func exporterReconcilers(ki *v1alpha1.KeplerInternal, cluster k8s.Cluster) []reconciler.Reconciler {
+// carbonIntensityReconcilers creates the recording rules of the emissions of
+// the containers measured by kepler and deploys the exporter querying the
+// intensity from a provider if needed. The resources are removed when they
+// are disabled or the exporter is no longer needed.
+func carbonIntensityReconcilers(ki *v1alpha1.KeplerInternal) ([]reconciler.Reconciler, error) {
+	ci := ki.Spec.CarbonIntensity
+	enabled := ci.IsEnabled() && ki.DeletionTimestamp.IsZero()
+	if enabled && ci.Image == "" {
+		ci.Image = InternalConfig.CarbonIntensityImage
+	}
+
+	detail := components.Metadata
+	if enabled {
+		detail = components.Full
+	}
+	rule, err := carbon.NewPrometheusRule(detail, ki)
+	if err != nil {
+		return nil, err
+	}
+
+	exporterDetail := components.Metadata
+	if enabled && carbon.NeedsExporter(ki) {
+		exporterDetail = components.Full
+	}
+	cm, err := carbon.NewConfigMap(exporterDetail, ki)
+	if err != nil {
+		return nil, err
+	}
+	deploy := carbon.NewDeployment(ki, cm)
+	sm := carbon.NewServiceMonitor(exporterDetail, ki)
+	svc := carbon.NewService(ki)
+
+	if !enabled {
+		return resourceReconcilers(deleteResource, rule, cm, deploy, svc, sm), nil
+	}
+
+	updateResource := newUpdaterWithOwner(ki)
+	rs := resourceReconcilers(updateResource, rule)
+	if !carbon.NeedsExporter(ki) {
+		return append(rs, resourceReconcilers(deleteResource, cm, deploy, svc, sm)...), nil
+	}
+	rs = append(rs, resourceReconcilers(updateResource, cm, svc, sm)...)
+	return append(rs, reconciler.SecretRollout{Owner: ki, Resource: deploy, Secrets: carbon.PodSecrets(ki)}), nil
+}
+
+// energyReportReconcilers creates the CronJob writing the energy reports along
+// with the service account it runs as and removes them when the reports are
+// disabled. The reports written to the ConfigMap are kept.
+func energyReportReconcilers(ki *v1alpha1.KeplerInternal) []reconciler.Reconciler {
+	er := ki.Spec.EnergyReport
+	sa := energyreport.NewServiceAccount(ki)
+	binding := energyreport.NewRoleBinding(ki)
+	if !er.IsEnabled() || !ki.DeletionTimestamp.IsZero() {
+		return resourceReconcilers(deleteResource,
+			energyreport.NewCronJob(components.Metadata, ki),
+			binding,
+			energyreport.NewRole(components.Metadata, ki),
+			sa,
+		)
+	}
+
+	if er.Image == "" {
+		er.Image = InternalConfig.EnergyReportImage
+	}
+	return resourceReconcilers(newUpdaterWithOwner(ki),
+		sa,
+		energyreport.NewRole(components.Full, ki),
+		binding,
+		energyreport.NewCronJob(components.Full, ki),
+	)
+}
+
+// telegrafReconcilers deploys telegraf writing the kepler metrics to InfluxDB
+// or TimescaleDB and removes it when it is disabled
+func telegrafReconcilers(ki *v1alpha1.KeplerInternal) ([]reconciler.Reconciler, error) {
+	enabled := ki.Spec.Telegraf.IsEnabled() && ki.DeletionTimestamp.IsZero()
+	if enabled && ki.Spec.Telegraf.Image == "" {
+		// NOTE: the default image is set on a copy to leave the spec as-is
+		ki = ki.DeepCopy()
+		ki.Spec.Telegraf.Image = InternalConfig.TelegrafImage
+	}
+
+	detail := components.Metadata
+	if enabled {
+		detail = components.Full
+	}
+	cm, err := telegraf.NewConfigMap(detail, ki)
+	if err != nil {
+		return nil, err
+	}
+	deploy := telegraf.NewDeployment(ki, cm)
+	sa := telegraf.NewServiceAccount(ki)
+	if !enabled {
+		return resourceReconcilers(deleteResource, deploy, cm, sa,
+			telegraf.NewMetricsReaderBinding(components.Metadata, ki)), nil
+	}
+
+	rs := resourceReconcilers(newUpdaterWithOwner(ki), sa, cm)
+	if ki.Spec.Exporter.RBACProxy.IsEnabled() {
+		rs = append(rs, resourceReconcilers(newUpdaterWithOwner(ki), telegraf.NewMetricsReaderBinding(components.Full, ki))...)
+	} else {
+		rs = append(rs, resourceReconcilers(deleteResource, telegraf.NewMetricsReaderBinding(components.Metadata, ki))...)
+	}
+	return append(rs, reconciler.SecretRollout{Owner: ki, Resource: deploy, Secrets: telegraf.PodSecrets(ki)}), nil
+}
+
+// consolePluginReconcilers deploys the console plugin when it is enabled and
+// removes it otherwise
+func consolePluginReconcilers(ki *v1alpha1.KeplerInternal) []reconciler.Reconciler {
+	cp := &ki.Spec.OpenShift.ConsolePlugin
+	if cp.Image == "" {
+		cp.Image = InternalConfig.ConsolePluginImage
+	}
+
+	deploy := consoleplugin.NewDeployment(ki)
+	resources := []client.Object{
+		consoleplugin.NewService(ki),
+		consoleplugin.NewConsolePlugin(ki),
+	}
+	pdb := consoleplugin.NewPodDisruptionBudget(ki)
+
+	if cleanup := !ki.DeletionTimestamp.IsZero(); cleanup || !ki.Spec.OpenShift.Enabled || !cp.Enabled {
+		rs := resourceReconcilers(deleteResource, deploy)
+		return append(rs, resourceReconcilers(deleteResource, append(resources, pdb)...)...)
+	}
+	rs := []reconciler.Reconciler{
+		reconciler.SecretRollout{Owner: ki, Resource: deploy, Secrets: consoleplugin.PodSecrets(ki)},
+	}
+	rs = append(rs, resourceReconcilers(newUpdaterWithOwner(ki), resources...)...)
+	if components.NeedsPodDisruptionBudget(cp.Replicas) {
+		return append(rs, resourceReconcilers(newUpdaterWithOwner(ki), pdb)...)
+	}
+	return append(rs, resourceReconcilers(deleteResource, pdb)...)
+}
+
+func exporterReconcilers(ki *v1alpha1.KeplerInternal, cluster k8s.Cluster) ([]reconciler.Reconciler, specErrors) {
 
 	if cleanup := !ki.DeletionTimestamp.IsZero(); cleanup {
 		rs := resourceReconcilers(
@@ -521,49 +1585,260 @@ func exporterReconcilers(ki *v1alpha1.KeplerInternal, cluster k8s.Cluster) []rec
 			// cluster-scoped
 			exporter.NewClusterRoleBinding(components.Metadata, ki),
 			exporter.NewClusterRole(components.Metadata, ki),
+			exporter.NewNodeFeatureRule(components.Metadata, ki),
+			exporter.NewMetricsReaderClusterRoleBinding(components.Metadata, ki),
+			exporter.NewMetricsReaderClusterRole(components.Metadata, ki),
 		)
+		rs = append(rs, resourceReconcilers(deleteResource, openshiftClusterResources(components.Metadata, ki, cluster)...)...)
 		rs = append(rs, resourceReconcilers(deleteResource, openshiftNamespacedResources(ki, cluster)...)...)
-		return rs
+		return rs, nil
 	}
 
+	var specErrs specErrors
 	updateResource := newUpdaterWithOwner(ki)
+	// NOTE: resources that do not depend on each other are grouped and applied
+	// in parallel; the groups are applied in order
+
 	// cluster-scoped resources first
-	rs := resourceReconcilers(updateResource,
+	clusterScoped := resourceReconcilers(updateResource,
 		exporter.NewClusterRole(components.Full, ki),
 		exporter.NewClusterRoleBinding(components.Full, ki),
 	)
-	rs = append(rs, resourceReconcilers(updateResource, openshiftClusterResources(ki, cluster)...)...)
+	clusterScoped = append(clusterScoped, resourceReconcilers(updateResource, openshiftClusterResources(components.Full, ki, cluster)...)...)
+	if ki.Spec.Exporter.NodeFeatureDiscovery.IsEnabled() {
+		clusterScoped = append(clusterScoped, resourceReconcilers(updateResource, exporter.NewNodeFeatureRule(components.Full, ki))...)
+	} else {
+		// remove the NodeFeatureRule that may have been created before it was disabled
+		clusterScoped = append(clusterScoped, resourceReconcilers(deleteResource, exporter.NewNodeFeatureRule(components.Metadata, ki))...)
+	}
+	// NOTE: the metrics reader role is bound to the service accounts of the
+	// components scraping kube-rbac-proxy by the reconcilers of the components
+	if ki.Spec.Exporter.RBACProxy.IsEnabled() {
+		clusterScoped = append(clusterScoped, resourceReconcilers(updateResource,
+			exporter.NewMetricsReaderClusterRole(components.Full, ki))...)
+	} else {
+		clusterScoped = append(clusterScoped, resourceReconcilers(deleteResource,
+			exporter.NewMetricsReaderClusterRole(components.Metadata, ki))...)
+	}
+	if exporter.NeedsScrapeServiceAccount(ki) {
+		clusterScoped = append(clusterScoped, resourceReconcilers(updateResource,
+			exporter.NewMetricsReaderClusterRoleBinding(components.Full, ki))...)
+	} else {
+		clusterScoped = append(clusterScoped, resourceReconcilers(deleteResource,
+			exporter.NewMetricsReaderClusterRoleBinding(components.Metadata, ki))...)
+	}
+	rs := []reconciler.Reconciler{parallel(clusterScoped...)}
 
 	// namespace scoped
-	rs = append(rs, resourceReconcilers(updateResource,
+	namespaced := resourceReconcilers(updateResource,
 		exporter.NewServiceAccount(ki),
 		exporter.NewService(ki),
-		exporter.NewServiceMonitor(ki),
-		exporter.NewPrometheusRule(ki),
-	)...)
-
-	if ki.Spec.Exporter.Redfish == nil {
+	)
+	if exporter.NeedsScrapeServiceAccount(ki) {
+		// NOTE: the token is populated only once the service account exists
 		rs = append(rs, resourceReconcilers(updateResource,
-			exporter.NewDaemonSet(components.Full, ki),
-			exporter.NewConfigMap(components.Full, ki),
+			exporter.NewScrapeServiceAccount(ki),
+			exporter.NewScrapeTokenSecret(ki),
+		)...)
+	} else {
+		rs = append(rs, resourceReconcilers(deleteResource,
+			exporter.NewScrapeTokenSecret(ki),
+			exporter.NewScrapeServiceAccount(ki),
 		)...)
+	}
+	if ki.Spec.NetworkPolicy.IsEnabled() {
+		namespaced = append(namespaced, resourceReconcilers(updateResource, exporter.NewNetworkPolicy(components.Full, ki))...)
+	} else {
+		namespaced = append(namespaced, resourceReconcilers(deleteResource, exporter.NewNetworkPolicy(components.Metadata, ki))...)
+	}
+	// NOTE: monitors that cannot be configured from the spec are left as-is
+	// so that the exporter is still scraped
+	if sm := ki.Spec.Exporter.ServiceMonitor; sm.IsEnabled() {
+		if err := exporter.ValidateScrape(ptr.Deref(sm, v1alpha1.ServiceMonitorSpec{}).ScrapeSpec); err != nil {
+			specErrs.add("service monitor", err)
+		} else {
+			namespaced = append(namespaced, resourceReconcilers(updateResource, exporter.NewServiceMonitor(ki))...)
+		}
+	} else {
+		// remove the service monitor that may have been created before it was disabled
+		namespaced = append(namespaced, resourceReconcilers(deleteResource, exporter.NewServiceMonitor(ki))...)
+	}
+	if pm := ki.Spec.Exporter.PodMonitor; pm.IsEnabled() {
+		if err := exporter.ValidateScrape(pm.ScrapeSpec); err != nil {
+			specErrs.add("pod monitor", err)
+		} else {
+			namespaced = append(namespaced, resourceReconcilers(updateResource, exporter.NewPodMonitor(ki))...)
+		}
+	} else {
+		namespaced = append(namespaced, resourceReconcilers(deleteResource, exporter.NewPodMonitor(ki))...)
+	}
+	// NOTE: the recording rules are evaluated by the Prometheus Operator that
+	// the monitors are created for
+	if ki.Spec.Exporter.ServiceMonitor.IsEnabled() || ki.Spec.Exporter.PodMonitor.IsEnabled() {
+		namespaced = append(namespaced, resourceReconcilers(updateResource, exporter.NewPrometheusRule(ki))...)
+	} else {
+		namespaced = append(namespaced, resourceReconcilers(deleteResource, exporter.NewPrometheusRule(ki))...)
+	}
+	if ki.Spec.Exporter.Alerts.IsEnabled() {
+		namespaced = append(namespaced, resourceReconcilers(updateResource, exporter.NewAlertsPrometheusRule(ki))...)
+	} else {
+		namespaced = append(namespaced, resourceReconcilers(deleteResource, exporter.NewAlertsPrometheusRule(ki))...)
+	}
+	if ki.Spec.Exporter.Aggregations.IsEnabled() {
+		namespaced = append(namespaced, resourceReconcilers(updateResource, exporter.NewAggregationsPrometheusRule(ki))...)
+	} else {
+		namespaced = append(namespaced, resourceReconcilers(deleteResource, exporter.NewAggregationsPrometheusRule(ki))...)
+	}
+	if ki.Spec.Exporter.IdleWaste.IsEnabled() {
+		namespaced = append(namespaced, resourceReconcilers(updateResource, exporter.NewIdleWastePrometheusRule(components.Full, ki))...)
+	} else {
+		namespaced = append(namespaced, resourceReconcilers(deleteResource, exporter.NewIdleWastePrometheusRule(components.Metadata, ki))...)
+	}
+	if ki.Spec.Exporter.PUE.IsEnabled() {
+		namespaced = append(namespaced, resourceReconcilers(updateResource, exporter.NewPUEPrometheusRule(components.Full, ki))...)
+	} else {
+		namespaced = append(namespaced, resourceReconcilers(deleteResource, exporter.NewPUEPrometheusRule(components.Metadata, ki))...)
+	}
+	// NOTE: the rules that cannot be configured from the spec are left as-is
+	// so that the rules applied before keep recording
+	if reconcilers, err := ruleReconcilers(ki, ki.Spec.Exporter.ElectricityPrice.IsEnabled(), exporter.NewEnergyCostPrometheusRule); err != nil {
+		specErrs.add("electricity price", err)
+	} else {
+		namespaced = append(namespaced, reconcilers...)
+	}
+	if reconcilers, err := ruleReconcilers(ki, ki.Spec.Exporter.OpenCost.IsEnabled(), exporter.NewOpenCostPrometheusRule); err != nil {
+		specErrs.add("opencost", err)
+	} else {
+		namespaced = append(namespaced, reconcilers...)
+	}
+	// NOTE: the namespace of the scrape config is only known while it is set
+	if oc := ki.Spec.Exporter.OpenCost; oc != nil && oc.ScrapeConfig != nil {
+		if reconcilers, err := openCostScrapeConfigReconcilers(ki); err != nil {
+			specErrs.add("opencost scrape config", err)
+		} else {
+			namespaced = append(namespaced, reconcilers...)
+		}
+	}
+	if ki.Spec.Exporter.VerticalPodAutoscaler.IsEnabled() {
+		namespaced = append(namespaced, resourceReconcilers(updateResource, exporter.NewVerticalPodAutoscaler(components.Full, ki))...)
+	} else {
+		namespaced = append(namespaced, resourceReconcilers(deleteResource, exporter.NewVerticalPodAutoscaler(components.Metadata, ki))...)
+	}
+	if proxy := ki.Spec.Exporter.RBACProxy; proxy.IsEnabled() && proxy.CertManager != nil {
+		// NOTE: request the certificate before the pods that mount it are created
+		namespaced = append(namespaced, resourceReconcilers(updateResource, exporter.NewCertificate(components.Full, ki))...)
+	} else {
+		namespaced = append(namespaced, resourceReconcilers(deleteResource, exporter.NewCertificate(components.Metadata, ki))...)
+	}
+	if exporter.ManagesServingCert(ki) {
+		// NOTE: issue the certificate before the pods that mount it are created
+		namespaced = append(namespaced, reconciler.ServingCertReconciler{
+			Owner:    ki,
+			CA:       exporter.NewServingCASecret(ki),
+			Cert:     exporter.NewServingCertSecret(ki),
+			DNSNames: exporter.ServingCertDNSNames(ki),
+		})
+	} else {
+		// NOTE: only the CA is removed since the certificate secret has the same
+		// name as the one issued by cert-manager or the service CA of OpenShift
+		namespaced = append(namespaced, resourceReconcilers(deleteResource, exporter.NewServingCASecret(ki))...)
+	}
+
+	// NOTE: update the configmap before the daemonset so that pods that are
+	// redeployed due to a change in the config-hash read the latest config
+	if ki.Spec.Exporter.Redfish == nil {
+		namespaced = append(namespaced, resourceReconcilers(updateResource, exporter.NewConfigMap(components.Full, ki))...)
+		rs = append(rs, parallel(namespaced...))
+		rs = append(rs, reconciler.SecretRollout{
+			Owner:    ki,
+			Resource: exporter.NewDaemonSet(components.Full, ki),
+			Secrets:  exporter.PodSecrets(ki),
+		})
 	} else {
 		rs = append(rs,
-			reconciler.KeplerReconciler{
-				Ki: ki,
-				Ds: exporter.NewDaemonSet(components.Full, ki),
-			},
+			parallel(namespaced...),
 			reconciler.KeplerConfigMapReconciler{
 				Ki:  ki,
 				Cfm: exporter.NewConfigMap(components.Full, ki),
 			},
+			reconciler.KeplerReconciler{
+				Ki: ki,
+				Ds: exporter.NewDaemonSet(components.Full, ki),
+			},
 		)
 	}
-	rs = append(rs, resourceReconcilers(updateResource, openshiftNamespacedResources(ki, cluster)...)...)
-	return rs
+
+	dashboards := resourceReconcilers(updateResource, openshiftNamespacedResources(ki, cluster)...)
+	if cluster == k8s.OpenShift && ki.Name == v1alpha1.KeplerInstanceName && !ki.Spec.OpenShift.Dashboard.Enabled {
+		// remove the console dashboards that may have been created before they
+		// were disabled; the dashboards are shared, so only the kepler instance
+		// removes them
+		dashboards = append(dashboards, resourceReconcilers(deleteResource,
+			exporter.NewOverviewDashboard(components.Metadata),
+			exporter.NewNamespaceInfoDashboard(components.Metadata),
+		)...)
+	}
+
+	dashboards = append(dashboards, grafanaReconcilers(ki)...)
+	return append(rs, parallel(dashboards...)), specErrs
 }
 
-func openshiftClusterResources(ki *v1alpha1.KeplerInternal, cluster k8s.Cluster) []client.Object {
+// ruleReconcilers applies the rule while it is enabled and removes it once it
+// is disabled. Returns an error, and no reconcilers so that the rule applied
+// before is kept, if the rule cannot be configured from the spec.
+func ruleReconcilers(ki *v1alpha1.KeplerInternal, enabled bool,
+	newRule func(components.Detail, *v1alpha1.KeplerInternal) (*monv1.PrometheusRule, error)) ([]reconciler.Reconciler, error) {
+	if !enabled {
+		rule, _ := newRule(components.Metadata, ki)
+		return resourceReconcilers(deleteResource, rule), nil
+	}
+	rule, err := newRule(components.Full, ki)
+	if err != nil {
+		return nil, err
+	}
+	return resourceReconcilers(newUpdaterWithOwner(ki), rule), nil
+}
+
+// grafanaReconcilers creates the dashboards of the kind selected in the spec
+// and removes the ones that may have been created before they were disabled
+// or their kind was changed
+func grafanaReconcilers(ki *v1alpha1.KeplerInternal) []reconciler.Reconciler {
+	updateResource := newUpdaterWithOwner(ki)
+	grafana := ki.Spec.Dashboards.Grafana
+
+	configMaps := toObjects(exporter.NewGrafanaDashboards(components.Full, ki))
+	operatorResources := toObjects(exporter.NewGrafanaOperatorDashboards(components.Full, ki))
+	datasource := exporter.NewGrafanaDatasource(components.Full, ki)
+
+	if !grafana.IsEnabled() {
+		rs := resourceReconcilers(deleteResource, configMaps...)
+		rs = append(rs, resourceReconcilers(deleteResource, operatorResources...)...)
+		return append(rs, resourceReconcilers(deleteResource, datasource)...)
+	}
+
+	if grafana.Kind != v1alpha1.GrafanaOperatorDashboards {
+		rs := resourceReconcilers(updateResource, configMaps...)
+		rs = append(rs, resourceReconcilers(deleteResource, operatorResources...)...)
+		return append(rs, resourceReconcilers(deleteResource, datasource)...)
+	}
+
+	rs := resourceReconcilers(deleteResource, configMaps...)
+	rs = append(rs, resourceReconcilers(updateResource, operatorResources...)...)
+	if grafana.Datasource != nil {
+		return append(rs, resourceReconcilers(updateResource, datasource)...)
+	}
+	return append(rs, resourceReconcilers(deleteResource, datasource)...)
+}
+
+func toObjects[T client.Object](objs []T) []client.Object {
+	ret := make([]client.Object, 0, len(objs))
+	for _, o := range objs {
+		ret = append(ret, o)
+	}
+	return ret
+}
+
+func openshiftClusterResources(d components.Detail, ki *v1alpha1.KeplerInternal, cluster k8s.Cluster) []client.Object {
 
 	oshift := ki.Spec.OpenShift
 	if cluster != k8s.OpenShift || !oshift.Enabled {
@@ -571,7 +1846,7 @@ func openshiftClusterResources(ki *v1alpha1.KeplerInternal, cluster k8s.Cluster)
 	}
 	// NOTE: SCC is required for kepler deployment even if openshift is not enabled
 	return []client.Object{
-		exporter.NewSCC(components.Full, ki),
+		exporter.NewSCC(d, ki),
 	}
 }
 
@@ -593,11 +1868,43 @@ func openshiftNamespacedResources(ki *v1alpha1.KeplerInternal, cluster k8s.Clust
 }
 
 func modelServerInternalReconcilers(ki *v1alpha1.KeplerInternal) ([]reconciler.Reconciler, error) {
+	rs := []reconciler.Reconciler{}
+	updateResource := newUpdaterWithOwner(ki)
+	for _, res := range modelServerResources(ki) {
+		// NOTE: on OpenShift, models are downloaded using the cluster-wide proxy
+		if deploy, ok := res.(*appsv1.Deployment); ok && Config.Cluster == k8s.OpenShift {
+			rs = append(rs, reconciler.Mutator{
+				Owner:    ki,
+				Resource: deploy,
+				Mutate: func(ctx context.Context, c client.Reader) error {
+					return modelserver.MountClusterProxy(ctx, c, deploy)
+				},
+			})
+			continue
+		}
+		rs = append(rs, updateResource(res))
+	}
+	if !ki.Spec.NetworkPolicy.IsEnabled() {
+		// remove the network policy that may have been created before it was disabled
+		np := modelserver.NewNetworkPolicy(ki.ModelServerDeploymentName(), components.Metadata, ki.Spec.ModelServer, ki.Namespace())
+		rs = append(rs, resourceReconcilers(deleteResource, np)...)
+	}
+	if !components.NeedsPodDisruptionBudget(ki.Spec.ModelServer.Replicas) {
+		// remove the pod disruption budget that may have been created before
+		// the model server was scaled down
+		pdb := modelserver.NewPodDisruptionBudget(ki.ModelServerDeploymentName(), ki.Spec.ModelServer, ki.Namespace())
+		rs = append(rs, resourceReconcilers(deleteResource, pdb)...)
+	}
+	return rs, nil
+}
+
+// modelServerResources returns the resources of the model server
+func modelServerResources(ki *v1alpha1.KeplerInternal) []client.Object {
 	ms := ki.Spec.ModelServer
 	msName := ki.ModelServerDeploymentName()
 	namespace := ki.Spec.Exporter.Deployment.Namespace
 	cm := modelserver.NewConfigMap(msName, components.Full, ms, namespace)
-	deploy := modelserver.NewDeployment(msName, ms, namespace)
+	deploy := modelserver.NewDeployment(msName, ms, namespace, verifiedModels(ki)...)
 	svc := modelserver.NewService(msName, ms, namespace)
 
 	resources := []client.Object{cm, deploy, svc}
@@ -606,9 +1913,34 @@ func modelServerInternalReconcilers(ki *v1alpha1.KeplerInternal) ([]reconciler.R
 		pvc := modelserver.NewPVC(msName, namespace, ms.Storage.PersistentVolumeClaim)
 		resources = append(resources, pvc)
 	}
+	if Config.Cluster == k8s.OpenShift {
+		resources = append(resources, modelserver.NewTrustedCAConfigMap(msName, namespace))
+	}
+	if ki.Spec.NetworkPolicy.IsEnabled() {
+		resources = append(resources, modelserver.NewNetworkPolicy(msName, components.Full, ms, namespace))
+	}
+	if components.NeedsPodDisruptionBudget(ms.Replicas) {
+		resources = append(resources, modelserver.NewPodDisruptionBudget(msName, ms, namespace))
+	}
+	return resources
+}
 
-	rs := updatersForInternalResources(ki, resources...)
-	return rs, nil
+// modelServerCleanupReconcilers deletes the resources of the model server
+// except for its PVC, which holds the models of the user and is left as-is;
+// the PVC is deleted along with the kepler-internal that owns it
+func modelServerCleanupReconcilers(ki *v1alpha1.KeplerInternal) []reconciler.Reconciler {
+	msName := ki.ModelServerDeploymentName()
+	namespace := ki.Spec.Exporter.Deployment.Namespace
+	ms := &v1alpha1.InternalModelServerSpec{}
+
+	return resourceReconcilers(deleteResource,
+		modelserver.NewDeployment(msName, ms, namespace),
+		modelserver.NewService(msName, ms, namespace),
+		modelserver.NewConfigMap(msName, components.Metadata, ms, namespace),
+		modelserver.NewNetworkPolicy(msName, components.Metadata, ms, namespace),
+		modelserver.NewPodDisruptionBudget(msName, ms, namespace),
+		modelserver.NewTrustedCAConfigMap(msName, namespace),
+	)
 }
 
 func updatersForInternalResources(ki *v1alpha1.KeplerInternal, resources ...client.Object) []reconciler.Reconciler {
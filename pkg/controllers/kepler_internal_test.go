@@ -0,0 +1,106 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/features"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/reconciler"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestMapDaemonSetEventToRequests(t *testing.T) {
+	s := newScheme(t)
+	ki := &v1alpha1.KeplerInternal{ObjectMeta: metav1.ObjectMeta{Name: "kepler-internal", UID: "ki-uid"}}
+	owned := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "kepler-internal-kernel-setup", Namespace: "kepler"}}
+	assert.NoError(t, ctrlutil.SetControllerReference(ki, owned, s))
+	other := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "kepler-internal", Namespace: "other"}}
+
+	r := &KeplerInternalReconciler{Client: fake.NewClientBuilder().WithScheme(s).WithObjects(owned, other).Build()}
+	event := func(ds *appsv1.DaemonSet) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{Name: ds.Name + ".1", Namespace: ds.Namespace},
+			InvolvedObject: corev1.ObjectReference{
+				Kind: "DaemonSet", Name: ds.Name, Namespace: ds.Namespace,
+			},
+			Reason: "FailedCreate",
+		}
+	}
+
+	assert.Equal(t,
+		[]reconcile.Request{{NamespacedName: types.NamespacedName{Name: "kepler-internal"}}},
+		r.mapDaemonSetEventToRequests(context.TODO(), event(owned)))
+	assert.Empty(t, r.mapDaemonSetEventToRequests(context.TODO(), event(other)))
+	assert.Empty(t, r.mapDaemonSetEventToRequests(context.TODO(), event(&appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "missing", Namespace: "kepler"},
+	})))
+}
+
+func TestUpdateFeatureStatus(t *testing.T) {
+	ki := &v1alpha1.KeplerInternal{ObjectMeta: metav1.ObjectMeta{Name: "kepler", Generation: 1}}
+	ki.Spec.Estimator = &v1alpha1.InternalEstimatorSpec{}
+	ki.Spec.CarbonIntensity = &v1alpha1.InternalCarbonIntensitySpec{}
+
+	t.Run("features shipped before the gates are enabled by default", func(t *testing.T) {
+		ki := ki.DeepCopy()
+		ki.Spec.CarbonIntensity = nil
+		assert.False(t, updateFeatureStatus(ki))
+		assert.Empty(t, ki.Status.Conditions)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Cleanup(featuregatetesting.SetFeatureGateDuringTest(t, features.Gate, features.Estimator, false))
+		ki := ki.DeepCopy()
+		assert.True(t, updateFeatureStatus(ki))
+		c := meta.FindStatusCondition(ki.Status.Conditions, v1alpha1.FeatureDisabled)
+		if assert.NotNil(t, c) {
+			assert.Equal(t, metav1.ConditionTrue, c.Status)
+			assert.Equal(t, v1alpha1.FeatureGateDisabled, c.Reason)
+			assert.Contains(t, c.Message, "--feature-gates=Estimator=true,CarbonIntensity=true")
+		}
+		assert.False(t, updateFeatureStatus(ki))
+
+		ki.Spec.Estimator, ki.Spec.CarbonIntensity = nil, nil
+		assert.True(t, updateFeatureStatus(ki), "condition must be removed")
+		assert.Empty(t, ki.Status.Conditions)
+	})
+}
+
+func TestModelServerCleanupKeepsPVC(t *testing.T) {
+	ki := &v1alpha1.KeplerInternal{ObjectMeta: metav1.ObjectMeta{Name: "kepler"}}
+	ki.Spec.Exporter.Deployment.Namespace = "kepler"
+
+	deleted := []string{}
+	for _, r := range modelServerCleanupReconcilers(ki) {
+		if d, ok := r.(*reconciler.Deleter); ok {
+			deleted = append(deleted, d.Resource.GetObjectKind().GroupVersionKind().Kind)
+		}
+	}
+	assert.Contains(t, deleted, "Deployment")
+	assert.NotContains(t, deleted, "PersistentVolumeClaim", "the models of the user must not be deleted")
+}
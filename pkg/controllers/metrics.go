@@ -0,0 +1,52 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Metrics about the health of the operator; reconcile errors and queue
+// metrics are already exposed by controller-runtime
+var (
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kepler_operator_reconcile_duration_seconds",
+		Help:    "Time taken to reconcile a resource including the update of its status",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 12),
+	}, []string{"controller"})
+
+	lastSuccessfulReconcile = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kepler_operator_last_successful_reconcile_timestamp_seconds",
+		Help: "Time at which the resource was last reconciled successfully",
+	}, []string{"controller", "name"})
+
+	nodeIdlePower = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kepler_operator_node_idle_power_watts",
+		Help: "Idle power of the node measured by the last calibration",
+	}, []string{"node"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(reconcileDuration, lastSuccessfulReconcile, nodeIdlePower)
+}
+
+func observeReconcileDuration(controller string, start time.Time) {
+	reconcileDuration.WithLabelValues(controller).Observe(time.Since(start).Seconds())
+}
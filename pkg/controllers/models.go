@@ -0,0 +1,318 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components/estimator"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/features"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/sigstore"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	// cosignPublicKey is the key of the public key in the model verification secret
+	cosignPublicKey = "cosign.pub"
+	// fulcioChain is the key of the certificate chain in the model verification
+	// secret used to verify keyless signatures
+	fulcioChain = "fulcio.crt"
+	// rekorPublicKey is the key of the public key of the transparency log in
+	// the model verification secret used to verify keyless signatures
+	rekorPublicKey = "rekor.pub"
+
+	// defaultModelVerificationInterval is the default interval between the
+	// verifications of the models of a kepler-internal
+	defaultModelVerificationInterval = time.Hour
+)
+
+// modelVerificationError lists the model archives that failed verification
+type modelVerificationError struct {
+	err error
+}
+
+func (e *modelVerificationError) Error() string {
+	return fmt.Sprintf("refusing to deploy models that failed verification: %v", e.err)
+}
+
+func (e *modelVerificationError) Unwrap() error {
+	return e.err
+}
+
+func isModelVerificationError(err error) bool {
+	var mvErr *modelVerificationError
+	return errors.As(err, &mvErr)
+}
+
+// modelURLs returns the urls of the model archives pulled by the model
+// server and the estimator sidecars deployed for the kepler-internal. Returns
+// an error if the url of a deployed model is not set since the model pulled
+// by default cannot be verified.
+func modelURLs(ki *v1alpha1.KeplerInternal) ([]string, error) {
+	spec := &ki.Spec
+	urls := []string{}
+	errs := []error{}
+	if ms := spec.ModelServer; ms != nil && ms.Enabled && features.Enabled(features.ModelServer) {
+		if ms.PipelineURL == "" {
+			errs = append(errs, errors.New("model-server: pipelineUrl must be set to verify the model"))
+		} else {
+			urls = append(urls, ms.PipelineURL)
+		}
+	}
+
+	es := spec.Estimator
+	if !estimator.NeedsEstimatorSidecar(es) || !features.Enabled(features.Estimator) {
+		return urls, errors.Join(errs...)
+	}
+	configs := []struct {
+		name   string
+		config *v1alpha1.EstimatorConfig
+	}{
+		{"node total", es.Node.Total},
+		{"node components", es.Node.Components},
+		{"container total", es.Container.Total},
+		{"container components", es.Container.Components},
+	}
+	for _, c := range configs {
+		if c.config == nil || !c.config.SidecarEnabled {
+			continue
+		}
+		if c.config.InitUrl == "" {
+			errs = append(errs, fmt.Errorf("estimator %s: initUrl must be set to verify the model", c.name))
+			continue
+		}
+		urls = append(urls, c.config.InitUrl)
+	}
+	return urls, errors.Join(errs...)
+}
+
+// ModelVerificationReconciler verifies the signatures of the model archives
+// deployed for the kepler-internals with model verification configured and
+// records their digests in the status. Archives are downloaded to be
+// verified, so they are verified apart from the KeplerInternalReconciler
+// which deploys the models once their digests are recorded; the pods check
+// the digests of the archives they download against the recorded ones.
+// Models are verified again every interval so that archives signed again
+// are rolled out.
+type ModelVerificationReconciler struct {
+	client.Client
+	Recorder record.EventRecorder
+
+	// APIReader reads the model verification secrets which are not cached by
+	// the operator; defaults to Client
+	APIReader client.Reader
+
+	Verifier sigstore.Verifier
+
+	// Interval between the verifications of the models of a kepler-internal;
+	// defaults to 1h
+	Interval time.Duration
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ModelVerificationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("model-verification").
+		For(&v1alpha1.KeplerInternal{}, builder.WithPredicates(
+			predicate.GenerationChangedPredicate{}, Config.Shard.Predicate())).
+		Complete(r)
+}
+
+// Reconcile verifies the models of the kepler-internal and records the
+// digests of the verified ones in its status. The digests verified before are
+// kept when the models fail verification so that the models deployed are
+// left as-is; the pods refuse archives that do not match them.
+func (r *ModelVerificationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	ki := v1alpha1.KeplerInternal{}
+	if err := r.Client.Get(ctx, req.NamespacedName, &ki); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !ki.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	var models []v1alpha1.VerifiedModel
+	var verifyErr error
+	if ki.Spec.ModelVerification != nil {
+		models, verifyErr = r.verifyModels(ctx, &ki)
+	}
+	if verifyErr != nil {
+		logger.Error(verifyErr, "failed to verify models")
+		r.Recorder.Event(&ki, corev1.EventTypeWarning, v1alpha1.ModelVerificationFailed, verifyErr.Error())
+	}
+
+	if !equality.Semantic.DeepEqual(ki.Status.VerifiedModels, models) {
+		base := ki.DeepCopy()
+		ki.Status.VerifiedModels = models
+		if err := patchStatus(ctx, r.Client, &ki, base); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if ki.Spec.ModelVerification == nil {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: r.interval()}, nil
+}
+
+func (r *ModelVerificationReconciler) interval() time.Duration {
+	if r.Interval > 0 {
+		return r.Interval
+	}
+	return defaultModelVerificationInterval
+}
+
+// verifyModels verifies the signatures of the model archives deployed for the
+// kepler-internal. Returns the verified models along with the models verified
+// before that failed verification this time, and an error listing the
+// failures.
+func (r *ModelVerificationReconciler) verifyModels(ctx context.Context, ki *v1alpha1.KeplerInternal) ([]v1alpha1.VerifiedModel, error) {
+	verified := map[string]v1alpha1.VerifiedModel{}
+	for _, m := range ki.Status.VerifiedModels {
+		verified[m.URL] = m
+	}
+
+	// NOTE: the spec error is reported by the kepler-internal reconciler;
+	// the models that can be verified are verified anyway
+	urls, _ := modelURLs(ki)
+
+	apiReader := r.APIReader
+	if apiReader == nil {
+		apiReader = r.Client
+	}
+	policy, policyErr := verificationPolicy(ctx, apiReader, ki)
+
+	models := []v1alpha1.VerifiedModel{}
+	errs := []error{}
+	for _, url := range urls {
+		if policyErr != nil {
+			if m, ok := verified[url]; ok {
+				models = append(models, m)
+			}
+			continue
+		}
+		digest, err := r.Verifier.Verify(ctx, url, policy)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", url, err))
+			if m, ok := verified[url]; ok {
+				models = append(models, m)
+			}
+			continue
+		}
+		models = append(models, v1alpha1.VerifiedModel{URL: url, Digest: digest})
+	}
+	if policyErr != nil {
+		errs = append(errs, policyErr)
+	}
+	if len(models) == 0 {
+		models = nil
+	}
+	return models, errors.Join(errs...)
+}
+
+// verificationPolicy returns the policy configured by the model verification
+// secret of the kepler-internal
+func verificationPolicy(ctx context.Context, c client.Reader, ki *v1alpha1.KeplerInternal) (sigstore.Policy, error) {
+	mv := ki.Spec.ModelVerification
+	secret := corev1.Secret{}
+	key := types.NamespacedName{Namespace: ki.Namespace(), Name: mv.SecretRef}
+	if err := c.Get(ctx, key, &secret); err != nil {
+		return sigstore.Policy{}, fmt.Errorf("failed to get model verification secret %s: %w", key, err)
+	}
+
+	if mv.Keyless == nil {
+		data, ok := secret.Data[cosignPublicKey]
+		if !ok {
+			return sigstore.Policy{}, fmt.Errorf("model verification secret %s has no %s", key, cosignPublicKey)
+		}
+		return sigstore.PublicKeyPolicy(data)
+	}
+	chain, ok := secret.Data[fulcioChain]
+	if !ok {
+		return sigstore.Policy{}, fmt.Errorf("model verification secret %s has no %s", key, fulcioChain)
+	}
+	rekorKey, ok := secret.Data[rekorPublicKey]
+	if !ok {
+		return sigstore.Policy{}, fmt.Errorf("model verification secret %s has no %s", key, rekorPublicKey)
+	}
+	return sigstore.KeylessPolicy(chain, rekorKey, mv.Keyless.Issuer, mv.Keyless.Subject)
+}
+
+// checkModelsVerified returns a *modelVerificationError listing the model
+// archives deployed for the kepler-internal whose digests have not been
+// recorded by the ModelVerificationReconciler; nothing must be deployed in
+// that case.
+func checkModelsVerified(ki *v1alpha1.KeplerInternal) error {
+	urls, err := modelURLs(ki)
+	if err != nil {
+		return &modelVerificationError{err}
+	}
+
+	verified := map[string]bool{}
+	for _, m := range ki.Status.VerifiedModels {
+		verified[m.URL] = true
+	}
+	errs := []error{}
+	for _, url := range urls {
+		if !verified[url] {
+			errs = append(errs, fmt.Errorf("%s: not verified yet or failed verification; see the %s events", url, v1alpha1.ModelVerificationFailed))
+		}
+	}
+	if len(errs) != 0 {
+		return &modelVerificationError{errors.Join(errs...)}
+	}
+	return nil
+}
+
+// verifiedModels returns the verified models whose digests are checked by the
+// pods of the kepler-internal; none if model verification is not configured
+func verifiedModels(ki *v1alpha1.KeplerInternal) []v1alpha1.VerifiedModel {
+	if ki.Spec.ModelVerification == nil {
+		return nil
+	}
+	return ki.Status.VerifiedModels
+}
+
+// modelsVerified passes updates of the kepler-internal that change the digests
+// of its verified models so that the models are deployed once verified
+var modelsVerified = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		prev, ok := e.ObjectOld.(*v1alpha1.KeplerInternal)
+		if !ok {
+			return false
+		}
+		cur, ok := e.ObjectNew.(*v1alpha1.KeplerInternal)
+		return ok && !equality.Semantic.DeepEqual(prev.Status.VerifiedModels, cur.Status.VerifiedModels)
+	},
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/features"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/sigstore"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	s := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(s))
+	assert.NoError(t, v1alpha1.AddToScheme(s))
+	return s
+}
+
+// newModelServer serves the model archives which are signed with key if the
+// key is set
+func newModelServer(t *testing.T, key *ecdsa.PrivateKey, archives map[string]string) *httptest.Server {
+	mux := http.NewServeMux()
+	for path, data := range archives {
+		data := []byte(data)
+		mux.HandleFunc(path, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write(data)
+		})
+		if key == nil {
+			continue
+		}
+		digest := sha256.Sum256(data)
+		sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+		assert.NoError(t, err)
+		mux.HandleFunc(path+".sig", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(base64.StdEncoding.EncodeToString(sig)))
+		})
+	}
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newVerificationSecret(t *testing.T, key *ecdsa.PrivateKey) *corev1.Secret {
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "cosign", Namespace: "kepler"},
+		Data: map[string][]byte{
+			cosignPublicKey: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}),
+		},
+	}
+}
+
+func newVerifiedInternal(pipelineURL string, verified ...v1alpha1.VerifiedModel) *v1alpha1.KeplerInternal {
+	return &v1alpha1.KeplerInternal{
+		ObjectMeta: metav1.ObjectMeta{Name: "kepler-internal"},
+		Spec: v1alpha1.KeplerInternalSpec{
+			Exporter: v1alpha1.InternalExporterSpec{
+				Deployment: v1alpha1.InternalExporterDeploymentSpec{Namespace: "kepler"},
+			},
+			ModelServer: &v1alpha1.InternalModelServerSpec{
+				Enabled:     true,
+				PipelineURL: pipelineURL,
+			},
+			ModelVerification: &v1alpha1.ModelVerificationSpec{SecretRef: "cosign"},
+		},
+		Status: v1alpha1.KeplerInternalStatus{VerifiedModels: verified},
+	}
+}
+
+func TestModelVerification(t *testing.T) {
+	t.Cleanup(featuregatetesting.SetFeatureGateDuringTest(t, features.Gate, features.ModelServer, true))
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	signed := newModelServer(t, key, map[string]string{"/pipeline.zip": "pipeline"})
+	unsigned := newModelServer(t, nil, map[string]string{"/pipeline.zip": "tampered"})
+
+	digest := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte("pipeline")))
+	tt := []struct {
+		url      string
+		verified []v1alpha1.VerifiedModel
+		expected []v1alpha1.VerifiedModel
+		failed   bool
+		scenario string
+	}{
+		{
+			url:      signed.URL + "/pipeline.zip",
+			expected: []v1alpha1.VerifiedModel{{URL: signed.URL + "/pipeline.zip", Digest: digest}},
+			scenario: "signed",
+		},
+		{
+			url:      unsigned.URL + "/pipeline.zip",
+			failed:   true,
+			scenario: "unsigned",
+		},
+		{
+			url:      unsigned.URL + "/pipeline.zip",
+			verified: []v1alpha1.VerifiedModel{{URL: unsigned.URL + "/pipeline.zip", Digest: digest}},
+			expected: []v1alpha1.VerifiedModel{{URL: unsigned.URL + "/pipeline.zip", Digest: digest}},
+			failed:   true,
+			scenario: "replaced since verified",
+		},
+		{
+			url:      signed.URL + "/pipeline.zip",
+			verified: []v1alpha1.VerifiedModel{{URL: signed.URL + "/other.zip", Digest: digest}},
+			expected: []v1alpha1.VerifiedModel{{URL: signed.URL + "/pipeline.zip", Digest: digest}},
+			scenario: "url changed",
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			ki := newVerifiedInternal(tc.url, tc.verified...)
+			c := fake.NewClientBuilder().WithScheme(newScheme(t)).
+				WithObjects(ki, newVerificationSecret(t, key)).
+				WithStatusSubresource(ki).
+				Build()
+			recorder := record.NewFakeRecorder(10)
+			r := ModelVerificationReconciler{Client: c, Recorder: recorder}
+
+			result, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(ki)})
+			assert.NoError(t, err)
+			assert.Equal(t, defaultModelVerificationInterval, result.RequeueAfter)
+			assert.Equal(t, tc.failed, len(recorder.Events) != 0)
+
+			actual := v1alpha1.KeplerInternal{}
+			assert.NoError(t, c.Get(context.TODO(), client.ObjectKeyFromObject(ki), &actual))
+			assert.Equal(t, tc.expected, actual.Status.VerifiedModels)
+			assert.Equal(t, tc.expected == nil, isModelVerificationError(checkModelsVerified(&actual)))
+		})
+	}
+}
+
+func TestModelVerificationDisabled(t *testing.T) {
+	ki := newVerifiedInternal("https://models/pipeline.zip",
+		v1alpha1.VerifiedModel{URL: "https://models/pipeline.zip", Digest: "sha256:00"})
+	ki.Spec.ModelVerification = nil
+	c := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(ki).WithStatusSubresource(ki).Build()
+	r := ModelVerificationReconciler{Client: c, Recorder: record.NewFakeRecorder(10), Verifier: sigstore.Verifier{}}
+
+	result, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(ki)})
+	assert.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+
+	actual := v1alpha1.KeplerInternal{}
+	assert.NoError(t, c.Get(context.TODO(), client.ObjectKeyFromObject(ki), &actual))
+	assert.Empty(t, actual.Status.VerifiedModels)
+	assert.Empty(t, verifiedModels(&actual))
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// NodePowerAnnotation is the smoothed power of a node in watts
+	NodePowerAnnotation = "kepler.system.sustainable.computing.io/power-watts"
+
+	// NodePeakPowerAnnotation is the peak of the 5m power of a node, in
+	// watts, over the peak window
+	NodePeakPowerAnnotation = "kepler.system.sustainable.computing.io/peak-power-watts"
+
+	// NodePowerUpdatedAnnotation is the time the power annotations were last
+	// updated at
+	NodePowerUpdatedAnnotation = "kepler.system.sustainable.computing.io/power-updated-at"
+
+	// nodePowerQuery returns the power of each node exporting kepler metrics;
+	// kepler metrics are labelled with the node name as instance
+	nodePowerQuery = `sum by (instance) (rate(kepler_node_platform_joules_total[%s]))`
+
+	// nodePeakPowerQuery returns the peak of the 5m power of each node over
+	// the peak window
+	nodePeakPowerQuery = `max_over_time((sum by (instance) (rate(kepler_node_platform_joules_total[5m])))[%s:5m])`
+
+	// minPowerRange is the shortest range of the power query which must cover
+	// at least two scrapes
+	minPowerRange = time.Minute
+)
+
+// RBAC for publishing the power of nodes as annotations
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=patch
+
+// NodePowerAnnotator periodically annotates the nodes with their power and
+// peak power read from Prometheus so that power aware schedulers can read them
+// from the nodes. The power is smoothed with an exponential moving average
+// kept in memory; it restarts from the latest power when the operator
+// restarts.
+type NodePowerAnnotator struct {
+	Client     client.Client
+	Prometheus *PrometheusClient
+
+	// Interval between updates
+	Interval time.Duration
+
+	// MinChange is the change, in watts, of the power or peak power of a node
+	// below which its annotations are not updated so that the nodes, which
+	// every component in the cluster watches, are not patched every interval
+	MinChange float64
+
+	// Smoothing is the weight of the latest power in the moving average; 1
+	// disables smoothing
+	Smoothing float64
+
+	// PeakWindow is the window over which the peak power of a node is taken
+	// as the power it can draw
+	PeakWindow time.Duration
+
+	smoothed  map[string]float64
+	published map[string]nodePower
+}
+
+// nodePower is the power of a node published in its annotations
+type nodePower struct {
+	watts float64
+	peak  *float64
+}
+
+// changed returns true if the power differs from p by more than minChange or
+// the peak power is published or dropped
+func (p nodePower) changed(prev nodePower, minChange float64) bool {
+	if math.Abs(p.watts-prev.watts) > minChange || (p.peak == nil) != (prev.peak == nil) {
+		return true
+	}
+	return p.peak != nil && math.Abs(*p.peak-*prev.peak) > minChange
+}
+
+// Validate returns an error if the settings of the annotator are invalid
+func (a *NodePowerAnnotator) Validate() error {
+	if err := a.Prometheus.Validate(); err != nil {
+		return err
+	}
+	if a.Interval <= 0 {
+		return fmt.Errorf("interval %s must be positive", a.Interval)
+	}
+	if a.MinChange < 0 {
+		return fmt.Errorf("min change %v must not be negative", a.MinChange)
+	}
+	if a.Smoothing <= 0 || a.Smoothing > 1 {
+		return fmt.Errorf("smoothing %v must be in (0, 1]", a.Smoothing)
+	}
+	if a.PeakWindow < 5*time.Minute {
+		return fmt.Errorf("peak window %s must be at least 5m", a.PeakWindow)
+	}
+	return nil
+}
+
+// Start updates the annotations every interval until ctx is done
+func (a *NodePowerAnnotator) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("node-power")
+	ticker := time.NewTicker(a.Interval)
+	defer ticker.Stop()
+	for {
+		if err := a.update(ctx, time.Now()); err != nil {
+			logger.Error(err, "failed to update node power annotations")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection returns true so that the nodes are annotated by the
+// leader only
+func (a *NodePowerAnnotator) NeedLeaderElection() bool {
+	return true
+}
+
+// update queries the power of the nodes at now and patches their annotations
+func (a *NodePowerAnnotator) update(ctx context.Context, now time.Time) error {
+	logger := log.FromContext(ctx).WithName("node-power")
+
+	q, err := a.Prometheus.Querier()
+	if err != nil {
+		return err
+	}
+
+	rng := a.Interval
+	if rng < minPowerRange {
+		rng = minPowerRange
+	}
+	power, err := q.Vector(ctx, fmt.Sprintf(nodePowerQuery, promDuration(rng)), "instance", now)
+	if err != nil {
+		return fmt.Errorf("power query failed: %w", err)
+	}
+	peak, err := q.Vector(ctx, fmt.Sprintf(nodePeakPowerQuery, promDuration(a.PeakWindow)), "instance", now)
+	if err != nil {
+		return fmt.Errorf("peak power query failed: %w", err)
+	}
+
+	smoothed := make(map[string]float64, len(power))
+	published := make(map[string]nodePower, len(power))
+	for node, watts := range power {
+		if prev, ok := a.smoothed[node]; ok {
+			watts = a.Smoothing*watts + (1-a.Smoothing)*prev
+		}
+		smoothed[node] = watts
+
+		current := nodePower{watts: watts}
+		if p, ok := peak[node]; ok {
+			current.peak = &p
+		}
+		if prev, ok := a.published[node]; ok && !current.changed(prev, a.MinChange) {
+			published[node] = prev
+			continue
+		}
+
+		annotations := map[string]*string{
+			NodePowerAnnotation:        ptr.To(formatWatts(watts)),
+			NodePowerUpdatedAnnotation: ptr.To(now.UTC().Format(time.RFC3339)),
+			// NOTE: a nil value removes the annotation in a merge patch
+			NodePeakPowerAnnotation: nil,
+		}
+		if current.peak != nil {
+			annotations[NodePeakPowerAnnotation] = ptr.To(formatWatts(*current.peak))
+		}
+		if err := a.annotate(ctx, node, annotations); err != nil {
+			logger.Error(err, "failed to annotate node", "node", node)
+			continue
+		}
+		published[node] = current
+	}
+	// NOTE: nodes that no longer report power are forgotten
+	a.smoothed = smoothed
+	a.published = published
+	return nil
+}
+
+// annotate merges the annotations into the node; nodes that were deleted
+// are skipped
+func (a *NodePowerAnnotator) annotate(ctx context.Context, name string, annotations map[string]*string) error {
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{"annotations": annotations},
+	})
+	if err != nil {
+		return err
+	}
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	err = a.Client.Patch(ctx, node, client.RawPatch(types.MergePatchType, patch))
+	return client.IgnoreNotFound(err)
+}
+
+// promDuration formats d in seconds as Prometheus durations do not support
+// fractions
+func promDuration(d time.Duration) string {
+	return strconv.FormatInt(int64(d/time.Second), 10) + "s"
+}
+
+func formatWatts(w float64) string {
+	return strconv.FormatFloat(w, 'f', 1, 64)
+}
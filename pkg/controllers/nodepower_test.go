@@ -0,0 +1,140 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newPrometheus serves the value returned by values for each query as the
+// value of the series of each node
+func newPrometheus(t *testing.T, values func(query string) map[string]float64) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result := []string{}
+		for node, v := range values(r.URL.Query().Get("query")) {
+			result = append(result, fmt.Sprintf(`{"metric":{"instance":%q},"value":[0,"%v"]}`, node, v))
+		}
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":[%s]}}`, strings.Join(result, ","))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestNodePowerAnnotator(t *testing.T) {
+	power, peak := 100.0, 150.0
+	srv := newPrometheus(t, func(query string) map[string]float64 {
+		if strings.HasPrefix(query, "max_over_time") {
+			return map[string]float64{"node-a": peak}
+		}
+		// NOTE: node-b is not in the cluster and is skipped
+		return map[string]float64{"node-a": power, "node-b": power}
+	})
+
+	c := fake.NewClientBuilder().WithScheme(newScheme(t)).
+		WithObjects(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}}).
+		Build()
+	a := &NodePowerAnnotator{
+		Client:     c,
+		Prometheus: &PrometheusClient{URL: srv.URL},
+		Interval:   time.Minute,
+		MinChange:  5,
+		Smoothing:  1,
+		PeakWindow: time.Hour,
+	}
+	assert.NoError(t, a.Validate())
+
+	annotations := func() map[string]string {
+		node := corev1.Node{}
+		assert.NoError(t, c.Get(context.Background(), client.ObjectKey{Name: "node-a"}, &node))
+		return node.Annotations
+	}
+
+	ctx := context.Background()
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, a.update(ctx, start))
+	assert.Equal(t, map[string]string{
+		NodePowerAnnotation:        "100.0",
+		NodePeakPowerAnnotation:    "150.0",
+		NodePowerUpdatedAnnotation: "2024-03-01T00:00:00Z",
+	}, annotations())
+
+	t.Run("small changes are not published", func(t *testing.T) {
+		power, peak = 104, 154
+		assert.NoError(t, a.update(ctx, start.Add(time.Minute)))
+		assert.Equal(t, "100.0", annotations()[NodePowerAnnotation])
+		assert.Equal(t, "2024-03-01T00:00:00Z", annotations()[NodePowerUpdatedAnnotation])
+	})
+
+	t.Run("changes are published", func(t *testing.T) {
+		power, peak = 120, 154
+		assert.NoError(t, a.update(ctx, start.Add(2*time.Minute)))
+		assert.Equal(t, "120.0", annotations()[NodePowerAnnotation])
+		assert.Equal(t, "154.0", annotations()[NodePeakPowerAnnotation])
+		assert.Equal(t, "2024-03-01T00:02:00Z", annotations()[NodePowerUpdatedAnnotation])
+	})
+
+	t.Run("power is smoothed", func(t *testing.T) {
+		a.Smoothing = 0.5
+		power = 140
+		assert.NoError(t, a.update(ctx, start.Add(3*time.Minute)))
+		assert.Equal(t, "130.0", annotations()[NodePowerAnnotation])
+	})
+}
+
+func TestPrometheusClient(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[]}}`)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	p := &PrometheusClient{
+		URL:       srv.URL + "/",
+		TokenFile: filepath.Join(dir, "token"),
+		CAFile:    filepath.Join(dir, "ca.crt"),
+	}
+	assert.NoError(t, os.WriteFile(p.TokenFile, []byte("secret\n"), 0o600))
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	assert.NoError(t, os.WriteFile(p.CAFile, ca, 0o600))
+
+	q, err := p.Querier()
+	assert.NoError(t, err)
+	assert.Equal(t, srv.URL, q.URL)
+	_, err = q.Vector(context.Background(), "up", "instance", time.Now())
+	assert.NoError(t, err)
+
+	again, err := p.Querier()
+	assert.NoError(t, err)
+	assert.Same(t, q.Client, again.Client)
+
+	assert.Error(t, (&PrometheusClient{}).Validate())
+}
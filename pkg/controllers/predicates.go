@@ -0,0 +1,197 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"reflect"
+
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components/kernel"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// specChanged filters out updates of the status of kepler and kepler-internal
+// objects which are made by the operator itself; annotations and labels are
+// included since they pause the reconcile
+var specChanged = predicate.Or(
+	predicate.GenerationChangedPredicate{},
+	predicate.AnnotationChangedPredicate{},
+	predicate.LabelChangedPredicate{},
+)
+
+// rolloutChanged filters out updates of the status of the workloads owned by
+// kepler-internal that do not change its status e.g. the observed generation
+// or collision count of a daemonset. The counters of ready pods are mirrored
+// in the status of kepler-internal, so updates of them pass through and
+// storms of them on large clusters are absorbed by the rate limiter. Updates
+// of the spec and metadata pass through so that drifts are detected.
+var rolloutChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		if specChanged.Update(e) {
+			return true
+		}
+		switch prev := e.ObjectOld.(type) {
+		case *appsv1.DaemonSet:
+			cur, ok := e.ObjectNew.(*appsv1.DaemonSet)
+			return !ok || daemonSetRollout(prev) != daemonSetRollout(cur)
+		case *appsv1.Deployment:
+			cur, ok := e.ObjectNew.(*appsv1.Deployment)
+			return !ok || deploymentRollout(prev) != deploymentRollout(cur)
+		}
+		return true
+	},
+}
+
+// rollout summarizes the status of a workload as reported in the status of
+// kepler-internal
+type rollout struct {
+	available   string
+	progressing string
+	updating    bool
+
+	// daemonset counters
+	numberMisscheduled     int32
+	currentNumberScheduled int32
+	desiredNumberScheduled int32
+	numberReady            int32
+	updatedNumberScheduled int32
+	numberAvailable        int32
+	numberUnavailable      int32
+
+	// deployment counters
+	replicas      int32
+	readyReplicas int32
+}
+
+// daemonSetRollout summarizes the status of the kepler daemonset by the
+// reasons of the conditions it sets on kepler-internal and the counters
+// mirrored in the exporter status
+func daemonSetRollout(ds *appsv1.DaemonSet) rollout {
+	s := ds.Status
+	return rollout{
+		available:              availableCondition(ds).Reason,
+		progressing:            progressingCondition(ds).Reason,
+		numberMisscheduled:     s.NumberMisscheduled,
+		currentNumberScheduled: s.CurrentNumberScheduled,
+		desiredNumberScheduled: s.DesiredNumberScheduled,
+		numberReady:            s.NumberReady,
+		updatedNumberScheduled: s.UpdatedNumberScheduled,
+		numberAvailable:        s.NumberAvailable,
+		numberUnavailable:      s.NumberUnavailable,
+	}
+}
+
+// deploymentRollout summarizes the status of a deployment by its progress and
+// the ready replicas mirrored in the model server status
+func deploymentRollout(deploy *appsv1.Deployment) rollout {
+	s := deploy.Status
+	return rollout{
+		updating:      deploy.Generation > s.ObservedGeneration || s.UpdatedReplicas < s.Replicas,
+		replicas:      s.Replicas,
+		readyReplicas: s.ReadyReplicas,
+	}
+}
+
+// nodeChanged filters out the updates of nodes that do not change the status
+// of kepler-internal e.g. heartbeats and the power annotations. The labels
+// select the nodes kepler runs on, the system info tells whether kepler can
+// run on a node and the annotations of the Machine Config Operator whether
+// the kernel prerequisites are met.
+var nodeChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		prev, ok := e.ObjectOld.(*corev1.Node)
+		if !ok {
+			return true
+		}
+		cur, ok := e.ObjectNew.(*corev1.Node)
+		if !ok {
+			return true
+		}
+
+		if !reflect.DeepEqual(prev.Labels, cur.Labels) ||
+			prev.Status.NodeInfo != cur.Status.NodeInfo ||
+			!prev.Status.Allocatable.Pods().Equal(*cur.Status.Allocatable.Pods()) {
+			return true
+		}
+		for _, key := range kernel.MachineConfigAnnotations {
+			if prev.Annotations[key] != cur.Annotations[key] {
+				return true
+			}
+		}
+		return false
+	},
+}
+
+// podChanged filters out the updates of pods that do not change the status of
+// kepler-internal, which reports the nodes pods fail on and whether the pods
+// of the kernel setup are ready
+var podChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		prev, ok := e.ObjectOld.(*corev1.Pod)
+		if !ok {
+			return true
+		}
+		cur, ok := e.ObjectNew.(*corev1.Pod)
+		if !ok {
+			return true
+		}
+		return !reflect.DeepEqual(newPodState(prev), newPodState(cur))
+	},
+}
+
+// podState summarizes the status of a pod as reported in the status of
+// kepler-internal
+type podState struct {
+	node       string
+	phase      corev1.PodPhase
+	ready      bool
+	containers []containerState
+}
+
+type containerState struct {
+	ready      bool
+	waiting    string
+	terminated string
+}
+
+func newPodState(pod *corev1.Pod) podState {
+	s := podState{node: pod.Spec.NodeName, phase: pod.Status.Phase}
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			s.ready = c.Status == corev1.ConditionTrue
+		}
+	}
+	for _, c := range pod.Status.InitContainerStatuses {
+		s.containers = append(s.containers, newContainerState(c))
+	}
+	for _, c := range pod.Status.ContainerStatuses {
+		s.containers = append(s.containers, newContainerState(c))
+	}
+	return s
+}
+
+func newContainerState(c corev1.ContainerStatus) containerState {
+	s := containerState{ready: c.Ready}
+	if c.State.Waiting != nil {
+		s.waiting = c.State.Waiting.Reason
+	}
+	if c.State.Terminated != nil {
+		s.terminated = c.State.Terminated.Reason
+	}
+	return s
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestRolloutChanged(t *testing.T) {
+	daemonSet := func() *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "kepler", Generation: 1, Labels: map[string]string{"app": "kepler"}},
+			Status: appsv1.DaemonSetStatus{
+				ObservedGeneration:     1,
+				DesiredNumberScheduled: 3,
+				CurrentNumberScheduled: 3,
+				UpdatedNumberScheduled: 3,
+				NumberReady:            3,
+				NumberAvailable:        3,
+			},
+		}
+	}
+	deployment := func() *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "model-server", Generation: 1},
+			Status: appsv1.DeploymentStatus{
+				ObservedGeneration: 1,
+				Replicas:           1,
+				UpdatedReplicas:    1,
+				ReadyReplicas:      1,
+			},
+		}
+	}
+
+	tt := []struct {
+		scenario string
+		old      client.Object
+		new      func() client.Object
+		changed  bool
+	}{
+		{
+			"daemonset unchanged",
+			daemonSet(),
+			func() client.Object { return daemonSet() },
+			false,
+		},
+		{
+			"daemonset collision count",
+			daemonSet(),
+			func() client.Object {
+				ds := daemonSet()
+				count := int32(1)
+				ds.Status.CollisionCount = &count
+				return ds
+			},
+			false,
+		},
+		{
+			"daemonset ready pods",
+			daemonSet(),
+			func() client.Object {
+				ds := daemonSet()
+				ds.Status.NumberReady, ds.Status.NumberAvailable, ds.Status.NumberUnavailable = 2, 2, 1
+				return ds
+			},
+			true,
+		},
+		{
+			"daemonset desired pods",
+			daemonSet(),
+			func() client.Object {
+				ds := daemonSet()
+				ds.Status.DesiredNumberScheduled = 4
+				return ds
+			},
+			true,
+		},
+		{
+			"daemonset labels",
+			daemonSet(),
+			func() client.Object {
+				ds := daemonSet()
+				ds.Labels["app"] = "other"
+				return ds
+			},
+			true,
+		},
+		{
+			"deployment observed generation",
+			deployment(),
+			func() client.Object {
+				d := deployment()
+				d.Status.ObservedGeneration = 2
+				return d
+			},
+			false,
+		},
+		{
+			"deployment ready replicas",
+			deployment(),
+			func() client.Object {
+				d := deployment()
+				d.Status.ReadyReplicas = 0
+				return d
+			},
+			true,
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			e := event.UpdateEvent{ObjectOld: tc.old, ObjectNew: tc.new()}
+			assert.Equal(t, tc.changed, rolloutChanged.Update(e))
+		})
+	}
+}
+
+func TestNodeChanged(t *testing.T) {
+	node := func() *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "worker-0",
+				Labels: map[string]string{"node-role.kubernetes.io/worker": ""},
+				Annotations: map[string]string{
+					"machineconfiguration.openshift.io/state": "Done",
+				},
+			},
+			Status: corev1.NodeStatus{
+				NodeInfo:    corev1.NodeSystemInfo{KernelVersion: "5.14.0", Architecture: "amd64"},
+				Allocatable: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("110")},
+			},
+		}
+	}
+
+	tt := []struct {
+		scenario string
+		update   func(*corev1.Node)
+		changed  bool
+	}{
+		{"heartbeat", func(n *corev1.Node) {
+			n.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, LastHeartbeatTime: metav1.Now()}}
+		}, false},
+		{"power annotation", func(n *corev1.Node) {
+			n.Annotations["kepler.system.sustainable.computing.io/power-watts"] = "120"
+		}, false},
+		{"labels", func(n *corev1.Node) { n.Labels["kepler"] = "true" }, true},
+		{"kernel", func(n *corev1.Node) { n.Status.NodeInfo.KernelVersion = "4.18.0" }, true},
+		{"allocatable pods", func(n *corev1.Node) {
+			n.Status.Allocatable[corev1.ResourcePods] = resource.MustParse("250")
+		}, true},
+		{"machine config state", func(n *corev1.Node) {
+			n.Annotations["machineconfiguration.openshift.io/state"] = "Working"
+		}, true},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			cur := node()
+			tc.update(cur)
+			e := event.UpdateEvent{ObjectOld: node(), ObjectNew: cur}
+			assert.Equal(t, tc.changed, nodeChanged.Update(e))
+		})
+	}
+}
+
+func TestPodChanged(t *testing.T) {
+	pod := func() *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "kepler-abcde", Namespace: "kepler-operator"},
+			Spec:       corev1.PodSpec{NodeName: "worker-0"},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  "kepler",
+					Ready: true,
+					State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+				}},
+			},
+		}
+	}
+
+	tt := []struct {
+		scenario string
+		update   func(*corev1.Pod)
+		changed  bool
+	}{
+		{"restart count", func(p *corev1.Pod) { p.Status.ContainerStatuses[0].RestartCount = 1 }, false},
+		{"crash loop", func(p *corev1.Pod) {
+			p.Status.ContainerStatuses[0].Ready = false
+			p.Status.ContainerStatuses[0].State = corev1.ContainerState{
+				Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+			}
+		}, true},
+		{"failed", func(p *corev1.Pod) { p.Status.Phase = corev1.PodFailed }, true},
+		{"not ready", func(p *corev1.Pod) { p.Status.Conditions[0].Status = corev1.ConditionFalse }, true},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			cur := pod()
+			tc.update(cur)
+			e := event.UpdateEvent{ObjectOld: pod(), ObjectNew: cur}
+			assert.Equal(t, tc.changed, podChanged.Update(e))
+		})
+	}
+}
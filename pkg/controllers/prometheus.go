@@ -0,0 +1,89 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/report"
+)
+
+// PrometheusClient configures the Prometheus compatible API the power of the
+// nodes is read from by the operator
+type PrometheusClient struct {
+	URL string
+
+	// TokenFile, if set, is read before every query and sent as bearer token
+	// so that rotated service account tokens are picked up
+	TokenFile string
+
+	// CAFile, if set, is trusted in addition to the system CAs, e.g. the
+	// service CA of OpenShift which signs the certificate of thanos-querier
+	CAFile string
+
+	mu     sync.Mutex
+	ca     []byte
+	client *http.Client
+}
+
+// Validate returns an error if the settings of the client are invalid
+func (p *PrometheusClient) Validate() error {
+	if p.URL == "" {
+		return fmt.Errorf("prometheus url is required")
+	}
+	return nil
+}
+
+// Querier returns the querier of the API with the current token and CA
+func (p *PrometheusClient) Querier() (report.Querier, error) {
+	q := report.Querier{URL: strings.TrimSuffix(p.URL, "/")}
+	if p.TokenFile != "" {
+		token, err := os.ReadFile(p.TokenFile)
+		if err != nil {
+			return q, fmt.Errorf("failed to read token: %w", err)
+		}
+		q.Token = strings.TrimSpace(string(token))
+	}
+	if p.CAFile == "" {
+		return q, nil
+	}
+
+	// NOTE: the client is kept until the CA is rotated so that connections
+	// are reused between queries
+	ca, err := os.ReadFile(p.CAFile)
+	if err != nil {
+		return q, fmt.Errorf("failed to read CA: %w", err)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.client == nil || !bytes.Equal(ca, p.ca) {
+		client, err := report.NewClient(p.CAFile)
+		if err != nil {
+			return q, err
+		}
+		if p.client != nil {
+			p.client.CloseIdleConnections()
+		}
+		p.ca, p.client = ca, client
+	}
+	q.Client = p.client
+	return q, nil
+}
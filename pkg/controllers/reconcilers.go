@@ -16,7 +16,12 @@ limitations under the License.
 package controllers
 
 import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/sustainable.computing.io/kepler-operator/pkg/reconciler"
@@ -25,11 +30,40 @@ import (
 func resourceReconcilers(fn reconcileFn, resources ...client.Object) []reconciler.Reconciler {
 	rs := []reconciler.Reconciler{}
 	for _, res := range resources {
+		if gvk := res.GetObjectKind().GroupVersionKind(); !apiServed(gvk.Group) {
+			rs = append(rs, apiNotServed{Reconciler: fn(res), gvk: gvk})
+			continue
+		}
 		rs = append(rs, fn(res))
 	}
 	return rs
 }
 
+// apiServed returns true if the cluster serves the api group; all groups are
+// assumed to be served until they have been discovered
+func apiServed(group string) bool {
+	groups := Config.APIGroups
+	return group == "" || !groups.Discovered() || groups.Has(group)
+}
+
+// apiNotServed reconciles resources whose api is not served by the cluster
+// without sending any request; deleting them is a no-op and applying them
+// fails as it would if the request was sent
+type apiNotServed struct {
+	reconciler.Reconciler
+	gvk schema.GroupVersionKind
+}
+
+func (r apiNotServed) Reconcile(context.Context, client.Client, *runtime.Scheme) reconciler.Result {
+	if _, ok := r.Reconciler.(*reconciler.Deleter); ok {
+		return reconciler.Result{}
+	}
+	return reconciler.Result{
+		Action: reconciler.Continue,
+		Error:  &meta.NoKindMatchError{GroupKind: r.gvk.GroupKind(), SearchedVersions: []string{r.gvk.Version}},
+	}
+}
+
 // TODO: decide if this this should move to reconciler
 type reconcileFn func(client.Object) reconciler.Reconciler
 
@@ -41,7 +75,15 @@ func newUpdaterWithOwner(owner metav1.Object) reconcileFn {
 	}
 }
 
-// deleteResource is a resourceFn that deletes resources
+// deleteResource is a resourceFn that deletes resources; reconciliation is
+// requeued if the deletion fails so that finalizers that follow are removed
+// only after all resources have been deleted
 func deleteResource(obj client.Object) reconciler.Reconciler {
-	return &reconciler.Deleter{Resource: obj}
+	return &reconciler.Deleter{Resource: obj, OnError: reconciler.Requeue}
+}
+
+// parallel returns a reconciler that runs rs concurrently; rs must not
+// depend on each other
+func parallel(rs ...reconciler.Reconciler) reconciler.Reconciler {
+	return reconciler.Parallel{Reconcilers: rs}
 }
@@ -0,0 +1,108 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"github.com/go-logr/logr"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components/exporter"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/reconciler"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Render returns the kepler-internal created for the kepler along with all
+// the resources created for the kepler-internal
+func Render(k *v1alpha1.Kepler) ([]client.Object, error) {
+	ki := newKeplerInternal(components.Full, k)
+	resources, err := RenderInternal(ki)
+	if err != nil {
+		return nil, err
+	}
+	return append([]client.Object{ki}, resources...), nil
+}
+
+// RenderInternal returns the resources the operator creates for the
+// kepler-internal; the resources are those applied by the reconcilers of the
+// kepler-internal. Unlike the reconcilers, it does not read anything from the
+// cluster, so the redfish secret is only referenced by name and the CA of
+// the serving certificate is left out.
+func RenderInternal(ki *v1alpha1.KeplerInternal) ([]client.Object, error) {
+	r := KeplerInternalReconciler{logger: logr.Discard()}
+	reconcilers, err := r.reconcilersForInternal(ki.DeepCopy())
+	if err != nil {
+		return nil, err
+	}
+	return renderReconcilers(reconcilers)
+}
+
+// renderReconcilers returns the resources the reconcilers apply; reconcilers
+// that only delete resources or whose resources are generated by the cluster
+// are left out
+func renderReconcilers(rs []reconciler.Reconciler) ([]client.Object, error) {
+	resources := []client.Object{}
+	for _, rec := range rs {
+		switch r := rec.(type) {
+		case apiNotServed:
+			res, err := renderReconcilers([]reconciler.Reconciler{r.Reconciler})
+			if err != nil {
+				return nil, err
+			}
+			resources = append(resources, res...)
+
+		case reconciler.Parallel:
+			res, err := renderReconcilers(r.Reconcilers)
+			if err != nil {
+				return nil, err
+			}
+			resources = append(resources, res...)
+
+		case *reconciler.Updater:
+			resources = append(resources, r.Resource)
+		case reconciler.Updater:
+			resources = append(resources, r.Resource)
+		case reconciler.SecretRollout:
+			resources = append(resources, r.Resource)
+
+		case reconciler.CABundleUpdater:
+			res, err := r.Resource(nil)
+			if err != nil {
+				return nil, err
+			}
+			resources = append(resources, res)
+
+		case reconciler.KeplerConfigMapReconciler:
+			exporter.MountRedfishConfigToConfigMap(r.Cfm, r.Ki.Spec.Exporter.Redfish)
+			resources = append(resources, r.Cfm)
+
+		case reconciler.KeplerReconciler:
+			rf := r.Ki.Spec.Exporter.Redfish
+			hash, err := exporter.RedfishSpecHash(rf)
+			if err != nil {
+				return nil, err
+			}
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: rf.SecretRef, Namespace: r.Ki.Namespace()}}
+			exporter.MountRedfishSecretToDaemonSet(r.Ds, secret, hash)
+			resources = append(resources, r.Ds)
+
+		case reconciler.Mutator:
+			resources = append(resources, r.Resource)
+		}
+	}
+	return resources, nil
+}
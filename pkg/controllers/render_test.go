@@ -0,0 +1,160 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/components"
+	appsv1 "k8s.io/api/apps/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func renderedNames(objs []client.Object) []string {
+	names := []string{}
+	for _, obj := range objs {
+		names = append(names, fmt.Sprintf("%T %s", obj, client.ObjectKeyFromObject(obj)))
+	}
+	return names
+}
+
+func TestRenderInternal(t *testing.T) {
+	exporterResources := []string{
+		"*v1.Namespace /kepler-operator",
+		"*v1.ClusterRole /kepler",
+		"*v1.ClusterRoleBinding /kepler",
+		"*v1.ServiceAccount kepler-operator/kepler",
+		"*v1.Service kepler-operator/kepler",
+		"*v1.ServiceMonitor kepler-operator/kepler",
+		"*v1.PrometheusRule kepler-operator/kepler",
+		"*v1.ConfigMap kepler-operator/kepler",
+		"*v1.DaemonSet kepler-operator/kepler",
+	}
+
+	tt := []struct {
+		scenario string
+		spec     func(*v1alpha1.KeplerInternal)
+		rendered []string
+		err      bool
+	}{
+		{
+			scenario: "defaults",
+			spec:     func(*v1alpha1.KeplerInternal) {},
+			rendered: exporterResources,
+		},
+		{
+			scenario: "energy report",
+			spec: func(ki *v1alpha1.KeplerInternal) {
+				ki.Spec.EnergyReport = &v1alpha1.InternalEnergyReportSpec{EnergyReportSpec: v1alpha1.EnergyReportSpec{Enabled: true}}
+			},
+			rendered: append(exporterResources[:len(exporterResources):len(exporterResources)],
+				"*v1.ServiceAccount kepler-operator/kepler-energy-report",
+				"*v1.Role kepler-operator/kepler-energy-report",
+				"*v1.RoleBinding kepler-operator/kepler-energy-report",
+				"*v1.CronJob kepler-operator/kepler-energy-report",
+			),
+		},
+		{
+			scenario: "invalid spec",
+			spec: func(ki *v1alpha1.KeplerInternal) {
+				ki.Spec.Exporter.ElectricityPrice = &v1alpha1.ElectricityPriceSpec{Enabled: true, Currency: "EUR"}
+			},
+			err: true,
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			ki := newKeplerInternal(components.Full, &v1alpha1.Kepler{ObjectMeta: metav1.ObjectMeta{Name: "kepler"}})
+			tc.spec(ki)
+			spec := ki.Spec.DeepCopy()
+
+			objs, err := RenderInternal(ki)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.rendered, renderedNames(objs))
+			assert.Equal(t, spec, &ki.Spec, "spec must not be changed")
+		})
+	}
+}
+
+func TestRenderInternalRedfish(t *testing.T) {
+	ki := newKeplerInternal(components.Full, &v1alpha1.Kepler{ObjectMeta: metav1.ObjectMeta{Name: "kepler"}})
+	ki.Spec.Exporter.Redfish = &v1alpha1.RedfishSpec{SecretRef: "redfish-creds"}
+
+	objs, err := RenderInternal(ki)
+	assert.NoError(t, err)
+
+	var ds *appsv1.DaemonSet
+	for _, obj := range objs {
+		if d, ok := obj.(*appsv1.DaemonSet); ok {
+			ds = d
+		}
+	}
+	if assert.NotNil(t, ds) {
+		secrets := []string{}
+		for _, v := range ds.Spec.Template.Spec.Volumes {
+			if v.Secret != nil {
+				secrets = append(secrets, v.Secret.SecretName)
+			}
+		}
+		assert.Contains(t, secrets, "redfish-creds")
+	}
+}
+
+func TestRenderNamespace(t *testing.T) {
+	k := &v1alpha1.Kepler{ObjectMeta: metav1.ObjectMeta{Name: "kepler"}}
+	k.Spec.Namespace = "power-monitoring"
+
+	objs, err := Render(k)
+	assert.NoError(t, err)
+	assert.Contains(t, renderedNames(objs), "*v1.Namespace /power-monitoring")
+	for _, obj := range objs {
+		switch o := obj.(type) {
+		case *rbacv1.ClusterRoleBinding:
+			assert.Equal(t, "power-monitoring", o.Subjects[0].Namespace)
+		default:
+			if obj.GetNamespace() != "" {
+				assert.Equal(t, "power-monitoring", obj.GetNamespace(), renderedNames([]client.Object{obj}))
+			}
+		}
+	}
+}
+
+func TestValidateNamespace(t *testing.T) {
+	watched := Config.WatchNamespaces
+	t.Cleanup(func() { Config.WatchNamespaces = watched })
+	Config.WatchNamespaces = []string{"power-monitoring"}
+
+	k := &v1alpha1.Kepler{ObjectMeta: metav1.ObjectMeta{Name: "kepler"}}
+	assert.NoError(t, validateNamespace(k))
+	assert.Equal(t, KeplerDeploymentNS, deploymentNamespace(k))
+
+	k.Spec.Namespace = "power-monitoring"
+	assert.NoError(t, validateNamespace(k))
+
+	k.Spec.Namespace = "default"
+	assert.ErrorContains(t, validateNamespace(k), `namespace "default" is not watched`)
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// defaultResyncInterval is the interval at which resyncs are moved to the
+// queue of the controller if none is configured
+const defaultResyncInterval = 100 * time.Millisecond
+
+// isResync passes only the updates sent by the informers on every sync
+// period; these carry the same object as both the old and new object
+var isResync = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return e.ObjectOld.GetResourceVersion() == e.ObjectNew.GetResourceVersion()
+	},
+}
+
+// resyncHandler enqueues the objects of periodic resyncs in a second, lower
+// priority tier so that a resync of all objects does not hold up the
+// reconcile of a spec change or a new object. The resyncs are moved to the
+// queue of the controller one at a time and only while the queue holds no
+// changes made by users.
+type resyncHandler struct {
+	resyncs  *resyncQueue
+	interval time.Duration
+	start    *sync.Once
+}
+
+var _ handler.EventHandler = resyncHandler{}
+
+func newResyncHandler(interval time.Duration) resyncHandler {
+	if interval <= 0 {
+		interval = defaultResyncInterval
+	}
+	return resyncHandler{resyncs: newResyncQueue(), interval: interval, start: &sync.Once{}}
+}
+
+func (h resyncHandler) Create(ctx context.Context, e event.CreateEvent, q workqueue.RateLimitingInterface) {
+}
+
+func (h resyncHandler) Delete(ctx context.Context, e event.DeleteEvent, q workqueue.RateLimitingInterface) {
+}
+
+func (h resyncHandler) Generic(ctx context.Context, e event.GenericEvent, q workqueue.RateLimitingInterface) {
+}
+
+func (h resyncHandler) Update(ctx context.Context, e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	if e.ObjectNew == nil {
+		return
+	}
+	h.resyncs.add(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(e.ObjectNew)})
+
+	// NOTE: the context is the one of the controller, which lives as long as
+	// the queue of the controller does
+	h.start.Do(func() {
+		go wait.UntilWithContext(ctx, func(context.Context) {
+			if !q.ShuttingDown() {
+				h.resyncs.promote(q)
+			}
+		}, h.interval)
+	})
+}
+
+// resyncQueue holds the resyncs in the order they were added; an object is
+// held only once
+type resyncQueue struct {
+	mu      sync.Mutex
+	pending []reconcile.Request
+	held    map[reconcile.Request]struct{}
+}
+
+func newResyncQueue() *resyncQueue {
+	return &resyncQueue{held: map[reconcile.Request]struct{}{}}
+}
+
+func (r *resyncQueue) add(req reconcile.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.held[req]; ok {
+		return
+	}
+	r.held[req] = struct{}{}
+	r.pending = append(r.pending, req)
+}
+
+// promote moves the oldest resync to q if q has nothing queued and returns
+// true if a resync was moved
+func (r *resyncQueue) promote(q workqueue.Interface) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.pending) == 0 || q.Len() > 0 {
+		return false
+	}
+	req := r.pending[0]
+	r.pending = r.pending[1:]
+	delete(r.held, req)
+	q.Add(req)
+	return true
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func request(name string) reconcile.Request {
+	return reconcile.Request{NamespacedName: types.NamespacedName{Name: name}}
+}
+
+func TestResyncQueue(t *testing.T) {
+	q := workqueue.New()
+	defer q.ShutDown()
+
+	resyncs := newResyncQueue()
+	resyncs.add(request("a"))
+	resyncs.add(request("b"))
+	resyncs.add(request("a"))
+
+	// a change made by a user is reconciled ahead of the resyncs
+	q.Add(request("c"))
+	assert.False(t, resyncs.promote(q))
+
+	item, _ := q.Get()
+	assert.Equal(t, request("c"), item)
+	q.Done(item)
+
+	// resyncs are moved one at a time in the order they were added
+	assert.True(t, resyncs.promote(q))
+	assert.False(t, resyncs.promote(q))
+	item, _ = q.Get()
+	assert.Equal(t, request("a"), item)
+	q.Done(item)
+
+	assert.True(t, resyncs.promote(q))
+	item, _ = q.Get()
+	assert.Equal(t, request("b"), item)
+	q.Done(item)
+
+	assert.False(t, resyncs.promote(q), "an object is held once")
+}
+
+func TestResyncHandler(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer q.ShutDown()
+
+	h := newResyncHandler(time.Millisecond)
+	ki := &v1alpha1.KeplerInternal{ObjectMeta: metav1.ObjectMeta{Name: "kepler-internal", ResourceVersion: "1"}}
+	h.Update(ctx, event.UpdateEvent{ObjectOld: ki, ObjectNew: ki}, q)
+
+	assert.Eventually(t, func() bool { return q.Len() == 1 }, time.Second, time.Millisecond)
+	item, _ := q.Get()
+	assert.Equal(t, request("kepler-internal"), item)
+	q.Done(item)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// ShardLabel assigns a Kepler or KeplerInternal to a shard explicitly;
+// objects without the label are assigned to a shard by the hash of their name
+const ShardLabel = "kepler.system.sustainable.computing.io/shard"
+
+// Shard is the partition of Kepler and KeplerInternal objects reconciled by
+// a replica of the operator. The zero value and a shard of count 1 reconcile
+// all objects.
+type Shard struct {
+	Index int
+	Count int
+}
+
+// Validate returns an error if the index is not within the count
+func (s Shard) Validate() error {
+	if s.Count < 1 || s.Index < 0 || s.Index >= s.Count {
+		return fmt.Errorf("shard index %d must be in the range [0, %d) and count must be at least 1", s.Index, s.Count)
+	}
+	return nil
+}
+
+// IsSharded returns true if objects are partitioned across replicas
+func (s Shard) IsSharded() bool {
+	return s.Count > 1
+}
+
+// Owns returns true if obj is reconciled by this shard
+func (s Shard) Owns(obj client.Object) bool {
+	if !s.IsSharded() {
+		return true
+	}
+	return s.of(obj) == s.Index
+}
+
+// of returns the index of the shard obj belongs to
+func (s Shard) of(obj client.Object) int {
+	if v, ok := obj.GetLabels()[ShardLabel]; ok {
+		if index, err := strconv.Atoi(v); err == nil && index >= 0 {
+			return index % s.Count
+		}
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(obj.GetName()))
+	return int(h.Sum32() % uint32(s.Count))
+}
+
+// Predicate filters out the events of objects that belong to other shards
+func (s Shard) Predicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(s.Owns)
+}
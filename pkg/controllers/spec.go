@@ -0,0 +1,63 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// invalidSpecError lists the components that cannot be configured from the
+// spec; the resources deployed for them before are left as they are until
+// the spec is fixed
+type invalidSpecError struct {
+	errs []error
+}
+
+func (e *invalidSpecError) Error() string {
+	msgs := make([]string, 0, len(e.errs))
+	for _, err := range e.errs {
+		msgs = append(msgs, err.Error())
+	}
+	return "invalid spec: " + strings.Join(msgs, "; ")
+}
+
+func (e *invalidSpecError) Unwrap() []error {
+	return e.errs
+}
+
+func isInvalidSpecError(err error) bool {
+	var isErr *invalidSpecError
+	return errors.As(err, &isErr)
+}
+
+// specErrors collects the errors of the components that cannot be configured
+// from the spec while the reconcilers of the other components are created
+type specErrors []error
+
+func (s *specErrors) add(component string, err error) {
+	*s = append(*s, fmt.Errorf("%s: %w", component, err))
+}
+
+// err returns an invalidSpecError with the errors collected or nil if there
+// are none
+func (s specErrors) err() error {
+	if len(s) == 0 {
+		return nil
+	}
+	return &invalidSpecError{errs: s}
+}
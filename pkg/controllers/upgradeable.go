@@ -0,0 +1,117 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// Upgradeable is the OLM OperatorCondition type that blocks upgrades of
+	// the operator when False
+	Upgradeable = "Upgradeable"
+
+	RolloutInProgress   = "RolloutInProgress"
+	NoRolloutInProgress = "NoRolloutInProgress"
+)
+
+var operatorConditionGVK = schema.GroupVersionKind{
+	Group:   "operators.coreos.com",
+	Version: "v2",
+	Kind:    "OperatorCondition",
+}
+
+//+kubebuilder:rbac:groups=operators.coreos.com,resources=operatorconditions,verbs=get;update;patch
+
+// updateUpgradeable sets the Upgradeable condition of the OLM OperatorCondition
+// so that OLM does not upgrade the operator while kepler is being rolled out.
+// The OperatorCondition is updated as unstructured to avoid depending on OLM
+// and is skipped if the operator is not installed by OLM.
+func updateUpgradeable(ctx context.Context, c client.Client, rollingOut bool) error {
+	key := Config.OperatorCondition
+	if key.Name == "" {
+		return nil
+	}
+
+	upgradeable := metav1.Condition{
+		Type:    Upgradeable,
+		Status:  metav1.ConditionTrue,
+		Reason:  NoRolloutInProgress,
+		Message: "Kepler is not being rolled out",
+	}
+	if rollingOut {
+		upgradeable.Status = metav1.ConditionFalse
+		upgradeable.Reason = RolloutInProgress
+		upgradeable.Message = "Kepler is being rolled out; upgrade once the rollout completes"
+	}
+
+	oc := &unstructured.Unstructured{}
+	oc.SetGroupVersionKind(operatorConditionGVK)
+	if err := c.Get(ctx, key, oc); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	conditions, err := specConditions(oc)
+	if err != nil {
+		return err
+	}
+
+	upgradeable.ObservedGeneration = oc.GetGeneration()
+	if !meta.SetStatusCondition(&conditions, upgradeable) {
+		return nil
+	}
+
+	items := make([]interface{}, 0, len(conditions))
+	for i := range conditions {
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&conditions[i])
+		if err != nil {
+			return err
+		}
+		items = append(items, u)
+	}
+	if err := unstructured.SetNestedSlice(oc.Object, items, "spec", "conditions"); err != nil {
+		return err
+	}
+	return c.Update(ctx, oc)
+}
+
+func specConditions(oc *unstructured.Unstructured) ([]metav1.Condition, error) {
+	items, _, err := unstructured.NestedSlice(oc.Object, "spec", "conditions")
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := make([]metav1.Condition, 0, len(items))
+	for _, item := range items {
+		u, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		c := metav1.Condition{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u, &c); err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, c)
+	}
+	return conditions, nil
+}
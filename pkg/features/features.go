@@ -0,0 +1,61 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import (
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/component-base/featuregate"
+)
+
+const (
+	// Estimator enables the estimator sidecar of the kepler exporter
+	Estimator featuregate.Feature = "Estimator"
+
+	// ModelServer enables the deployment of the kepler model server
+	ModelServer featuregate.Feature = "ModelServer"
+
+	// GPU enables the GPU power metrics of the kepler exporter
+	GPU featuregate.Feature = "GPU"
+
+	// CarbonIntensity enables the carbon intensity of the grid and the
+	// emission metrics derived from it
+	CarbonIntensity featuregate.Feature = "CarbonIntensity"
+)
+
+// defaultFeatureGates lists all features known to the operator; features
+// shipped before they were gated are Beta and enabled by default so that
+// upgrades do not turn them off, while experimental features must be added as
+// Alpha and disabled by default
+var defaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
+	Estimator:       {Default: true, PreRelease: featuregate.Beta},
+	ModelServer:     {Default: true, PreRelease: featuregate.Beta},
+	GPU:             {Default: true, PreRelease: featuregate.Beta},
+	CarbonIntensity: {Default: false, PreRelease: featuregate.Alpha},
+}
+
+// Gate is the feature gate of the operator and is set using the
+// --feature-gates flag
+var Gate featuregate.MutableFeatureGate = featuregate.NewFeatureGate()
+
+func init() {
+	runtime.Must(Gate.Add(defaultFeatureGates))
+}
+
+// Enabled returns true if the feature is enabled
+func Enabled(f featuregate.Feature) bool {
+	return Gate.Enabled(f)
+}
@@ -0,0 +1,41 @@
+package features
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/component-base/featuregate"
+)
+
+func TestFeatureGates(t *testing.T) {
+	tt := []struct {
+		flag     string
+		enabled  map[string]bool
+		err      bool
+		scenario string
+	}{
+		{"", map[string]bool{"Estimator": true, "ModelServer": true, "GPU": true, "CarbonIntensity": false}, false, "defaults"},
+		{"Estimator=false", map[string]bool{"Estimator": false, "ModelServer": true}, false, "disable estimator"},
+		{"GPU=false,CarbonIntensity=true", map[string]bool{"GPU": false, "CarbonIntensity": true, "Estimator": true}, false, "disable gpu and enable carbon"},
+		{"AllAlpha=true", map[string]bool{"Estimator": true, "ModelServer": true, "GPU": true, "CarbonIntensity": true}, false, "enable all alpha"},
+		{"AllBeta=false", map[string]bool{"Estimator": false, "ModelServer": false, "GPU": false, "CarbonIntensity": false}, false, "disable all beta"},
+		{"Unknown=true", nil, true, "unknown feature"},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			gate := Gate.DeepCopy()
+			err := gate.Set(tc.flag)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			for f, enabled := range tc.enabled {
+				assert.Equal(t, enabled, gate.Enabled(featuregate.Feature(f)), f)
+			}
+		})
+	}
+}
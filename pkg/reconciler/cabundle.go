@@ -0,0 +1,80 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+
+	monv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CABundleUpdater applies the resource built for the CA bundle read from the
+// ConfigMap or Secret in Namespace so that the CA can be distributed to
+// namespaces it is not available in. The resource is not applied until the
+// CA bundle is found, e.g. issued by cert-manager.
+type CABundleUpdater struct {
+	Owner     metav1.Object
+	Namespace string
+	CA        monv1.SecretOrConfigMap
+	Resource  func(ca []byte) (client.Object, error)
+	OnError   Action
+}
+
+func (r CABundleUpdater) Reconcile(ctx context.Context, c client.Client, s *runtime.Scheme) Result {
+	ca, err := CABundle(ctx, c, r.Namespace, r.CA)
+	if err != nil {
+		return Result{Action: r.OnError, Error: fmt.Errorf("failed to read the CA bundle: %w", err)}
+	}
+	resource, err := r.Resource(ca)
+	if err != nil {
+		return Result{Action: Stop, Error: err}
+	}
+	return Updater{Owner: r.Owner, Resource: resource, OnError: r.OnError}.Reconcile(ctx, c, s)
+}
+
+// CABundle returns the CA bundle under the key of the ConfigMap or Secret in
+// the namespace
+func CABundle(ctx context.Context, c client.Reader, ns string, ca monv1.SecretOrConfigMap) ([]byte, error) {
+	switch {
+	case ca.ConfigMap != nil:
+		cm := corev1.ConfigMap{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: ns, Name: ca.ConfigMap.Name}, &cm); err != nil {
+			return nil, err
+		}
+		if data, ok := cm.Data[ca.ConfigMap.Key]; ok && data != "" {
+			return []byte(data), nil
+		}
+		return nil, fmt.Errorf("configmap %s/%s has no %s", ns, ca.ConfigMap.Name, ca.ConfigMap.Key)
+
+	case ca.Secret != nil:
+		secret := corev1.Secret{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: ns, Name: ca.Secret.Name}, &secret); err != nil {
+			return nil, err
+		}
+		if data := secret.Data[ca.Secret.Key]; len(data) != 0 {
+			return data, nil
+		}
+		return nil, fmt.Errorf("secret %s/%s has no %s", ns, ca.Secret.Name, ca.Secret.Key)
+	}
+	return nil, fmt.Errorf("no CA bundle configured")
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	monv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCABundleUpdater(t *testing.T) {
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", UID: "owner-uid"}}
+	caSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "kepler-tls", Namespace: "kepler"},
+		Data:       map[string][]byte{"ca.crt": []byte("ca-bundle")},
+	}
+	applied := ""
+	r := CABundleUpdater{
+		Owner:     owner,
+		Namespace: "kepler",
+		CA: monv1.SecretOrConfigMap{Secret: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "kepler-tls"},
+			Key:                  "ca.crt",
+		}},
+		Resource: func(ca []byte) (client.Object, error) {
+			applied = string(ca)
+			return &corev1.ConfigMap{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+				ObjectMeta: metav1.ObjectMeta{Name: "scrape-config", Namespace: "opencost"},
+				Data:       map[string]string{"ca": string(ca)},
+			}, nil
+		},
+		OnError: Requeue,
+	}
+	ctx := context.TODO()
+
+	t.Run("not issued", func(t *testing.T) {
+		c := fake.NewClientBuilder().Build()
+		result := r.Reconcile(ctx, c, scheme.Scheme)
+		assert.Error(t, result.Error)
+		assert.Equal(t, Requeue, result.Action)
+		assert.Empty(t, applied)
+	})
+
+	t.Run("issued", func(t *testing.T) {
+		// NOTE: the fake client applies patches to existing objects only
+		live := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "scrape-config", Namespace: "opencost"}}
+		c := fake.NewClientBuilder().WithObjects(caSecret.DeepCopy(), live).Build()
+		assert.NoError(t, r.Reconcile(ctx, c, scheme.Scheme).Error)
+		assert.Equal(t, "ca-bundle", applied)
+	})
+
+	t.Run("configmap", func(t *testing.T) {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "openshift-service-ca.crt", Namespace: "kepler"},
+			Data:       map[string]string{"service-ca.crt": "service-ca"},
+		}
+		c := fake.NewClientBuilder().WithObjects(cm).Build()
+		ca, err := CABundle(ctx, c, "kepler", monv1.SecretOrConfigMap{ConfigMap: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "openshift-service-ca.crt"},
+			Key:                  "service-ca.crt",
+		}})
+		assert.NoError(t, err)
+		assert.Equal(t, "service-ca", string(ca))
+
+		_, err = CABundle(ctx, c, "kepler", monv1.SecretOrConfigMap{ConfigMap: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "openshift-service-ca.crt"},
+			Key:                  "ca.crt",
+		}})
+		assert.Error(t, err)
+	})
+}
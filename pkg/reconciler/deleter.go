@@ -23,6 +23,7 @@ import (
 
 	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -45,6 +46,20 @@ func maxDuration(a, b time.Duration) time.Duration {
 func (r Deleter) Reconcile(ctx context.Context, c client.Client, scheme *runtime.Scheme) Result {
 	objKey := client.ObjectKeyFromObject(r.Resource)
 
+	// NOTE: avoid issuing delete requests for resources that do not exist
+	// including those whose CRD is not installed e.g. ServiceMonitor
+	if err := c.Get(ctx, objKey, r.Resource.DeepCopyObject().(client.Object)); errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return Result{}
+	}
+
+	if IsDryRun(c) {
+		if err := c.Delete(ctx, r.Resource); client.IgnoreNotFound(err) != nil {
+			return Result{Error: r.error("failed to delete", err), Action: r.OnError}
+		}
+		// NOTE: the resource is not deleted, so do not wait for it to be deleted
+		return Result{}
+	}
+
 	if err := c.Delete(ctx, r.Resource); client.IgnoreNotFound(err) != nil {
 		return Result{
 			Error:  r.error("failed to delete", err),
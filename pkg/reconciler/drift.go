@@ -0,0 +1,86 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// EventDriftDetected is the reason of the event recorded when a resource
+// managed by the operator is modified by someone else
+const EventDriftDetected = "DriftDetected"
+
+// recordDrift counts the drift of the live object from the resource and
+// records an event on the owner noting who made the change
+func (r Updater) recordDrift(ctx context.Context, scheme *runtime.Scheme, live client.Object) {
+	kind := ""
+	if gvk, err := apiutil.GVKForObject(r.Resource, scheme); err == nil {
+		kind = gvk.Kind
+	}
+	driftReverts.WithLabelValues(kind).Inc()
+
+	by := "out-of-band"
+	if latest := LastUpdate(live); latest != nil {
+		by = fmt.Sprintf("by %q", latest.Manager)
+	}
+	r.Logger.V(3).Info("resource was modified "+by, "resource", k8s.GVKName(r.Resource))
+
+	recorder := eventRecorderFrom(ctx)
+	owner, ok := r.Owner.(runtime.Object)
+	if recorder == nil || !ok {
+		return
+	}
+	recorder.Eventf(owner, corev1.EventTypeWarning, EventDriftDetected,
+		"%s was modified %s; reverting to the desired state",
+		strings.TrimSpace(kind+" "+client.ObjectKeyFromObject(r.Resource).String()), by)
+}
+
+// modifiedByOperator returns true if the last change to the object was made
+// by the operator
+func modifiedByOperator(obj client.Object) bool {
+	latest := LastUpdate(obj)
+	return latest != nil && latest.Manager == FieldManager
+}
+
+// appliedHash returns the hash of the resource last applied to obj
+func appliedHash(obj client.Object) string {
+	return obj.GetAnnotations()[AppliedHashAnnotation]
+}
+
+// LastUpdate returns the managed fields entry of the last change made to the
+// object excluding changes made to its subresources such as status
+func LastUpdate(obj client.Object) *metav1.ManagedFieldsEntry {
+	var latest *metav1.ManagedFieldsEntry
+	for i, mf := range obj.GetManagedFields() {
+		if mf.Subresource != "" || mf.Time == nil {
+			continue
+		}
+		if latest == nil || latest.Time.Before(mf.Time) {
+			latest = &obj.GetManagedFields()[i]
+		}
+	}
+	return latest
+}
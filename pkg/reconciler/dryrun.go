@@ -0,0 +1,72 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// dryRunClient is a client that does not persist any changes
+type dryRunClient struct {
+	client.Client
+}
+
+// NewDryRunClient returns a client that sends all requests that change
+// objects with dry-run set so that the changes are not persisted. Reconcilers
+// log the changes they would have made when using this client.
+func NewDryRunClient(c client.Client) client.Client {
+	return dryRunClient{client.NewDryRunClient(c)}
+}
+
+// IsDryRun returns true if the client does not persist changes
+func IsDryRun(c client.Client) bool {
+	_, ok := c.(dryRunClient)
+	return ok
+}
+
+// diff returns the difference between the current and the updated objects
+func diff(current, updated client.Object) string {
+	from, err := runtime.DefaultUnstructuredConverter.ToUnstructured(current)
+	if err != nil {
+		return err.Error()
+	}
+	to, err := runtime.DefaultUnstructuredConverter.ToUnstructured(updated)
+	if err != nil {
+		return err.Error()
+	}
+
+	// NOTE: ignore fields that change on every update along with the fields
+	// that are not cached
+	for _, obj := range []map[string]interface{}{from, to} {
+		delete(obj, "status")
+		if meta, ok := obj["metadata"].(map[string]interface{}); ok {
+			delete(meta, "managedFields")
+			delete(meta, "resourceVersion")
+			delete(meta, "generation")
+			if annotations, ok := meta["annotations"].(map[string]interface{}); ok {
+				delete(annotations, corev1.LastAppliedConfigAnnotation)
+				if len(annotations) == 0 {
+					delete(meta, "annotations")
+				}
+			}
+		}
+	}
+	return cmp.Diff(from, to)
+}
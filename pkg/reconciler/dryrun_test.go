@@ -0,0 +1,33 @@
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDryRunClient(t *testing.T) {
+	c := fake.NewClientBuilder().Build()
+	assert.False(t, IsDryRun(c))
+	assert.True(t, IsDryRun(NewDryRunClient(c)))
+}
+
+func TestDiff(t *testing.T) {
+	current := k8s.Deployment("ns", "name").WithLabels(map[string]string{"app": "kepler"}).Build()
+	current.ResourceVersion = "1"
+
+	updated := current.DeepCopy()
+	updated.ResourceVersion = "2"
+	assert.Empty(t, diff(current, updated))
+
+	// the last applied configuration is not cached
+	updated.Annotations = map[string]string{corev1.LastAppliedConfigAnnotation: "{}"}
+	assert.Empty(t, diff(current, updated))
+
+	updated.Labels["app"] = "kepler-exporter"
+	assert.Contains(t, diff(current, updated), "kepler-exporter")
+}
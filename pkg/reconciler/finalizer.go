@@ -35,7 +35,7 @@ type Finalizer struct {
 }
 
 func (r Finalizer) Reconcile(ctx context.Context, c client.Client, s *runtime.Scheme) Result {
-	logger := r.Logger.WithValues("reconciler", "finalizer")
+	logger := loggerFor(ctx, r.Logger).WithValues("reconciler", "finalizer")
 
 	// NOTE: we can safely typecast since Resource is a client.Object
 
@@ -58,16 +58,18 @@ func (r Finalizer) Reconcile(ctx context.Context, c client.Client, s *runtime.Sc
 	if deleted && hasFinalizer {
 		logger.V(3).Info("removing finalizer")
 
+		patch := client.MergeFromWithOptions(refreshed.DeepCopyObject().(client.Object), client.MergeFromWithOptimisticLock{})
 		ctrlutil.RemoveFinalizer(refreshed, r.Finalizer)
-		err := c.Update(ctx, refreshed)
+		err := c.Patch(ctx, refreshed, patch)
 		return Result{Error: err, Action: Stop}
 	}
 
 	if !deleted && !hasFinalizer {
 		logger.V(3).Info("no finalizer found; adding it")
 
+		patch := client.MergeFromWithOptions(refreshed.DeepCopyObject().(client.Object), client.MergeFromWithOptimisticLock{})
 		ctrlutil.AddFinalizer(refreshed, r.Finalizer)
-		err := c.Update(ctx, refreshed)
+		err := c.Patch(ctx, refreshed, patch)
 		return Result{Error: err, Action: Stop}
 	}
 
@@ -18,11 +18,8 @@ package reconciler
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"strconv"
 
-	"github.com/cespare/xxhash/v2"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/components/exporter"
 	appsv1 "k8s.io/api/apps/v1"
@@ -39,7 +36,7 @@ type KeplerReconciler struct {
 
 func (r KeplerReconciler) Reconcile(ctx context.Context, cli client.Client, s *runtime.Scheme) Result {
 	redfish := r.Ki.Spec.Exporter.Redfish
-	redfishBytes, err := json.Marshal(redfish)
+	redfishHash, err := exporter.RedfishSpecHash(redfish)
 	if err != nil {
 		return Result{Action: Stop, Error: fmt.Errorf("Error occurred while marshaling Redfish spec %w", err)}
 	}
@@ -61,9 +58,8 @@ func (r KeplerReconciler) Reconcile(ctx context.Context, cli client.Client, s *r
 		return Result{Action: Stop, Error: fmt.Errorf("Redfish secret is missing %q key", exporter.RedfishCSV)}
 	}
 
-	redfishHash := xxhash.Sum64(redfishBytes)
 	exporter.MountRedfishSecretToDaemonSet(r.Ds, secret, redfishHash)
-	return Updater{Owner: r.Ki, Resource: r.Ds}.Reconcile(ctx, cli, s)
+	return SecretRollout{Owner: r.Ki, Resource: r.Ds, Secrets: exporter.PodSecrets(r.Ki)}.Reconcile(ctx, cli, s)
 }
 
 func (r KeplerReconciler) getRedfishSecret(ctx context.Context, cli client.Client, secretName string) (*corev1.Secret, error) {
@@ -81,8 +77,6 @@ type KeplerConfigMapReconciler struct {
 }
 
 func (r KeplerConfigMapReconciler) Reconcile(ctx context.Context, cli client.Client, s *runtime.Scheme) Result {
-	rf := r.Ki.Spec.Exporter.Redfish
-	r.Cfm.Data["REDFISH_PROBE_INTERVAL_IN_SECONDS"] = strconv.FormatFloat(rf.ProbeInterval.Duration.Seconds(), 'f', 0, 64)
-	r.Cfm.Data["REDFISH_SKIP_SSL_VERIFY"] = strconv.FormatBool(rf.SkipSSLVerify)
+	exporter.MountRedfishConfigToConfigMap(r.Cfm, r.Ki.Spec.Exporter.Redfish)
 	return Updater{Owner: r.Ki, Resource: r.Cfm}.Reconcile(ctx, cli, s)
 }
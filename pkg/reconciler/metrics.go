@@ -0,0 +1,40 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// applyErrors counts the resources that could not be applied by kind
+var applyErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "kepler_operator_apply_errors_total",
+	Help: "Number of errors encountered while applying resources",
+}, []string{"kind"})
+
+// driftReverts counts the out-of-band changes to resources that were reverted
+// by kind
+var driftReverts = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "kepler_operator_drift_reverts_total",
+	Help: "Number of out-of-band changes to managed resources that were reverted",
+}, []string{"kind"})
+
+func init() {
+	metrics.Registry.MustRegister(applyErrors, driftReverts)
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ClusterMonitoringNS is the namespace of the OpenShift cluster monitoring stack
+	ClusterMonitoringNS = "openshift-monitoring"
+
+	// ClusterMonitoringConfig is the ConfigMap configuring cluster monitoring
+	ClusterMonitoringConfig = "cluster-monitoring-config"
+
+	clusterMonitoringConfigKey = "config.yaml"
+	enableUserWorkloadKey      = "enableUserWorkload"
+)
+
+// UserWorkloadMonitoringEnabled returns true if User Workload Monitoring is
+// enabled in the OpenShift cluster monitoring config. The reader must not be a
+// cached client since the config is not watched by the operator.
+func UserWorkloadMonitoringEnabled(ctx context.Context, c client.Reader) (bool, error) {
+	cm := corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: ClusterMonitoringNS, Name: ClusterMonitoringConfig}
+	if err := c.Get(ctx, key, &cm); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+
+	cfg, err := parseMonitoringConfig(&cm)
+	if err != nil {
+		return false, err
+	}
+	enabled, _ := cfg[enableUserWorkloadKey].(bool)
+	return enabled, nil
+}
+
+func parseMonitoringConfig(cm *corev1.ConfigMap) (map[string]interface{}, error) {
+	cfg := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(cm.Data[clusterMonitoringConfigKey]), &cfg); err != nil {
+		return nil, fmt.Errorf("invalid %s in %s/%s: %w", clusterMonitoringConfigKey, cm.Namespace, cm.Name, err)
+	}
+	if cfg == nil {
+		cfg = map[string]interface{}{}
+	}
+	return cfg, nil
+}
+
+// UserWorkloadMonitoringEnabler enables User Workload Monitoring in the
+// OpenShift cluster monitoring config while preserving the rest of the config
+type UserWorkloadMonitoringEnabler struct {
+	// Reader reads the cluster monitoring config; must not be a cached client
+	Reader  client.Reader
+	OnError Action
+}
+
+func (r UserWorkloadMonitoringEnabler) Reconcile(ctx context.Context, c client.Client, s *runtime.Scheme) Result {
+	logger := loggerFor(ctx, logr.Logger{})
+
+	cm := corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: ClusterMonitoringNS, Name: ClusterMonitoringConfig}
+	err := r.Reader.Get(ctx, key, &cm)
+	if err != nil && !errors.IsNotFound(err) {
+		return Result{Action: r.OnError, Error: fmt.Errorf("failed to get cluster monitoring config: %w", err)}
+	}
+	notFound := errors.IsNotFound(err)
+	if notFound {
+		cm = corev1.ConfigMap{}
+		cm.Namespace = ClusterMonitoringNS
+		cm.Name = ClusterMonitoringConfig
+	}
+
+	cfg, err := parseMonitoringConfig(&cm)
+	if err != nil {
+		return Result{Action: r.OnError, Error: err}
+	}
+	if enabled, _ := cfg[enableUserWorkloadKey].(bool); enabled {
+		return Result{}
+	}
+
+	cfg[enableUserWorkloadKey] = true
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return Result{Action: r.OnError, Error: fmt.Errorf("failed to marshal cluster monitoring config: %w", err)}
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[clusterMonitoringConfigKey] = string(data)
+
+	logger.Info("enabling user workload monitoring", "configmap", key)
+
+	// NOTE: the config is owned by the cluster admin, so it is updated instead
+	// of applied to avoid taking ownership of the entire config. Creating it
+	// requires the unscoped create on configmaps since create cannot be
+	// restricted to a resource name.
+	if notFound {
+		err = c.Create(ctx, &cm)
+	} else {
+		err = c.Update(ctx, &cm)
+	}
+	if err != nil {
+		return Result{Action: r.OnError, Error: fmt.Errorf("failed to enable user workload monitoring: %w", err)}
+	}
+	return Result{}
+}
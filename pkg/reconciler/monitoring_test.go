@@ -0,0 +1,71 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestUserWorkloadMonitoringEnabler(t *testing.T) {
+	monitoringConfig := func(cfg string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ClusterMonitoringNS, Name: ClusterMonitoringConfig},
+			Data:       map[string]string{clusterMonitoringConfigKey: cfg},
+		}
+	}
+
+	tt := []struct {
+		scenario string
+		existing *corev1.ConfigMap
+		enabled  bool
+	}{
+		{"config not found", nil, false},
+		{"disabled", monitoringConfig("enableUserWorkload: false\n"), false},
+		{"enabled", monitoringConfig("enableUserWorkload: true\n"), true},
+		{"other settings", monitoringConfig("prometheusK8s:\n  retention: 7d\n"), false},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			objs := []client.Object{}
+			if tc.existing != nil {
+				objs = append(objs, tc.existing)
+			}
+			c := fake.NewClientBuilder().WithObjects(objs...).Build()
+			ctx := context.TODO()
+
+			enabled, err := UserWorkloadMonitoringEnabled(ctx, c)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.enabled, enabled)
+
+			result := UserWorkloadMonitoringEnabler{Reader: c}.Reconcile(ctx, c, nil)
+			assert.NoError(t, result.Error)
+
+			enabled, err = UserWorkloadMonitoringEnabled(ctx, c)
+			assert.NoError(t, err)
+			assert.True(t, enabled)
+
+			// NOTE: other settings in the config must be preserved
+			if tc.existing != nil {
+				cm := corev1.ConfigMap{}
+				assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(tc.existing), &cm))
+				cfg, err := parseMonitoringConfig(&cm)
+				assert.NoError(t, err)
+				original, _ := parseMonitoringConfig(tc.existing)
+				for k, v := range original {
+					if k != enableUserWorkloadKey {
+						assert.Equal(t, v, cfg[k])
+					}
+				}
+			}
+		})
+	}
+}
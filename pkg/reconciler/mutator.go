@@ -0,0 +1,41 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Mutator updates the Resource after Mutate has changed it using the objects
+// it reads from the cluster, e.g. the configuration of the platform. The
+// Resource is not updated if Mutate fails.
+type Mutator struct {
+	Owner    metav1.Object
+	Resource client.Object
+	Mutate   func(ctx context.Context, c client.Reader) error
+}
+
+func (r Mutator) Reconcile(ctx context.Context, c client.Client, s *runtime.Scheme) Result {
+	if err := r.Mutate(ctx, c); err != nil {
+		return Result{Action: Stop, Error: err}
+	}
+	return Updater{Owner: r.Owner, Resource: r.Resource}.Reconcile(ctx, c, s)
+}
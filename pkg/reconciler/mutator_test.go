@@ -0,0 +1,68 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMutator(t *testing.T) {
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", UID: "owner-uid"}}
+	newConfigMap := func() *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "kepler"},
+		}
+	}
+	ctx := context.TODO()
+
+	t.Run("mutated", func(t *testing.T) {
+		// NOTE: the fake client applies patches to existing objects only
+		c := fake.NewClientBuilder().WithObjects(newConfigMap()).Build()
+		cm := newConfigMap()
+		r := Mutator{Owner: owner, Resource: cm, Mutate: func(context.Context, client.Reader) error {
+			cm.Data = map[string]string{"mutated": "true"}
+			return nil
+		}}
+		assert.NoError(t, r.Reconcile(ctx, c, scheme.Scheme).Error)
+
+		live := &corev1.ConfigMap{}
+		assert.NoError(t, c.Get(ctx, types.NamespacedName{Name: "config", Namespace: "kepler"}, live))
+		assert.Equal(t, "true", live.Data["mutated"])
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		c := fake.NewClientBuilder().WithObjects(newConfigMap()).Build()
+		r := Mutator{Owner: owner, Resource: newConfigMap(), Mutate: func(context.Context, client.Reader) error {
+			return fmt.Errorf("unreachable")
+		}}
+		result := r.Reconcile(ctx, c, scheme.Scheme)
+		assert.Error(t, result.Error)
+		assert.Equal(t, Stop, result.Action)
+	})
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Parallel runs reconcilers that do not depend on each other concurrently so
+// that the latency of the api server is paid once rather than per resource.
+// All reconcilers are run to completion; the errors are aggregated and the
+// most severe action requested (Stop over Requeue over Continue) is returned.
+type Parallel struct {
+	Reconcilers []Reconciler
+}
+
+func (r Parallel) Reconcile(ctx context.Context, c client.Client, s *runtime.Scheme) Result {
+	results := make([]Result, len(r.Reconcilers))
+
+	var wg sync.WaitGroup
+	for i, rec := range r.Reconcilers {
+		wg.Add(1)
+		go func(i int, rec Reconciler) {
+			defer wg.Done()
+			results[i] = rec.Reconcile(ctx, c, s)
+		}(i, rec)
+	}
+	wg.Wait()
+
+	action := Continue
+	errs := []error{}
+	for _, res := range results {
+		if res.Error != nil {
+			errs = append(errs, res.Error)
+		}
+		if res.Action > action {
+			action = res.Action
+		}
+	}
+	return Result{Action: action, Error: errors.Join(errs...)}
+}
@@ -0,0 +1,74 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type stubReconciler Result
+
+func (r stubReconciler) Reconcile(context.Context, client.Client, *runtime.Scheme) Result {
+	return Result(r)
+}
+
+func TestParallel(t *testing.T) {
+	errA := errors.New("a failed")
+	errB := errors.New("b failed")
+
+	tt := []struct {
+		scenario    string
+		reconcilers []Reconciler
+		action      Action
+		errs        []error
+	}{
+		{
+			scenario: "no reconcilers",
+			action:   Continue,
+		},
+		{
+			scenario:    "all succeed",
+			reconcilers: []Reconciler{stubReconciler{}, stubReconciler{}},
+			action:      Continue,
+		},
+		{
+			scenario: "errors are aggregated",
+			reconcilers: []Reconciler{
+				stubReconciler{Action: Continue, Error: errA},
+				stubReconciler{},
+				stubReconciler{Action: Continue, Error: errB},
+			},
+			action: Continue,
+			errs:   []error{errA, errB},
+		},
+		{
+			scenario: "most severe action wins",
+			reconcilers: []Reconciler{
+				stubReconciler{Action: Requeue},
+				stubReconciler{Action: Stop, Error: errA},
+				stubReconciler{},
+			},
+			action: Stop,
+			errs:   []error{errA},
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			result := Parallel{Reconcilers: tc.reconcilers}.Reconcile(context.TODO(), fake.NewFakeClient(), nil)
+			assert.Exactly(t, tc.action, result.Action)
+			if len(tc.errs) == 0 {
+				assert.NoError(t, result.Error)
+				return
+			}
+			for _, err := range tc.errs {
+				assert.ErrorIs(t, result.Error, err)
+			}
+		})
+	}
+}
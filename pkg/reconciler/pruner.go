@@ -0,0 +1,62 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Pruner deletes the resources of the kind of List that match the Labels but
+// are not Desired, e.g. resources created for entries removed from the spec
+type Pruner struct {
+	List    client.ObjectList
+	Labels  map[string]string
+	Desired []client.Object
+	OnError Action
+}
+
+func (r Pruner) Reconcile(ctx context.Context, c client.Client, s *runtime.Scheme) Result {
+	if err := c.List(ctx, r.List, client.MatchingLabels(r.Labels)); err != nil {
+		return Result{Action: r.OnError, Error: fmt.Errorf("failed to list resources to prune: %w", err)}
+	}
+
+	desired := map[client.ObjectKey]bool{}
+	for _, obj := range r.Desired {
+		desired[client.ObjectKeyFromObject(obj)] = true
+	}
+
+	items, err := meta.ExtractList(r.List)
+	if err != nil {
+		return Result{Action: Stop, Error: fmt.Errorf("failed to list resources to prune: %w", err)}
+	}
+	for _, item := range items {
+		obj, ok := item.(client.Object)
+		if !ok || desired[client.ObjectKeyFromObject(obj)] {
+			continue
+		}
+		deleter := Deleter{Resource: obj, OnError: r.OnError}
+		if result := deleter.Reconcile(ctx, c, s); result.Action != Continue || result.Error != nil {
+			return result
+		}
+	}
+	return Result{}
+}
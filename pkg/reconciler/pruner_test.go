@@ -0,0 +1,67 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/test"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPrunerReconcile(t *testing.T) {
+	managed := map[string]string{"app.kubernetes.io/managed-by": "kepler-operator"}
+	binding := func(ns string, labels map[string]string) *rbacv1.RoleBinding {
+		return &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "viewer", Namespace: ns, Labels: labels},
+		}
+	}
+
+	desired := binding("team-a", managed)
+	stale := binding("team-b", managed)
+	unmanaged := binding("team-c", nil)
+	c := fake.NewFakeClient(desired, stale, unmanaged)
+	f := test.NewFramework(t, test.WithClient(c))
+
+	pruner := Pruner{
+		List:    &rbacv1.RoleBindingList{},
+		Labels:  managed,
+		Desired: []client.Object{binding("team-a", managed)},
+	}
+	result := pruner.Reconcile(context.TODO(), c, f.Scheme())
+	assert.Exactly(t, Continue, result.Action)
+	assert.NoError(t, result.Error)
+
+	tt := []struct {
+		resource *rbacv1.RoleBinding
+		exists   bool
+	}{
+		{desired, true},
+		{stale, false},
+		{unmanaged, true},
+	}
+	for _, tc := range tt {
+		err := c.Get(context.TODO(), client.ObjectKeyFromObject(tc.resource), &rbacv1.RoleBinding{})
+		assert.Equal(t, tc.exists, err == nil, tc.resource.Namespace)
+	}
+}
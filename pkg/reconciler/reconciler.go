@@ -19,8 +19,11 @@ package reconciler
 import (
 	"context"
 
+	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 type Action int
@@ -44,3 +47,27 @@ type Result struct {
 type Reconciler interface {
 	Reconcile(context.Context, client.Client, *runtime.Scheme) Result
 }
+
+// loggerFor returns l if it is set and the logger in ctx otherwise; the logger
+// set by controller-runtime includes the reconcileID, which allows correlating
+// the logs of all reconcilers run in a single reconcile
+func loggerFor(ctx context.Context, l logr.Logger) logr.Logger {
+	if l.GetSink() != nil {
+		return l
+	}
+	return log.FromContext(ctx)
+}
+
+type recorderKey struct{}
+
+// withEventRecorder returns a copy of ctx that carries r so that reconcilers
+// can record events on the owner of the resources they reconcile
+func withEventRecorder(ctx context.Context, r record.EventRecorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, r)
+}
+
+// eventRecorderFrom returns the event recorder in ctx or nil if there is none
+func eventRecorderFrom(ctx context.Context) record.EventRecorder {
+	r, _ := ctx.Value(recorderKey{}).(record.EventRecorder)
+	return r
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SecretsHashAnnotation is set on the pods of workloads that use secrets so
+// that the pods are rolled out when the data of the secrets change
+const SecretsHashAnnotation = "kepler.system.sustainable.computing.io/secrets-hash"
+
+// SecretRollout applies a DaemonSet or Deployment whose pods are annotated
+// with a hash of the data of the Secrets they use, so that rotating any of the
+// secrets rolls out the pods. Secrets that are not found are hashed as empty
+// since they may be created after the workload e.g. by cert-manager.
+type SecretRollout struct {
+	Owner    metav1.Object
+	Resource client.Object
+	Secrets  []string
+	OnError  Action
+}
+
+func (r SecretRollout) Reconcile(ctx context.Context, c client.Client, s *runtime.Scheme) Result {
+	updater := Updater{Owner: r.Owner, Resource: r.Resource, OnError: r.OnError}
+	if len(r.Secrets) == 0 {
+		return updater.Reconcile(ctx, c, s)
+	}
+
+	template, err := podTemplate(r.Resource)
+	if err != nil {
+		return Result{Action: Stop, Error: err}
+	}
+	hash, err := SecretsHash(ctx, c, r.Resource.GetNamespace(), r.Secrets)
+	if err != nil {
+		return Result{Action: r.OnError, Error: fmt.Errorf("failed to hash secrets of %s: %w", k8s.GVKName(r.Resource), err)}
+	}
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[SecretsHashAnnotation] = hash
+	return updater.Reconcile(ctx, c, s)
+}
+
+// SecretsHash returns a hash of the data of the named secrets in the namespace
+func SecretsHash(ctx context.Context, c client.Reader, ns string, names []string) (string, error) {
+	names = slices.Clone(names)
+	slices.Sort(names)
+
+	h := xxhash.New()
+	for _, name := range slices.Compact(names) {
+		secret := corev1.Secret{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: ns, Name: name}, &secret); client.IgnoreNotFound(err) != nil {
+			return "", err
+		}
+		keys := make([]string, 0, len(secret.Data))
+		for k := range secret.Data {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+
+		_, _ = h.WriteString(name)
+		for _, k := range keys {
+			// NOTE: lengths are written so that moving bytes between keys or
+			// values changes the hash
+			_, _ = h.WriteString(k + "\x00" + strconv.Itoa(len(secret.Data[k])) + "\x00")
+			_, _ = h.Write(secret.Data[k])
+		}
+		_, _ = h.WriteString("\x00")
+	}
+	return strconv.FormatUint(h.Sum64(), 10), nil
+}
+
+func podTemplate(obj client.Object) (*corev1.PodTemplateSpec, error) {
+	switch w := obj.(type) {
+	case *appsv1.DaemonSet:
+		return &w.Spec.Template, nil
+	case *appsv1.Deployment:
+		return &w.Spec.Template, nil
+	default:
+		return nil, fmt.Errorf("cannot roll out pods of %s", k8s.GVKName(obj))
+	}
+}
@@ -0,0 +1,88 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/test"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSecretsHash(t *testing.T) {
+	secret := func(name string, data map[string]string) *corev1.Secret {
+		s := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kepler"}, Data: map[string][]byte{}}
+		for k, v := range data {
+			s.Data[k] = []byte(v)
+		}
+		return s
+	}
+	hash := func(t *testing.T, names []string, secrets ...*corev1.Secret) string {
+		c := fake.NewFakeClient()
+		for _, s := range secrets {
+			assert.NoError(t, c.Create(context.TODO(), s))
+		}
+		h, err := SecretsHash(context.TODO(), c, "kepler", names)
+		assert.NoError(t, err)
+		return h
+	}
+
+	tls := secret("tls", map[string]string{"tls.crt": "cert", "tls.key": "key"})
+	redfish := secret("redfish", map[string]string{"redfish.csv": "node,user,pass"})
+	names := []string{"tls", "redfish"}
+	original := hash(t, names, tls.DeepCopy(), redfish.DeepCopy())
+
+	t.Run("stable", func(t *testing.T) {
+		labelled := redfish.DeepCopy()
+		labelled.Labels = map[string]string{"team": "a"}
+		assert.Equal(t, original, hash(t, []string{"redfish", "tls", "tls"}, tls.DeepCopy(), labelled))
+	})
+
+	tt := []struct {
+		scenario string
+		names    []string
+		secrets  []*corev1.Secret
+	}{
+		{
+			scenario: "rotated",
+			names:    names,
+			secrets:  []*corev1.Secret{secret("tls", map[string]string{"tls.crt": "new", "tls.key": "key"}), redfish.DeepCopy()},
+		},
+		{
+			scenario: "key added",
+			names:    names,
+			secrets:  []*corev1.Secret{tls.DeepCopy(), secret("redfish", map[string]string{"redfish.csv": "node,user,pass", "ca.crt": ""})},
+		},
+		{
+			scenario: "not found",
+			names:    names,
+			secrets:  []*corev1.Secret{tls.DeepCopy()},
+		},
+		{
+			scenario: "secret removed from pods",
+			names:    []string{"tls"},
+			secrets:  []*corev1.Secret{tls.DeepCopy(), redfish.DeepCopy()},
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			assert.NotEqual(t, original, hash(t, tc.names, tc.secrets...))
+		})
+	}
+}
+
+func TestSecretRolloutUnsupportedKind(t *testing.T) {
+	c := fake.NewFakeClient()
+	f := test.NewFramework(t, test.WithClient(c))
+	owner := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kepler"}}
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "kepler", Namespace: "kepler"}}
+
+	result := SecretRollout{Owner: owner, Resource: cm, Secrets: []string{"tls"}}.Reconcile(context.TODO(), c, f.Scheme())
+	assert.Exactly(t, Stop, result.Action)
+	assert.Error(t, result.Error)
+}
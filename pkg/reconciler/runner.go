@@ -22,6 +22,7 @@ import (
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -31,11 +32,19 @@ type Runner struct {
 	Client      client.Client
 	Scheme      *runtime.Scheme
 	Logger      logr.Logger
+
+	// Recorder, if set, records events such as drifts on the owner of the
+	// reconciled resources
+	Recorder record.EventRecorder
 }
 
 // TODO: make sure that model server container (deployment) is ready before creating kepler daemonset
 func (runner Runner) Run(ctx context.Context) (ctrl.Result, error) {
 	var err error
+	runner.Logger = loggerFor(ctx, runner.Logger)
+	if runner.Recorder != nil {
+		ctx = withEventRecorder(ctx, runner.Recorder)
+	}
 
 	for _, r := range runner.Reconcilers {
 		runner.Logger.V(6).Info("reconciler.run ...")
@@ -58,10 +67,13 @@ func (runner Runner) Run(ctx context.Context) (ctrl.Result, error) {
 
 		case Requeue:
 			if err != nil {
-				runner.Logger.V(3).Info("requeue reconciliation despite error", "error", err)
-			} else {
-				runner.Logger.V(3).Info("requeue reconciliation; no error so far")
+				// NOTE: the error is returned so that the reconcile is retried
+				// with an exponential backoff rather than every few seconds
+				// when the error persists e.g. when a CRD is missing
+				runner.Logger.V(3).Info("requeue reconciliation with backoff", "error", err)
+				return ctrl.Result{}, err
 			}
+			runner.Logger.V(3).Info("requeue reconciliation; no error so far")
 			return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
 		}
 	}
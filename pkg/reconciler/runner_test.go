@@ -0,0 +1,36 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRunnerRequeue(t *testing.T) {
+	errForbidden := errors.New("forbidden")
+
+	t.Run("without error", func(t *testing.T) {
+		result, err := Runner{
+			Reconcilers: []Reconciler{stubReconciler{Action: Requeue}},
+			Client:      fake.NewFakeClient(),
+		}.Run(context.TODO())
+		assert.NoError(t, err)
+		assert.NotZero(t, result.RequeueAfter)
+	})
+
+	t.Run("with error backs off", func(t *testing.T) {
+		result, err := Runner{
+			Reconcilers: []Reconciler{
+				stubReconciler{Action: Requeue, Error: errForbidden},
+				stubReconciler{Action: Stop, Error: errors.New("not run")},
+			},
+			Client: fake.NewFakeClient(),
+		}.Run(context.TODO())
+		assert.ErrorIs(t, err, errForbidden)
+		assert.Equal(t, ctrl.Result{}, result)
+	})
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// CABundleKey is the key of the CA bundle in the serving certificate secret
+	CABundleKey = "ca.crt"
+
+	caValidity   = 2 * 365 * 24 * time.Hour
+	certValidity = 90 * 24 * time.Hour
+)
+
+// ServingCertReconciler issues the serving certificate stored in Cert from a
+// CA managed by the operator and stored in CA. Both are renewed once less than
+// a third of their validity remains; the CA bundle keeps the previous CA until
+// it expires so that clients trust the certificate while it is rotated.
+type ServingCertReconciler struct {
+	Owner    metav1.Object
+	CA       *corev1.Secret
+	Cert     *corev1.Secret
+	DNSNames []string
+}
+
+func (r ServingCertReconciler) Reconcile(ctx context.Context, cli client.Client, s *runtime.Scheme) Result {
+	liveCA, err := getSecret(ctx, cli, r.CA)
+	if err != nil {
+		return Result{Action: Stop, Error: fmt.Errorf("Error occurred while getting CA secret %w", err)}
+	}
+	liveCert, err := getSecret(ctx, cli, r.Cert)
+	if err != nil {
+		return Result{Action: Stop, Error: fmt.Errorf("Error occurred while getting serving cert secret %w", err)}
+	}
+
+	caData, certData, err := ServingCertData(liveCA, liveCert, r.DNSNames, time.Now())
+	if err != nil {
+		return Result{Action: Stop, Error: fmt.Errorf("failed to issue serving certificate: %w", err)}
+	}
+
+	// NOTE: the CA is applied first so that it is never lost once a
+	// certificate has been issued by it
+	r.CA.Data = caData
+	if result := (Updater{Owner: r.Owner, Resource: r.CA}).Reconcile(ctx, cli, s); result.Action != Continue || result.Error != nil {
+		return result
+	}
+	r.Cert.Data = certData
+	return Updater{Owner: r.Owner, Resource: r.Cert}.Reconcile(ctx, cli, s)
+}
+
+func getSecret(ctx context.Context, cli client.Client, secret *corev1.Secret) (*corev1.Secret, error) {
+	live := &corev1.Secret{}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(secret), live); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	return live, nil
+}
+
+// ServingCertData returns the data of the CA and serving certificate secrets.
+// The live data is returned as is unless the CA or the certificate has to be
+// renewed or the certificate does not match the DNS names.
+func ServingCertData(liveCA, liveCert *corev1.Secret, dnsNames []string, now time.Time) (map[string][]byte, map[string][]byte, error) {
+	ca, caKey, err := parseKeyPair(liveCA)
+	var caData map[string][]byte
+	if err == nil && !needsRenewal(ca, caValidity, now) {
+		caData = liveCA.Data
+	} else {
+		if ca, caKey, err = newCA(now); err != nil {
+			return nil, nil, err
+		}
+		caData = map[string][]byte{
+			corev1.TLSCertKey:       encodeCert(ca),
+			corev1.TLSPrivateKeyKey: encodeKey(caKey),
+		}
+	}
+
+	bundle := caBundle(ca, liveCert, now)
+	cert, _, err := parseKeyPair(liveCert)
+	if err == nil && !needsRenewal(cert, certValidity, now) && issuedFor(cert, ca, dnsNames) {
+		certData := liveCert.Data
+		certData[CABundleKey] = bundle
+		return caData, certData, nil
+	}
+
+	cert, key, err := newCert(ca, caKey, dnsNames, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	return caData, map[string][]byte{
+		corev1.TLSCertKey:       encodeCert(cert),
+		corev1.TLSPrivateKeyKey: encodeKey(key),
+		CABundleKey:             bundle,
+	}, nil
+}
+
+// caBundle returns the CA followed by the unexpired CAs of the live bundle
+func caBundle(ca *x509.Certificate, liveCert *corev1.Secret, now time.Time) []byte {
+	bundle := encodeCert(ca)
+	if liveCert == nil {
+		return bundle
+	}
+	rest := liveCert.Data[CABundleKey]
+	for {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			return bundle
+		}
+		prev, err := x509.ParseCertificate(block.Bytes)
+		if err != nil || prev.Equal(ca) || now.After(prev.NotAfter) {
+			continue
+		}
+		bundle = append(bundle, pem.EncodeToMemory(block)...)
+	}
+}
+
+func parseKeyPair(secret *corev1.Secret) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	if secret == nil {
+		return nil, nil, errors.New("secret not found")
+	}
+	certBlock, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+	keyBlock, _ := pem.Decode(secret.Data[corev1.TLSPrivateKeyKey])
+	if certBlock == nil || keyBlock == nil {
+		return nil, nil, errors.New("secret has no certificate or key")
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	ecKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, nil, errors.New("private key is not an ECDSA key")
+	}
+	return cert, ecKey, nil
+}
+
+// needsRenewal returns true once less than a third of the validity remains
+func needsRenewal(cert *x509.Certificate, validity time.Duration, now time.Time) bool {
+	return now.Add(validity / 3).After(cert.NotAfter)
+}
+
+func issuedFor(cert, ca *x509.Certificate, dnsNames []string) bool {
+	return cert.CheckSignatureFrom(ca) == nil && slices.Equal(cert.DNSNames, dnsNames)
+}
+
+func newCA(now time.Time) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	template := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: fmt.Sprintf("kepler-operator-ca@%d", now.Unix())},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	return issue(template, nil, nil)
+}
+
+func newCert(ca *x509.Certificate, caKey *ecdsa.PrivateKey, dnsNames []string, now time.Time) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	template := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:    dnsNames,
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    now.Add(certValidity),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	return issue(template, ca, caKey)
+}
+
+// issue signs the template by the CA or self-signs it if no CA is given
+func issue(template, ca *x509.Certificate, caKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+	template.SerialNumber = serial
+
+	if ca == nil {
+		ca, caKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	return cert, key, err
+}
+
+func encodeCert(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+}
+
+func encodeKey(key *ecdsa.PrivateKey) []byte {
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
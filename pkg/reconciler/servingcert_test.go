@@ -0,0 +1,100 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestServingCertData(t *testing.T) {
+	dnsNames := []string{"kepler.kepler.svc", "kepler.kepler.svc.cluster.local"}
+	now := time.Now()
+
+	// issue the CA and certificate
+	caData, certData, err := ServingCertData(nil, nil, dnsNames, now)
+	assert.NoError(t, err)
+	ca, _, err := parseKeyPair(&corev1.Secret{Data: caData})
+	assert.NoError(t, err)
+	cert, _, err := parseKeyPair(&corev1.Secret{Data: certData})
+	assert.NoError(t, err)
+	assert.True(t, ca.IsCA)
+	assert.Equal(t, dnsNames, cert.DNSNames)
+	assert.Len(t, bundle(t, certData), 1)
+
+	pool := x509.NewCertPool()
+	assert.True(t, pool.AppendCertsFromPEM(certData[CABundleKey]))
+	_, err = cert.Verify(x509.VerifyOptions{DNSName: dnsNames[0], Roots: pool, CurrentTime: now})
+	assert.NoError(t, err)
+
+	liveCA, liveCert := &corev1.Secret{Data: caData}, &corev1.Secret{Data: certData}
+
+	t.Run("up-to-date", func(t *testing.T) {
+		ca, cert, err := ServingCertData(liveCA.DeepCopy(), liveCert.DeepCopy(), dnsNames, now.Add(time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, caData, ca)
+		assert.Equal(t, certData, cert)
+	})
+
+	t.Run("dns names changed", func(t *testing.T) {
+		ca, cert, err := ServingCertData(liveCA.DeepCopy(), liveCert.DeepCopy(), dnsNames[:1], now)
+		assert.NoError(t, err)
+		assert.Equal(t, caData, ca)
+		assert.NotEqual(t, certData[corev1.TLSCertKey], cert[corev1.TLSCertKey])
+	})
+
+	t.Run("certificate renewed", func(t *testing.T) {
+		later := now.Add(certValidity * 3 / 4)
+		ca, cert, err := ServingCertData(liveCA.DeepCopy(), liveCert.DeepCopy(), dnsNames, later)
+		assert.NoError(t, err)
+		assert.Equal(t, caData, ca)
+		assert.NotEqual(t, certData[corev1.TLSCertKey], cert[corev1.TLSCertKey])
+		assert.Equal(t, certData[CABundleKey], cert[CABundleKey])
+	})
+
+	t.Run("CA rotated", func(t *testing.T) {
+		later := now.Add(caValidity * 3 / 4)
+		ca, cert, err := ServingCertData(liveCA.DeepCopy(), liveCert.DeepCopy(), dnsNames, later)
+		assert.NoError(t, err)
+		assert.NotEqual(t, caData[corev1.TLSCertKey], ca[corev1.TLSCertKey])
+
+		// the previous CA is trusted until it expires
+		cas := bundle(t, cert)
+		assert.Len(t, cas, 2)
+		assert.Equal(t, ca[corev1.TLSCertKey], pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cas[0].Raw}))
+		assert.Equal(t, caData[corev1.TLSCertKey], pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cas[1].Raw}))
+	})
+}
+
+func bundle(t *testing.T, certData map[string][]byte) []*x509.Certificate {
+	cas := []*x509.Certificate{}
+	rest := certData[CABundleKey]
+	for {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			return cas
+		}
+		ca, err := x509.ParseCertificate(block.Bytes)
+		assert.NoError(t, err)
+		cas = append(cas, ca)
+	}
+}
@@ -18,17 +18,29 @@ package reconciler
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
 
+	"github.com/cespare/xxhash/v2"
 	"github.com/go-logr/logr"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
+// FieldManager is the name of the field manager used to apply resources
+const FieldManager = "kepler-operator"
+
+// AppliedHashAnnotation is a hash of the resource last applied by the operator
+const AppliedHashAnnotation = "kepler.system.sustainable.computing.io/applied-hash"
+
 type Updater struct {
 	Owner    metav1.Object
 	Resource client.Object
@@ -37,6 +49,7 @@ type Updater struct {
 }
 
 func (r Updater) Reconcile(ctx context.Context, c client.Client, scheme *runtime.Scheme) Result {
+	r.Logger = loggerFor(ctx, r.Logger)
 	ownerNs := r.Owner.GetNamespace()
 	resourceNs := r.Resource.GetNamespace()
 
@@ -50,9 +63,56 @@ func (r Updater) Reconcile(ctx context.Context, c client.Client, scheme *runtime
 		}
 	}
 
+	if err := setAppliedHash(r.Resource); err != nil {
+		return Result{
+			Action: Stop,
+			Error:  r.error("hashing resource failed", err),
+		}
+	}
+
+	// NOTE: skip applying resources that are already up-to-date so that
+	// periodic reconciles do not generate writes to the api server
+	live, upToDate := r.isUpToDate(ctx, c)
+	if upToDate {
+		r.Logger.V(8).Info("resource is up-to-date; skipping update", "resource", k8s.GVKName(r.Resource))
+		return Result{}
+	}
+
+	// NOTE: the live object may differ from the resource only in fields that
+	// are normalized by the api server e.g. quantities, so a dry-run apply is
+	// used to check whether applying the resource would change the object
+	if live != nil && !IsDryRun(c) && r.isUnchangedByApply(ctx, c, live) {
+		r.Logger.V(8).Info("applying resource changes nothing; skipping update", "resource", k8s.GVKName(r.Resource))
+		return Result{}
+	}
+
+	// NOTE: the resource is unchanged since it was last applied, so the live
+	// object no longer having its fields means it was modified out-of-band
+	// unless the operator made the last change itself
+	drifted := live != nil && !IsDryRun(c) && appliedHash(live) == appliedHash(r.Resource) &&
+		!modifiedByOperator(live)
+
 	r.Logger.V(8).Info("updating resource", "resource", k8s.GVKName(r.Resource))
 
-	if err := c.Patch(ctx, r.Resource, client.Apply, client.ForceOwnership, client.FieldOwner("kepler-operator")); err != nil {
+	// NOTE: apply without forcing ownership first so that fields set by other
+	// managers are detected and logged before they are taken over
+	err := c.Patch(ctx, r.Resource, client.Apply, client.FieldOwner(FieldManager))
+	if errors.IsConflict(err) {
+		r.Logger.V(3).Info("taking ownership of conflicting fields",
+			"resource", k8s.GVKName(r.Resource), "conflicts", err.Error())
+		err = c.Patch(ctx, r.Resource, client.Apply, client.ForceOwnership, client.FieldOwner(FieldManager))
+	}
+
+	if err == nil && IsDryRun(c) {
+		if live == nil {
+			r.Logger.Info("dry-run: resource would be created", "resource", k8s.GVKName(r.Resource))
+		} else {
+			r.Logger.Info("dry-run: resource would be updated", "resource", k8s.GVKName(r.Resource),
+				"diff", diff(live, r.Resource))
+		}
+	}
+
+	if err != nil {
 		if errors.IsConflict(err) || errors.IsAlreadyExists(err) {
 			// the cache may be stale; requests a Reconcile
 			r.Logger.V(3).Error(err, "patch failed")
@@ -62,14 +122,83 @@ func (r Updater) Reconcile(ctx context.Context, c client.Client, scheme *runtime
 			}
 		}
 
+		if gvk, gvkErr := apiutil.GVKForObject(r.Resource, scheme); gvkErr == nil {
+			applyErrors.WithLabelValues(gvk.Kind).Inc()
+		}
 		return Result{
 			Action: r.OnError,
 			Error:  r.error("patch failed", err),
 		}
 	}
+
+	// NOTE: a drift is counted only once it has been reverted
+	if drifted {
+		r.recordDrift(ctx, scheme, live)
+	}
 	return Result{}
 }
 
+// isUpToDate returns the live object and true if the resource has been
+// applied before and the live object still has all the fields of the resource.
+// The applied hash detects fields that have been removed from the resource
+// since they are not detected by comparing the resource with the live object.
+func (r Updater) isUpToDate(ctx context.Context, c client.Client) (client.Object, bool) {
+	live := r.Resource.DeepCopyObject().(client.Object)
+	if err := c.Get(ctx, client.ObjectKeyFromObject(r.Resource), live); err != nil {
+		return nil, false
+	}
+	if live.GetAnnotations()[AppliedHashAnnotation] != r.Resource.GetAnnotations()[AppliedHashAnnotation] {
+		return live, false
+	}
+
+	desired, err := runtime.DefaultUnstructuredConverter.ToUnstructured(r.Resource)
+	if err != nil {
+		return live, false
+	}
+	actual, err := runtime.DefaultUnstructuredConverter.ToUnstructured(live)
+	if err != nil {
+		return live, false
+	}
+
+	// NOTE: type meta of typed objects read from the cache may be empty and
+	// status is not applied
+	for _, f := range []string{"apiVersion", "kind", "status"} {
+		delete(desired, f)
+	}
+	unstructured.RemoveNestedField(desired, "metadata", "creationTimestamp")
+
+	return live, equality.Semantic.DeepDerivative(desired, actual)
+}
+
+// isUnchangedByApply returns true if a server-side dry-run apply of the
+// resource results in the live object
+func (r Updater) isUnchangedByApply(ctx context.Context, c client.Client, live client.Object) bool {
+	applied := r.Resource.DeepCopyObject().(client.Object)
+	err := c.Patch(ctx, applied, client.Apply, client.DryRunAll, client.ForceOwnership, client.FieldOwner(FieldManager))
+	if err != nil {
+		return false
+	}
+	return diff(live, applied) == ""
+}
+
+// setAppliedHash annotates obj with a hash of obj
+func setAppliedHash(obj client.Object) error {
+	annotations := obj.GetAnnotations()
+	delete(annotations, AppliedHashAnnotation)
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[AppliedHashAnnotation] = strconv.FormatUint(xxhash.Sum64(data), 16)
+	obj.SetAnnotations(annotations)
+	return nil
+}
+
 func (r Updater) error(msg string, err error) error {
 	return fmt.Errorf("%s: updater: %s : %w", k8s.GVKName(r.Resource), msg, err)
 }
@@ -0,0 +1,200 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func TestUpdaterIsUpToDate(t *testing.T) {
+	desired := func() *appsv1.Deployment {
+		d := k8s.Deployment("ns", "name").WithLabels(map[string]string{"app": "kepler"}).Build()
+		assert.NoError(t, setAppliedHash(d))
+		return d
+	}
+
+	tt := []struct {
+		scenario string
+		live     func() *appsv1.Deployment
+		upToDate bool
+	}{
+		{"not found", nil, false},
+		{"same as desired", desired, true},
+		{
+			"extra fields in live",
+			func() *appsv1.Deployment {
+				d := desired()
+				d.Labels["extra"] = "label"
+				d.Spec.MinReadySeconds = 10
+				return d
+			},
+			true,
+		},
+		{
+			"changed out-of-band",
+			func() *appsv1.Deployment {
+				d := desired()
+				d.Labels["app"] = "other"
+				return d
+			},
+			false,
+		},
+		{
+			"applied by an older version",
+			func() *appsv1.Deployment {
+				d := desired()
+				d.Labels["removed"] = "label"
+				assert.NoError(t, setAppliedHash(d))
+				return d
+			},
+			false,
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			objs := []client.Object{}
+			if tc.live != nil {
+				objs = append(objs, tc.live())
+			}
+			c := fake.NewClientBuilder().WithObjects(objs...).Build()
+
+			updater := Updater{Resource: desired()}
+			_, upToDate := updater.isUpToDate(context.TODO(), c)
+			assert.Equal(t, tc.upToDate, upToDate)
+		})
+	}
+}
+
+func TestUpdaterDrift(t *testing.T) {
+	owner := k8s.Deployment("ns", "owner").Build()
+	desired := func() *appsv1.Deployment {
+		d := k8s.Deployment("ns", "name").WithLabels(map[string]string{"app": "kepler"}).Build()
+		assert.NoError(t, ctrlutil.SetControllerReference(owner, d, scheme.Scheme))
+		assert.NoError(t, setAppliedHash(d))
+		return d
+	}
+
+	now := metav1.Now()
+
+	tt := []struct {
+		scenario string
+		live     func() *appsv1.Deployment
+		drifted  bool
+	}{
+		{"not found", nil, false},
+		{
+			"status and extra fields changed by others",
+			func() *appsv1.Deployment {
+				d := desired()
+				d.Labels["injected"] = "by-others"
+				d.Status.ReadyReplicas = 3
+				d.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: "kube-controller-manager", Time: &now}}
+				return d
+			},
+			false,
+		},
+		{
+			"resource changed by the operator",
+			func() *appsv1.Deployment {
+				d := desired()
+				d.Labels["app"] = "older"
+				assert.NoError(t, setAppliedHash(d))
+				return d
+			},
+			false,
+		},
+		{
+			"applied field changed by the operator",
+			func() *appsv1.Deployment {
+				d := desired()
+				d.Labels["app"] = "other"
+				d.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: FieldManager, Time: &now}}
+				return d
+			},
+			false,
+		},
+		{
+			"applied field changed out-of-band",
+			func() *appsv1.Deployment {
+				d := desired()
+				d.Labels["app"] = "other"
+				d.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: "kubectl-edit", Time: &now}}
+				return d
+			},
+			true,
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			objs := []client.Object{}
+			if tc.live != nil {
+				objs = append(objs, tc.live())
+			}
+			c := fake.NewClientBuilder().WithObjects(objs...).Build()
+			recorder := record.NewFakeRecorder(10)
+			ctx := withEventRecorder(context.TODO(), recorder)
+
+			before := testutil.ToFloat64(driftReverts.WithLabelValues("Deployment"))
+			Updater{Owner: owner, Resource: desired()}.Reconcile(ctx, c, scheme.Scheme)
+			reverts := testutil.ToFloat64(driftReverts.WithLabelValues("Deployment")) - before
+
+			if !tc.drifted {
+				assert.Zero(t, reverts)
+				assert.Empty(t, recorder.Events)
+				return
+			}
+			assert.Equal(t, 1.0, reverts)
+			assert.Len(t, recorder.Events, 1)
+			if len(recorder.Events) == 0 {
+				return
+			}
+			event := <-recorder.Events
+			assert.Contains(t, event, EventDriftDetected)
+			assert.Contains(t, event, `"kubectl-edit"`)
+		})
+	}
+}
+
+func TestUpdaterDriftNotReverted(t *testing.T) {
+	owner := k8s.Deployment("ns", "owner").Build()
+	desired := k8s.Deployment("ns", "name").WithLabels(map[string]string{"app": "kepler"}).Build()
+	assert.NoError(t, ctrlutil.SetControllerReference(owner, desired, scheme.Scheme))
+	assert.NoError(t, setAppliedHash(desired))
+
+	now := metav1.Now()
+	live := desired.DeepCopy()
+	live.Labels["app"] = "other"
+	live.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: "kubectl-edit", Time: &now}}
+
+	c := fake.NewClientBuilder().WithObjects(live).WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(context.Context, client.WithWatch, client.Object, client.Patch, ...client.PatchOption) error {
+			return fmt.Errorf("forbidden")
+		},
+	}).Build()
+	recorder := record.NewFakeRecorder(10)
+	ctx := withEventRecorder(context.TODO(), recorder)
+
+	before := testutil.ToFloat64(driftReverts.WithLabelValues("Deployment"))
+	result := Updater{Owner: owner, Resource: desired}.Reconcile(ctx, c, scheme.Scheme)
+	assert.Error(t, result.Error)
+	assert.Zero(t, testutil.ToFloat64(driftReverts.WithLabelValues("Deployment"))-before)
+	assert.Empty(t, recorder.Events)
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// APIGroups caches the api groups served by the cluster so that the
+// capabilities of the cluster are not discovered on every reconcile. The
+// groups are refreshed periodically so that add-ons installed or removed
+// after the operator started are detected.
+type APIGroups struct {
+	discovery discovery.DiscoveryInterface
+	interval  time.Duration
+	changes   chan event.GenericEvent
+
+	mu     sync.RWMutex
+	groups map[string]bool
+}
+
+// NewAPIGroups returns a cache of the api groups that is refreshed every
+// interval once started
+func NewAPIGroups(dc discovery.DiscoveryInterface, interval time.Duration) *APIGroups {
+	return &APIGroups{
+		discovery: dc,
+		interval:  interval,
+		changes:   make(chan event.GenericEvent, 1),
+	}
+}
+
+// Has returns true if the cluster serves the api group; returns false if the
+// groups have not been discovered
+func (a *APIGroups) Has(group string) bool {
+	if a == nil {
+		return false
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.groups[group]
+}
+
+// Discovered returns true if the groups have been discovered at least once
+func (a *APIGroups) Discovered() bool {
+	if a == nil {
+		return false
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.groups != nil
+}
+
+// Changes returns a channel that receives an event whenever the served api
+// groups change after they were first discovered
+func (a *APIGroups) Changes() <-chan event.GenericEvent {
+	return a.changes
+}
+
+// Refresh discovers the api groups served by the cluster
+func (a *APIGroups) Refresh() error {
+	list, err := a.discovery.ServerGroups()
+	if err != nil {
+		return fmt.Errorf("failed to list api groups: %w", err)
+	}
+	groups := make(map[string]bool, len(list.Groups))
+	for _, g := range list.Groups {
+		groups[g.Name] = true
+	}
+
+	a.mu.Lock()
+	changed := a.groups != nil && !sameGroups(a.groups, groups)
+	a.groups = groups
+	a.mu.Unlock()
+
+	if changed {
+		select {
+		case a.changes <- event.GenericEvent{Object: &metav1.PartialObjectMetadata{}}:
+		default: // a change is already pending
+		}
+	}
+	return nil
+}
+
+// Start refreshes the groups every interval until ctx is done
+func (a *APIGroups) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("api-groups")
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := a.Refresh(); err != nil {
+				logger.Error(err, "failed to refresh api groups")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection returns false so that the groups are refreshed by all
+// replicas of the operator
+func (a *APIGroups) NeedLeaderElection() bool {
+	return false
+}
+
+func sameGroups(a, b map[string]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for g := range a {
+		if !b[g] {
+			return false
+		}
+	}
+	return true
+}
@@ -20,9 +20,10 @@ import (
 	"fmt"
 
 	secv1 "github.com/openshift/api/security/v1"
-	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -34,6 +35,38 @@ const (
 	OpenShift
 )
 
+func (c Cluster) String() string {
+	if c == OpenShift {
+		return "openshift"
+	}
+	return "kubernetes"
+}
+
+// DetectCluster returns OpenShift if the cluster serves the security api of
+// OpenShift and Kubernetes otherwise
+func DetectCluster(dc discovery.DiscoveryInterface) (Cluster, error) {
+	found, err := HasAPIGroup(dc, secv1.GroupName)
+	if err != nil || !found {
+		return Kubernetes, err
+	}
+	return OpenShift, nil
+}
+
+// HasAPIGroup returns true if the cluster serves the api group, e.g. when the
+// CRDs of an add-on are installed
+func HasAPIGroup(dc discovery.DiscoveryInterface, group string) (bool, error) {
+	groups, err := dc.ServerGroups()
+	if err != nil {
+		return false, fmt.Errorf("failed to list api groups: %w", err)
+	}
+	for _, g := range groups.Groups {
+		if g.Name == group {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // ContainerIndex type represents the hard-coded index of Containers in a PodSpec
 type ContainerIndex int
 
@@ -49,7 +82,7 @@ type SCCAllows struct {
 }
 
 func (l StringMap) Merge(other StringMap) StringMap {
-	ret := StringMap{}
+	ret := make(StringMap, len(l)+len(other))
 
 	for k, v := range l {
 		ret[k] = v
@@ -140,13 +173,13 @@ func GVKName(o client.Object) string {
 	return fmt.Sprintf("%s/%s (%s)", ns, name, gvk)
 }
 
-func FindCondition(c []v1alpha1.Condition, t v1alpha1.ConditionType) (v1alpha1.Condition, error) {
+func FindCondition(c []metav1.Condition, t string) (metav1.Condition, error) {
 	for _, cond := range c {
 		if cond.Type == t {
 			return cond, nil
 		}
 	}
-	return v1alpha1.Condition{}, fmt.Errorf("condition %s not found", t)
+	return metav1.Condition{}, fmt.Errorf("condition %s not found", t)
 }
 
 func NodeSelectorFromDS(ds *appsv1.DaemonSet) map[string]string {
@@ -165,6 +198,10 @@ func CommandFromDS(ds *appsv1.DaemonSet, index ContainerIndex) []string {
 	return ds.Spec.Template.Spec.Containers[index].Command
 }
 
+func ImageFromDS(ds *appsv1.DaemonSet, index ContainerIndex) string {
+	return ds.Spec.Template.Spec.Containers[index].Image
+}
+
 func AnnotationFromDS(ds *appsv1.DaemonSet) map[string]string {
 	return ds.Spec.Template.Annotations
 }
@@ -0,0 +1,345 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	dockerHub         = "docker.io"
+	dockerHubRegistry = "registry-1.docker.io"
+)
+
+// manifestTypes are the media types of the manifests a tag may point to;
+// indexes are preferred so that the digest is the same on all architectures
+var manifestTypes = strings.Join([]string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}, ", ")
+
+// digestRe matches image references pinned to a digest, e.g. image@sha256:...
+var digestRe = regexp.MustCompile(`@[a-z0-9]+:[a-f0-9]{32,}$`)
+
+// HasDigest returns true if the image is referenced by digest
+func HasDigest(image string) bool {
+	return digestRe.MatchString(image)
+}
+
+// Pin returns the image referenced by the digest; the tag is kept so that the
+// version can still be read from the image
+func Pin(image, digest string) string {
+	return image + "@" + digest
+}
+
+// Resolver resolves the tag of an image to the digest of its manifest
+type Resolver interface {
+	Resolve(ctx context.Context, image string) (string, error)
+}
+
+// Credentials authenticate the access to a registry
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// Keychain returns the credentials keyed by the host of the registry
+type Keychain func(ctx context.Context) (map[string]Credentials, error)
+
+// HTTPResolver resolves tags using the Docker Registry HTTP API V2
+type HTTPResolver struct {
+	// Client defaults to http.DefaultClient
+	Client *http.Client
+
+	// Keychain, if set, returns the credentials of private registries;
+	// registries without credentials are accessed anonymously
+	Keychain Keychain
+}
+
+var _ Resolver = HTTPResolver{}
+
+// Resolve returns the digest of the manifest the tag of the image points to
+func (r HTTPResolver) Resolve(ctx context.Context, image string) (string, error) {
+	ref, err := parse(image)
+	if err != nil {
+		return "", err
+	}
+
+	creds, err := r.credentials(ctx, ref.registry)
+	if err != nil {
+		return "", fmt.Errorf("failed to get credentials of %s: %w", ref.registry, err)
+	}
+
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", ref.registry, ref.repository, ref.tag)
+	resp, err := r.get(ctx, http.MethodHead, manifestURL, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", image, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		authorization, err := r.authorization(ctx, resp.Header.Get("WWW-Authenticate"), creds)
+		if err != nil {
+			return "", fmt.Errorf("failed to authorize resolving %s: %w", image, err)
+		}
+		resp.Body.Close()
+		if resp, err = r.get(ctx, http.MethodHead, manifestURL, authorization); err != nil {
+			return "", fmt.Errorf("failed to resolve %s: %w", image, err)
+		}
+		defer resp.Body.Close()
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to resolve %s: registry responded %s", image, resp.Status)
+	}
+
+	if digest := resp.Header.Get("Docker-Content-Digest"); digest != "" {
+		return digest, nil
+	}
+
+	// NOTE: registries are not required to return the digest of the manifest,
+	// in which case it is computed from the manifest itself
+	manifest, err := r.get(ctx, http.MethodGet, manifestURL, resp.Request.Header.Get("Authorization"))
+	if err != nil {
+		return "", fmt.Errorf("failed to get manifest of %s: %w", image, err)
+	}
+	defer manifest.Body.Close()
+	if manifest.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get manifest of %s: registry responded %s", image, manifest.Status)
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, manifest.Body); err != nil {
+		return "", fmt.Errorf("failed to read manifest of %s: %w", image, err)
+	}
+	return fmt.Sprintf("sha256:%x", h.Sum(nil)), nil
+}
+
+func (r HTTPResolver) client() *http.Client {
+	if r.Client != nil {
+		return r.Client
+	}
+	return http.DefaultClient
+}
+
+func (r HTTPResolver) get(ctx context.Context, method, url, authorization string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", manifestTypes)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	return r.client().Do(req)
+}
+
+// credentials returns the credentials of the registry; nil if the registry is
+// accessed anonymously
+func (r HTTPResolver) credentials(ctx context.Context, registry string) (*Credentials, error) {
+	if r.Keychain == nil {
+		return nil, nil
+	}
+	keychain, err := r.Keychain(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if creds, ok := keychain[registry]; ok {
+		return &creds, nil
+	}
+	return nil, nil
+}
+
+// authorization returns the Authorization header answering the challenge of
+// the registry
+func (r HTTPResolver) authorization(ctx context.Context, challenge string, creds *Credentials) (string, error) {
+	scheme, _, _ := strings.Cut(challenge, " ")
+	switch {
+	case strings.EqualFold(scheme, "Bearer"):
+		token, err := r.token(ctx, challenge, creds)
+		if err != nil {
+			return "", err
+		}
+		return "Bearer " + token, nil
+	case strings.EqualFold(scheme, "Basic") && creds != nil:
+		return "Basic " + basicAuth(*creds), nil
+	}
+	return "", fmt.Errorf("unsupported authentication challenge %q", challenge)
+}
+
+func basicAuth(creds Credentials) string {
+	return base64.StdEncoding.EncodeToString([]byte(creds.Username + ":" + creds.Password))
+}
+
+// token returns a bearer token for the challenge of the registry; the token
+// is requested anonymously unless there are credentials
+func (r HTTPResolver) token(ctx context.Context, challenge string, creds *Credentials) (string, error) {
+	_, params, _ := strings.Cut(challenge, " ")
+
+	realm := ""
+	query := url.Values{}
+	for _, p := range splitParams(params) {
+		key, value, _ := strings.Cut(p, "=")
+		value = strings.Trim(value, `"`)
+		switch key {
+		case "realm":
+			realm = value
+		case "service", "scope":
+			query.Set(key, value)
+		}
+	}
+	if realm == "" {
+		return "", fmt.Errorf("no realm in authentication challenge %q", challenge)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	if creds != nil {
+		req.SetBasicAuth(creds.Username, creds.Password)
+	}
+	resp, err := r.client().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token service responded %s", resp.Status)
+	}
+
+	body := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("invalid token response: %w", err)
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	return body.AccessToken, nil
+}
+
+// splitParams splits the comma separated params of a challenge; commas may
+// appear in the quoted scope
+func splitParams(params string) []string {
+	parts := []string{}
+	quoted := false
+	start := 0
+	for i, c := range params {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case c == ',' && !quoted:
+			parts = append(parts, strings.TrimSpace(params[start:i]))
+			start = i + 1
+		}
+	}
+	return append(parts, strings.TrimSpace(params[start:]))
+}
+
+type reference struct {
+	registry   string
+	repository string
+	tag        string
+}
+
+// parse splits the image into registry, repository and tag following the
+// conventions of docker for images without registry or tag
+func parse(image string) (reference, error) {
+	if HasDigest(image) {
+		return reference{}, fmt.Errorf("image %s is already referenced by digest", image)
+	}
+	if image == "" {
+		return reference{}, fmt.Errorf("image is empty")
+	}
+
+	ref := reference{registry: dockerHub, repository: image, tag: "latest"}
+	if first, rest, ok := strings.Cut(image, "/"); ok &&
+		(strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.registry, ref.repository = first, rest
+	}
+
+	// NOTE: the tag follows the last colon that is not part of the registry
+	if i := strings.LastIndex(ref.repository, ":"); i != -1 {
+		ref.repository, ref.tag = ref.repository[:i], ref.repository[i+1:]
+	}
+
+	if ref.registry == dockerHub {
+		ref.registry = dockerHubRegistry
+		if !strings.Contains(ref.repository, "/") {
+			ref.repository = "library/" + ref.repository
+		}
+	}
+	return ref, nil
+}
+
+// ParseDockerConfig returns the credentials in the auths of a docker config,
+// i.e. the .dockerconfigjson of an image pull secret, keyed by the host of
+// the registry
+func ParseDockerConfig(data []byte) (map[string]Credentials, error) {
+	config := struct {
+		Auths map[string]struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+			Auth     string `json:"auth"`
+		} `json:"auths"`
+	}{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid docker config: %w", err)
+	}
+
+	keychain := map[string]Credentials{}
+	for server, auth := range config.Auths {
+		creds := Credentials{Username: auth.Username, Password: auth.Password}
+		if auth.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("invalid auth of %s: %w", server, err)
+			}
+			username, password, ok := strings.Cut(string(decoded), ":")
+			if !ok {
+				return nil, fmt.Errorf("invalid auth of %s: not username:password", server)
+			}
+			creds = Credentials{Username: username, Password: password}
+		}
+		keychain[registryHost(server)] = creds
+	}
+	return keychain, nil
+}
+
+// registryHost returns the host of the registry as used by parse for the
+// server of a docker config, e.g. https://index.docker.io/v1/
+func registryHost(server string) string {
+	host := strings.TrimPrefix(strings.TrimPrefix(server, "https://"), "http://")
+	host, _, _ = strings.Cut(host, "/")
+	switch host {
+	case dockerHub, "index.docker.io":
+		return dockerHubRegistry
+	}
+	return host
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tt := []struct {
+		image    string
+		ref      reference
+		scenario string
+	}{
+		{
+			image:    "quay.io/sustainable_computing_io/kepler:release-0.7.8",
+			ref:      reference{"quay.io", "sustainable_computing_io/kepler", "release-0.7.8"},
+			scenario: "registry and tag",
+		},
+		{
+			image:    "localhost:5001/kepler",
+			ref:      reference{"localhost:5001", "kepler", "latest"},
+			scenario: "registry with port and no tag",
+		},
+		{
+			image:    "busybox:1.36",
+			ref:      reference{"registry-1.docker.io", "library/busybox", "1.36"},
+			scenario: "docker hub official image",
+		},
+		{
+			image:    "grafana/grafana",
+			ref:      reference{"registry-1.docker.io", "grafana/grafana", "latest"},
+			scenario: "docker hub image",
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			ref, err := parse(tc.image)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.ref, ref)
+		})
+	}
+
+	_, err := parse("quay.io/kepler@sha256:" + strings.Repeat("a", 64))
+	assert.Error(t, err)
+}
+
+func TestResolve(t *testing.T) {
+	const token = "secret"
+	manifest := `{"schemaVersion":2}`
+	digest := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(manifest)))
+
+	mux := http.NewServeMux()
+	srv := httptest.NewTLSServer(mux)
+	t.Cleanup(srv.Close)
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "repository:kepler:pull", r.URL.Query().Get("scope"))
+		fmt.Fprintf(w, `{"token":%q}`, token)
+	})
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.Header().Set("WWW-Authenticate",
+				fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:kepler:pull"`, srv.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/kepler/manifests/v1":
+			w.Header().Set("Docker-Content-Digest", digest)
+		case "/v2/kepler/manifests/no-digest-header":
+			// the digest is computed from the manifest
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, manifest)
+	})
+
+	host := strings.TrimPrefix(srv.URL, "https://")
+	resolver := HTTPResolver{Client: srv.Client()}
+
+	tt := []struct {
+		image    string
+		err      bool
+		scenario string
+	}{
+		{image: host + "/kepler:v1", scenario: "digest header"},
+		{image: host + "/kepler:no-digest-header", scenario: "digest of manifest"},
+		{image: host + "/kepler:unknown", err: true, scenario: "unknown tag"},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			actual, err := resolver.Resolve(context.TODO(), tc.image)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, digest, actual)
+		})
+	}
+}
+
+func TestResolveWithCredentials(t *testing.T) {
+	const token = "private"
+	manifest := `{"schemaVersion":2}`
+	digest := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(manifest)))
+
+	mux := http.NewServeMux()
+	srv := httptest.NewTLSServer(mux)
+	t.Cleanup(srv.Close)
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "robot" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintf(w, `{"access_token":%q}`, token)
+	})
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.Header().Set("WWW-Authenticate",
+				fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:private/kepler:pull"`, srv.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Docker-Content-Digest", digest)
+		fmt.Fprint(w, manifest)
+	})
+
+	host := strings.TrimPrefix(srv.URL, "https://")
+	image := host + "/private/kepler:v1"
+
+	_, err := HTTPResolver{Client: srv.Client()}.Resolve(context.TODO(), image)
+	assert.Error(t, err)
+
+	auth := base64.StdEncoding.EncodeToString([]byte("robot:secret"))
+	keychain, err := ParseDockerConfig([]byte(fmt.Sprintf(`{"auths":{%q:{"auth":%q}}}`, host, auth)))
+	assert.NoError(t, err)
+	resolver := HTTPResolver{
+		Client: srv.Client(),
+		Keychain: func(context.Context) (map[string]Credentials, error) {
+			return keychain, nil
+		},
+	}
+	actual, err := resolver.Resolve(context.TODO(), image)
+	assert.NoError(t, err)
+	assert.Equal(t, digest, actual)
+}
+
+func TestParseDockerConfig(t *testing.T) {
+	auth := base64.StdEncoding.EncodeToString([]byte("user:pass:word"))
+	keychain, err := ParseDockerConfig([]byte(`{"auths":{
+		"https://index.docker.io/v1/": {"auth": "` + auth + `"},
+		"quay.io": {"username": "robot", "password": "secret"}
+	}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]Credentials{
+		"registry-1.docker.io": {Username: "user", Password: "pass:word"},
+		"quay.io":              {Username: "robot", Password: "secret"},
+	}, keychain)
+
+	_, err = ParseDockerConfig([]byte(`{"auths":{"quay.io":{"auth":"not base64"}}}`))
+	assert.Error(t, err)
+}
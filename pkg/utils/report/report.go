@@ -0,0 +1,236 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	// energyQuery returns the kWh consumed by the containers of each namespace
+	// over the period as measured by the kepler deployed in a namespace
+	energyQuery = `sum by (container_namespace) (increase(kepler_container_joules_total{namespace=%q}[%s])) / 3600000`
+
+	// emissionsQuery returns the gCO2e emitted by the containers of each
+	// namespace over the period from the rate recorded by the carbon
+	// intensity rules every 5m
+	emissionsQuery = `sum by (container_namespace) (sum_over_time(namespace:kepler_container_gco2e:rate5m[%s:5m])) * 300`
+)
+
+// Namespace is the energy consumed by the containers of a namespace
+type Namespace struct {
+	Namespace string   `json:"namespace"`
+	EnergyKWh float64  `json:"energyKWh"`
+	CO2eGrams *float64 `json:"co2eGrams,omitempty"`
+}
+
+// Report is the energy consumed by each namespace over a period
+type Report struct {
+	Start      time.Time   `json:"start"`
+	End        time.Time   `json:"end"`
+	Namespaces []Namespace `json:"namespaces"`
+}
+
+// JSON returns the report as indented JSON
+func (r Report) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// CSV returns the report with one row per namespace; the emissions are left
+// empty if they are not reported
+func (r Report) CSV() ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write([]string{"start", "end", "namespace", "energy_kwh", "co2e_grams"}); err != nil {
+		return nil, err
+	}
+	start, end := r.Start.Format(time.RFC3339), r.End.Format(time.RFC3339)
+	for _, ns := range r.Namespaces {
+		co2e := ""
+		if ns.CO2eGrams != nil {
+			co2e = strconv.FormatFloat(*ns.CO2eGrams, 'f', -1, 64)
+		}
+		row := []string{start, end, ns.Namespace, strconv.FormatFloat(ns.EnergyKWh, 'f', -1, 64), co2e}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// Period returns the start of the period reported at end; weekly reports
+// cover the past 7 days and monthly reports the past calendar month.
+// NOTE: the energy consumed before the retention of the metrics is not
+// reported, e.g. the last 15 days of a month by default on OpenShift
+func Period(end time.Time, monthly bool) time.Time {
+	if monthly {
+		return end.AddDate(0, -1, 0)
+	}
+	return end.AddDate(0, 0, -7)
+}
+
+// Querier queries the energy consumed, or any instant vector, from a
+// Prometheus compatible API
+type Querier struct {
+	// URL of the API, e.g. https://thanos-querier.openshift-monitoring.svc:9091
+	URL string
+	// Token is sent as bearer token if set
+	Token string
+	// Namespace of the kepler whose metrics are reported
+	Namespace string
+	// Client defaults to http.DefaultClient
+	Client *http.Client
+}
+
+// NewClient returns a client that trusts the CA bundle in caFile, e.g. the
+// service CA of OpenShift, in addition to the system CAs
+func NewClient(caFile string) (*http.Client, error) {
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", caFile)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	return &http.Client{Transport: transport}, nil
+}
+
+// Report returns the energy consumed by each namespace between start and
+// end, and the emissions if withEmissions is set
+func (q Querier) Report(ctx context.Context, start, end time.Time, withEmissions bool) (Report, error) {
+	// NOTE: Prometheus durations do not support days longer than 24h, so the
+	// range is given in seconds
+	rng := strconv.FormatInt(int64(end.Sub(start)/time.Second), 10) + "s"
+
+	energy, err := q.query(ctx, fmt.Sprintf(energyQuery, q.Namespace, rng), end)
+	if err != nil {
+		return Report{}, fmt.Errorf("energy query failed: %w", err)
+	}
+	var emissions map[string]float64
+	if withEmissions {
+		if emissions, err = q.query(ctx, fmt.Sprintf(emissionsQuery, rng), end); err != nil {
+			return Report{}, fmt.Errorf("emissions query failed: %w", err)
+		}
+	}
+
+	report := Report{Start: start.UTC(), End: end.UTC(), Namespaces: make([]Namespace, 0, len(energy))}
+	for ns, kwh := range energy {
+		n := Namespace{Namespace: ns, EnergyKWh: kwh}
+		if co2e, ok := emissions[ns]; ok {
+			n.CO2eGrams = &co2e
+		}
+		report.Namespaces = append(report.Namespaces, n)
+	}
+	sort.Slice(report.Namespaces, func(i, j int) bool {
+		return report.Namespaces[i].Namespace < report.Namespaces[j].Namespace
+	})
+	return report, nil
+}
+
+type queryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			Value  [2]interface{}    `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// query evaluates an instant query at t and returns the value of each
+// container_namespace
+func (q Querier) query(ctx context.Context, query string, t time.Time) (map[string]float64, error) {
+	return q.Vector(ctx, query, "container_namespace", t)
+}
+
+// Vector evaluates an instant query at t and returns the value of each series
+// by the value of its label
+func (q Querier) Vector(ctx context.Context, query, label string, t time.Time) (map[string]float64, error) {
+	params := url.Values{
+		"query": {query},
+		"time":  {strconv.FormatInt(t.Unix(), 10)},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, q.URL+"/api/v1/query?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if q.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+q.Token)
+	}
+
+	client := q.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var qr queryResponse
+	if err := json.Unmarshal(body, &qr); err != nil {
+		return nil, fmt.Errorf("unexpected response %s: %w", resp.Status, err)
+	}
+	if qr.Status != "success" {
+		return nil, fmt.Errorf("%s: %s", resp.Status, qr.Error)
+	}
+	if qr.Data.ResultType != "vector" {
+		return nil, fmt.Errorf("unexpected result type %q", qr.Data.ResultType)
+	}
+
+	values := make(map[string]float64, len(qr.Data.Result))
+	for _, r := range qr.Data.Result {
+		s, ok := r.Value[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected value %v", r.Value[1])
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, err
+		}
+		values[r.Metric[label]] = v
+	}
+	return values, nil
+}
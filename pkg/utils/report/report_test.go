@@ -0,0 +1,159 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeriod(t *testing.T) {
+	end := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 2, 23, 0, 0, 0, 0, time.UTC), Period(end, false))
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Period(end, true))
+}
+
+func TestQuerierReport(t *testing.T) {
+	queries := []string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/query", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		query := r.URL.Query().Get("query")
+		queries = append(queries, query)
+
+		values := `{"metric":{"container_namespace":"b"},"value":[1709251200,"2.5"]},` +
+			`{"metric":{"container_namespace":"a"},"value":[1709251200,"1"]}`
+		if strings.Contains(query, "gco2e") {
+			values = `{"metric":{"container_namespace":"a"},"value":[1709251200,"400"]}`
+		}
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":[%s]}}`, values)
+	}))
+	defer srv.Close()
+
+	end := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	q := Querier{URL: srv.URL, Token: "secret", Namespace: "kepler"}
+	r, err := q.Report(context.Background(), Period(end, false), end, true)
+	assert.NoError(t, err)
+
+	assert.Len(t, queries, 2)
+	assert.Contains(t, queries[0], `kepler_container_joules_total{namespace="kepler"}[604800s]`)
+
+	co2e := 400.0
+	assert.Equal(t, []Namespace{
+		{Namespace: "a", EnergyKWh: 1, CO2eGrams: &co2e},
+		{Namespace: "b", EnergyKWh: 2.5},
+	}, r.Namespaces)
+
+	csv, err := r.CSV()
+	assert.NoError(t, err)
+	assert.Equal(t, "start,end,namespace,energy_kwh,co2e_grams\n"+
+		"2024-02-23T00:00:00Z,2024-03-01T00:00:00Z,a,1,400\n"+
+		"2024-02-23T00:00:00Z,2024-03-01T00:00:00Z,b,2.5,\n", string(csv))
+}
+
+func TestQuerierError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"status":"error","error":"parse error"}`)
+	}))
+	defer srv.Close()
+
+	end := time.Now()
+	_, err := Querier{URL: srv.URL}.Report(context.Background(), Period(end, false), end, false)
+	assert.ErrorContains(t, err, "parse error")
+}
+
+func TestQuerierVector(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "1709251200", r.URL.Query().Get("time"))
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[`+
+			`{"metric":{"instance":"node-a"},"value":[1709251200,"120.5"]}]}}`)
+	}))
+	defer srv.Close()
+
+	values, err := Querier{URL: srv.URL}.Vector(context.Background(), "up", "instance", time.Unix(1709251200, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]float64{"node-a": 120.5}, values)
+}
+
+func TestNewClient(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[]}}`)
+	}))
+	defer srv.Close()
+
+	_, err := Querier{URL: srv.URL}.Vector(context.Background(), "up", "instance", time.Now())
+	assert.ErrorContains(t, err, "certificate")
+
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	assert.NoError(t, os.WriteFile(caFile, cert, 0o644))
+	client, err := NewClient(caFile)
+	assert.NoError(t, err)
+	_, err = Querier{URL: srv.URL, Client: client}.Vector(context.Background(), "up", "instance", time.Now())
+	assert.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(caFile, []byte("not a certificate"), 0o644))
+	_, err = NewClient(caFile)
+	assert.Error(t, err)
+}
+
+func TestS3Sign(t *testing.T) {
+	s3 := S3{
+		Region:          "eu-west-1",
+		AccessKeyID:     "key",
+		SecretAccessKey: "secret",
+		now:             func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) },
+	}
+	body := []byte(`{"namespaces":[]}`)
+	req, _ := http.NewRequest(http.MethodPut, "https://s3.example.com/reports/kepler/energy-report-2024-01-01.json", nil)
+	s3.sign(req, body)
+
+	assert.Equal(t, "20240101T000000Z", req.Header.Get("X-Amz-Date"))
+	assert.Equal(t, "AWS4-HMAC-SHA256 Credential=key/20240101/eu-west-1/s3/aws4_request, "+
+		"SignedHeaders=host;x-amz-content-sha256;x-amz-date, "+
+		"Signature=f2ec28ed68c78bdbf574a38c90bbc7115fa460d5d13420e523aec3451fc432f8",
+		req.Header.Get("Authorization"))
+}
+
+func TestS3Put(t *testing.T) {
+	var path, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key/"))
+	}))
+	defer srv.Close()
+
+	s3 := S3{Endpoint: srv.URL, Region: "us-east-1", Bucket: "reports", AccessKeyID: "key", SecretAccessKey: "secret"}
+	err := s3.Put(context.Background(), "kepler/energy report.csv", "text/csv", []byte("a,b\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "/reports/kepler/energy%20report.csv", path)
+	assert.Equal(t, "a,b\n", body)
+}
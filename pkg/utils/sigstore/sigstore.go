@@ -0,0 +1,373 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sigstore verifies the signatures of blobs signed by
+// `cosign sign-blob` and published next to the blob as <url>.sig or, for
+// keyless signatures, as the bundle of cosign at <url>.bundle.
+package sigstore
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+)
+
+var (
+	// oidIssuer is the extension of Fulcio certificates carrying the OIDC
+	// issuer of the identity as a DER encoded string
+	oidIssuer = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+	// oidIssuerV1 is the deprecated extension carrying the issuer as raw bytes
+	oidIssuerV1 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+)
+
+// Policy selects the signatures that are trusted: signatures made with the
+// PublicKey or, if it is nil, keyless signatures made with a certificate issued
+// by one of the Roots to the Subject by the Issuer and logged, while the
+// certificate was valid, by the transparency log signing with the RekorKey.
+type Policy struct {
+	PublicKey crypto.PublicKey
+
+	Roots         *x509.CertPool
+	Intermediates *x509.CertPool
+	RekorKey      crypto.PublicKey
+	Issuer        string
+	Subject       string
+}
+
+// PublicKeyPolicy returns the policy trusting the PEM encoded public key
+func PublicKeyPolicy(data []byte) (Policy, error) {
+	key, err := parsePublicKey(data)
+	if err != nil {
+		return Policy{}, err
+	}
+	return Policy{PublicKey: key}, nil
+}
+
+func parsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM encoded public key found")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	return key, nil
+}
+
+// KeylessPolicy returns the policy trusting certificates issued by the PEM
+// encoded certificate chain to the subject by the issuer and logged by the
+// transparency log with the PEM encoded rekorKey; self-signed certificates of
+// the chain are roots, the others are intermediates
+func KeylessPolicy(chain, rekorKey []byte, issuer, subject string) (Policy, error) {
+	key, err := parsePublicKey(rekorKey)
+	if err != nil {
+		return Policy{}, fmt.Errorf("rekor: %w", err)
+	}
+	p := Policy{
+		Roots:         x509.NewCertPool(),
+		Intermediates: x509.NewCertPool(),
+		RekorKey:      key,
+		Issuer:        issuer,
+		Subject:       subject,
+	}
+	roots := 0
+	for block, rest := pem.Decode(chain); block != nil; block, rest = pem.Decode(rest) {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return Policy{}, fmt.Errorf("invalid certificate: %w", err)
+		}
+		if bytes.Equal(cert.RawIssuer, cert.RawSubject) {
+			p.Roots.AddCert(cert)
+			roots++
+		} else {
+			p.Intermediates.AddCert(cert)
+		}
+	}
+	if roots == 0 {
+		return Policy{}, errors.New("no root certificate found")
+	}
+	return p, nil
+}
+
+// Verifier downloads blobs along with their signatures and verifies them
+type Verifier struct {
+	// Client defaults to http.DefaultClient
+	Client *http.Client
+}
+
+// Verify returns the sha256 digest of the blob at url if its signature is
+// trusted by the policy. Keyless signatures are trusted only if the
+// transparency log has signed, with a signed entry timestamp, an entry for
+// the blob, signature and certificate at a time the certificate was valid;
+// the certificate chain is verified as of that time.
+func (v Verifier) Verify(ctx context.Context, url string, p Policy) (string, error) {
+	h := sha256.New()
+	if err := v.get(ctx, url, h); err != nil {
+		return "", err
+	}
+	digest := h.Sum(nil)
+
+	var signature []byte
+	key := p.PublicKey
+	if key != nil {
+		sig := &bytes.Buffer{}
+		if err := v.get(ctx, url+".sig", sig); err != nil {
+			return "", fmt.Errorf("failed to get signature: %w", err)
+		}
+		var err error
+		signature, err = base64.StdEncoding.DecodeString(string(bytes.TrimSpace(sig.Bytes())))
+		if err != nil {
+			return "", fmt.Errorf("invalid signature: %w", err)
+		}
+	} else {
+		data := &bytes.Buffer{}
+		if err := v.get(ctx, url+".bundle", data); err != nil {
+			return "", fmt.Errorf("failed to get bundle: %w", err)
+		}
+		b := bundle{}
+		if err := json.Unmarshal(data.Bytes(), &b); err != nil {
+			return "", fmt.Errorf("invalid bundle: %w", err)
+		}
+		var err error
+		signature, err = base64.StdEncoding.DecodeString(b.Base64Signature)
+		if err != nil {
+			return "", fmt.Errorf("invalid signature: %w", err)
+		}
+		cert, err := parseCertificate([]byte(b.Cert))
+		if err != nil {
+			return "", err
+		}
+		signedAt, err := p.verifyLogEntry(b.RekorBundle, digest, signature, cert)
+		if err != nil {
+			return "", err
+		}
+		if err := p.verifyCertificate(cert, signedAt); err != nil {
+			return "", err
+		}
+		key = cert.PublicKey
+	}
+
+	if err := verifySignature(key, digest, signature); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("sha256:%x", digest), nil
+}
+
+// bundle is the bundle written by `cosign sign-blob --bundle` holding the
+// signature, the signing certificate and the entry of the transparency log
+type bundle struct {
+	Base64Signature string       `json:"base64Signature"`
+	Cert            string       `json:"cert"`
+	RekorBundle     *rekorBundle `json:"rekorBundle"`
+}
+
+// rekorBundle is an entry of the transparency log along with its signed entry
+// timestamp (SET)
+type rekorBundle struct {
+	SignedEntryTimestamp []byte       `json:"SignedEntryTimestamp"`
+	Payload              rekorPayload `json:"Payload"`
+}
+
+// rekorPayload is the entry signed by the SET; the fields are in the order of
+// the canonical JSON the SET is computed over
+type rekorPayload struct {
+	Body           string `json:"body"`
+	IntegratedTime int64  `json:"integratedTime"`
+	LogID          string `json:"logID"`
+	LogIndex       int64  `json:"logIndex"`
+}
+
+// hashedRekord is the body of the entry of a signed blob
+type hashedRekord struct {
+	Kind string `json:"kind"`
+	Spec struct {
+		Data struct {
+			Hash struct {
+				Algorithm string `json:"algorithm"`
+				Value     string `json:"value"`
+			} `json:"hash"`
+		} `json:"data"`
+		Signature struct {
+			Content   []byte `json:"content"`
+			PublicKey struct {
+				Content []byte `json:"content"`
+			} `json:"publicKey"`
+		} `json:"signature"`
+	} `json:"spec"`
+}
+
+// verifyLogEntry verifies that the transparency log has signed an entry for
+// the digest, signature and certificate at a time the certificate was valid
+// and returns that time
+func (p Policy) verifyLogEntry(rb *rekorBundle, digest, signature []byte, cert *x509.Certificate) (time.Time, error) {
+	if rb == nil {
+		return time.Time{}, errors.New("bundle has no transparency log entry")
+	}
+
+	keyDER, err := x509.MarshalPKIXPublicKey(p.RekorKey)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid rekor public key: %w", err)
+	}
+	if logID := sha256.Sum256(keyDER); rb.Payload.LogID != hex.EncodeToString(logID[:]) {
+		return time.Time{}, fmt.Errorf("entry logged by %s instead of the trusted transparency log", rb.Payload.LogID)
+	}
+	payload, err := json.Marshal(rb.Payload)
+	if err != nil {
+		return time.Time{}, err
+	}
+	payloadDigest := sha256.Sum256(payload)
+	if err := verifySignature(p.RekorKey, payloadDigest[:], rb.SignedEntryTimestamp); err != nil {
+		return time.Time{}, fmt.Errorf("signed entry timestamp: %w", err)
+	}
+
+	body, err := base64.StdEncoding.DecodeString(rb.Payload.Body)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid transparency log entry: %w", err)
+	}
+	entry := hashedRekord{}
+	if err := json.Unmarshal(body, &entry); err != nil {
+		return time.Time{}, fmt.Errorf("invalid transparency log entry: %w", err)
+	}
+	if entry.Kind != "hashedrekord" || entry.Spec.Data.Hash.Algorithm != "sha256" ||
+		entry.Spec.Data.Hash.Value != hex.EncodeToString(digest) {
+		return time.Time{}, errors.New("transparency log entry is not for the blob")
+	}
+	if !bytes.Equal(entry.Spec.Signature.Content, signature) {
+		return time.Time{}, errors.New("transparency log entry is not for the signature")
+	}
+	logged, err := parseCertificate(entry.Spec.Signature.PublicKey.Content)
+	if err != nil || !logged.Equal(cert) {
+		return time.Time{}, errors.New("transparency log entry is not for the certificate")
+	}
+
+	signedAt := time.Unix(rb.Payload.IntegratedTime, 0)
+	if signedAt.Before(cert.NotBefore) || signedAt.After(cert.NotAfter) {
+		return time.Time{}, fmt.Errorf("signature logged at %s outside of the validity of the certificate from %s to %s",
+			signedAt.UTC(), cert.NotBefore.UTC(), cert.NotAfter.UTC())
+	}
+	return signedAt, nil
+}
+
+func (v Verifier) get(ctx context.Context, url string, w io.Writer) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	client := v.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to get %s: server responded %s", url, resp.Status)
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// parseCertificate parses the PEM encoded certificate which cosign writes
+// base64 encoded
+func parseCertificate(data []byte) (*x509.Certificate, error) {
+	data = bytes.TrimSpace(data)
+	if decoded, err := base64.StdEncoding.DecodeString(string(data)); err == nil {
+		data = decoded
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// verifyCertificate verifies the certificate as of the time the signature
+// was logged since signing certificates are valid for minutes only
+func (p Policy) verifyCertificate(cert *x509.Certificate, signedAt time.Time) error {
+	_, err := cert.Verify(x509.VerifyOptions{
+		Roots:         p.Roots,
+		Intermediates: p.Intermediates,
+		CurrentTime:   signedAt,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	})
+	if err != nil {
+		return fmt.Errorf("untrusted certificate: %w", err)
+	}
+
+	subjects := append([]string{}, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		subjects = append(subjects, uri.String())
+	}
+	if !slices.Contains(subjects, p.Subject) {
+		return fmt.Errorf("certificate issued to %v instead of %s", subjects, p.Subject)
+	}
+	if issuer := certificateIssuer(cert); issuer != p.Issuer {
+		return fmt.Errorf("certificate issued for an identity of %q instead of %q", issuer, p.Issuer)
+	}
+	return nil
+}
+
+// certificateIssuer returns the OIDC issuer of the identity the certificate
+// has been issued to
+func certificateIssuer(cert *x509.Certificate) string {
+	for _, ext := range cert.Extensions {
+		switch {
+		case ext.Id.Equal(oidIssuer):
+			var issuer string
+			if _, err := asn1.Unmarshal(ext.Value, &issuer); err == nil {
+				return issuer
+			}
+		case ext.Id.Equal(oidIssuerV1):
+			return string(ext.Value)
+		}
+	}
+	return ""
+}
+
+func verifySignature(key crypto.PublicKey, digest, signature []byte) error {
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(k, digest, signature) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(k, crypto.SHA256, digest, signature); err != nil {
+			return fmt.Errorf("invalid signature: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported public key %T", key)
+	}
+}
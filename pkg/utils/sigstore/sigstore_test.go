@@ -0,0 +1,221 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sigstore
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	issuer  = "https://token.actions.githubusercontent.com"
+	subject = "release@sustainable-computing.io"
+)
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	return key
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, blob []byte) string {
+	digest := sha256.Sum256(blob)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	assert.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(sig)
+}
+
+func newCert(t *testing.T, template, parent *x509.Certificate, pub, signer any) (*x509.Certificate, []byte) {
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, pub, signer)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func publicKeyPEM(t *testing.T, key *ecdsa.PrivateKey) []byte {
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+// newBundle returns the bundle of the signature of the blob made with the
+// certificate and logged by the transparency log at the given time
+func newBundle(t *testing.T, rekorKey, key *ecdsa.PrivateKey, certPEM, blob []byte, loggedAt time.Time) bundle {
+	sig := sign(t, key, blob)
+	entry := hashedRekord{Kind: "hashedrekord"}
+	entry.Spec.Data.Hash.Algorithm = "sha256"
+	entry.Spec.Data.Hash.Value = fmt.Sprintf("%x", sha256.Sum256(blob))
+	entry.Spec.Signature.Content, _ = base64.StdEncoding.DecodeString(sig)
+	entry.Spec.Signature.PublicKey.Content = certPEM
+	body, err := json.Marshal(entry)
+	assert.NoError(t, err)
+
+	keyDER, err := x509.MarshalPKIXPublicKey(&rekorKey.PublicKey)
+	assert.NoError(t, err)
+	logID := sha256.Sum256(keyDER)
+	payload := rekorPayload{
+		Body:           base64.StdEncoding.EncodeToString(body),
+		IntegratedTime: loggedAt.Unix(),
+		LogID:          hex.EncodeToString(logID[:]),
+		LogIndex:       42,
+	}
+	canonical, err := json.Marshal(payload)
+	assert.NoError(t, err)
+	set, err := base64.StdEncoding.DecodeString(sign(t, rekorKey, canonical))
+	assert.NoError(t, err)
+
+	return bundle{
+		Base64Signature: sig,
+		Cert:            base64.StdEncoding.EncodeToString(certPEM),
+		RekorBundle:     &rekorBundle{SignedEntryTimestamp: set, Payload: payload},
+	}
+}
+
+func encodeBundle(t *testing.T, b bundle) string {
+	data, err := json.Marshal(b)
+	assert.NoError(t, err)
+	return string(data)
+}
+
+func TestVerify(t *testing.T) {
+	blob := []byte("model archive")
+	digest := fmt.Sprintf("sha256:%x", sha256.Sum256(blob))
+
+	key := newKey(t)
+	keyPolicy, err := PublicKeyPolicy(publicKeyPEM(t, key))
+	assert.NoError(t, err)
+
+	rootKey := newKey(t)
+	rootTemplate := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "sigstore"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	root, rootPEM := newCert(t, rootTemplate, rootTemplate, &rootKey.PublicKey, rootKey)
+	rekorKey := newKey(t)
+	keylessPolicy, err := KeylessPolicy(rootPEM, publicKeyPEM(t, rekorKey), issuer, subject)
+	assert.NoError(t, err)
+
+	issuerExt, err := asn1.Marshal(issuer)
+	assert.NoError(t, err)
+	leafKey := newKey(t)
+	_, leafPEM := newCert(t, &x509.Certificate{
+		NotBefore:       time.Now().Add(-time.Hour),
+		NotAfter:        time.Now().Add(-50 * time.Minute),
+		EmailAddresses:  []string{subject},
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		ExtraExtensions: []pkix.Extension{{Id: oidIssuer, Value: issuerExt}},
+	}, root, &leafKey.PublicKey, rootKey)
+
+	// NOTE: the certificate is valid from 60 to 50 minutes ago
+	loggedAt := time.Now().Add(-55 * time.Minute)
+	unlogged := newBundle(t, rekorKey, leafKey, leafPEM, blob, loggedAt)
+	unlogged.RekorBundle = nil
+	forged := newBundle(t, rekorKey, leafKey, leafPEM, blob, loggedAt)
+	forged.RekorBundle.SignedEntryTimestamp = newBundle(t, newKey(t), leafKey, leafPEM, blob, loggedAt).RekorBundle.SignedEntryTimestamp
+
+	files := map[string]string{
+		"/key/model.zip":                string(blob),
+		"/key/model.zip.sig":            sign(t, key, blob),
+		"/tampered/model.zip":           "tampered archive",
+		"/tampered/model.zip.sig":       sign(t, key, blob),
+		"/keyless/model.zip":            string(blob),
+		"/keyless/model.zip.bundle":     encodeBundle(t, newBundle(t, rekorKey, leafKey, leafPEM, blob, loggedAt)),
+		"/expired/model.zip":            string(blob),
+		"/expired/model.zip.bundle":     encodeBundle(t, newBundle(t, rekorKey, leafKey, leafPEM, blob, time.Now())),
+		"/unlogged/model.zip":           string(blob),
+		"/unlogged/model.zip.bundle":    encodeBundle(t, unlogged),
+		"/forged/model.zip":             string(blob),
+		"/forged/model.zip.bundle":      encodeBundle(t, forged),
+		"/other-log/model.zip":          string(blob),
+		"/other-log/model.zip.bundle":   encodeBundle(t, newBundle(t, newKey(t), leafKey, leafPEM, blob, loggedAt)),
+		"/other-entry/model.zip":        string(blob),
+		"/other-entry/model.zip.bundle": encodeBundle(t, newBundle(t, rekorKey, leafKey, leafPEM, []byte("other archive"), loggedAt)),
+		"/unsigned/model.zip":           string(blob),
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, ok := files[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, content)
+	}))
+	t.Cleanup(srv.Close)
+
+	otherSubject := keylessPolicy
+	otherSubject.Subject = "someone@example.com"
+	otherIssuer := keylessPolicy
+	otherIssuer.Issuer = "https://accounts.google.com"
+
+	tt := []struct {
+		path     string
+		policy   Policy
+		err      bool
+		scenario string
+	}{
+		{path: "/key/model.zip", policy: keyPolicy, scenario: "public key"},
+		{path: "/tampered/model.zip", policy: keyPolicy, err: true, scenario: "tampered archive"},
+		{path: "/unsigned/model.zip", policy: keyPolicy, err: true, scenario: "no signature"},
+		{path: "/keyless/model.zip", policy: keyPolicy, err: true, scenario: "signed by another key"},
+		{path: "/keyless/model.zip", policy: keylessPolicy, scenario: "keyless"},
+		{path: "/expired/model.zip", policy: keylessPolicy, err: true, scenario: "keyless logged after the certificate expired"},
+		{path: "/unlogged/model.zip", policy: keylessPolicy, err: true, scenario: "keyless not logged"},
+		{path: "/forged/model.zip", policy: keylessPolicy, err: true, scenario: "keyless forged entry timestamp"},
+		{path: "/other-log/model.zip", policy: keylessPolicy, err: true, scenario: "keyless logged by another log"},
+		{path: "/other-entry/model.zip", policy: keylessPolicy, err: true, scenario: "keyless entry of another blob"},
+		{path: "/keyless/model.zip", policy: otherSubject, err: true, scenario: "keyless other subject"},
+		{path: "/keyless/model.zip", policy: otherIssuer, err: true, scenario: "keyless other issuer"},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+			actual, err := Verifier{Client: srv.Client()}.Verify(context.TODO(), srv.URL+tc.path, tc.policy)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, digest, actual)
+		})
+	}
+}
@@ -27,6 +27,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 )
@@ -112,19 +113,19 @@ func (f Framework) AssertNoResourceExists(name, ns string, obj client.Object, fn
 
 func (f Framework) AssertInternalStatus(name string, fns ...AssertOptionFn) {
 	// the status will be updated
-	ki := f.WaitUntilInternalCondition(name, v1alpha1.Reconciled, v1alpha1.ConditionTrue, fns...)
+	ki := f.WaitUntilInternalCondition(name, v1alpha1.Reconciled, metav1.ConditionTrue, fns...)
 	assert.Equal(f.T, []corev1.Toleration{{Operator: "Exists"}}, ki.Spec.Exporter.Deployment.Tolerations)
 
-	reconciled, err := k8s.FindCondition(ki.Status.Exporter.Conditions, v1alpha1.Reconciled)
+	reconciled, err := k8s.FindCondition(ki.Status.Conditions, v1alpha1.Reconciled)
 	assert.NoError(f.T, err, "unable to get reconciled condition")
 	assert.Equal(f.T, reconciled.ObservedGeneration, ki.Generation)
-	assert.Equal(f.T, reconciled.Status, v1alpha1.ConditionTrue)
+	assert.Equal(f.T, reconciled.Status, metav1.ConditionTrue)
 	//
-	ki = f.WaitUntilInternalCondition(name, v1alpha1.Available, v1alpha1.ConditionTrue, fns...)
-	available, err := k8s.FindCondition(ki.Status.Exporter.Conditions, v1alpha1.Available)
+	ki = f.WaitUntilInternalCondition(name, v1alpha1.Available, metav1.ConditionTrue, fns...)
+	available, err := k8s.FindCondition(ki.Status.Conditions, v1alpha1.Available)
 	assert.NoError(f.T, err, "unable to get available condition")
 	assert.Equal(f.T, available.ObservedGeneration, ki.Generation)
-	assert.Equal(f.T, available.Status, v1alpha1.ConditionTrue)
+	assert.Equal(f.T, available.Status, metav1.ConditionTrue)
 
 	f.AssertModelServerStatus(name, fns...)
 	f.AssertEstimatorStatus(name, fns...)
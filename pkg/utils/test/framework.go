@@ -253,7 +253,7 @@ func (f Framework) DeleteInternal(name string, fns ...AssertOptionFn) {
 	}, fns...)
 }
 
-func (f Framework) WaitUntilInternalCondition(name string, t v1alpha1.ConditionType, s v1alpha1.ConditionStatus, fns ...AssertOptionFn) *v1alpha1.KeplerInternal {
+func (f Framework) WaitUntilInternalCondition(name string, t string, s metav1.ConditionStatus, fns ...AssertOptionFn) *v1alpha1.KeplerInternal {
 	f.T.Helper()
 	k := v1alpha1.KeplerInternal{}
 	f.WaitUntil(fmt.Sprintf("kepler-internal %s is %s", name, t),
@@ -263,7 +263,7 @@ func (f Framework) WaitUntilInternalCondition(name string, t v1alpha1.ConditionT
 				return true, fmt.Errorf("kepler-internal %s is not found", name)
 			}
 
-			condition, _ := k8s.FindCondition(k.Status.Exporter.Conditions, t)
+			condition, _ := k8s.FindCondition(k.Status.Conditions, t)
 			return condition.Status == s, nil
 		}, fns...)
 	return &k
@@ -315,7 +315,7 @@ func (f Framework) AssertModelServerStatus(name string, fns ...AssertOptionFn) *
 	return &k
 }
 
-func (f Framework) WaitUntilKeplerCondition(name string, t v1alpha1.ConditionType, s v1alpha1.ConditionStatus) *v1alpha1.Kepler {
+func (f Framework) WaitUntilKeplerCondition(name string, t string, s metav1.ConditionStatus) *v1alpha1.Kepler {
 	f.T.Helper()
 	k := v1alpha1.Kepler{}
 	f.WaitUntil(fmt.Sprintf("kepler %s is %s", name, t),
@@ -325,7 +325,7 @@ func (f Framework) WaitUntilKeplerCondition(name string, t v1alpha1.ConditionTyp
 				return true, fmt.Errorf("kepler %s is not found", name)
 			}
 
-			condition, _ := k8s.FindCondition(k.Status.Exporter.Conditions, t)
+			condition, _ := k8s.FindCondition(k.Status.Conditions, t)
 			return condition.Status == s, nil
 		})
 	return &k
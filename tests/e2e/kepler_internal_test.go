@@ -25,6 +25,8 @@ import (
 	"github.com/sustainable.computing.io/kepler-operator/pkg/api/v1alpha1"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/components/exporter"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/controllers"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/features"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/reconciler"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/test"
 	appsv1 "k8s.io/api/apps/v1"
@@ -99,8 +101,8 @@ func TestKeplerInternal_ReconciliationWithRedfish(t *testing.T) {
 
 	// provide time for controller to reconcile
 	// NOTE: reconcile should be false since the secret is not created yet
-	ki = f.WaitUntilInternalCondition(name, v1alpha1.Reconciled, v1alpha1.ConditionFalse)
-	reconciled, _ := k8s.FindCondition(ki.Status.Exporter.Conditions, v1alpha1.Reconciled)
+	ki = f.WaitUntilInternalCondition(name, v1alpha1.Reconciled, metav1.ConditionFalse)
+	reconciled, _ := k8s.FindCondition(ki.Status.Conditions, v1alpha1.Reconciled)
 	assert.Equal(t, fmt.Sprintf("Redfish secret %q configured, but not found in %q namespace", secretName, testNs), reconciled.Message)
 
 	// create redfish secret
@@ -120,7 +122,7 @@ func TestKeplerInternal_ReconciliationWithRedfish(t *testing.T) {
 	f.AssertResourceExists(ki.Name, testNs, &ds)
 
 	// expect reconcile to be true after secret is created
-	ki = f.WaitUntilInternalCondition(name, v1alpha1.Reconciled, v1alpha1.ConditionTrue)
+	ki = f.WaitUntilInternalCondition(name, v1alpha1.Reconciled, metav1.ConditionTrue)
 
 	containers := ds.Spec.Template.Spec.Containers
 	assert.Equal(t, 1, len(containers))
@@ -130,7 +132,7 @@ func TestKeplerInternal_ReconciliationWithRedfish(t *testing.T) {
 		corev1.VolumeMount{Name: "redfish-cred", MountPath: "/etc/redfish", ReadOnly: true})
 	assert.Contains(t, ds.Spec.Template.Spec.Volumes,
 		k8s.VolumeFromSecret("redfish-cred", redfishSecret.Name))
-	assert.Contains(t, ds.Spec.Template.Annotations, exporter.RedfishSecretAnnotation)
+	assert.Contains(t, ds.Spec.Template.Annotations, reconciler.SecretsHashAnnotation)
 
 	og := ds.Status.ObservedGeneration
 	assert.Equal(t, og, int64(1))
@@ -157,6 +159,7 @@ func TestKeplerInternal_ReconciliationWithRedfish(t *testing.T) {
 }
 
 func TestKeplerInternal_WithEstimator(t *testing.T) {
+	requireFeatures(t, features.Estimator)
 	f := test.NewFramework(t)
 	name := "e2e-ki-with-estimator"
 	// Ensure Kepler is not deployed (by any chance)
@@ -190,6 +193,7 @@ func TestKeplerInternal_WithEstimator(t *testing.T) {
 }
 
 func TestKeplerInternal_WithModelServer(t *testing.T) {
+	requireFeatures(t, features.ModelServer)
 	f := test.NewFramework(t)
 	name := "e2e-ki-with-modelserver"
 	// Ensure Kepler is not deployed (by any chance)
@@ -228,6 +232,7 @@ func TestKeplerInternal_WithModelServer(t *testing.T) {
 }
 
 func TestKeplerInternal_WithEstimatorAndModelServer(t *testing.T) {
+	requireFeatures(t, features.Estimator, features.ModelServer)
 	f := test.NewFramework(t)
 	name := "e2e-ki-est-mserver"
 	// Ensure Kepler is not deployed (by any chance)
@@ -13,6 +13,7 @@ import (
 	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/test"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestKepler_Deletion(t *testing.T) {
@@ -20,7 +21,7 @@ func TestKepler_Deletion(t *testing.T) {
 
 	// pre-condition: ensure kepler exists
 	f.CreateKepler("kepler")
-	k := f.WaitUntilKeplerCondition("kepler", v1alpha1.Available, v1alpha1.ConditionTrue)
+	k := f.WaitUntilKeplerCondition("kepler", v1alpha1.Available, metav1.ConditionTrue)
 
 	//
 	ds := appsv1.DaemonSet{}
@@ -52,20 +53,20 @@ func TestKepler_Reconciliation(t *testing.T) {
 	ds := appsv1.DaemonSet{}
 	f.AssertResourceExists(k.Name, controllers.KeplerDeploymentNS, &ds)
 
-	kepler := f.WaitUntilKeplerCondition("kepler", v1alpha1.Reconciled, v1alpha1.ConditionTrue)
+	kepler := f.WaitUntilKeplerCondition("kepler", v1alpha1.Reconciled, metav1.ConditionTrue)
 	// ensure the default toleration is set
 	assert.Equal(t, []corev1.Toleration{{Operator: "Exists"}}, kepler.Spec.Exporter.Deployment.Tolerations)
 
-	reconciled, err := k8s.FindCondition(kepler.Status.Exporter.Conditions, v1alpha1.Reconciled)
+	reconciled, err := k8s.FindCondition(kepler.Status.Conditions, v1alpha1.Reconciled)
 	assert.NoError(t, err, "unable to get reconciled condition")
 	assert.Equal(t, reconciled.ObservedGeneration, kepler.Generation)
-	assert.Equal(t, reconciled.Status, v1alpha1.ConditionTrue)
+	assert.Equal(t, reconciled.Status, metav1.ConditionTrue)
 
-	kepler = f.WaitUntilKeplerCondition("kepler", v1alpha1.Available, v1alpha1.ConditionTrue)
-	available, err := k8s.FindCondition(kepler.Status.Exporter.Conditions, v1alpha1.Available)
+	kepler = f.WaitUntilKeplerCondition("kepler", v1alpha1.Available, metav1.ConditionTrue)
+	available, err := k8s.FindCondition(kepler.Status.Conditions, v1alpha1.Available)
 	assert.NoError(t, err, "unable to get available condition")
 	assert.Equal(t, available.ObservedGeneration, kepler.Generation)
-	assert.Equal(t, available.Status, v1alpha1.ConditionTrue)
+	assert.Equal(t, available.Status, metav1.ConditionTrue)
 }
 
 func TestBadKepler_Reconciliation(t *testing.T) {
@@ -97,7 +98,7 @@ func TestNodeSelector(t *testing.T) {
 	ds := appsv1.DaemonSet{}
 	f.AssertResourceExists(k.Name, controllers.KeplerDeploymentNS, &ds)
 
-	kepler := f.WaitUntilKeplerCondition("kepler", v1alpha1.Available, v1alpha1.ConditionTrue)
+	kepler := f.WaitUntilKeplerCondition("kepler", v1alpha1.Available, metav1.ConditionTrue)
 	assert.EqualValues(t, 1, kepler.Status.Exporter.NumberAvailable)
 
 	f.DeleteKepler("kepler")
@@ -122,7 +123,7 @@ func TestNodeSelectorUnavailableLabel(t *testing.T) {
 	ds := appsv1.DaemonSet{}
 	f.AssertResourceExists(k.Name, controllers.KeplerDeploymentNS, &ds)
 
-	kepler := f.WaitUntilKeplerCondition("kepler", v1alpha1.Available, v1alpha1.ConditionFalse)
+	kepler := f.WaitUntilKeplerCondition("kepler", v1alpha1.Available, metav1.ConditionFalse)
 	assert.EqualValues(t, 0, kepler.Status.Exporter.NumberAvailable)
 
 	f.DeleteKepler("kepler")
@@ -156,7 +157,7 @@ func TestTaint_WithToleration(t *testing.T) {
 	ds := appsv1.DaemonSet{}
 	f.AssertResourceExists(k.Name, controllers.KeplerDeploymentNS, &ds)
 
-	kepler := f.WaitUntilKeplerCondition("kepler", v1alpha1.Available, v1alpha1.ConditionTrue)
+	kepler := f.WaitUntilKeplerCondition("kepler", v1alpha1.Available, metav1.ConditionTrue)
 	assert.EqualValues(t, len(nodes), kepler.Status.Exporter.NumberAvailable)
 
 	f.DeleteKepler("kepler")
@@ -194,7 +195,7 @@ func TestBadTaint_WithToleration(t *testing.T) {
 	ds := appsv1.DaemonSet{}
 	f.AssertResourceExists(k.Name, controllers.KeplerDeploymentNS, &ds)
 
-	kepler := f.WaitUntilKeplerCondition("kepler", v1alpha1.Available, v1alpha1.ConditionTrue)
+	kepler := f.WaitUntilKeplerCondition("kepler", v1alpha1.Available, metav1.ConditionTrue)
 	assert.EqualValues(t, len(nodes)-1, kepler.Status.Exporter.NumberAvailable)
 
 	f.DeleteKepler("kepler")
@@ -22,7 +22,9 @@ import (
 	"testing"
 
 	"github.com/sustainable.computing.io/kepler-operator/pkg/controllers"
+	"github.com/sustainable.computing.io/kepler-operator/pkg/features"
 	"github.com/sustainable.computing.io/kepler-operator/pkg/utils/k8s"
+	"k8s.io/component-base/featuregate"
 )
 
 var (
@@ -33,6 +35,7 @@ func TestMain(m *testing.M) {
 	openshift := flag.Bool("openshift", true, "Indicate if tests are run aginast an OpenShift cluster.")
 	flag.StringVar(&controllers.KeplerDeploymentNS, "deployment-namespace", controllers.KeplerDeploymentNS,
 		"Namespace where kepler and its components are deployed.")
+	flag.Func("feature-gates", "Features enabled in the operator under test.", features.Gate.Set)
 
 	flag.Parse()
 
@@ -42,3 +45,13 @@ func TestMain(m *testing.M) {
 
 	os.Exit(m.Run())
 }
+
+// requireFeatures skips the test unless the features are enabled in the
+// operator under test
+func requireFeatures(t *testing.T, fs ...featuregate.Feature) {
+	for _, f := range fs {
+		if !features.Enabled(f) {
+			t.Skipf("feature %s is disabled; run with -feature-gates=%s=true", f, f)
+		}
+	}
+}